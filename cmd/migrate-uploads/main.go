@@ -0,0 +1,90 @@
+// Command migrate-uploads moves product images saved under cmd/asset/uploads
+// into the storage backend configured with STORAGE and points the matching
+// image_items rows at the new object keys.
+//
+// Run it from cmd/migrate-uploads so the relative upload directory resolves
+// the same way it does for the API server.
+package main
+
+import (
+	"flag"
+	"io/fs"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"main.go/internal/infrastructure/config"
+	"main.go/internal/infrastructure/storage"
+)
+
+func main() {
+	keepLocal := flag.Bool("keep-local", false, "keep the local files after they are uploaded")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("cannot load config: ", err)
+	}
+	if cfg.STORAGE == "" || strings.EqualFold(cfg.STORAGE, "local") {
+		log.Fatal("STORAGE is local, files are already served from ", storage.LocalUploadDir)
+	}
+	store, err := storage.NewStorage(cfg)
+	if err != nil {
+		log.Fatal("cannot create storage backend: ", err)
+	}
+	db, err := gorm.Open(postgres.Open(cfg.DB_KEY), &gorm.Config{})
+	if err != nil {
+		log.Fatal("cannot connect to database: ", err)
+	}
+
+	var moved, failed int
+	err = filepath.WalkDir(storage.LocalUploadDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(storage.LocalUploadDir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if err := migrateFile(db, store, path, key); err != nil {
+			log.Printf("skipping %s: %v", path, err)
+			failed++
+			return nil
+		}
+		if !*keepLocal {
+			if err := os.Remove(path); err != nil {
+				log.Printf("uploaded %s but could not remove it: %v", path, err)
+			}
+		}
+		moved++
+		return nil
+	})
+	if err != nil {
+		log.Fatal("error walking upload directory: ", err)
+	}
+	log.Printf("migrated %d files, %d failed", moved, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func migrateFile(db *gorm.DB, store storage.Storage, path, key string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if err := store.Upload(key, file, info.Size(), mime.TypeByExtension(filepath.Ext(path))); err != nil {
+		return err
+	}
+	return db.Exec(`UPDATE image_items SET image=? WHERE image=?`, key, storage.LocalUploadDir+"/"+key).Error
+}
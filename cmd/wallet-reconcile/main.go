@@ -0,0 +1,50 @@
+// Command wallet-reconcile checks the wallet ledger: every wallet's amount
+// must equal the sum of its ledger entries and every transaction's entries
+// must add up to zero. It exits with status 1 when anything is off.
+//
+// With -fix mismatched wallets are reset to their ledger balance, unbalanced
+// transactions are only reported.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"main.go/internal/infrastructure/config"
+	"main.go/internal/repository"
+)
+
+func main() {
+	fix := flag.Bool("fix", false, "reset mismatched wallets to their ledger balance")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal("cannot load config: ", err)
+	}
+	db, err := gorm.Open(postgres.Open(cfg.DB_KEY), &gorm.Config{})
+	if err != nil {
+		log.Fatal("cannot connect to database: ", err)
+	}
+
+	reconciliation, err := repository.ReconcileWallets(db, *fix)
+	if err != nil {
+		log.Fatal("error reconciling wallets: ", err)
+	}
+	for _, mismatch := range reconciliation.Mismatches {
+		log.Printf("user %d: wallet has %d, ledger has %d", mismatch.UserId, mismatch.WalletAmount, mismatch.LedgerBalance)
+	}
+	for _, transactionId := range reconciliation.UnbalancedTransactions {
+		log.Printf("transaction %d: entries don't add up to zero", transactionId)
+	}
+	log.Printf("%d mismatched wallets, %d unbalanced transactions", len(reconciliation.Mismatches), len(reconciliation.UnbalancedTransactions))
+	if *fix && len(reconciliation.Mismatches) > 0 {
+		log.Printf("reset %d wallets to their ledger balance", len(reconciliation.Mismatches))
+	}
+	if len(reconciliation.UnbalancedTransactions) > 0 || (!*fix && len(reconciliation.Mismatches) > 0) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+package helperStruct
+
+import "time"
+
+type Collection struct {
+	Title           string     `json:"title" validate:"required"`
+	Slug            string     `json:"slug"` //generated from the title when empty
+	Description     string     `json:"description"`
+	Type            string     `json:"type"` //manual or rule
+	Min_price       float64    `json:"min_price"`
+	Max_price       float64    `json:"max_price"`
+	Brand_id        uint       `json:"brand_id"`
+	Attribute       string     `json:"attribute"` //color, ram, storage, battery, screen_size or graphic_processor
+	Attribute_value string     `json:"attribute_value"`
+	Tag             string     `json:"tag"`
+	Sort_by         string     `json:"sort_by"` //price_asc, price_desc or newest, rule collections only
+	Item_limit      int        `json:"item_limit"`
+	Show_on_home    bool       `json:"show_on_home"`
+	Position        int        `json:"position"`
+	Is_active       *bool      `json:"is_active"`
+	Starts_at       *time.Time `json:"starts_at"`
+	Ends_at         *time.Time `json:"ends_at"`
+}
+type CollectionItems struct {
+	Product_item_ids []uint `json:"product_item_ids"` //in display order
+}
+type ProductTags struct {
+	Tags []string `json:"tags"`
+}
+type Banner struct {
+	Title     string    `json:"title" validate:"required"`
+	Subtitle  string    `json:"subtitle"`
+	Image     string    `json:"image"`
+	Link_url  string    `json:"link_url"`
+	Placement string    `json:"placement"` //defaults to home
+	Position  int       `json:"position"`
+	Is_active *bool     `json:"is_active"`
+	Starts_at time.Time `json:"starts_at" validate:"required"`
+	Ends_at   time.Time `json:"ends_at" validate:"required"`
+}
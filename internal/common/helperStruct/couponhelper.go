@@ -1,15 +1,46 @@
 package helperStruct
 
+import "time"
+
 type Coupon struct {
-	Name     string `json:"name"`
-	Amount   int    `json:"amount"`
-	Quantity int    `json:"quantity"`
+	Name              string              `json:"name"`
+	Type              string              `json:"type"` //fixed or percentage, fixed by default
+	Amount            int                 `json:"amount"`
+	DiscountPercent   float64             `json:"discount_percent"`
+	MaxDiscount       int                 `json:"max_discount"`
+	MinCartValue      int                 `json:"min_cart_value"`
+	Quantity          int                 `json:"quantity"`
+	UsageLimitPerUser int                 `json:"usage_limit_per_user"` //1 when left out
+	FirstOrderOnly    bool                `json:"first_order_only"`
+	ValidFrom         time.Time           `json:"valid_from"` //now when left out
+	ValidUntil        time.Time           `json:"valid_until"`
+	Restrictions      []CouponRestriction `json:"restrictions"`
 }
 type UpdateCoupon struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Amount   int    `json:"amount"`
-	Quantity int    `json:"quantity"`
+	Id int `json:"id"`
+	Coupon
+}
+
+// CouponRestriction limits a coupon to a brand, a category or a single item.
+type CouponRestriction struct {
+	Scope   string `json:"scope"`
+	ScopeId uint   `json:"scope_id"`
+}
+
+// CouponUsage is what a user did before that decides whether they may use a coupon.
+type CouponUsage struct {
+	TimesUsed  int
+	PastOrders int
+}
+
+// CouponCampaign is a campaign to generate Count codes of Prefix followed by
+// CodeLength random characters. The embedded coupon names the campaign and
+// holds the rules every code carries.
+type CouponCampaign struct {
+	Prefix     string `json:"prefix"`
+	CodeLength int    `json:"code_length"` //8 when left out
+	Count      int    `json:"count"`
+	Coupon
 }
 type CouponName struct {
 	CouponName string
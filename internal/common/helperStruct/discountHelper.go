@@ -4,8 +4,55 @@ import (
 	"time"
 )
 
+// Discount is a discount as an admin adds or changes it. BrandId is still
+// accepted in place of a brand scope for clients that predate scopes.
 type Discount struct {
+	Name            string
+	Scope           string //brand, category, product, item or cart
+	ScopeId         uint
+	Type            string //percentage or fixed, percentage by default
 	DiscountPercent float64
-	BrandId         uint
+	Amount          float64
+	MaxDiscount     float64
+	MinCartValue    float64 //cart discounts only
+	Priority        int
+	Stackable       bool
+	StartDate       time.Time
 	ExpiryDate      time.Time
+	BrandId         uint
+}
+
+type PriceRule struct {
+	Name             string    `json:"name" validate:"required"`
+	Scope            string    `json:"scope"` //item, product, brand or category
+	Scope_id         uint      `json:"scope_id"`
+	Discount_percent float64   `json:"discount_percent"`
+	Sale_price       float64   `json:"sale_price"` //item scope only, instead of a percentage
+	Flash_quantity   int       `json:"flash_quantity"`
+	Starts_at        time.Time `json:"starts_at" validate:"required"`
+	Ends_at          time.Time `json:"ends_at" validate:"required"`
+	Is_active        *bool     `json:"is_active"`
+}
+
+// Promotion is a buy-x-get-y or bundle promotion as an admin adds or changes
+// it. Items hold the triggers and rewards of a buy_get promotion, or the
+// items of a bundle.
+type Promotion struct {
+	Name            string          `json:"name"`
+	Type            string          `json:"type"` //buy_get or bundle
+	TriggerQuantity int             `json:"trigger_quantity"`
+	RewardQuantity  int             `json:"reward_quantity"`
+	RewardPercent   float64         `json:"reward_percent"` //100 makes the reward free
+	BundlePrice     float64         `json:"bundle_price"`
+	MaxApplications int             `json:"max_applications"`
+	StartsAt        time.Time       `json:"starts_at"`
+	EndsAt          time.Time       `json:"ends_at"`
+	IsActive        *bool           `json:"is_active"`
+	Items           []PromotionItem `json:"items"`
+}
+
+type PromotionItem struct {
+	Role    string `json:"role"`  //trigger, reward or bundle
+	Scope   string `json:"scope"` //item, product, brand or category
+	ScopeId uint   `json:"scope_id"`
 }
@@ -0,0 +1,20 @@
+package helperStruct
+
+type LoyaltySettings struct {
+	IsActive         *bool   `json:"is_active"`
+	PointsPerRupee   float64 `json:"points_per_rupee"`
+	RupeesPerPoint   float64 `json:"rupees_per_point"`
+	MinRedeemPoints  int     `json:"min_redeem_points"`
+	MaxRedeemPercent float64 `json:"max_redeem_percent"` //0 lets points pay for a whole order
+	ExpiryDays       int     `json:"expiry_days"`        //0 keeps points forever
+}
+
+type LoyaltyTier struct {
+	Name                 string  `json:"name"`
+	MinSpend             int     `json:"min_spend"` //on delivered orders over the last 12 months
+	EarnMultiplier       float64 `json:"earn_multiplier"`
+	ExtraDiscountPercent float64 `json:"extra_discount_percent"`
+	FreeShipping         bool    `json:"free_shipping"`
+	PrioritySupport      bool    `json:"priority_support"`
+	ReturnWindowDays     int     `json:"return_window_days"` //0 keeps the store's return window
+}
@@ -14,6 +14,63 @@ type CartItems struct {
 	QtyInStock      int
 }
 
+// PricingItem is a cart line with what the pricing engine and the cart page need.
+type PricingItem struct {
+	ProductItemId     uint
+	ProductName       string
+	ProductId         uint
+	BrandId           uint
+	CategoryId        uint
+	Brand             string
+	Sku               string
+	Color             string
+	Ram               int
+	Battery           int
+	Storage           int
+	Graphic_Processor string
+	Quantity          int
+	FreeQuantity      int //units the cart added as a free promotion reward
+	QtyInStock        int
+	Price             float64
+	SalePrice         float64 //0 unless a sale is live
+	SaleRuleId        uint
+	SaleName          string
+	Discounts         []ApplicableDiscount `gorm:"-"` //live discounts covering the item
+}
+
+// ApplicablePromotion is a live promotion as the pricing engine applies it.
+type ApplicablePromotion struct {
+	Id              uint
+	Name            string
+	Type            string //buy_get or bundle
+	TriggerQuantity int
+	RewardQuantity  int
+	RewardPercent   float64
+	BundlePrice     float64
+	MaxApplications int
+	Items           []PromotionItem `gorm:"-"`
+}
+
+// ApplicableTier is the checkout benefits of the loyalty tier a user is in.
+type ApplicableTier struct {
+	Name                 string
+	ExtraDiscountPercent float64
+	FreeShipping         bool
+}
+
+// ApplicableDiscount is a live discount as the pricing engine applies it.
+type ApplicableDiscount struct {
+	Id              uint
+	Name            string
+	Type            string //percentage or fixed
+	DiscountPercent float64
+	Amount          float64
+	MaxDiscount     float64
+	MinCartValue    float64
+	Priority        int
+	Stackable       bool
+}
+
 type UpdateOrder struct {
 	OrderId       uint
 	OrderStatusID uint
@@ -6,6 +6,11 @@ type PaymentVerification struct {
 	PaymentRef string
 	Total      float64
 }
+type TopUpVerification struct {
+	RazorpayOrderId string
+	PaymentRef      string
+	Signature       string
+}
 type PaymentType struct {
 	Type string
 }
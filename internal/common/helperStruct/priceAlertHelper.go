@@ -0,0 +1,5 @@
+package helperStruct
+
+type PriceAlert struct {
+	Target_price float64 `json:"target_price" validate:"required"`
+}
@@ -5,18 +5,35 @@ import (
 )
 
 type Category struct {
-	Name string `json:"name" validate:"required"`
+	Name             string `json:"name" validate:"required"`
+	Parent_id        uint   `json:"parent_id"` //0 creates a top level category
+	Sort_order       int    `json:"sort_order"`
+	Slug             string `json:"slug"` //generated from the name when empty
+	Meta_title       string `json:"meta_title"`
+	Meta_description string `json:"meta_description"`
+}
+type MoveCategory struct {
+	Parent_id  uint `json:"parent_id"` //0 moves the category to the top level
+	Sort_order int  `json:"sort_order"`
 }
 type Brand struct {
-	Id          uint
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Category_id uint   `json:"category_id" validate:"required"`
+	Id               uint
+	Name             string `json:"name" validate:"required"`
+	Description      string `json:"description" validate:"required"`
+	Category_id      uint   `json:"category_id" validate:"required"`
+	Category_ids     []uint `json:"category_ids"` //extra categories the brand sells in
+	Slug             string `json:"slug"`         //generated from the name when empty
+	Meta_title       string `json:"meta_title"`
+	Meta_description string `json:"meta_description"`
 }
 type Product struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	Brand       uint   `json:"brand" validate:"required"`
+	Name             string `json:"name" validate:"required"`
+	Description      string `json:"description" validate:"required"`
+	Brand            uint   `json:"brand" validate:"required"`
+	Category_id      uint   `json:"category_id"` //defaults to the brand's primary category
+	Slug             string `json:"slug"`        //generated from the name when empty
+	Meta_title       string `json:"meta_title"`
+	Meta_description string `json:"meta_description"`
 }
 
 type ProductItem struct {
@@ -31,6 +48,9 @@ type ProductItem struct {
 	Graphic_Processor string  `json:"graphic_processor"`
 	Price             int     `json:"price"`
 	Image             string  `json:"image"`
+	Slug              string  `json:"slug"` //generated from the name when empty
+	Meta_title        string  `json:"meta_title"`
+	Meta_description  string  `json:"meta_description"`
 }
 type QueryParams struct {
 	Page     int    `json:"page"`
@@ -42,6 +62,7 @@ type QueryParams struct {
 }
 type ImageHelper struct {
 	ImageFile     multipart.File
+	FileName      string
 	ImageType     string
 	ProductItemId uint
 	ImageSize     int64
@@ -0,0 +1,9 @@
+package helperStruct
+
+type RecommendationOverride struct {
+	Product_item_id     uint   `json:"product_item_id" validate:"required"`
+	Recommended_item_id uint   `json:"recommended_item_id" validate:"required"`
+	Kind                string `json:"kind"`   //bought_together or similar
+	Action              string `json:"action"` //pin or exclude
+	Position            int    `json:"position"`
+}
@@ -1,11 +1,12 @@
 package helperStruct
 
 type UserReq struct {
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
-	Mobile   string `json:"mobile" binding:"required"`
-	Password string `json:"password"`
-	OTP      string `json:"OTP"`
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	Mobile       string `json:"mobile" binding:"required"`
+	Password     string `json:"password"`
+	OTP          string `json:"OTP"`
+	ReferralCode string `json:"referral_code"`
 }
 
 type LoginReq struct {
@@ -51,3 +52,9 @@ type UpdateEmail struct {
 type ReferralOffer struct {
 	ReferralId string `json:"referralId"`
 }
+type ReferralSettings struct {
+	IsActive              *bool `json:"is_active"`
+	RefereeReward         int   `json:"referee_reward"`
+	ReferrerReward        int   `json:"referrer_reward"`
+	MaxRewardsPerReferrer int   `json:"max_rewards_per_referrer"` //0 rewards every referral
+}
@@ -0,0 +1,17 @@
+package helperStruct
+
+// WalletAdjustment credits a user's wallet with a positive Amount and debits
+// it with a negative one.
+type WalletAdjustment struct {
+	UserId    int    `json:"user_id"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason"`
+	Reference string `json:"reference"` //ticket or order the adjustment is for
+}
+type WalletAdjustmentDecision struct {
+	Note string `json:"note"`
+}
+type WalletAdjustmentFilter struct {
+	Status string
+	UserId int
+}
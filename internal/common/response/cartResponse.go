@@ -12,10 +12,23 @@ type DisplayCart struct {
 	PricePerUnit      float64
 	DiscountPrice     float64 `json:"discount_price,omitempty"`
 	DiscountedPrice   float64 `json:"discounted_price,omitempty"`
+	Promotion         string  `json:"promotion,omitempty"`
+	PromotionDiscount float64 `json:"promotion_discount,omitempty"` //part of the discount price
+	FreeQuantity      int     `json:"free_quantity,omitempty"`      //units added as a free promotion reward
 	Total             float64
 }
 type ViewCart struct {
-	CartItems []DisplayCart `json:"cart_items"`
-	SubTotal  float64       `json:"sub_total"`
-	CartTotal float64       `json:"cart_total"`
+	CartItems      []DisplayCart `json:"cart_items"`
+	SubTotal       float64       `json:"sub_total"`
+	Discount       float64       `json:"discount"`
+	CartDiscount   float64       `json:"cart_discount"`
+	Coupon         string        `json:"coupon,omitempty"`
+	CouponDiscount float64       `json:"coupon_discount"`
+	CouponNotice   string        `json:"coupon_notice,omitempty"` //why the coupon applied to the cart was taken off
+	Shipping       float64       `json:"shipping"`
+	Tax            float64       `json:"tax"` //GST included in the cart total
+	CartTotal      float64       `json:"cart_total"`
+	Explanation    []PricingStep `json:"explanation"`
+	// Recommendations is filled in by the handler, it is not part of the cart itself.
+	Recommendations *Recommendations `json:"recommendations,omitempty"`
 }
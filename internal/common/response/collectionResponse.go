@@ -0,0 +1,41 @@
+package response
+
+import "time"
+
+type Collection struct {
+	Id             int
+	Title          string
+	Slug           string
+	Description    string
+	Type           string
+	MinPrice       float64 `json:",omitempty"`
+	MaxPrice       float64 `json:",omitempty"`
+	BrandId        int     `json:",omitempty"`
+	Attribute      string  `json:",omitempty"`
+	AttributeValue string  `json:",omitempty"`
+	Tag            string  `json:",omitempty"`
+	SortBy         string  `json:",omitempty"`
+	ItemLimit      int     `json:",omitempty"`
+	ShowOnHome     bool
+	Position       int
+	IsActive       bool
+	StartsAt       *time.Time    `json:",omitempty"`
+	EndsAt         *time.Time    `json:",omitempty"`
+	Items          []ProductItem `gorm:"-" json:",omitempty"`
+}
+type Banner struct {
+	Id        int
+	Title     string
+	Subtitle  string
+	Image     string
+	LinkUrl   string
+	Placement string
+	Position  int
+	IsActive  bool
+	StartsAt  time.Time
+	EndsAt    time.Time
+}
+type Home struct {
+	Banners  []Banner
+	Sections []Collection
+}
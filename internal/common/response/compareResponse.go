@@ -0,0 +1,28 @@
+package response
+
+type ComparedItem struct {
+	Id              uint
+	ProductName     string
+	Brand           string
+	Sku             string
+	Slug            string
+	CanonicalUrl    string `json:",omitempty"`
+	Image           string `json:",omitempty"`
+	Price           float64
+	DiscountPrice   float64
+	DiscountedPrice float64
+	QtyInStock      int
+	InStock         bool
+}
+
+// CompareRow holds one attribute for every compared item, in the same order as Comparison.Items.
+type CompareRow struct {
+	Attribute string
+	Values    []string
+	Different bool
+}
+type Comparison struct {
+	CategoryName string
+	Items        []ComparedItem
+	Attributes   []CompareRow
+}
@@ -1,9 +1,64 @@
 package response
 
+import "time"
+
 type Coupon struct {
-	Id         uint
-	Name       string
-	Quantity   int
-	Amount     int
-	IsDisabled bool
+	Id                uint
+	Name              string
+	Type              string
+	Quantity          int
+	Amount            int
+	DiscountPercent   float64
+	MaxDiscount       int
+	MinCartValue      int
+	UsageLimitPerUser int
+	FirstOrderOnly    bool
+	IsDisabled        bool
+	ValidFrom         time.Time
+	ValidUntil        time.Time
+	CampaignId        uint                `json:",omitempty"`
+	CodeId            uint                `json:"-"` //the campaign code the coupon was looked up by
+	CodeRedeemed      bool                `json:"-"`
+	Reward            bool                `json:"-"` //the user holds it as a reward
+	Restrictions      []CouponRestriction `gorm:"-"`
+}
+
+// CouponOffer is what a coupon the user can use would save on their cart.
+type CouponOffer struct {
+	Coupon     string
+	Saving     float64
+	CartTotal  float64 //the cart total with the coupon applied
+	Reward     bool
+	ValidUntil time.Time
+}
+
+type CouponRestriction struct {
+	Scope     string
+	ScopeId   uint
+	ScopeName string
+}
+
+// CouponCampaign is a campaign with how its codes were redeemed, orders that
+// were cancelled don't count towards the discount given or the order value.
+type CouponCampaign struct {
+	Id             uint
+	Name           string
+	CouponId       uint
+	Prefix         string
+	CodeLength     int
+	TotalCodes     int
+	Redeemed       int
+	RedemptionRate float64 //percent of the codes redeemed
+	DiscountGiven  int
+	OrderValue     int
+	CreatedAt      time.Time
+	Coupon         Coupon `gorm:"-"`
+}
+
+type CouponCode struct {
+	Code       string
+	Redeemed   bool
+	UserId     uint
+	OrderId    uint
+	RedeemedAt *time.Time
 }
@@ -4,7 +4,70 @@ import "time"
 
 type Discount struct {
 	Id              uint
+	Name            string
+	Scope           string
+	ScopeId         uint
+	ScopeName       string
+	Type            string
 	DiscountPercent float64
-	BrandName       string
+	Amount          float64
+	MaxDiscount     float64
+	MinCartValue    float64
+	Priority        int
+	Stackable       bool
+	StartDate       time.Time
 	ExpiryDate      time.Time
+	Status          string           //scheduled, live or expired
+	AffectedItems   []DiscountedItem `gorm:"-"`
+}
+
+// DiscountedItem is a product item a live discount covers, DiscountedPrice is
+// what it sells for with every discount and sale on it.
+type DiscountedItem struct {
+	ProductItemId   uint
+	ProductName     string
+	Sku             string
+	Price           float64
+	Saving          float64 //taken off by this discount alone
+	DiscountedPrice float64
+}
+
+type PriceRule struct {
+	Id              uint
+	Name            string
+	Scope           string
+	ScopeId         uint
+	ScopeName       string
+	DiscountPercent float64
+	SalePrice       float64
+	FlashQuantity   int
+	SoldQuantity    int
+	StartsAt        time.Time
+	EndsAt          time.Time
+	IsActive        bool
+	Status          string //scheduled, live, sold_out, ended or inactive
+	AffectedItems   int
+}
+
+type Promotion struct {
+	Id              uint
+	Name            string
+	Type            string
+	TriggerQuantity int
+	RewardQuantity  int
+	RewardPercent   float64
+	BundlePrice     float64
+	MaxApplications int
+	StartsAt        time.Time
+	EndsAt          time.Time
+	IsActive        bool
+	Status          string          //scheduled, live, ended or inactive
+	Items           []PromotionItem `gorm:"-"`
+}
+
+type PromotionItem struct {
+	Role      string
+	Scope     string
+	ScopeId   uint
+	ScopeName string
 }
@@ -0,0 +1,47 @@
+package response
+
+import "time"
+
+type LoyaltySettings struct {
+	IsActive         bool
+	PointsPerRupee   float64
+	RupeesPerPoint   float64
+	MinRedeemPoints  int
+	MaxRedeemPercent float64
+	ExpiryDays       int
+	UpdatedAt        time.Time
+}
+
+type LoyaltyTier struct {
+	Id                   uint
+	Name                 string
+	MinSpend             int
+	EarnMultiplier       float64
+	ExtraDiscountPercent float64
+	FreeShipping         bool
+	PrioritySupport      bool
+	ReturnWindowDays     int
+}
+
+// LoyaltySummary is a user's points balance, what it is worth at checkout,
+// the tier they are in and how far their spend over the last 12 months is
+// from the next one.
+type LoyaltySummary struct {
+	Balance         int
+	Value           float64
+	LifetimePoints  int
+	ExpiringSoon    int    //points expiring in the next 30 days
+	Tier            string `json:",omitempty"`
+	Spend           float64
+	NextTier        string  `json:",omitempty"`
+	SpendToNextTier float64 `json:",omitempty"`
+}
+
+type LoyaltyEntry struct {
+	Id        uint
+	OrderId   uint `json:",omitempty"`
+	Type      string
+	Points    int
+	ExpiresAt *time.Time `json:",omitempty"`
+	CreatedAt time.Time
+}
@@ -5,30 +5,39 @@ import (
 )
 
 type OrderResponse struct {
-	Id            uint
-	OrderDate     time.Time
-	PaymentTypeId uint
-	PaymentType   string
-	Address       `gorm:"embedded" json:"ShippingAddress,omitempty"`
-	OrderStatusID uint
-	OrderStatus   string
-	PaymentStatus string
-	CouponCode    string `json:"coupon,omitempty"`
-	SubTotal      int    `json:"SubTotal,omitempty"`
-	CouponAmount  int    `json:"couponAmount,omitempty"`
-	DiscountPrice int    `json:"discount_price,omitempty"`
-	OrderTotal    int
+	Id             uint
+	OrderDate      time.Time
+	PaymentTypeId  uint
+	PaymentType    string
+	Address        `gorm:"embedded" json:"ShippingAddress,omitempty"`
+	OrderStatusID  uint
+	OrderStatus    string
+	PaymentStatus  string
+	CouponCode     string  `json:"coupon,omitempty"`
+	SubTotal       int     `json:"SubTotal,omitempty"`
+	CouponAmount   int     `json:"couponAmount,omitempty"`
+	DiscountPrice  int     `json:"discount_price,omitempty"`
+	CartDiscount   int     `json:"cart_discount,omitempty"`
+	PointsRedeemed int     `json:"points_redeemed,omitempty"`
+	PointsDiscount int     `json:"points_discount,omitempty"`
+	Shipping       int     `json:"shipping,omitempty"`
+	Tax            float64 `json:"tax,omitempty"`
+	OrderTotal     int
+	WalletAmount   int `json:"wallet_amount,omitempty"` //paid from the wallet, the rest is paid through PaymentType
 }
 type OrderProduct struct {
-	ProductItemId uint
-	Price         int     `json:"price,omitempty"`
-	DiscountPrice float64 `json:"DiscountPrice,omitempty"`
-	ProductName   string
-	Quantity      int
+	ProductItemId     uint
+	Price             int     `json:"price,omitempty"`
+	DiscountPrice     float64 `json:"DiscountPrice,omitempty"`
+	Promotion         string  `json:"promotion,omitempty"`
+	PromotionDiscount float64 `json:"promotion_discount,omitempty"` //for the whole line, on top of the unit discount
+	ProductName       string
+	Quantity          int
 }
 type ResponseOrder struct {
 	OrderResponse OrderResponse
 	OrderProducts []OrderProduct
+	Pricing       *PriceBreakdown `json:",omitempty"`
 }
 type ReturnOrder struct {
 	OrderDate     time.Time
@@ -48,3 +57,9 @@ type OrderStatus struct {
 	Id     uint
 	Status string
 }
+type PaymentRefund struct {
+	Id         uint
+	OrdersId   int
+	PaymentRef string
+	Amount     int
+}
@@ -0,0 +1,37 @@
+package response
+
+import "time"
+
+type PricePoint struct {
+	Price           float64
+	DiscountPercent float64
+	EffectivePrice  float64
+	RecordedAt      time.Time
+}
+type PriceHistory struct {
+	ProductItemId int
+	CurrentPrice  float64
+	LowestPrice   float64
+	HighestPrice  float64
+	History       []PricePoint
+}
+type PriceAlert struct {
+	Id                int
+	ProductItemId     int
+	ProductName       string
+	Sku               string
+	TargetPrice       float64
+	CurrentPrice      float64
+	LastNotifiedPrice *float64   `json:",omitempty"`
+	NotifiedAt        *time.Time `json:",omitempty"`
+}
+
+// PriceDrop is an alert whose target has been reached and the user still has to be told about.
+type PriceDrop struct {
+	AlertId      int
+	Email        string
+	ProductName  string
+	Sku          string
+	TargetPrice  float64
+	CurrentPrice float64
+}
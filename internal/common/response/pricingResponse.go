@@ -0,0 +1,41 @@
+package response
+
+// PricedLine is one cart or order line as priced by the pricing engine,
+// prices are per unit and the discount and total cover the whole line. A
+// promotion only covers some units, so its discount is kept for the line and
+// is part of Discount.
+type PricedLine struct {
+	ProductItemId     uint
+	ProductName       string
+	Quantity          int
+	ListPrice         float64
+	UnitPrice         float64
+	Discount          float64
+	Promotion         string  `json:",omitempty"`
+	PromotionDiscount float64 `json:",omitempty"`
+	Total             float64
+	SaleRuleId        uint `json:"-"`
+}
+
+// PricingStep is one entry of the explanation trail, Amount is negative for
+// anything taken off and positive for anything added.
+type PricingStep struct {
+	ProductItemId uint `json:",omitempty"`
+	Description   string
+	Amount        float64
+}
+
+type PriceBreakdown struct {
+	Lines          []PricedLine
+	SubTotal       float64
+	ItemDiscount   float64
+	CartDiscount   float64
+	CouponCode     string `json:",omitempty"`
+	CouponDiscount float64
+	PointsRedeemed int     `json:",omitempty"`
+	PointsDiscount float64 `json:",omitempty"` //paid with loyalty points
+	Shipping       float64
+	Tax            float64 //GST included in the total
+	Total          float64
+	Explanation    []PricingStep
+}
@@ -1,23 +1,51 @@
 package response
 
+import "time"
+
 type Category struct {
-	Id           int
-	CategoryName string
+	Id              int
+	CategoryName    string
+	Slug            string
+	MetaTitle       string
+	MetaDescription string
+	CanonicalUrl    string `json:",omitempty"`
+	ParentId        int    `json:",omitempty"`
+	SortOrder       int
+	Breadcrumbs     []Breadcrumb `gorm:"-" json:",omitempty"`
+	Children        []Category   `gorm:"-" json:",omitempty"`
+	Products        []Product    `gorm:"-" json:",omitempty"`
+}
+
+type Breadcrumb struct {
+	Id   int
+	Name string
 }
 
 type Product struct {
-	Id           int `json:",omitempty"`
-	Name         string
-	Description  string
-	Brand        string
-	CategoryName string
+	Id              int `json:",omitempty"`
+	Name            string
+	Description     string
+	BrandId         int
+	Brand           string
+	CategoryId      int
+	CategoryName    string
+	Slug            string
+	MetaTitle       string
+	MetaDescription string
+	CanonicalUrl    string       `json:",omitempty"`
+	Breadcrumbs     []Breadcrumb `gorm:"-" json:",omitempty"`
 }
 type Brand struct {
-	Id            int
-	Name          string
-	Description   string
-	Category_id   string
-	Category_name string
+	Id              int
+	Name            string
+	Description     string
+	Category_id     string
+	Category_name   string
+	Slug            string
+	MetaTitle       string
+	MetaDescription string
+	CanonicalUrl    string     `json:",omitempty"`
+	Categories      []Category `gorm:"-" json:",omitempty"`
 }
 
 type ProductItem struct {
@@ -25,8 +53,14 @@ type ProductItem struct {
 	ProductName       string
 	Description       string
 	Brand             string
+	CategoryId        int
 	CategoryName      string
+	Breadcrumbs       []Breadcrumb `gorm:"-" json:",omitempty"`
 	Sku               string
+	Slug              string
+	MetaTitle         string
+	MetaDescription   string
+	CanonicalUrl      string `json:",omitempty"`
 	QtyInStock        int
 	Color             string
 	Ram               int
@@ -35,9 +69,13 @@ type ProductItem struct {
 	Storage           int
 	Graphic_Processor string
 	Price             float64
-	DiscountPrice     float64 `json:"discount_price,omitempty"`
-	DiscountedPrice   float64 `json:"discounted_price,omitempty"`
-	Image             string  `json:"image,omitempty"`
+	DiscountPrice     float64    `json:"discount_price,omitempty"`
+	DiscountedPrice   float64    `json:"discounted_price,omitempty"`
+	SalePrice         *float64   `json:"sale_price,omitempty"`
+	SaleEndsAt        *time.Time `json:"sale_ends_at,omitempty"`
+	SaleSecondsLeft   *int64     `json:"sale_seconds_left,omitempty"`
+	FlashQuantityLeft *int       `json:"flash_quantity_left,omitempty"`
+	Image             string     `json:"image,omitempty"`
 }
 type ImageResponse struct {
 	ID    int    `json:"id"`
@@ -48,6 +86,7 @@ type Image struct {
 	Image string `json:"image"`
 }
 type DisplayProductItem struct {
-	ProductSpecs ProductItem
-	Images       []Image
+	ProductSpecs    ProductItem
+	Images          []Image
+	Recommendations *Recommendations `json:",omitempty"`
 }
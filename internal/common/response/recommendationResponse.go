@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type Recommendations struct {
+	FrequentlyBoughtTogether []ProductItem `json:",omitempty"`
+	SimilarProducts          []ProductItem `json:",omitempty"`
+}
+type RecommendationOverride struct {
+	Id                  int
+	ProductItemId       int
+	RecommendedItemId   int
+	RecommendedItemName string
+	Kind                string
+	Action              string
+	Position            int
+	CreatedAt           time.Time
+}
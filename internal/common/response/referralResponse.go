@@ -0,0 +1,38 @@
+package response
+
+import "time"
+
+type ReferralSettings struct {
+	IsActive              bool
+	RefereeReward         int
+	ReferrerReward        int
+	MaxRewardsPerReferrer int
+	UpdatedAt             time.Time
+}
+
+// ReferralDashboard is what a user's referral code has brought in so far.
+type ReferralDashboard struct {
+	ReferralCode  string
+	ReferralLink  string
+	Invited       int
+	Pending       int
+	Rewarded      int
+	Earned        int  //credited to the wallet
+	PendingAmount int  //credited once the pending referrals order
+	RewardsLeft   *int `json:",omitempty"` //before the referrer cap, nil without one
+	Referrals     []ReferralEntry
+}
+
+// ReferralCode is a user's referral code and the signup link that fills it in.
+type ReferralCode struct {
+	Code string
+	Link string
+}
+
+type ReferralEntry struct {
+	Name       string
+	Status     string
+	Reward     int
+	JoinedAt   time.Time
+	RewardedAt *time.Time `json:",omitempty"`
+}
@@ -18,6 +18,8 @@ type UserDetails struct {
 	BlockedAt         string `json:",omitempty"`
 	BlockedBy         uint   `json:",omitempty"`
 	ReasonForBlocking string `json:",omitempty"`
+	Tier              string `json:",omitempty"`
+	PrioritySupport   bool
 }
 type UserProfile struct {
 	Name       string `json:"name"`
@@ -25,6 +27,7 @@ type UserProfile struct {
 	Mobile     string `json:"mobile"`
 	ReferralId string `json:"ReferralId,omitempty"`
 	Address    `gorm:"embedded" json:"address,omitempty"`
+	Tier       *LoyaltyTier `gorm:"-" json:"tier,omitempty"`
 }
 type Address struct {
 	House_number string `json:"house_number,omitempty" `
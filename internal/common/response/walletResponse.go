@@ -5,10 +5,50 @@ import "time"
 type Wallet struct {
 	Amount int
 }
-type WalletHistories struct {
-	Id                uint `gorm:"primaryKey;unique;not null"`
-	UserId            uint
-	RecentTransaction string
-	Balance           int
-	Time              time.Time
+type WalletTransaction struct {
+	Id          uint
+	Type        string
+	Amount      int
+	Balance     int
+	OrderId     *uint
+	Description string
+	CreatedAt   time.Time
+}
+
+// WalletMismatch is a wallet whose stored amount differs from its ledger balance.
+type WalletMismatch struct {
+	UserId        uint
+	WalletAmount  int
+	LedgerBalance int
+}
+
+type WalletReconciliation struct {
+	Mismatches             []WalletMismatch
+	UnbalancedTransactions []uint
+}
+
+type WalletTopUp struct {
+	Id              uint
+	UserId          uint
+	Amount          int
+	RazorpayOrderId string
+	Status          string
+	CreatedAt       time.Time
+	PaidAt          *time.Time
+}
+
+type WalletAdjustment struct {
+	Id        uint
+	UserId    uint
+	UserName  string
+	Amount    int
+	Reason    string
+	Reference string
+	Status    string
+	CreatedBy uint
+	AdminName string
+	DecidedBy *uint  `json:",omitempty"`
+	Note      string `json:",omitempty"`
+	CreatedAt time.Time
+	DecidedAt *time.Time `json:",omitempty"`
 }
@@ -1,12 +1,14 @@
 package domain
 
 type Carts struct {
-	Id       uint `gorm:"primaryKey;unique;not null"`
-	User_id  uint
-	Users    Users `gorm:"foreignKey:User_id"`
-	CouponId uint
-	SubTotal int
-	Total    int
+	Id           uint `gorm:"primaryKey;unique;not null"`
+	User_id      uint
+	Users        Users `gorm:"foreignKey:User_id"`
+	CouponId     uint
+	CouponCodeId uint   //the campaign code when the coupon came from one
+	CouponNotice string //why an applied coupon was taken off, until the cart is next shown
+	SubTotal     int
+	Total        int
 }
 
 type CartItem struct {
@@ -16,4 +18,5 @@ type CartItem struct {
 	ProductItem_id uint
 	ProductItem    ProductItem `gorm:"foreignKey:ProductItem_id"`
 	Quantity       int
+	FreeQuantity   int `gorm:"default:0"` //units added as a free promotion reward, part of Quantity
 }
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Collection is a merchandised list of product items. Manual collections
+// list the items in collection_items, rule collections pick every item that
+// matches the filled in rule fields.
+type Collection struct {
+	Id              uint   `gorm:"primaryKey;unique;not null"`
+	Title           string `gorm:"not null"`
+	Slug            string `gorm:"uniqueIndex"`
+	Description     string
+	Type            string `gorm:"not null;default:manual"`
+	Min_price       float64
+	Max_price       float64
+	Brand_id        *uint
+	Brand           Brand `gorm:"foreignKey:Brand_id"`
+	Attribute       string
+	Attribute_value string
+	Tag             string
+	Sort_by         string
+	Item_limit      int
+	Show_on_home    bool `gorm:"default:false"`
+	Position        int  `gorm:"default:0"`
+	Is_active       bool `gorm:"default:true"`
+	Starts_at       *time.Time
+	Ends_at         *time.Time
+	Created_at      time.Time
+	Updated_at      time.Time
+}
+type CollectionItems struct {
+	Collection_id   uint        `gorm:"primaryKey"`
+	Collection      Collection  `gorm:"foreignKey:Collection_id"`
+	Product_item_id uint        `gorm:"primaryKey"`
+	ProductItem     ProductItem `gorm:"foreignKey:Product_item_id"`
+	Position        int
+}
+type ProductItemTags struct {
+	Product_item_id uint        `gorm:"primaryKey"`
+	ProductItem     ProductItem `gorm:"foreignKey:Product_item_id"`
+	Tag             string      `gorm:"primaryKey"`
+}
+type Banners struct {
+	Id         uint   `gorm:"primaryKey;unique;not null"`
+	Title      string `gorm:"not null"`
+	Subtitle   string
+	Image      string
+	Link_url   string
+	Placement  string `gorm:"not null;default:home"`
+	Position   int    `gorm:"default:0"`
+	Is_active  bool   `gorm:"default:true"`
+	Starts_at  time.Time
+	Ends_at    time.Time
+	Created_at time.Time
+	Updated_at time.Time
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type CompareItems struct {
+	User_id         uint        `gorm:"primaryKey"`
+	Users           Users       `gorm:"foreignKey:User_id"`
+	Product_item_id uint        `gorm:"primaryKey"`
+	ProductItem     ProductItem `gorm:"foreignKey:Product_item_id"`
+	Added_at        time.Time
+}
@@ -2,14 +2,59 @@ package domain
 
 import "time"
 
+// Coupon takes a fixed Amount or a percentage, capped at MaxDiscount, off an
+// order. Quantity is how many uses are left across all users, and each user
+// may use it UsageLimitPerUser times while it is valid.
 type Coupon struct {
+	Id                uint   `gorm:"primaryKey;unique;not null"`
+	Name              string `gorm:"unique"`
+	Type              string `gorm:"default:fixed"` //fixed or percentage
+	Amount            int    `gorm:"CHECK(amount>=0)"`
+	DiscountPercent   float64
+	MaxDiscount       int //caps a percentage coupon, 0 means no cap
+	MinCartValue      int
+	Quantity          int `gorm:"CHECK(quantity>=0)"`
+	UsageLimitPerUser int `gorm:"default:1"`
+	FirstOrderOnly    bool
+	IsDisabled        bool `gorm:"default:false"`
+	ValidFrom         time.Time
+	ValidUntil        time.Time
+	CampaignId        uint //set for the coupon behind a campaign, which is only used through its codes
+	CreatedAt         time.Time
+}
+
+// CouponCampaigns hand out single use codes that all carry the rules of the
+// campaign's coupon.
+type CouponCampaigns struct {
 	Id         uint   `gorm:"primaryKey;unique;not null"`
-	Name       string `gorm:"unique"`
-	Amount     int    `gorm:"CHECK(amount>=0)"`
-	Quantity   int    `gorm:"CHECK(quantity>=0)"`
-	IsDisabled bool   `gorm:"default:false"`
+	Name       string `gorm:"unique;not null"`
+	CouponId   uint   `gorm:"not null"`
+	Coupon     Coupon `gorm:"foreignKey:CouponId"`
+	Prefix     string
+	CodeLength int
 	CreatedAt  time.Time
 }
+
+// CouponCodes are the codes of a campaign. UserId stays 0 until the code is redeemed.
+type CouponCodes struct {
+	Id         uint            `gorm:"primaryKey;unique;not null"`
+	CampaignId uint            `gorm:"index;not null"`
+	Campaign   CouponCampaigns `gorm:"foreignKey:CampaignId"`
+	Code       string          `gorm:"unique;not null"`
+	UserId     uint            `gorm:"default:0"`
+	OrderId    uint            `gorm:"default:0"`
+	RedeemedAt *time.Time
+}
+
+// CouponRestrictions limit a coupon to items of some brands, categories or
+// single items. A coupon without restrictions applies to the whole order.
+type CouponRestrictions struct {
+	Id       uint   `gorm:"primaryKey;unique;not null"`
+	CouponId uint   `gorm:"index;not null"`
+	Coupon   Coupon `gorm:"foreignKey:CouponId"`
+	Scope    string `gorm:"not null"` //brand, category or item
+	ScopeId  uint   `gorm:"not null"`
+}
 type UserCoupons struct {
 	Id       uint `gorm:"primaryKey;unique;not null"`
 	UserId   uint
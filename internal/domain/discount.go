@@ -2,22 +2,107 @@ package domain
 
 import "time"
 
+// Discount takes a percentage or a fixed amount off every item of a brand,
+// category or product, a single item, or the whole cart. Discounts that are
+// not Stackable compete on Priority and only the best of them applies, while
+// stackable ones add up; a customer gets whichever of the two saves more.
 type Discount struct {
 	Id              uint
+	Name            string
+	Scope           string `gorm:"default:brand"` //brand, category, product, item or cart
+	ScopeId         uint   //unused for cart discounts
+	Type            string `gorm:"default:percentage"` //percentage or fixed
 	DiscountPercent float64
-	BrandId         uint
-	Brand           Brand `gorm:"foreignKey:BrandId"`
+	Amount          float64 //fixed discounts only
+	MaxDiscount     float64 //caps a percentage discount, 0 means no cap
+	MinCartValue    float64 //cart discounts only
+	Priority        int     `gorm:"default:0"`
+	Stackable       bool    `gorm:"default:false"`
+	StartDate       time.Time
 	ExpiryDate      time.Time
 }
+
+// PriceRules schedule a sale for a product item, product, brand or category.
+// A rule takes either a percentage off or, for a single item, a fixed sale
+// price. Flash sales stop once Flash_quantity units were sold at the sale
+// price, 0 means unlimited.
+type PriceRules struct {
+	Id               uint   `gorm:"primaryKey;unique;not null"`
+	Name             string `gorm:"not null"`
+	Scope            string `gorm:"not null"`
+	Scope_id         uint   `gorm:"not null"`
+	Discount_percent float64
+	Sale_price       float64
+	Flash_quantity   int       `gorm:"default:0"`
+	Sold_quantity    int       `gorm:"default:0"`
+	Starts_at        time.Time `gorm:"index;not null"`
+	Ends_at          time.Time `gorm:"index;not null"`
+	Is_active        bool      `gorm:"default:true"`
+	Created_at       time.Time
+	Updated_at       time.Time
+}
+
+// Promotions reward buying items together. A buy_get promotion takes
+// RewardPercent off RewardQuantity units of every reward item for each
+// TriggerQuantity units of trigger items in the cart, 100 percent makes them
+// free. A bundle sells one of each of its items together for BundlePrice.
+// MaxApplications limits how often a promotion applies to one order, 0 means
+// no limit.
+type Promotions struct {
+	Id              uint   `gorm:"primaryKey;unique;not null"`
+	Name            string `gorm:"not null"`
+	Type            string `gorm:"not null"` //buy_get or bundle
+	TriggerQuantity int    `gorm:"default:1"`
+	RewardQuantity  int    `gorm:"default:1"`
+	RewardPercent   float64
+	BundlePrice     float64
+	MaxApplications int       `gorm:"default:0"`
+	StartsAt        time.Time `gorm:"index;not null"`
+	EndsAt          time.Time `gorm:"index;not null"`
+	IsActive        bool      `gorm:"default:true"`
+	CreatedAt       time.Time
+}
+
+// PromotionItems are what sets a promotion off and what it rewards. Triggers
+// cover an item, product, brand or category, rewards and bundle items are
+// single items.
+type PromotionItems struct {
+	Id          uint       `gorm:"primaryKey;unique;not null"`
+	PromotionId uint       `gorm:"index;not null"`
+	Promotion   Promotions `gorm:"foreignKey:PromotionId"`
+	Role        string     `gorm:"not null"` //trigger, reward or bundle
+	Scope       string     `gorm:"not null"`
+	ScopeId     uint       `gorm:"not null"`
+}
 type Referrals struct {
 	Id         uint
-	ReferralId string
+	ReferralId string `gorm:"uniqueIndex"` //stored in upper case, codes match whatever the case
 	UserId     uint
 	Users      Users `gorm:"foreignKey:UserId"`
 }
+
+// UserReferrals are the users who signed up with a referral code. The rewards
+// are what the program paid when the code was used, they are credited once
+// the referee has an order that can no longer be returned.
 type UserReferrals struct {
-	Id         uint
-	UserId     uint  `gorm:"unique;not null"`
-	Users      Users `gorm:"foreignKey:UserId"`
-	ReferredBy uint
+	Id             uint
+	UserId         uint   `gorm:"unique;not null"`
+	Users          Users  `gorm:"foreignKey:UserId"`
+	ReferredBy     uint   `gorm:"index"`
+	Status         string //pending or rewarded
+	RefereeReward  int    `gorm:"default:0"`
+	ReferrerReward int    `gorm:"default:0"` //0 once the referrer reached the cap
+	OrderId        uint   `gorm:"default:0"` //the order that released the rewards
+	CreatedAt      time.Time
+	RewardedAt     *time.Time
+}
+
+// ReferralSettings is the one row configuring the referral program.
+type ReferralSettings struct {
+	Id                    uint `gorm:"primaryKey;unique;not null"`
+	IsActive              bool `gorm:"default:true"`
+	RefereeReward         int  `gorm:"default:20"`
+	ReferrerReward        int  `gorm:"default:50"`
+	MaxRewardsPerReferrer int  `gorm:"default:10"` //0 rewards every referral
+	UpdatedAt             time.Time
 }
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// LoyaltySettings is the one row configuring the loyalty points program.
+type LoyaltySettings struct {
+	Id               uint    `gorm:"primaryKey;unique;not null"`
+	IsActive         bool    `gorm:"default:true"`
+	PointsPerRupee   float64 `gorm:"default:0.01"` //earned for every rupee paid for the goods of a delivered order
+	RupeesPerPoint   float64 `gorm:"default:1"`    //what a point is worth at checkout
+	MinRedeemPoints  int     `gorm:"default:100"`
+	MaxRedeemPercent float64 `gorm:"default:20"`  //share of an order points may pay for
+	ExpiryDays       int     `gorm:"default:365"` //0 keeps points forever
+	UpdatedAt        time.Time
+}
+
+// LoyaltyTiers are reached by spending MinSpend on delivered orders over the
+// last 12 months and carry the benefits their members get.
+type LoyaltyTiers struct {
+	Id                   uint    `gorm:"primaryKey;unique;not null"`
+	Name                 string  `gorm:"unique;not null"`
+	MinSpend             int     `gorm:"default:0"`
+	EarnMultiplier       float64 `gorm:"default:1"`
+	ExtraDiscountPercent float64 `gorm:"default:0"` //off every order, after the cart discounts
+	FreeShipping         bool    `gorm:"default:false"`
+	PrioritySupport      bool    `gorm:"default:false"`
+	ReturnWindowDays     int     `gorm:"default:0"` //0 keeps the store's return window
+}
+
+// LoyaltyPoints is the points ledger. Points is positive for points earned or
+// given back and negative for points redeemed, reversed or expired. Remaining
+// is what is left of a positive entry to redeem, it is used up by the entries
+// expiring first.
+type LoyaltyPoints struct {
+	Id        uint   `gorm:"primaryKey;unique;not null"`
+	UserId    uint   `gorm:"index;not null"`
+	Users     Users  `gorm:"foreignKey:UserId"`
+	OrderId   uint   `gorm:"default:0"`
+	Type      string `gorm:"not null"` //earned, redeemed, refunded, reversed or expired
+	Points    int    `gorm:"not null"`
+	Remaining int    `gorm:"default:0"`
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
@@ -20,16 +20,29 @@ type Orders struct {
 	PaymentStatus   PaymentStatus `gorm:"foreignKey:PaymentStatusId" json:"-"`
 	CouponCode      uint
 	Coupon          Coupon `gorm:"foreignKey:CouponCode"`
+	SubTotal        int
+	DiscountAmount  int
+	CartDiscount    int
+	CouponDiscount  int
+	PointsRedeemed  int
+	PointsDiscount  int //paid with loyalty points
+	WalletAmount    int //part of the order total paid from the wallet
+	ShippingCharge  int
+	TaxAmount       float64 //GST included in the order total
+	DeliveredAt     *time.Time
 }
 
 type OrderItem struct {
-	Id            uint `gorm:"primaryKey;unique;not null"`
-	OrdersId      uint
-	Orders        Orders `gorm:"foreignKey:OrdersId" json:"-"`
-	ProductItemId uint
-	ProductItem   ProductItem `gorm:"foreignKey:ProductItemId" json:"-"`
-	Quantity      int
-	Price         int
+	Id                uint `gorm:"primaryKey;unique;not null"`
+	OrdersId          uint
+	Orders            Orders `gorm:"foreignKey:OrdersId" json:"-"`
+	ProductItemId     uint
+	ProductItem       ProductItem `gorm:"foreignKey:ProductItemId" json:"-"`
+	Quantity          int
+	Price             int //paid per unit after discounts and sales, before a promotion
+	ListPrice         int //regular price per unit when the order was placed
+	Promotion         string
+	PromotionDiscount int //taken off the whole line by the promotion
 }
 
 type OrderStatus struct {
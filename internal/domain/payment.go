@@ -2,9 +2,13 @@ package domain
 
 import "time"
 
+// PaymentDetails is one leg of an order's payment, an order paid partly from
+// the wallet has a wallet leg and a leg for the rest.
 type PaymentDetails struct {
 	OrdersId        int
 	OrderTotal      int
+	Amount          int //paid through this leg
+	RefundedAmount  int
 	PaymentTypeId   int
 	PaymentStatusId int
 	UpdatedAt       time.Time
@@ -16,6 +20,30 @@ type PaymentStatus struct {
 	Id     int
 	Status string
 }
+
+// Payment types the store is set up with, orders and payment legs refer to
+// them by id.
+const (
+	CODPaymentTypeId      = 1
+	RazorpayPaymentTypeId = 2
+	WalletPaymentTypeId   = 3
+)
+
+// PaymentRefunds is a refund sent back through the gateway a payment leg was
+// paid with, it stays pending until the gateway accepts it.
+type PaymentRefunds struct {
+	Id             uint `gorm:"primaryKey"`
+	OrdersId       int
+	PaymentTypeId  int
+	PaymentRef     string
+	Amount         int
+	IdempotencyKey string `gorm:"unique;not null"`
+	Status         string //pending or refunded
+	GatewayRef     string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
 type PaymentType struct {
 	Id   uint   `gorm:"primaryKey;unique;not null"`
 	Type string `gorm:"unique;not null"`
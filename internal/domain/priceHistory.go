@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// PriceHistories records the base price of a product item and the price after
+// discounts every time either of them changes.
+type PriceHistories struct {
+	Id               uint        `gorm:"primaryKey;unique;not null"`
+	Product_item_id  uint        `gorm:"index"`
+	ProductItem      ProductItem `gorm:"foreignKey:Product_item_id"`
+	Price            float64
+	Discount_percent float64
+	Effective_price  float64
+	Recorded_at      time.Time
+}
+
+// PriceAlerts notify a user when a wishlisted item drops to their target price.
+type PriceAlerts struct {
+	Id                  uint        `gorm:"primaryKey;unique;not null"`
+	User_id             uint        `gorm:"uniqueIndex:idx_price_alert"`
+	Users               Users       `gorm:"foreignKey:User_id"`
+	Product_item_id     uint        `gorm:"uniqueIndex:idx_price_alert"`
+	ProductItem         ProductItem `gorm:"foreignKey:Product_item_id"`
+	Target_price        float64     `gorm:"not null"`
+	Last_notified_price *float64
+	Notified_at         *time.Time
+	Created_at          time.Time
+}
@@ -3,30 +3,52 @@ package domain
 import "time"
 
 type Category struct {
-	Id           uint   `gorm:"primaryKey;unique;not null"`
-	CategoryName string `gorm:"unique;not null"`
-	Created_at   time.Time
-	Updated_at   time.Time
+	Id               uint   `gorm:"primaryKey;unique;not null"`
+	CategoryName     string `gorm:"unique;not null"`
+	Slug             string `gorm:"uniqueIndex"`
+	Meta_title       string
+	Meta_description string
+	Parent_id        *uint
+	Parent           *Category `gorm:"foreignKey:Parent_id"`
+	Sort_order       int       `gorm:"default:0"`
+	Created_at       time.Time
+	Updated_at       time.Time
 }
 
 type Product struct {
-	Id          uint   `gorm:"primaryKey;unique;not null"`
-	ProductName string `gorm:"unique;not null"`
-	Description string
-	Brand       string
-	Category_id uint
-	Category    Category `gorm:"foreignKey:Category_id"`
-	Created_at  time.Time
-	Updated_at  time.Time
+	Id               uint   `gorm:"primaryKey;unique;not null"`
+	ProductName      string `gorm:"unique;not null"`
+	Description      string
+	Slug             string `gorm:"uniqueIndex"`
+	Meta_title       string
+	Meta_description string
+	Brand_id         uint
+	Brand            Brand `gorm:"foreignKey:Brand_id"`
+	Category_id      uint
+	Category         Category `gorm:"foreignKey:Category_id"`
+	Created_at       time.Time
+	Updated_at       time.Time
 }
 type Brand struct {
-	Id          uint   `gorm:"primaryKey;unique;not null"`
-	Brandname   string `gorm:"unique;not null"`
-	Description string
-	Category_id uint
+	Id               uint   `gorm:"primaryKey;unique;not null"`
+	Brandname        string `gorm:"unique;not null"`
+	Description      string
+	Slug             string `gorm:"uniqueIndex"`
+	Meta_title       string
+	Meta_description string
+	Category_id      uint
+	Category         Category `gorm:"foreignKey:Category_id"`
+	Created_at       time.Time
+	Updated_at       time.Time
+}
+
+// BrandCategories lists every category a brand sells in. Brand.Category_id
+// stays as the brand's primary category.
+type BrandCategories struct {
+	Brand_id    uint     `gorm:"primaryKey"`
+	Brand       Brand    `gorm:"foreignKey:Brand_id"`
+	Category_id uint     `gorm:"primaryKey"`
 	Category    Category `gorm:"foreignKey:Category_id"`
-	Created_at  time.Time
-	Updated_at  time.Time
 }
 
 type ProductItem struct {
@@ -34,6 +56,9 @@ type ProductItem struct {
 	Product_id        uint
 	Product           Product `gorm:"foreignKey:Product_id"`
 	Sku               string  `gorm:"not null"`
+	Slug              string  `gorm:"uniqueIndex"`
+	Meta_title        string
+	Meta_description  string
 	Qty_in_stock      int
 	Color             string
 	Ram               int
@@ -43,10 +68,22 @@ type ProductItem struct {
 	Camera            int
 	Graphic_Processor string
 	Price             float64
+	Sale_price        *float64 //set by the scheduler while a price rule is live
+	Sale_rule_id      *uint
+	Sale_ends_at      *time.Time
 	Created_at        time.Time
 	Updated_at        time.Time
 }
 
+// SlugRedirects keeps slugs that were replaced so old links keep resolving.
+type SlugRedirects struct {
+	Id         uint   `gorm:"primaryKey;unique;not null"`
+	Entity     string `gorm:"uniqueIndex:idx_slug_redirect;not null"`
+	Old_slug   string `gorm:"uniqueIndex:idx_slug_redirect;not null"`
+	Entity_id  uint
+	Created_at time.Time
+}
+
 type Images struct {
 	Id            uint `gorm:"primaryKey;unique;not null"`
 	ProductItemId uint
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ProductViews keeps the latest view of each product item per user, trimmed
+// to the newest entries by the repository.
+type ProductViews struct {
+	Id              uint        `gorm:"primaryKey;unique;not null"`
+	User_id         uint        `gorm:"uniqueIndex:idx_product_view"`
+	Users           Users       `gorm:"foreignKey:User_id"`
+	Product_item_id uint        `gorm:"uniqueIndex:idx_product_view"`
+	ProductItem     ProductItem `gorm:"foreignKey:Product_item_id"`
+	Viewed_at       time.Time   `gorm:"index"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Recommendations is rebuilt by the background job from delivered orders
+// (bought_together) and from item attributes (similar).
+type Recommendations struct {
+	Product_item_id     uint   `gorm:"primaryKey"`
+	Recommended_item_id uint   `gorm:"primaryKey"`
+	Kind                string `gorm:"primaryKey"`
+	Score               float64
+	Updated_at          time.Time
+}
+
+// RecommendationOverrides let admins pin or exclude a recommendation.
+type RecommendationOverrides struct {
+	Id                  uint        `gorm:"primaryKey;unique;not null"`
+	Product_item_id     uint        `gorm:"uniqueIndex:idx_recommendation_override"`
+	ProductItem         ProductItem `gorm:"foreignKey:Product_item_id"`
+	Recommended_item_id uint        `gorm:"uniqueIndex:idx_recommendation_override"`
+	RecommendedItem     ProductItem `gorm:"foreignKey:Recommended_item_id"`
+	Kind                string      `gorm:"uniqueIndex:idx_recommendation_override"`
+	Action              string      `gorm:"not null"`
+	Position            int
+	Created_at          time.Time
+}
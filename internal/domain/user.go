@@ -3,14 +3,15 @@ package domain
 import "time"
 
 type Users struct {
-	ID          uint   `gorm:"primarykey;unique;notnull"`
-	Name        string `json:"name" binding:"required"`
-	Email       string `json:"email" binding:"required,email" gorm:"unique;not null"`
-	Mobile      string `json:"mobile" binding:"required,eq=10" gorm:"unique;not null"`
-	Password    string `json:"password" gorm:"not null"`
-	IsBlocked   bool   `gorm:"default:false"`
-	ReportCount int
-	CreatedAt   time.Time
+	ID            uint   `gorm:"primarykey;unique;notnull"`
+	Name          string `json:"name" binding:"required"`
+	Email         string `json:"email" binding:"required,email" gorm:"unique;not null"`
+	Mobile        string `json:"mobile" binding:"required,eq=10" gorm:"unique;not null"`
+	Password      string `json:"password" gorm:"not null"`
+	IsBlocked     bool   `gorm:"default:false"`
+	ReportCount   int
+	LoyaltyTierId uint `gorm:"default:0"` //recalculated by the loyalty tiers job
+	CreatedAt     time.Time
 }
 type Address struct {
 	ID           uint `gorm:"primaryKey;unique;not null"`
@@ -2,17 +2,66 @@ package domain
 
 import "time"
 
+// Wallet.Amount is kept in step with the user's wallet entries in the ledger
+// so balances can be read without summing them.
 type Wallet struct {
 	Id     uint `gorm:"primaryKey;unique;not null"`
 	UserId uint
 	Users  Users `gorm:"foreignKey:UserId"`
 	Amount int
 }
-type WalletHistories struct {
-	Id                uint `gorm:"primaryKey;unique;not null"`
-	UserId            uint
-	Users             Users `gorm:"foreignKey:UserId"`
-	RecentTransaction string
-	Time              time.Time
-	Balance           int
+
+// WalletTransactions is one movement of money in the wallet ledger. Its
+// entries always sum to zero: one on the user's wallet and one on the store
+// account the money came from or went to.
+type WalletTransactions struct {
+	Id             uint   `gorm:"primaryKey;unique;not null"`
+	Type           string //order_payment|refund|referral_bonus|admin_adjustment|top_up|opening_balance
+	IdempotencyKey string `gorm:"uniqueIndex;not null"`
+	OrderId        *uint
+	ReferralId     *uint
+	Description    string
+	CreatedAt      time.Time
+}
+type WalletEntries struct {
+	Id                 uint               `gorm:"primaryKey;unique;not null"`
+	TransactionId      uint               `gorm:"index"`
+	WalletTransactions WalletTransactions `gorm:"foreignKey:TransactionId"`
+	Account            string             `gorm:"index"` //wallet for user wallets, otherwise a store account
+	UserId             *uint              `gorm:"index"`
+	Amount             int                //signed, credits to the account are positive
+	Balance            int                //wallet balance after the entry, 0 on store accounts
+	CreatedAt          time.Time
+}
+
+// WalletTopUps tracks money added to a wallet through Razorpay, the wallet is
+// only credited once the payment's signature checks out.
+type WalletTopUps struct {
+	Id              uint `gorm:"primaryKey;unique;not null"`
+	UserId          uint
+	Users           Users `gorm:"foreignKey:UserId"`
+	Amount          int
+	RazorpayOrderId string `gorm:"uniqueIndex"`
+	PaymentRef      string
+	Status          string //created|paid
+	CreatedAt       time.Time
+	PaidAt          *time.Time
+}
+
+// WalletAdjustments is the audit trail of wallet credits and debits made by
+// admins. Adjustments above the approval limit wait for a superadmin.
+type WalletAdjustments struct {
+	Id        uint   `gorm:"primaryKey;unique;not null"`
+	UserId    uint   `gorm:"index"`
+	Users     Users  `gorm:"foreignKey:UserId"`
+	Amount    int    //signed, debits are negative
+	Reason    string `gorm:"not null"`
+	Reference string `gorm:"not null"`
+	Status    string `gorm:"index"` //pending|approved|rejected
+	CreatedBy uint   //admin id
+	Admins    Admins `gorm:"foreignKey:CreatedBy"`
+	DecidedBy *uint  //superadmin id, empty for adjustments under the limit
+	Note      string
+	CreatedAt time.Time
+	DecidedAt *time.Time
 }
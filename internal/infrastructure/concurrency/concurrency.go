@@ -6,20 +6,44 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"main.go/internal/repository/interfaces"
 	"main.go/internal/web/middleware"
 )
 
 type Concurrency struct {
-	DB *gorm.DB
-	mu sync.Mutex
+	DB                 *gorm.DB
+	productRepo        interfaces.ProductRepository
+	discountRepo       interfaces.DiscountRepository
+	wishlistRepo       interfaces.WishlistRepository
+	recommendationRepo interfaces.RecommendationRepository
+	referralRepo       interfaces.ReferralRepository
+	loyaltyRepo        interfaces.LoyaltyRepository
+	mu                 sync.Mutex
 }
 
-func NewConcurrency(DB *gorm.DB) *Concurrency {
+func NewConcurrency(DB *gorm.DB, productRepo interfaces.ProductRepository, discountRepo interfaces.DiscountRepository,
+	wishlistRepo interfaces.WishlistRepository, recommendationRepo interfaces.RecommendationRepository,
+	referralRepo interfaces.ReferralRepository, loyaltyRepo interfaces.LoyaltyRepository) *Concurrency {
 	return &Concurrency{
-		DB: DB,
+		DB:                 DB,
+		productRepo:        productRepo,
+		discountRepo:       discountRepo,
+		wishlistRepo:       wishlistRepo,
+		recommendationRepo: recommendationRepo,
+		referralRepo:       referralRepo,
+		loyaltyRepo:        loyaltyRepo,
 	}
 
 }
+
+// Start runs the background jobs.
+func (un *Concurrency) Start() {
+	un.Concurrency()
+	un.Recommendations()
+	un.PriceRules()
+	un.LoyaltyTiers()
+}
+
 func (un *Concurrency) Concurrency() {
 	ticker := time.NewTicker(5 * time.Minute)
 	go func() {
@@ -73,58 +97,83 @@ func (un *Concurrency) Concurrency() {
 				fmt.Println(err)
 			}
 			if err := un.DB.Exec(`
-			UPDATE coupons SET is_disabled=true WHERE coupons.created_at+INTERVAL '2 weeks' < NOW() 
+			DELETE FROM discounts WHERE expiry_date<NOW()
 			`).Error; err != nil {
 				fmt.Println(err)
 			}
-			if err := un.DB.Exec(`
-			DELETE FROM discounts WHERE expiry_date<NOW()
-			`).Error; err != nil {
+			un.priceDrops()
+			if err := un.loyaltyRepo.ExpirePoints(); err != nil {
 				fmt.Println(err)
 			}
-			// Check for users with 10 or more completed orders
-			var usersIds []int
-			err := un.DB.Raw(`
-			SELECT users.id
-			FROM users
-			JOIN orders ON users.id = orders.user_id
-			WHERE orders.order_status_id = 4
-			GROUP BY users.id
-			HAVING COUNT(orders.id) >= 10
-			
-			`).Scan(&usersIds).Error
-			if err != nil {
+			if err := un.referralRepo.ReleaseReferralRewards(); err != nil {
 				fmt.Println(err)
-				un.mu.Unlock()
-				continue
 			}
+			un.mu.Unlock()
 
-			// Iterate through the result set
-			for _, userId := range usersIds {
+			fmt.Println("worked")
+		}
+	}()
+}
 
-				// Check if the user has already received a coupon
-				var couponCount int64
-				err := un.DB.Raw(`SELECT COUNT(*) FROM user_reward_coupons WHERE users_id=$1 `, userId).Scan(&couponCount).Error
-				if err != nil {
-					fmt.Println(err)
-				}
-				if couponCount == 0 {
-					var email string
-					err := un.DB.Raw(`SELECT email FROM users WHERE id=?`, userId).Scan(&email).Error
-					if err != nil {
-						fmt.Println(err)
-					}
-					// Save the coupon in the database to track that the user has received it
-					un.DB.Exec(`INSERT INTO user_reward_coupons (users_id,coupon_id) VALUES ($1,9)`, userId)
-					// Generate and send the coupon code
-					couponCode := "us-reward-10"
-					middleware.SendCouponEmail(email, couponCode)
+// Recommendations rebuilds the recommendation tables once at startup and then every hour.
+func (un *Concurrency) Recommendations() {
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for ; true; <-ticker.C {
+			if err := un.recommendationRepo.RefreshRecommendations(); err != nil {
+				fmt.Println(err)
+			}
+		}
+	}()
+}
 
-				}
+// LoyaltyTiers puts users in the loyalty tier their spend reaches once at
+// startup and then every hour.
+func (un *Concurrency) LoyaltyTiers() {
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for ; true; <-ticker.C {
+			if err := un.loyaltyRepo.RecalculateTiers(); err != nil {
+				fmt.Println(err)
 			}
-			un.mu.Unlock()
+		}
+	}()
+}
 
-			fmt.Println("worked")
+// PriceRules starts and ends scheduled sales once at startup and then every
+// minute, so sales begin close to the time they were scheduled for. Each run
+// also records the current prices, which starts the price history of items
+// created before it existed.
+func (un *Concurrency) PriceRules() {
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		for ; true; <-ticker.C {
+			if err := un.discountRepo.ApplyPriceRules(); err != nil {
+				fmt.Println(err)
+			}
 		}
 	}()
 }
+
+// priceDrops records prices that changed on their own, such as expired
+// discounts, and emails users whose price alerts were reached.
+func (un *Concurrency) priceDrops() {
+	if err := un.productRepo.RecordPriceChanges(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	priceDrops, err := un.wishlistRepo.DuePriceAlerts()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, priceDrop := range priceDrops {
+		if err := middleware.SendPriceDropEmail(priceDrop.Email, priceDrop.ProductName+" "+priceDrop.Sku, priceDrop.CurrentPrice); err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if err := un.wishlistRepo.MarkPriceAlertNotified(priceDrop.AlertId, priceDrop.CurrentPrice); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
@@ -19,6 +19,18 @@ type Config struct {
 	SECRETKEY      string `mapstructure:"SECRETKEY"`
 	BUCKETNAME     string `mapstructure:"BUCKETNAME"`
 	ACCESSKEY      string `mapstructure:"ACCESSKEY"`
+	STORAGE        string `mapstructure:"STORAGE"`
+	REGION         string `mapstructure:"REGION"`
+	PUBLICURL      string `mapstructure:"PUBLIC_URL"`
+	URLEXPIRY      string `mapstructure:"URL_EXPIRY"`
+	SIGNUPURL      string `mapstructure:"SIGNUP_URL"` //where referral links send new users
+	WALLETTOPUPMIN int    `mapstructure:"WALLET_TOPUP_MIN"`
+	WALLETTOPUPMAX int    `mapstructure:"WALLET_TOPUP_MAX"`
+	//wallet adjustments above this need a superadmin's approval
+	WALLETADJUSTMENTLIMIT int `mapstructure:"WALLET_ADJUSTMENT_LIMIT"`
+	//flat shipping charge, waived for orders worth FREE_SHIPPING_ABOVE or more
+	SHIPPINGFEE       float64 `mapstructure:"SHIPPING_FEE"`
+	FREESHIPPINGABOVE float64 `mapstructure:"FREE_SHIPPING_ABOVE"`
 }
 
 var envs = []string{
@@ -35,6 +47,16 @@ var envs = []string{
 	"SECRETKEY",
 	"BUCKETNAME",
 	"ACCESSKEY",
+	"STORAGE",
+	"REGION",
+	"PUBLIC_URL",
+	"URL_EXPIRY",
+	"SIGNUP_URL",
+	"WALLET_TOPUP_MIN",
+	"WALLET_TOPUP_MAX",
+	"WALLET_ADJUSTMENT_LIMIT",
+	"SHIPPING_FEE",
+	"FREE_SHIPPING_ABOVE",
 }
 
 func LoadConfig() (Config, error) {
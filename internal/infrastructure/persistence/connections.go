@@ -1,11 +1,13 @@
 package db
 
 import (
+	"fmt"
+	"log"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 	"main.go/internal/domain"
-	"main.go/internal/infrastructure/concurrency"
 	"main.go/internal/infrastructure/config"
 )
 
@@ -29,6 +31,7 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 		&domain.Category{},
 		&domain.PaymentDetails{},
 		&domain.PaymentStatus{},
+		&domain.PaymentRefunds{},
 		domain.Brand{},
 		&domain.Orders{},
 		&domain.OrderItem{},
@@ -42,16 +45,201 @@ func ConnectDatabase(cfg config.Config) (*gorm.DB, error) {
 		&domain.CartItem{},
 		&domain.Coupon{},
 		&domain.UserCoupons{},
-		&domain.WalletHistories{},
+		&domain.CouponRestrictions{},
+		&domain.CouponCampaigns{},
+		&domain.CouponCodes{},
+		&domain.WalletTransactions{},
+		&domain.WalletEntries{},
+		&domain.WalletTopUps{},
+		&domain.WalletAdjustments{},
 		&domain.Wishlist{},
 		&domain.Discount{},
 		&domain.Referrals{},
 		domain.UserReferrals{},
 		&domain.UserRewardCoupons{},
+		&domain.BrandCategories{},
+		&domain.SlugRedirects{},
+		&domain.Collection{},
+		&domain.CollectionItems{},
+		&domain.ProductItemTags{},
+		&domain.Banners{},
+		&domain.Recommendations{},
+		&domain.RecommendationOverrides{},
+		&domain.ProductViews{},
+		&domain.CompareItems{},
+		&domain.PriceHistories{},
+		&domain.PriceRules{},
+		&domain.Promotions{},
+		&domain.PromotionItems{},
+		&domain.LoyaltySettings{},
+		&domain.LoyaltyTiers{},
+		&domain.LoyaltyPoints{},
+		&domain.ReferralSettings{},
+		&domain.PriceAlerts{},
 	)
-	unblockUser := concurrency.NewConcurrency(db)
-
-	// Start the UserStatusChecker goroutine
-	unblockUser.Concurrency()
+	if err := migrateData(db); err != nil {
+		return nil, err
+	}
 	return db, err
 }
+
+// migrateData brings rows written by older versions in line with the current
+// schema. It stops at the first failing step so no column is dropped before
+// its data has been moved.
+func migrateData(db *gorm.DB) error {
+	// brands created before brands could span categories only have their primary category
+	if err := db.Exec(`INSERT INTO brand_categories (brand_id,category_id)
+	SELECT id,category_id FROM brands WHERE category_id<>0 ON CONFLICT DO NOTHING`).Error; err != nil {
+		return err
+	}
+
+	if err := migrateProductBrands(db); err != nil {
+		return err
+	}
+
+	// discounts used to belong to a single brand and start straight away
+	if db.Migrator().HasColumn("discounts", "brand_id") {
+		if err := db.Exec(`UPDATE discounts SET scope='brand',scope_id=brand_id WHERE brand_id IS NOT NULL AND scope_id IS NULL`).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`ALTER TABLE discounts DROP COLUMN brand_id`).Error; err != nil {
+			return err
+		}
+	}
+	if err := db.Exec(`UPDATE discounts SET start_date=NOW() WHERE start_date IS NULL`).Error; err != nil {
+		return err
+	}
+
+	// coupons used to be disabled two weeks after they were created
+	if err := db.Exec(`UPDATE coupons SET valid_from=created_at,valid_until=created_at+INTERVAL '2 weeks' WHERE valid_until IS NULL`).Error; err != nil {
+		return err
+	}
+
+	// the loyalty program is configured on a single settings row
+	if err := db.Exec(`INSERT INTO loyalty_settings (id,updated_at) SELECT 1,NOW() WHERE NOT EXISTS (SELECT 1 FROM loyalty_settings)`).Error; err != nil {
+		return err
+	}
+
+	// the referral program is configured on a single settings row
+	if err := db.Exec(`INSERT INTO referral_settings (id,updated_at) SELECT 1,NOW() WHERE NOT EXISTS (SELECT 1 FROM referral_settings)`).Error; err != nil {
+		return err
+	}
+	// referral codes used to be built from the user's mobile number
+	if err := db.Exec(`UPDATE referrals SET referral_id=UPPER(SUBSTRING(MD5(RANDOM()::text||id::text) FOR 10)) WHERE referral_id LIKE 'pc%4u'`).Error; err != nil {
+		return err
+	}
+	// referrals used to be rewarded with 20 and 50 as soon as the code was used
+	if err := db.Exec(`UPDATE user_referrals SET status='rewarded',referee_reward=20,referrer_reward=50,rewarded_at=NOW() WHERE status IS NULL`).Error; err != nil {
+		return err
+	}
+
+	// orders used to be paid through a single payment_details row
+	if err := db.Exec(`UPDATE payment_details SET amount=order_total WHERE amount=0`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(`UPDATE payment_details SET refunded_amount=amount WHERE payment_status_id=4 AND refunded_amount=0`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(`UPDATE orders SET wallet_amount=order_total WHERE payment_type_id=3 AND payment_status_id IN (4,5) AND wallet_amount=0`).Error; err != nil {
+		return err
+	}
+	// wallets used to keep a text history next to the balance, the ledger
+	// starts them from an opening balance instead
+	if err := db.Exec(`INSERT INTO wallet_transactions (type,idempotency_key,description,created_at)
+	SELECT 'opening_balance','opening:'||wallets.user_id,'balance carried over from the old wallet history',NOW()
+	FROM wallets WHERE wallets.amount<>0 AND NOT EXISTS (SELECT 1 FROM wallet_entries WHERE account='wallet' AND user_id=wallets.user_id)
+	ON CONFLICT (idempotency_key) DO NOTHING`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(`INSERT INTO wallet_entries (transaction_id,account,user_id,amount,balance,created_at)
+	SELECT t.id,'wallet',wallets.user_id,wallets.amount,wallets.amount,t.created_at
+	FROM wallet_transactions t JOIN wallets ON t.idempotency_key='opening:'||wallets.user_id
+	WHERE NOT EXISTS (SELECT 1 FROM wallet_entries WHERE transaction_id=t.id)
+	UNION ALL
+	SELECT t.id,'opening_balances',NULL,-wallets.amount,0,t.created_at
+	FROM wallet_transactions t JOIN wallets ON t.idempotency_key='opening:'||wallets.user_id
+	WHERE NOT EXISTS (SELECT 1 FROM wallet_entries WHERE transaction_id=t.id)`).Error; err != nil {
+		return err
+	}
+	// loyalty tiers used to be reached with lifetime points, they are reached with spend now
+	if db.Migrator().HasColumn("loyalty_tiers", "min_points") {
+		if err := db.Exec(`UPDATE loyalty_tiers SET min_spend=CEIL(min_points/loyalty_settings.points_per_rupee)
+		FROM loyalty_settings WHERE loyalty_tiers.min_spend=0 AND loyalty_settings.points_per_rupee>0`).Error; err != nil {
+			return err
+		}
+		if err := db.Exec(`ALTER TABLE loyalty_tiers DROP COLUMN min_points`).Error; err != nil {
+			return err
+		}
+	}
+
+	return backfillSlugs(db)
+}
+
+// migrateProductBrands points products at their brand by id, they used to
+// store the brand name. The name column is only dropped once every product
+// has found its brand.
+func migrateProductBrands(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("products", "brand") {
+		return nil
+	}
+	if err := db.Exec(`UPDATE products SET brand_id=brands.id FROM brands
+	WHERE products.brand_id IS NULL AND brands.brandname=products.brand`).Error; err != nil {
+		return err
+	}
+	var unresolved []string
+	if err := db.Raw(`SELECT product_name FROM products WHERE brand_id IS NULL`).Scan(&unresolved).Error; err != nil {
+		return err
+	}
+	if len(unresolved) != 0 {
+		log.Printf("keeping products.brand, no brand found for products %v", unresolved)
+		return nil
+	}
+	return db.Exec(`ALTER TABLE products DROP COLUMN brand`).Error
+}
+
+// slugSources names every catalog row that has no slug yet, for the tables
+// that have slugs, along with the prefix that keeps an all-digit slug apart
+// from ids.
+var slugSources = map[string]string{
+	"categories": `SELECT id,category_name AS name,'category' AS prefix FROM categories WHERE slug IS NULL OR slug=''`,
+	"brands":     `SELECT id,brandname AS name,'brand' AS prefix FROM brands WHERE slug IS NULL OR slug=''`,
+	"products":   `SELECT id,product_name AS name,'product' AS prefix FROM products WHERE slug IS NULL OR slug=''`,
+	"product_items": `SELECT product_items.id,products.product_name || ' ' || product_items.sku AS name,'item' AS prefix
+	FROM product_items JOIN products ON products.id=product_items.product_id
+	WHERE product_items.slug IS NULL OR product_items.slug=''`,
+}
+
+// backfillSlugs generates slugs for catalog rows created before slugs existed.
+// Names are slugged the way the repository slugs new rows: lowercased, runs of
+// other characters turned into single hyphens, a prefix when only digits are
+// left and a numeric suffix when the slug is taken.
+func backfillSlugs(db *gorm.DB) error {
+	for table, source := range slugSources {
+		var rows []struct {
+			Id   int
+			Slug string
+		}
+		if err := db.Raw(`SELECT id,CASE WHEN slug ~ '^[0-9]+$' THEN prefix||'-'||slug ELSE slug END AS slug FROM (
+		SELECT id,prefix,COALESCE(NULLIF(TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name),'[^a-z0-9]+','-','g')),''),'untitled') AS slug
+		FROM (` + source + `) source) slugged`).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			slug := row.Slug
+			for i := 2; ; i++ {
+				var exists bool
+				if err := db.Raw(fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE slug=? AND id<>?)`, table), slug, row.Id).Scan(&exists).Error; err != nil {
+					return err
+				}
+				if !exists {
+					break
+				}
+				slug = fmt.Sprintf("%s-%d", row.Slug, i)
+			}
+			if err := db.Exec(fmt.Sprintf(`UPDATE %s SET slug=? WHERE id=?`, table), slug, row.Id).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestMigrateProductBrands(t *testing.T) {
+	hasBrandColumn := "^SELECT count\\(\\*\\) FROM INFORMATION_SCHEMA.columns (.+)$"
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "already migrated",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(hasBrandColumn).WithArgs("products", "brand").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "every product found its brand",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(hasBrandColumn).WithArgs("products", "brand").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec("^UPDATE products SET brand_id=brands.id (.+)$").WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectQuery("^SELECT product_name FROM products WHERE brand_id IS NULL$").
+					WillReturnRows(sqlmock.NewRows([]string{"product_name"}))
+				mock.ExpectExec("^ALTER TABLE products DROP COLUMN brand$").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "unresolved products keep the column",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(hasBrandColumn).WithArgs("products", "brand").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec("^UPDATE products SET brand_id=brands.id (.+)$").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectQuery("^SELECT product_name FROM products WHERE brand_id IS NULL$").
+					WillReturnRows(sqlmock.NewRows([]string{"product_name"}).AddRow("galaxy s24"))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "failed backfill keeps the column",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(hasBrandColumn).WithArgs("products", "brand").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec("^UPDATE products SET brand_id=brands.id (.+)$").WillReturnError(errors.New("deadlock detected"))
+			},
+			expectedErr: errors.New("deadlock detected"),
+		},
+		{
+			name: "failed check keeps the column",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(hasBrandColumn).WithArgs("products", "brand").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectExec("^UPDATE products SET brand_id=brands.id (.+)$").WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectQuery("^SELECT product_name FROM products WHERE brand_id IS NULL$").WillReturnError(errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			err = migrateProductBrands(gormDB)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	root    string
+	baseURL string
+}
+
+func NewLocalStorage(root, baseURL string) Storage {
+	return &localStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// path resolves a key inside the root directory and rejects keys escaping it.
+func (l *localStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + NormalizeKey(key))
+	if cleaned == "/" {
+		return "", fmt.Errorf("empty object key")
+	}
+	return filepath.Join(l.root, cleaned), nil
+}
+
+// Upload implements Storage.
+func (l *localStorage) Upload(key string, file io.Reader, size int64, contentType string) error {
+	dst, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, file)
+	return err
+}
+
+// URL implements Storage.
+func (l *localStorage) URL(key string) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	return l.baseURL + "/" + strings.TrimPrefix(NormalizeKey(key), "/"), nil
+}
+
+// Delete implements Storage.
+func (l *localStorage) Delete(key string) error {
+	dst, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(dst)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
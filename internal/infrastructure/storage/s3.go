@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type S3Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL serves objects from a public bucket or CDN instead of signed URLs.
+	PublicURL string
+	URLExpiry time.Duration
+}
+
+// s3Storage talks to any S3 compatible service (AWS, MinIO, R2) using path
+// style requests signed with AWS signature version 4.
+type s3Storage struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3Storage(opts S3Options) Storage {
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	endpoint := opts.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, _ := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	return &s3Storage{
+		opts:     opts,
+		endpoint: u,
+		client:   &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+func (s *s3Storage) objectPath(key string) string {
+	return "/" + s.opts.Bucket + "/" + strings.TrimPrefix(NormalizeKey(key), "/")
+}
+
+// Upload implements Storage.
+func (s *s3Storage) Upload(key string, file io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequest(http.MethodPut, s.endpoint.String()+encodePath(s.objectPath(key)), file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return s.do(req, http.StatusOK)
+}
+
+// URL implements Storage.
+func (s *s3Storage) URL(key string) (string, error) {
+	if s.opts.PublicURL != "" {
+		return strings.TrimSuffix(s.opts.PublicURL, "/") + encodePath("/"+strings.TrimPrefix(NormalizeKey(key), "/")), nil
+	}
+	return s.presign(http.MethodGet, key, s.opts.URLExpiry, time.Now().UTC())
+}
+
+// Delete implements Storage.
+func (s *s3Storage) Delete(key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.endpoint.String()+encodePath(s.objectPath(key)), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, http.StatusNoContent, http.StatusOK)
+}
+
+func (s *s3Storage) do(req *http.Request, okStatus ...int) error {
+	s.sign(req, time.Now().UTC())
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	for _, status := range okStatus {
+		if res.StatusCode == status {
+			return nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("storage request failed with status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func (s *s3Storage) scope(now time.Time) string {
+	return fmt.Sprintf("%s/%s/s3/aws4_request", now.Format("20060102"), s.opts.Region)
+}
+
+func (s *s3Storage) signature(stringToSign string, now time.Time) string {
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *s3Storage) stringToSign(canonicalRequest string, now time.Time) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	return strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		s.scope(now),
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+}
+
+// sign adds an Authorization header to the request.
+func (s *s3Storage) sign(req *http.Request, now time.Time) {
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	signature := s.signature(s.stringToSign(canonicalRequest, now), now)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKey, s.scope(now), signedHeaders, signature))
+	req.Header.Del("Host")
+}
+
+// presign returns a URL that grants temporary access to an object.
+func (s *s3Storage) presign(method, key string, expiry time.Duration, now time.Time) (string, error) {
+	if expiry <= 0 {
+		expiry = 15 * time.Minute
+	}
+	objectPath := s.objectPath(key)
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.opts.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		encodePath(objectPath),
+		canonicalQuery(query),
+		"host:" + s.endpoint.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	query.Set("X-Amz-Signature", s.signature(s.stringToSign(canonicalRequest, now), now))
+	return s.endpoint.String() + encodePath(objectPath) + "?" + canonicalQuery(query), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodePath escapes every path segment the way S3 expects while keeping the slashes.
+func encodePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range values[key] {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
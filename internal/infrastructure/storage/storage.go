@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"main.go/internal/infrastructure/config"
+)
+
+// LocalUploadDir is where images were written before object storage existed
+// and where the local backend keeps its objects.
+const LocalUploadDir = "../asset/uploads"
+
+// legacyPrefix is stored in image_items.image for files uploaded before the
+// storage backend was introduced.
+const legacyPrefix = LocalUploadDir + "/"
+
+// Storage is implemented by every media backend.
+type Storage interface {
+	Upload(key string, file io.Reader, size int64, contentType string) error
+	URL(key string) (string, error)
+	Delete(key string) error
+}
+
+// NewStorage picks the backend from the STORAGE setting, defaulting to local disk.
+func NewStorage(cfg config.Config) (Storage, error) {
+	switch strings.ToLower(cfg.STORAGE) {
+	case "", "local":
+		return NewLocalStorage(LocalUploadDir, "/uploads"), nil
+	case "s3", "minio":
+		if cfg.ENDPOINT == "" || cfg.BUCKETNAME == "" {
+			return nil, fmt.Errorf("ENDPOINT and BUCKETNAME are required for s3 storage")
+		}
+		expiry := 15 * time.Minute
+		if cfg.URLEXPIRY != "" {
+			minutes, err := strconv.Atoi(cfg.URLEXPIRY)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URL_EXPIRY: %v", err)
+			}
+			expiry = time.Duration(minutes) * time.Minute
+		}
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.ENDPOINT,
+			Region:    cfg.REGION,
+			Bucket:    cfg.BUCKETNAME,
+			AccessKey: cfg.ACCESSKEY,
+			SecretKey: cfg.SECRETKEY,
+			PublicURL: cfg.PUBLICURL,
+			URLExpiry: expiry,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.STORAGE)
+	}
+}
+
+// ImageKey builds the object key for an uploaded product item image.
+func ImageKey(productItemId int, fileName string) string {
+	name := strings.ReplaceAll(path.Base(fileName), " ", "_")
+	return fmt.Sprintf("products/%d/%d-%s", productItemId, time.Now().UnixNano(), name)
+}
+
+// NormalizeKey turns a value stored in image_items into an object key, so rows
+// written before the migration still resolve.
+func NormalizeKey(stored string) string {
+	return strings.TrimPrefix(stored, legacyPrefix)
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"main.go/internal/infrastructure/config"
+)
+
+func TestNewStorage(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		expected    Storage
+		expectedErr error
+	}{
+		{name: "local by default", cfg: config.Config{}, expected: &localStorage{}},
+		{name: "local by name", cfg: config.Config{STORAGE: "Local"}, expected: &localStorage{}},
+		{name: "minio", cfg: config.Config{STORAGE: "minio", ENDPOINT: "localhost:9000", BUCKETNAME: "media"}, expected: &s3Storage{}},
+		{
+			name:        "s3 without a bucket",
+			cfg:         config.Config{STORAGE: "s3", ENDPOINT: "s3.amazonaws.com"},
+			expectedErr: errors.New("ENDPOINT and BUCKETNAME are required for s3 storage"),
+		},
+		{
+			name:        "invalid url expiry",
+			cfg:         config.Config{STORAGE: "s3", ENDPOINT: "s3.amazonaws.com", BUCKETNAME: "media", URLEXPIRY: "soon"},
+			expectedErr: errors.New(`invalid URL_EXPIRY: strconv.Atoi: parsing "soon": invalid syntax`),
+		},
+		{name: "unknown backend", cfg: config.Config{STORAGE: "ftp"}, expectedErr: errors.New(`unknown storage backend "ftp"`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewStorage(tt.cfg)
+			assert.Equal(t, tt.expectedErr, err)
+			if tt.expected != nil {
+				assert.IsType(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestImageKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		expected string
+	}{
+		{name: "plain name", fileName: "front.jpg", expected: `^products/7/\d+-front\.jpg$`},
+		{name: "spaces", fileName: "front view.jpg", expected: `^products/7/\d+-front_view\.jpg$`},
+		{name: "directories are dropped", fileName: "../../etc/passwd", expected: `^products/7/\d+-passwd$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Regexp(t, regexp.MustCompile(tt.expected), ImageKey(7, tt.fileName))
+		})
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		stored   string
+		expected string
+	}{
+		{name: "object key", stored: "products/7/1-front.jpg", expected: "products/7/1-front.jpg"},
+		{name: "legacy upload path", stored: "../asset/uploads/front.jpg", expected: "front.jpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeKey(tt.stored))
+		})
+	}
+}
+
+func TestLocalStorageURL(t *testing.T) {
+	local := NewLocalStorage(LocalUploadDir, "/uploads/")
+	tests := []struct {
+		name        string
+		key         string
+		expected    string
+		expectedErr error
+	}{
+		{name: "object key", key: "products/7/1-front.jpg", expected: "/uploads/products/7/1-front.jpg"},
+		{name: "legacy upload path", key: "../asset/uploads/front.jpg", expected: "/uploads/front.jpg"},
+		{name: "empty key", key: "", expectedErr: errors.New("empty object key")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := local.URL(tt.key)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestLocalStoragePath(t *testing.T) {
+	local := &localStorage{root: "/srv/uploads"}
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{name: "object key", key: "products/7/1-front.jpg", expected: "/srv/uploads/products/7/1-front.jpg"},
+		{name: "escaping the root", key: "../../etc/passwd", expected: "/srv/uploads/etc/passwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := local.path(tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestS3StorageURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     S3Options
+		key      string
+		expected string
+	}{
+		{
+			name:     "public bucket",
+			opts:     S3Options{Endpoint: "s3.amazonaws.com", Bucket: "media", PublicURL: "https://cdn.example.com/"},
+			key:      "products/7/1-front view.jpg",
+			expected: "https://cdn.example.com/products/7/1-front%20view.jpg",
+		},
+		{
+			name:     "public bucket with a legacy key",
+			opts:     S3Options{Endpoint: "s3.amazonaws.com", Bucket: "media", PublicURL: "https://cdn.example.com"},
+			key:      "../asset/uploads/front.jpg",
+			expected: "https://cdn.example.com/front.jpg",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewS3Storage(tt.opts).URL(tt.key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestS3Presign(t *testing.T) {
+	s3 := NewS3Storage(S3Options{Endpoint: "localhost:9000", Bucket: "media", AccessKey: "minio", SecretKey: "secret"}).(*s3Storage)
+	now := time.Date(2024, 1, 26, 10, 30, 0, 0, time.UTC)
+	presigned, err := s3.presign("GET", "products/7/1-front.jpg", 0, now)
+	assert.NoError(t, err)
+
+	u, err := url.Parse(presigned)
+	assert.NoError(t, err)
+	assert.Equal(t, "https", u.Scheme)
+	assert.Equal(t, "localhost:9000", u.Host)
+	assert.Equal(t, "/media/products/7/1-front.jpg", u.Path)
+	query := u.Query()
+	assert.Equal(t, "minio/20240126/us-east-1/s3/aws4_request", query.Get("X-Amz-Credential"))
+	assert.Equal(t, "20240126T103000Z", query.Get("X-Amz-Date"))
+	assert.Equal(t, "900", query.Get("X-Amz-Expires"))
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{64}$`), query.Get("X-Amz-Signature"))
+
+	again, err := s3.presign("GET", "products/7/1-front.jpg", 0, now)
+	assert.NoError(t, err)
+	assert.Equal(t, presigned, again)
+	later, err := s3.presign("GET", "products/7/1-front.jpg", 0, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.NotEqual(t, presigned, later)
+}
+
+func TestURIEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "unreserved characters", input: "AZaz09-_.~", expected: "AZaz09-_.~"},
+		{name: "space", input: "front view", expected: "front%20view"},
+		{name: "reserved characters", input: "a+b=c/d", expected: "a%2Bb%3Dc%2Fd"},
+		{name: "utf-8", input: "é", expected: "%C3%A9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, uriEncode(tt.input))
+		})
+	}
+	assert.Equal(t, "/media/front%20view.jpg", encodePath("/media/front view.jpg"))
+}
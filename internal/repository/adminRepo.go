@@ -32,7 +32,8 @@ func (c *adminDatabase) AdminLogin(email string) (domain.Admins, error) {
 // ListAllUsers implements interfaces.AdminRepository.
 func (c *adminDatabase) ListAllUsers(queryParams helperStruct.QueryParams) ([]response.UserDetails, int, error) {
 	var users []response.UserDetails
-	getUsers := `SELECT * FROM users`
+	getUsers := `SELECT users.*,loyalty_tiers.name AS tier,COALESCE(loyalty_tiers.priority_support,false) AS priority_support
+	FROM users LEFT JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id`
 	var count int
 	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", getUsers)
 	err := c.DB.Raw(getTotalCount).Scan(&count).Error
@@ -52,7 +53,8 @@ func (c *adminDatabase) ListAllUsers(queryParams helperStruct.QueryParams) ([]re
 // DispalyUser implements interfaces.AdminRepository.
 func (c *adminDatabase) DispalyUser(id int) (response.UserDetails, error) {
 	var user response.UserDetails
-	err := c.DB.Raw(`SELECT * FROM users WHERE id=?`, id).Scan(&user).Error
+	err := c.DB.Raw(`SELECT users.*,loyalty_tiers.name AS tier,COALESCE(loyalty_tiers.priority_support,false) AS priority_support
+	FROM users LEFT JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id WHERE users.id=?`, id).Scan(&user).Error
 	if user.Email == "" {
 		return user, fmt.Errorf("user not found")
 	}
@@ -4,8 +4,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
-	"main.go/internal/common/response"
-	"main.go/internal/domain"
+	"main.go/internal/common/helperStruct"
 	"main.go/internal/repository/interfaces"
 )
 
@@ -139,7 +138,9 @@ func (c *cartDatabase) RemoveFromCart(productId int, userId int) error {
 			return err
 		}
 	} else { // If there is  more than one product reduce the qty by 1
-		updateQty := `UPDATE cart_items SET quantity=cart_items.quantity-1 WHERE carts_id=$1 AND product_item_id=$2`
+		// once only free units are left, the one taken out is free
+		updateQty := `UPDATE cart_items SET quantity=cart_items.quantity-1,free_quantity=LEAST(COALESCE(cart_items.free_quantity,0),cart_items.quantity-1)
+		WHERE carts_id=$1 AND product_item_id=$2`
 		err = tx.Exec(updateQty, cartId, productId).Error
 		if err != nil {
 			tx.Rollback()
@@ -171,81 +172,171 @@ func (c *cartDatabase) RemoveFromCart(productId int, userId int) error {
 
 }
 
-// ListCart implements interfaces.CartRepository.
-func (c *cartDatabase) ListCart(userId int) (response.ViewCart, error) {
-	tx := c.DB.Begin()
-	type cartDetails struct {
-		Id       int
-		SubTotal float64
-		Total    float64
-	}
-	var cart cartDetails
-	getCartDetails := `SELECT 
-	c.id,
-	c.sub_total,
-	c.total
-	FROM carts c WHERE c.user_id=$1`
-	err := tx.Raw(getCartDetails, userId).Scan(&cart).Error
-	if err != nil {
-		tx.Rollback()
-		return response.ViewCart{}, err
+// CartItems implements interfaces.CartRepository.
+func (c *cartDatabase) CartItems(userId int) ([]helperStruct.PricingItem, error) {
+	var items []helperStruct.PricingItem
+	getCartItems := `SELECT pi.id AS product_item_id,pr.product_name,pr.id AS product_id,COALESCE(pr.brand_id,0) AS brand_id,pr.category_id,brands.brandname AS brand,pi.sku,pi.color,pi.ram,pi.battery,pi.storage,pi.graphic_processor,
+	ci.quantity,COALESCE(ci.free_quantity,0) AS free_quantity,pi.qty_in_stock,pi.price,
+	CASE WHEN pi.sale_ends_at>NOW() THEN pi.sale_price ELSE 0 END AS sale_price,
+	CASE WHEN pi.sale_ends_at>NOW() THEN pi.sale_rule_id ELSE 0 END AS sale_rule_id,
+	COALESCE(price_rules.name,'') AS sale_name
+	FROM cart_items ci
+	JOIN carts ON carts.id=ci.carts_id
+	JOIN product_items pi ON ci.product_item_id=pi.id
+	JOIN products pr ON pi.product_id=pr.id
+	LEFT JOIN brands ON brands.id=pr.brand_id
+	LEFT JOIN price_rules ON price_rules.id=pi.sale_rule_id AND pi.sale_ends_at>NOW()
+	WHERE carts.user_id=$1
+	ORDER BY ci.id`
+	err := c.DB.Raw(getCartItems, userId).Scan(&items).Error
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+	var ids []uint
+	for _, item := range items {
+		ids = append(ids, item.ProductItemId)
+	}
+	var covering []struct {
+		ProductItemId uint
+		helperStruct.ApplicableDiscount
+	}
+	getDiscounts := `SELECT product_items.id AS product_item_id,discounts.id,discounts.name,discounts.type,discounts.discount_percent,
+	discounts.amount,discounts.max_discount,discounts.min_cart_value,discounts.priority,discounts.stackable
+	FROM product_items
+	JOIN products ON products.id=product_items.product_id
+	JOIN discounts ON ` + discountCovers + `
+	WHERE product_items.id IN (?) AND ` + discountIsLive + `
+	ORDER BY discounts.priority DESC,discounts.id`
+	if err := c.DB.Raw(getDiscounts, ids).Scan(&covering).Error; err != nil {
+		return items, err
 	}
-	var cartItems domain.CartItem
-	getCartItemDetails := `SELECT * FROM cart_items WHERE carts_id=$1`
-	err = tx.Raw(getCartItemDetails, cart.Id).Scan(&cartItems).Error
+	for i := range items {
+		for _, discount := range covering {
+			if discount.ProductItemId == items[i].ProductItemId {
+				items[i].Discounts = append(items[i].Discounts, discount.ApplicableDiscount)
+			}
+		}
+	}
+	return items, nil
+}
+
+// CartDiscounts implements interfaces.CartRepository.
+func (c *cartDatabase) CartDiscounts() ([]helperStruct.ApplicableDiscount, error) {
+	var discounts []helperStruct.ApplicableDiscount
+	err := c.DB.Raw(`SELECT id,name,type,discount_percent,amount,max_discount,min_cart_value,priority,stackable
+	FROM discounts WHERE scope='cart' AND ` + discountIsLive + `
+	ORDER BY priority DESC,id`).Scan(&discounts).Error
+	return discounts, err
+}
+
+// CartTier implements interfaces.CartRepository.
+func (c *cartDatabase) CartTier(userId int) (helperStruct.ApplicableTier, error) {
+	var tier helperStruct.ApplicableTier
+	err := c.DB.Raw(`SELECT loyalty_tiers.name,loyalty_tiers.extra_discount_percent,loyalty_tiers.free_shipping
+	FROM users JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id WHERE users.id=?`, userId).Scan(&tier).Error
+	return tier, err
+}
+
+// CartPromotions implements interfaces.CartRepository.
+func (c *cartDatabase) CartPromotions() ([]helperStruct.ApplicablePromotion, error) {
+	var promotions []helperStruct.ApplicablePromotion
+	err := c.DB.Raw(`SELECT id,name,type,trigger_quantity,reward_quantity,reward_percent,bundle_price,max_applications
+	FROM promotions WHERE is_active=true AND starts_at<=NOW() AND ends_at>NOW()
+	ORDER BY id`).Scan(&promotions).Error
+	if err != nil || len(promotions) == 0 {
+		return promotions, err
+	}
+	var ids []uint
+	for _, promotion := range promotions {
+		ids = append(ids, promotion.Id)
+	}
+	var items []struct {
+		PromotionId uint
+		helperStruct.PromotionItem
+	}
+	err = c.DB.Raw(`SELECT promotion_id,role,scope,scope_id FROM promotion_items WHERE promotion_id IN (?) ORDER BY id`, ids).Scan(&items).Error
 	if err != nil {
-		tx.Rollback()
-		return response.ViewCart{}, err
-	}
-	var productDetails []response.DisplayCart
-	getProductDetails := `SELECT 
-    p.brand,
-    pr.product_name,
-    pi.sku AS product_sku,
-    pi.color,
-    pi.ram,
-    pi.battery,
-    pi.graphic_processor,
-    pi.storage,
-    ci.quantity,
-    pi.price AS price_per_unit,
-    (pi.price * ci.quantity) AS total ,
-	((pi.price*discount_percent)/100)*(ci.quantity) AS discount_price,
-	(pi.price * ci.quantity)-((pi.price*discount_percent)/100)*(ci.quantity) AS discounted_price
-   FROM 
-    cart_items ci 
-    JOIN 
-    product_items pi ON ci.product_item_id = pi.id
-    JOIN 
-    products pr ON pi.product_id = pr.id
-    JOIN 
-    products p ON pi.product_id = p.id 
-	LEFT JOIN
-	brands ON brands.brandname=p.brand
-	LEFT JOIN 
-	discounts ON discounts.brand_id=brands.id AND expiry_date>NOW()
-    WHERE 
-    ci.carts_id = $1`
-	err = tx.Raw(getProductDetails, cart.Id).Scan(&productDetails).Error
+		return promotions, err
+	}
+	for i := range promotions {
+		for _, item := range items {
+			if item.PromotionId == promotions[i].Id {
+				promotions[i].Items = append(promotions[i].Items, item.PromotionItem)
+			}
+		}
+	}
+	return promotions, nil
+}
+
+// SetFreeItems implements interfaces.CartRepository.
+func (c *cartDatabase) SetFreeItems(userId int, freeUnits map[uint]int) error {
+	tx := c.DB.Begin()
+	var cartId int
+	err := tx.Raw(`SELECT id FROM carts WHERE user_id=?`, userId).Scan(&cartId).Error
 	if err != nil {
 		tx.Rollback()
-		return response.ViewCart{}, err
-	}
-	var totalWithDiscount float64
-	var carts response.ViewCart
-	carts.CartTotal = cart.Total
-	carts.SubTotal = cart.SubTotal
-	for _, cartItem := range productDetails {
-		if cartItem.DiscountPrice != 0 {
-			cartItem.Total = cartItem.DiscountedPrice
+		return err
+	}
+	for productItemId, units := range freeUnits {
+		var item struct {
+			Id           uint
+			Quantity     int
+			FreeQuantity int
+			QtyInStock   int
+		}
+		getItem := `SELECT COALESCE(cart_items.id,0) AS id,COALESCE(cart_items.quantity,0) AS quantity,
+		COALESCE(cart_items.free_quantity,0) AS free_quantity,product_items.qty_in_stock
+		FROM product_items LEFT JOIN cart_items ON cart_items.product_item_id=product_items.id AND cart_items.carts_id=$1
+		WHERE product_items.id=$2`
+		if err := tx.Raw(getItem, cartId, productItemId).Scan(&item).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		// free units only go in while they are in stock
+		own := item.Quantity - item.FreeQuantity
+		if units > item.QtyInStock-own {
+			units = item.QtyInStock - own
+		}
+		if units < 0 {
+			units = 0
+		}
+		switch {
+		case item.Id == 0 && units > 0:
+			err = tx.Exec(`INSERT INTO cart_items (carts_id,product_item_id,quantity,free_quantity) VALUES ($1,$2,$3,$3)`, cartId, productItemId, units).Error
+		case item.Id != 0 && own+units == 0:
+			err = tx.Exec(`DELETE FROM cart_items WHERE id=?`, item.Id).Error
+		case item.Id != 0:
+			err = tx.Exec(`UPDATE cart_items SET quantity=$1,free_quantity=$2 WHERE id=$3`, own+units, units, item.Id).Error
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
 		}
-		totalWithDiscount += cartItem.Total
 	}
-	carts.CartTotal = totalWithDiscount
-	carts.CartItems = productDetails
-	if err = tx.Commit().Error; err != nil {
-		tx.Rollback()
-		return response.ViewCart{}, err
+	return tx.Commit().Error
+}
+
+// CartCoupon implements interfaces.CartRepository.
+func (c *cartDatabase) CartCoupon(userId int) (string, string, error) {
+	var cart struct {
+		Coupon       string
+		CouponNotice string
 	}
-	return carts, nil
+	getCoupon := `SELECT COALESCE(coupon_codes.code,coupons.name,'') AS coupon,COALESCE(carts.coupon_notice,'') AS coupon_notice
+	FROM carts
+	LEFT JOIN coupons ON coupons.id=carts.coupon_id
+	LEFT JOIN coupon_codes ON coupon_codes.id=carts.coupon_code_id
+	WHERE carts.user_id=?`
+	err := c.DB.Raw(getCoupon, userId).Scan(&cart).Error
+	return cart.Coupon, cart.CouponNotice, err
+}
+
+// SetCartCoupon implements interfaces.CartRepository.
+func (c *cartDatabase) SetCartCoupon(userId int, couponId, codeId uint, notice string) error {
+	return c.DB.Exec(`UPDATE carts SET coupon_id=$1,coupon_code_id=$2,coupon_notice=$3 WHERE user_id=$4`, couponId, codeId, notice, userId).Error
+}
+
+// ClearCartNotice implements interfaces.CartRepository.
+func (c *cartDatabase) ClearCartNotice(userId int) error {
+	return c.DB.Exec(`UPDATE carts SET coupon_notice='' WHERE user_id=$1`, userId).Error
 }
@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/repository/interfaces"
+)
+
+type CollectionDatabase struct {
+	DB *gorm.DB
+}
+
+func NewCollectionRepo(DB *gorm.DB) interfaces.CollectionRepository {
+	return &CollectionDatabase{
+		DB: DB,
+	}
+}
+
+// collectionIsLive matches collections and banners that are switched on and inside their schedule.
+const collectionIsLive = `is_active AND (starts_at IS NULL OR starts_at<=NOW()) AND (ends_at IS NULL OR ends_at>NOW())`
+
+const collectionItemDetails = `
+    SELECT product_items.id,products.product_name,products.description,brands.brandname AS brand,products.category_id,categories.category_name,
+	product_items.sku,product_items.slug,product_items.meta_title,product_items.meta_description,product_items.qty_in_stock,product_items.color,product_items.ram,product_items.battery,
+	product_items.screen_size,product_items.storage,product_items.graphic_processor,product_items.price,image_items.image,
+	` + discountColumns + `,
+	` + saleColumns + `
+    FROM product_items
+    JOIN products ON product_items.product_id = products.id
+    JOIN categories ON products.category_id = categories.id
+	LEFT JOIN brands ON brands.id=products.brand_id
+	` + discountsJoin + `
+	LEFT JOIN image_items ON product_items.id=image_items.product_item_id AND image_items.is_default=true
+	` + saleJoin
+
+// collectionAttributes are the product item columns a rule collection may filter on.
+var collectionAttributes = map[string]string{
+	"color":             "product_items.color",
+	"ram":               "product_items.ram",
+	"storage":           "product_items.storage",
+	"battery":           "product_items.battery",
+	"screen_size":       "product_items.screen_size",
+	"graphic_processor": "product_items.graphic_processor",
+}
+
+func nullableId(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+// AddCollection implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) AddCollection(collection helperStruct.Collection) (response.Collection, error) {
+	var newCollection response.Collection
+	slug, err := newSlug(c.DB, "collections", collection.Slug, collection.Title)
+	if err != nil {
+		return newCollection, err
+	}
+	addCollection := `INSERT INTO collections (title,slug,description,type,min_price,max_price,brand_id,attribute,attribute_value,tag,sort_by,
+	item_limit,show_on_home,position,is_active,starts_at,ends_at,created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,NOW()) RETURNING *`
+	err = c.DB.Raw(addCollection, collection.Title, slug, collection.Description, collection.Type, collection.Min_price, collection.Max_price,
+		nullableId(collection.Brand_id), collection.Attribute, collection.Attribute_value, collection.Tag, collection.Sort_by,
+		collection.Item_limit, collection.Show_on_home, collection.Position, *collection.Is_active, collection.Starts_at, collection.Ends_at).Scan(&newCollection).Error
+	return newCollection, err
+}
+
+// UpdateCollection implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) UpdateCollection(collection helperStruct.Collection, id int) (response.Collection, error) {
+	var updatedCollection response.Collection
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM collections WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return updatedCollection, fmt.Errorf("no collection found with given id")
+	}
+	if collection.Slug != "" {
+		if collection.Slug != slugify(collection.Slug) {
+			return updatedCollection, fmt.Errorf("slug may only contain lowercase letters, digits and single hyphens")
+		}
+		// storefront collections are looked up by slug or id
+		if numericSlug(collection.Slug) {
+			return updatedCollection, fmt.Errorf("slug cannot be only digits, it would be read as an id")
+		}
+		c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM collections WHERE slug=$1 AND id<>$2)`, collection.Slug, id).Scan(&exists)
+		if exists {
+			return updatedCollection, fmt.Errorf("slug %s is already in use", collection.Slug)
+		}
+	}
+	updateCollection := `UPDATE collections SET title=$1,slug=COALESCE(NULLIF($2,''),slug),description=$3,type=$4,min_price=$5,max_price=$6,brand_id=$7,
+	attribute=$8,attribute_value=$9,tag=$10,sort_by=$11,item_limit=$12,show_on_home=$13,position=$14,is_active=COALESCE($15,is_active),
+	starts_at=$16,ends_at=$17,updated_at=NOW() WHERE id=$18 RETURNING *`
+	err := c.DB.Raw(updateCollection, collection.Title, collection.Slug, collection.Description, collection.Type, collection.Min_price, collection.Max_price,
+		nullableId(collection.Brand_id), collection.Attribute, collection.Attribute_value, collection.Tag, collection.Sort_by,
+		collection.Item_limit, collection.Show_on_home, collection.Position, collection.Is_active, collection.Starts_at, collection.Ends_at, id).Scan(&updatedCollection).Error
+	return updatedCollection, err
+}
+
+// DeleteCollection implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) DeleteCollection(id int) error {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM collections WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such collection to delete")
+	}
+	tx := c.DB.Begin()
+	if err := tx.Exec(`DELETE FROM collection_items WHERE collection_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM collections WHERE id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ListAllCollections implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) ListAllCollections(queryParams helperStruct.QueryParams) ([]response.Collection, int, error) {
+	var collections []response.Collection
+	listAllCollections := `SELECT * FROM collections`
+	var count int
+	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", listAllCollections)
+	err := c.DB.Raw(getTotalCount).Scan(&count).Error
+	if err != nil {
+		return []response.Collection{}, 0, err
+	}
+	listAllCollections = fmt.Sprintf("%s ORDER BY position,created_at DESC", listAllCollections)
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listAllCollections = fmt.Sprintf("%s LIMIT %d OFFSET %d", listAllCollections, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	}
+	if queryParams.Limit == 0 || queryParams.Page == 0 {
+		listAllCollections = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listAllCollections)
+	}
+	err = c.DB.Raw(listAllCollections).Scan(&collections).Error
+	return collections, count, err
+}
+
+// DisplayCollection implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) DisplayCollection(id int) (response.Collection, error) {
+	var collection response.Collection
+	err := c.DB.Raw(`SELECT * FROM collections WHERE id=?`, id).Scan(&collection).Error
+	if err != nil {
+		return collection, err
+	}
+	if collection.Id == 0 {
+		return collection, fmt.Errorf("no collection found with given id")
+	}
+	return collection, nil
+}
+
+// SetCollectionItems implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) SetCollectionItems(id int, productItemIds []uint) error {
+	var collectionType string
+	c.DB.Raw(`SELECT type FROM collections WHERE id=?`, id).Scan(&collectionType)
+	if collectionType == "" {
+		return fmt.Errorf("no collection found with given id")
+	}
+	if collectionType != "manual" {
+		return fmt.Errorf("items can only be arranged in manual collections")
+	}
+	tx := c.DB.Begin()
+	if err := tx.Exec(`DELETE FROM collection_items WHERE collection_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for position, productItemId := range productItemIds {
+		var exists bool
+		tx.Raw(`SELECT EXISTS (SELECT 1 FROM product_items WHERE id=?)`, productItemId).Scan(&exists)
+		if !exists {
+			tx.Rollback()
+			return fmt.Errorf("no product item found with id %d", productItemId)
+		}
+		err := tx.Exec(`INSERT INTO collection_items (collection_id,product_item_id,position) VALUES ($1,$2,$3)
+		ON CONFLICT (collection_id,product_item_id) DO UPDATE SET position=EXCLUDED.position`, id, productItemId, position).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// CollectionItems implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) CollectionItems(collection response.Collection, queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error) {
+	var items []response.ProductItem
+	getItems := collectionItemDetails
+	var args []interface{}
+	if collection.Type == "manual" {
+		getItems = fmt.Sprintf("%s JOIN collection_items ON collection_items.product_item_id=product_items.id WHERE collection_items.collection_id=?", getItems)
+		args = append(args, collection.Id)
+	} else {
+		var conditions []string
+		if collection.MinPrice > 0 {
+			conditions = append(conditions, effectivePrice+">=?")
+			args = append(args, collection.MinPrice)
+		}
+		if collection.MaxPrice > 0 {
+			conditions = append(conditions, effectivePrice+"<=?")
+			args = append(args, collection.MaxPrice)
+		}
+		if collection.BrandId != 0 {
+			conditions = append(conditions, "products.brand_id=?")
+			args = append(args, collection.BrandId)
+		}
+		if column, ok := collectionAttributes[collection.Attribute]; ok {
+			conditions = append(conditions, fmt.Sprintf("LOWER(CAST(%s AS TEXT))=LOWER(?)", column))
+			args = append(args, collection.AttributeValue)
+		}
+		if collection.Tag != "" {
+			conditions = append(conditions, "EXISTS (SELECT 1 FROM product_item_tags WHERE product_item_tags.product_item_id=product_items.id AND product_item_tags.tag=?)")
+			args = append(args, collection.Tag)
+		}
+		if len(conditions) == 0 {
+			return []response.ProductItem{}, 0, nil
+		}
+		getItems = fmt.Sprintf("%s WHERE %s", getItems, strings.Join(conditions, " AND "))
+	}
+
+	var count int
+	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", getItems)
+	err := c.DB.Raw(getTotalCount, args...).Scan(&count).Error
+	if err != nil {
+		return []response.ProductItem{}, 0, err
+	}
+	if collection.ItemLimit > 0 && count > collection.ItemLimit {
+		count = collection.ItemLimit
+	}
+
+	switch {
+	case collection.Type == "manual":
+		getItems = fmt.Sprintf("%s ORDER BY collection_items.position", getItems)
+	case collection.SortBy == "price_asc":
+		getItems = fmt.Sprintf("%s ORDER BY %s ASC", getItems, effectivePrice)
+	case collection.SortBy == "price_desc":
+		getItems = fmt.Sprintf("%s ORDER BY %s DESC", getItems, effectivePrice)
+	default:
+		getItems = fmt.Sprintf("%s ORDER BY product_items.created_at DESC", getItems)
+	}
+	if queryParams.Limit == 0 || queryParams.Page == 0 {
+		queryParams.Limit, queryParams.Page = 10, 1
+	}
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	limit := queryParams.Limit
+	// the item limit caps the whole collection, not a single page
+	if collection.ItemLimit > 0 && offset+limit > collection.ItemLimit {
+		limit = collection.ItemLimit - offset
+	}
+	if limit <= 0 {
+		return []response.ProductItem{}, count, nil
+	}
+	getItems = fmt.Sprintf("%s LIMIT %d OFFSET %d", getItems, limit, offset)
+	err = c.DB.Raw(getItems, args...).Scan(&items).Error
+	return items, count, err
+}
+
+// HomeCollections implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) HomeCollections() ([]response.Collection, error) {
+	var collections []response.Collection
+	err := c.DB.Raw(`SELECT * FROM collections WHERE show_on_home AND ` + collectionIsLive + ` ORDER BY position,id`).Scan(&collections).Error
+	return collections, err
+}
+
+// ActiveCollection implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) ActiveCollection(slug string) (response.Collection, error) {
+	var collection response.Collection
+	err := c.DB.Raw(`SELECT * FROM collections WHERE (slug=$1 OR CAST(id AS TEXT)=$1) AND `+collectionIsLive, slug).Scan(&collection).Error
+	if err != nil {
+		return collection, err
+	}
+	if collection.Id == 0 {
+		return collection, fmt.Errorf("no collection found")
+	}
+	return collection, nil
+}
+
+// SetProductTags implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) SetProductTags(productItemId int, tags []string) ([]string, error) {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM product_items WHERE id=?)`, productItemId).Scan(&exists)
+	if !exists {
+		return nil, fmt.Errorf("no product item found with given id")
+	}
+	tx := c.DB.Begin()
+	if err := tx.Exec(`DELETE FROM product_item_tags WHERE product_item_id=?`, productItemId).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for _, tag := range tags {
+		err := tx.Exec(`INSERT INTO product_item_tags (product_item_id,tag) VALUES ($1,$2) ON CONFLICT DO NOTHING`, productItemId, tag).Error
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	var savedTags []string
+	err := c.DB.Raw(`SELECT tag FROM product_item_tags WHERE product_item_id=? ORDER BY tag`, productItemId).Scan(&savedTags).Error
+	return savedTags, err
+}
+
+// AddBanner implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) AddBanner(banner helperStruct.Banner) (response.Banner, error) {
+	var newBanner response.Banner
+	addBanner := `INSERT INTO banners (title,subtitle,image,link_url,placement,position,is_active,starts_at,ends_at,created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NOW()) RETURNING *`
+	err := c.DB.Raw(addBanner, banner.Title, banner.Subtitle, banner.Image, banner.Link_url, banner.Placement, banner.Position,
+		*banner.Is_active, banner.Starts_at, banner.Ends_at).Scan(&newBanner).Error
+	return newBanner, err
+}
+
+// UpdateBanner implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) UpdateBanner(banner helperStruct.Banner, id int) (response.Banner, error) {
+	var updatedBanner response.Banner
+	updateBanner := `UPDATE banners SET title=$1,subtitle=$2,image=$3,link_url=$4,placement=$5,position=$6,is_active=COALESCE($7,is_active),
+	starts_at=$8,ends_at=$9,updated_at=NOW() WHERE id=$10 RETURNING *`
+	err := c.DB.Raw(updateBanner, banner.Title, banner.Subtitle, banner.Image, banner.Link_url, banner.Placement, banner.Position,
+		banner.Is_active, banner.Starts_at, banner.Ends_at, id).Scan(&updatedBanner).Error
+	if err != nil {
+		return updatedBanner, err
+	}
+	if updatedBanner.Id == 0 {
+		return updatedBanner, fmt.Errorf("no banner found with given id")
+	}
+	return updatedBanner, nil
+}
+
+// DeleteBanner implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) DeleteBanner(id int) error {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM banners WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such banner to delete")
+	}
+	return c.DB.Exec(`DELETE FROM banners WHERE id=?`, id).Error
+}
+
+// ListAllBanners implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) ListAllBanners(queryParams helperStruct.QueryParams) ([]response.Banner, int, error) {
+	var banners []response.Banner
+	listAllBanners := `SELECT * FROM banners`
+	var count int
+	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", listAllBanners)
+	err := c.DB.Raw(getTotalCount).Scan(&count).Error
+	if err != nil {
+		return []response.Banner{}, 0, err
+	}
+	listAllBanners = fmt.Sprintf("%s ORDER BY starts_at DESC", listAllBanners)
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listAllBanners = fmt.Sprintf("%s LIMIT %d OFFSET %d", listAllBanners, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	}
+	if queryParams.Limit == 0 || queryParams.Page == 0 {
+		listAllBanners = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listAllBanners)
+	}
+	err = c.DB.Raw(listAllBanners).Scan(&banners).Error
+	return banners, count, err
+}
+
+// ActiveBanners implements interfaces.CollectionRepository.
+func (c *CollectionDatabase) ActiveBanners() ([]response.Banner, error) {
+	var banners []response.Banner
+	err := c.DB.Raw(`SELECT * FROM banners WHERE ` + collectionIsLive + ` ORDER BY placement,position,id`).Scan(&banners).Error
+	return banners, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+func TestActiveCollection(t *testing.T) {
+	activeCollection := "^SELECT \\* FROM collections WHERE \\(slug=\\$1 OR CAST\\(id AS TEXT\\)=\\$1\\) AND " + regexp.QuoteMeta(collectionIsLive) + "$"
+	tests := []struct {
+		name        string
+		slug        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expected    response.Collection
+		expectedErr error
+	}{
+		{
+			name: "live collection",
+			slug: "deals",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(activeCollection).WithArgs("deals").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug", "type", "is_active"}).AddRow(1, "deals", "deals", "manual", true))
+			},
+			expected:    response.Collection{Id: 1, Title: "deals", Slug: "deals", Type: "manual", IsActive: true},
+			expectedErr: nil,
+		},
+		{
+			name: "unscheduled or missing collection",
+			slug: "republic-day",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(activeCollection).WithArgs("republic-day").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug"}))
+			},
+			expected:    response.Collection{},
+			expectedErr: errors.New("no collection found"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			collectionRepo := NewCollectionRepo(gormDB)
+			actual, err := collectionRepo.ActiveCollection(tt.slug)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, actual)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestActiveBanners(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+	assert.NoError(t, err)
+	mock.ExpectQuery("^SELECT \\* FROM banners WHERE " + regexp.QuoteMeta(collectionIsLive) + " ORDER BY placement,position,id$").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "placement", "position"}).AddRow(2, "sale", "home", 1).AddRow(1, "welcome", "home", 2))
+
+	collectionRepo := NewCollectionRepo(gormDB)
+	banners, err := collectionRepo.ActiveBanners()
+	assert.NoError(t, err)
+	assert.Equal(t, []response.Banner{{Id: 2, Title: "sale", Placement: "home", Position: 1}, {Id: 1, Title: "welcome", Placement: "home", Position: 2}}, banners)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCollectionSlug(t *testing.T) {
+	tests := []struct {
+		name        string
+		slug        string
+		expectedErr error
+	}{
+		{name: "invalid characters", slug: "Republic Day", expectedErr: errors.New("slug may only contain lowercase letters, digits and single hyphens")},
+		{name: "digits only", slug: "2024", expectedErr: errors.New("slug cannot be only digits, it would be read as an id")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			mock.ExpectQuery("^SELECT EXISTS \\(SELECT 1 FROM collections WHERE id=(.+)\\)$").WithArgs(1).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+			collectionRepo := NewCollectionRepo(gormDB)
+			_, err = collectionRepo.UpdateCollection(helperStruct.Collection{Title: "republic day", Slug: tt.slug}, 1)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"main.go/internal/common/response"
+	"main.go/internal/repository/interfaces"
+)
+
+type CompareDatabase struct {
+	DB *gorm.DB
+}
+
+func NewCompareRepo(DB *gorm.DB) interfaces.CompareRepository {
+	return &CompareDatabase{
+		DB: DB,
+	}
+}
+
+// AddToCompare implements interfaces.CompareRepository.
+func (cm *CompareDatabase) AddToCompare(userId, productItemId, maxItems int) error {
+	var categoryId int
+	cm.DB.Raw(`SELECT products.category_id FROM product_items
+	JOIN products ON products.id=product_items.product_id WHERE product_items.id=?`, productItemId).Scan(&categoryId)
+	if categoryId == 0 {
+		return fmt.Errorf("no product item found with given id")
+	}
+	var current struct {
+		Count      int
+		CategoryId int
+		Present    bool
+	}
+	err := cm.DB.Raw(`SELECT COUNT(*) AS count,MIN(products.category_id) AS category_id,
+	COALESCE(BOOL_OR(compare_items.product_item_id=$2),false) AS present
+	FROM compare_items
+	JOIN product_items ON product_items.id=compare_items.product_item_id
+	JOIN products ON products.id=product_items.product_id
+	WHERE compare_items.user_id=$1`, userId, productItemId).Scan(&current).Error
+	if err != nil {
+		return err
+	}
+	if current.Present {
+		return fmt.Errorf("this product is already in the compare list")
+	}
+	if current.Count > 0 && current.CategoryId != categoryId {
+		return fmt.Errorf("only products from the same category can be compared")
+	}
+	if current.Count >= maxItems {
+		return fmt.Errorf("you can compare at most %d products", maxItems)
+	}
+	err = cm.DB.Exec(`INSERT INTO compare_items (user_id,product_item_id,added_at) VALUES ($1,$2,NOW())`, userId, productItemId).Error
+	return err
+}
+
+// RemoveFromCompare implements interfaces.CompareRepository.
+func (cm *CompareDatabase) RemoveFromCompare(userId, productItemId int) error {
+	var exists bool
+	cm.DB.Raw(`SELECT EXISTS (SELECT 1 FROM compare_items WHERE user_id=$1 AND product_item_id=$2)`, userId, productItemId).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("this product is not in the compare list")
+	}
+	err := cm.DB.Exec(`DELETE FROM compare_items WHERE user_id=$1 AND product_item_id=$2`, userId, productItemId).Error
+	return err
+}
+
+// ClearCompare implements interfaces.CompareRepository.
+func (cm *CompareDatabase) ClearCompare(userId int) error {
+	err := cm.DB.Exec(`DELETE FROM compare_items WHERE user_id=?`, userId).Error
+	return err
+}
+
+// CompareItems implements interfaces.CompareRepository.
+func (cm *CompareDatabase) CompareItems(userId int) ([]response.ProductItem, error) {
+	var productItems []response.ProductItem
+	err := cm.DB.Raw(collectionItemDetails+`
+	JOIN compare_items ON compare_items.product_item_id=product_items.id
+	WHERE compare_items.user_id=? ORDER BY compare_items.added_at`, userId).Scan(&productItems).Error
+	return productItems, err
+}
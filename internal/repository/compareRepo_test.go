@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestAddToCompare(t *testing.T) {
+	itemCategory := "^SELECT products.category_id FROM product_items (.+)$"
+	currentList := "^SELECT COUNT\\(\\*\\) AS count,(.+) FROM compare_items (.+)$"
+	insertItem := "^INSERT INTO compare_items (.+)$"
+	currentRows := func(count, categoryId int, present bool) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"count", "category_id", "present"}).AddRow(count, categoryId, present)
+	}
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "empty list",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(2))
+				mock.ExpectQuery(currentList).WithArgs(7, 3).WillReturnRows(currentRows(0, 0, false))
+				mock.ExpectExec(insertItem).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "one below the limit",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(2))
+				mock.ExpectQuery(currentList).WithArgs(7, 3).WillReturnRows(currentRows(3, 2, false))
+				mock.ExpectExec(insertItem).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "list full",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(2))
+				mock.ExpectQuery(currentList).WithArgs(7, 3).WillReturnRows(currentRows(4, 2, false))
+			},
+			expectedErr: errors.New("you can compare at most 4 products"),
+		},
+		{
+			name: "already in the list",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(2))
+				mock.ExpectQuery(currentList).WithArgs(7, 3).WillReturnRows(currentRows(2, 2, true))
+			},
+			expectedErr: errors.New("this product is already in the compare list"),
+		},
+		{
+			name: "another category",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}).AddRow(5))
+				mock.ExpectQuery(currentList).WithArgs(7, 3).WillReturnRows(currentRows(2, 2, false))
+			},
+			expectedErr: errors.New("only products from the same category can be compared"),
+		},
+		{
+			name: "unknown product item",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(itemCategory).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"category_id"}))
+			},
+			expectedErr: errors.New("no product item found with given id"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			compareRepo := NewCompareRepo(gormDB)
+			err = compareRepo.AddToCompare(7, 3, 4)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
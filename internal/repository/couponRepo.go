@@ -20,16 +20,72 @@ func NewCouponRepo(DB *gorm.DB) interfaces.CouponRepository {
 	}
 }
 
-// AddCoupon implements interfaces.CouponRepository.
-func (c *CouponDatabase) AddCoupon(coupon helperStruct.Coupon) (response.Coupon, error) {
+// saveRestrictions replaces the restrictions of a coupon, checking that each
+// one points at something that exists.
+func saveRestrictions(tx *gorm.DB, couponId uint, restrictions []helperStruct.CouponRestriction) error {
+	if err := tx.Exec(`DELETE FROM coupon_restrictions WHERE coupon_id=?`, couponId).Error; err != nil {
+		return err
+	}
+	for _, restriction := range restrictions {
+		var exists bool
+		tx.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id=?)`, priceRuleScopes[restriction.Scope]), restriction.ScopeId).Scan(&exists)
+		if !exists {
+			return fmt.Errorf("no %s found with id %d to restrict the coupon to", restriction.Scope, restriction.ScopeId)
+		}
+		err := tx.Exec(`INSERT INTO coupon_restrictions (coupon_id,scope,scope_id) VALUES ($1,$2,$3)`, couponId, restriction.Scope, restriction.ScopeId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// couponRestrictions fills in what a coupon is restricted to.
+func (c *CouponDatabase) couponRestrictions(coupon *response.Coupon) error {
+	coupon.Restrictions = []response.CouponRestriction{}
+	getRestrictions := `SELECT coupon_restrictions.scope,coupon_restrictions.scope_id,
+	COALESCE(products.product_name || ' ' || product_items.sku,brands.brandname,categories.category_name) AS scope_name
+	FROM coupon_restrictions
+	LEFT JOIN product_items ON coupon_restrictions.scope='item' AND product_items.id=coupon_restrictions.scope_id
+	LEFT JOIN products ON products.id=product_items.product_id
+	LEFT JOIN brands ON coupon_restrictions.scope='brand' AND brands.id=coupon_restrictions.scope_id
+	LEFT JOIN categories ON coupon_restrictions.scope='category' AND categories.id=coupon_restrictions.scope_id
+	WHERE coupon_restrictions.coupon_id=?
+	ORDER BY coupon_restrictions.id`
+	return c.DB.Raw(getRestrictions, coupon.Id).Scan(&coupon.Restrictions).Error
+}
+
+// insertCoupon adds a coupon and its restrictions inside tx.
+func insertCoupon(tx *gorm.DB, coupon helperStruct.Coupon) (response.Coupon, error) {
 	var newCoupon response.Coupon
 	var exists bool
-	c.DB.Raw(`SELECT EXISTS (select  1 from coupons where name=?)`, coupon.Name).Scan(&exists)
+	tx.Raw(`SELECT EXISTS (select  1 from coupons where name=?)`, coupon.Name).Scan(&exists)
 	if exists {
 		return response.Coupon{}, fmt.Errorf("coupon is already present please add a new unique coupon")
 	}
-	addCoupon := `INSERT INTO coupons(name,quantity,amount,created_at) VALUES ($1,$2,$3,NOW()) RETURNING *`
-	err := c.DB.Raw(addCoupon, coupon.Name, coupon.Quantity, coupon.Amount).Scan(&newCoupon).Error
+	addCoupon := `INSERT INTO coupons(name,type,amount,discount_percent,max_discount,min_cart_value,quantity,usage_limit_per_user,first_order_only,
+	valid_from,valid_until,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW()) RETURNING *`
+	err := tx.Raw(addCoupon, coupon.Name, coupon.Type, coupon.Amount, coupon.DiscountPercent, coupon.MaxDiscount, coupon.MinCartValue, coupon.Quantity,
+		coupon.UsageLimitPerUser, coupon.FirstOrderOnly, coupon.ValidFrom, coupon.ValidUntil).Scan(&newCoupon).Error
+	if err != nil {
+		return response.Coupon{}, err
+	}
+	err = saveRestrictions(tx, newCoupon.Id, coupon.Restrictions)
+	return newCoupon, err
+}
+
+// AddCoupon implements interfaces.CouponRepository.
+func (c *CouponDatabase) AddCoupon(coupon helperStruct.Coupon) (response.Coupon, error) {
+	tx := c.DB.Begin()
+	newCoupon, err := insertCoupon(tx, coupon)
+	if err != nil {
+		tx.Rollback()
+		return response.Coupon{}, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return response.Coupon{}, err
+	}
+	err = c.couponRestrictions(&newCoupon)
 	return newCoupon, err
 }
 
@@ -45,8 +101,23 @@ func (c *CouponDatabase) UpdateCoupon(coupon helperStruct.UpdateCoupon) (respons
 		return response.Coupon{}, fmt.Errorf("coupon is already present please add a new unique coupon name")
 	}
 	var updatedCoupon response.Coupon
-	updateCoupon := `UPDATE coupons SET name=$1,amount=$2,quantity=$3 WHERE id=$4 RETURNING *`
-	err := c.DB.Raw(updateCoupon, coupon.Name, coupon.Amount, coupon.Quantity, coupon.Id).Scan(&updatedCoupon).Error
+	tx := c.DB.Begin()
+	updateCoupon := `UPDATE coupons SET name=$1,type=$2,amount=$3,discount_percent=$4,max_discount=$5,min_cart_value=$6,quantity=$7,
+	usage_limit_per_user=$8,first_order_only=$9,valid_from=$10,valid_until=$11 WHERE id=$12 RETURNING *`
+	err := tx.Raw(updateCoupon, coupon.Name, coupon.Type, coupon.Amount, coupon.DiscountPercent, coupon.MaxDiscount, coupon.MinCartValue, coupon.Quantity,
+		coupon.UsageLimitPerUser, coupon.FirstOrderOnly, coupon.ValidFrom, coupon.ValidUntil, coupon.Id).Scan(&updatedCoupon).Error
+	if err != nil {
+		tx.Rollback()
+		return response.Coupon{}, err
+	}
+	if err := saveRestrictions(tx, updatedCoupon.Id, coupon.Restrictions); err != nil {
+		tx.Rollback()
+		return response.Coupon{}, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return response.Coupon{}, err
+	}
+	err = c.couponRestrictions(&updatedCoupon)
 	return updatedCoupon, err
 }
 
@@ -91,7 +162,15 @@ func (c *CouponDatabase) ListAllCoupons(queryParams helperStruct.QueryParams) ([
 
 	}
 	err = c.DB.Raw(getAllCoupons).Scan(&coupons).Error
-	return coupons, count, err
+	if err != nil {
+		return coupons, count, err
+	}
+	for i := range coupons {
+		if err := c.couponRestrictions(&coupons[i]); err != nil {
+			return coupons, count, err
+		}
+	}
+	return coupons, count, nil
 }
 
 // DisplayCoupon implements interfaces.CouponRepository.
@@ -104,6 +183,10 @@ func (c *CouponDatabase) DisplayCoupon(couponId int) (response.Coupon, error) {
 	var coupon response.Coupon
 	getAllCoupons := `SELECT * FROM coupons WHERE id=?`
 	err := c.DB.Raw(getAllCoupons, couponId).Scan(&coupon).Error
+	if err != nil {
+		return coupon, err
+	}
+	err = c.couponRestrictions(&coupon)
 	return coupon, err
 }
 func (c *CouponDatabase) EnableCoupon(couponId int) error {
@@ -120,6 +203,180 @@ func (c *CouponDatabase) EnableCoupon(couponId int) error {
 // CouponFromName implements interfaces.CouponRepository.
 func (c *CouponDatabase) CouponFromName(couponName string) (response.Coupon, error) {
 	var coupon response.Coupon
-	err := c.DB.Raw(`SELECT * FROM coupons WHERE name=?`, couponName).Scan(&coupon).Error
+	err := c.DB.Raw(`SELECT * FROM coupons WHERE name=? AND COALESCE(campaign_id,0)=0`, couponName).Scan(&coupon).Error
+	if err != nil {
+		return coupon, err
+	}
+	if coupon.Id == 0 {
+		// not a shared coupon, it may be a campaign code carrying the campaign coupon's rules
+		var code struct {
+			Id       uint
+			Code     string
+			UserId   uint
+			CouponId uint
+		}
+		err = c.DB.Raw(`SELECT coupon_codes.id,coupon_codes.code,coupon_codes.user_id,coupon_campaigns.coupon_id
+		FROM coupon_codes JOIN coupon_campaigns ON coupon_campaigns.id=coupon_codes.campaign_id
+		WHERE coupon_codes.code=?`, strings.ToUpper(couponName)).Scan(&code).Error
+		if err != nil || code.Id == 0 {
+			return coupon, err
+		}
+		if err = c.DB.Raw(`SELECT * FROM coupons WHERE id=?`, code.CouponId).Scan(&coupon).Error; err != nil {
+			return coupon, err
+		}
+		coupon.Name, coupon.CodeId, coupon.CodeRedeemed = code.Code, code.Id, code.UserId != 0
+	}
+	err = c.couponRestrictions(&coupon)
 	return coupon, err
 }
+
+// AvailableCoupons implements interfaces.CouponRepository.
+func (c *CouponDatabase) AvailableCoupons(userId int) ([]response.Coupon, error) {
+	var coupons []response.Coupon
+	// campaign coupons are left out, they can only be used through their codes
+	getCoupons := `SELECT coupons.*,
+	EXISTS (SELECT 1 FROM user_reward_coupons WHERE user_reward_coupons.coupon_id=coupons.id AND user_reward_coupons.users_id=$1) AS reward
+	FROM coupons
+	WHERE coupons.is_disabled=false AND COALESCE(coupons.campaign_id,0)=0 AND coupons.quantity>0
+	AND coupons.valid_from<=NOW() AND coupons.valid_until>NOW()
+	ORDER BY coupons.id`
+	if err := c.DB.Raw(getCoupons, userId).Scan(&coupons).Error; err != nil {
+		return nil, err
+	}
+	for i := range coupons {
+		if err := c.couponRestrictions(&coupons[i]); err != nil {
+			return nil, err
+		}
+	}
+	return coupons, nil
+}
+
+// CouponUsage implements interfaces.CouponRepository.
+func (c *CouponDatabase) CouponUsage(userId int, couponId uint) (helperStruct.CouponUsage, error) {
+	var usage helperStruct.CouponUsage
+	// uses on cancelled orders don't count
+	getUsage := `SELECT
+	(SELECT COUNT(*) FROM user_coupons LEFT JOIN orders ON orders.id=user_coupons.order_id
+	WHERE user_coupons.user_id=$1 AND user_coupons.coupon_id=$2 AND (orders.id IS NULL OR orders.order_status_id<>5)) AS times_used,
+	(SELECT COUNT(*) FROM orders WHERE user_id=$1 AND order_status_id<>5) AS past_orders`
+	err := c.DB.Raw(getUsage, userId, couponId).Scan(&usage).Error
+	return usage, err
+}
+
+// campaignReport adds up the codes of every campaign and the orders they were
+// redeemed on, it needs campaignGroup after any WHERE clause.
+const campaignReport = `SELECT coupon_campaigns.id,coupon_campaigns.name,coupon_campaigns.coupon_id,coupon_campaigns.prefix,
+	coupon_campaigns.code_length,coupon_campaigns.created_at,
+	COUNT(coupon_codes.id) AS total_codes,
+	COUNT(coupon_codes.id) FILTER (WHERE coupon_codes.user_id<>0) AS redeemed,
+	COALESCE(ROUND(100.0*COUNT(coupon_codes.id) FILTER (WHERE coupon_codes.user_id<>0)/NULLIF(COUNT(coupon_codes.id),0),2),0) AS redemption_rate,
+	COALESCE(SUM(orders.coupon_discount),0) AS discount_given,
+	COALESCE(SUM(orders.order_total),0) AS order_value
+	FROM coupon_campaigns
+	LEFT JOIN coupon_codes ON coupon_codes.campaign_id=coupon_campaigns.id
+	LEFT JOIN orders ON orders.id=coupon_codes.order_id AND orders.order_status_id<>5`
+
+const campaignGroup = ` GROUP BY coupon_campaigns.id`
+
+// ExistingCodes implements interfaces.CouponRepository.
+func (c *CouponDatabase) ExistingCodes(codes []string) ([]string, error) {
+	var existing []string
+	if len(codes) == 0 {
+		return existing, nil
+	}
+	err := c.DB.Raw(`SELECT code FROM coupon_codes WHERE code IN (?) UNION SELECT name FROM coupons WHERE name IN (?)`, codes, codes).Scan(&existing).Error
+	return existing, err
+}
+
+// AddCampaign implements interfaces.CouponRepository.
+func (c *CouponDatabase) AddCampaign(campaign helperStruct.CouponCampaign, codes []string) (response.CouponCampaign, error) {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM coupon_campaigns WHERE name=?)`, campaign.Name).Scan(&exists)
+	if exists {
+		return response.CouponCampaign{}, fmt.Errorf("there is already a campaign with this name")
+	}
+	tx := c.DB.Begin()
+	coupon, err := insertCoupon(tx, campaign.Coupon)
+	if err != nil {
+		tx.Rollback()
+		return response.CouponCampaign{}, err
+	}
+	var campaignId int
+	addCampaign := `INSERT INTO coupon_campaigns (name,coupon_id,prefix,code_length,created_at) VALUES ($1,$2,$3,$4,NOW()) RETURNING id`
+	err = tx.Raw(addCampaign, campaign.Name, coupon.Id, campaign.Prefix, campaign.CodeLength).Scan(&campaignId).Error
+	if err != nil {
+		tx.Rollback()
+		return response.CouponCampaign{}, err
+	}
+	if err := tx.Exec(`UPDATE coupons SET campaign_id=$1 WHERE id=$2`, campaignId, coupon.Id).Error; err != nil {
+		tx.Rollback()
+		return response.CouponCampaign{}, err
+	}
+	// codes go in a thousand at a time to stay well below the bind parameter limit
+	for start := 0; start < len(codes); start += 1000 {
+		end := start + 1000
+		if end > len(codes) {
+			end = len(codes)
+		}
+		var values []string
+		var args []interface{}
+		for _, code := range codes[start:end] {
+			values = append(values, "(?,?,0,0)")
+			args = append(args, campaignId, code)
+		}
+		addCodes := `INSERT INTO coupon_codes (campaign_id,code,user_id,order_id) VALUES ` + strings.Join(values, ",")
+		if err := tx.Exec(addCodes, args...).Error; err != nil {
+			tx.Rollback()
+			return response.CouponCampaign{}, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return response.CouponCampaign{}, err
+	}
+	return c.DisplayCampaign(campaignId)
+}
+
+// ListCampaigns implements interfaces.CouponRepository.
+func (c *CouponDatabase) ListCampaigns(queryParams helperStruct.QueryParams) ([]response.CouponCampaign, int, error) {
+	var campaigns []response.CouponCampaign
+	var count int
+	err := c.DB.Raw(`SELECT COUNT(*) FROM coupon_campaigns`).Scan(&count).Error
+	if err != nil {
+		return []response.CouponCampaign{}, 0, err
+	}
+	listCampaigns := campaignReport + campaignGroup + ` ORDER BY coupon_campaigns.created_at DESC`
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listCampaigns = fmt.Sprintf("%s LIMIT %d OFFSET %d", listCampaigns, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	} else {
+		listCampaigns = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listCampaigns)
+	}
+	err = c.DB.Raw(listCampaigns).Scan(&campaigns).Error
+	return campaigns, count, err
+}
+
+// DisplayCampaign implements interfaces.CouponRepository.
+func (c *CouponDatabase) DisplayCampaign(campaignId int) (response.CouponCampaign, error) {
+	var campaign response.CouponCampaign
+	err := c.DB.Raw(campaignReport+` WHERE coupon_campaigns.id=?`+campaignGroup, campaignId).Scan(&campaign).Error
+	if err != nil {
+		return response.CouponCampaign{}, err
+	}
+	if campaign.Id == 0 {
+		return response.CouponCampaign{}, fmt.Errorf("no campaign found with given id")
+	}
+	campaign.Coupon, err = c.DisplayCoupon(int(campaign.CouponId))
+	return campaign, err
+}
+
+// CampaignCodes implements interfaces.CouponRepository.
+func (c *CouponDatabase) CampaignCodes(campaignId int) ([]response.CouponCode, error) {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS (SELECT 1 FROM coupon_campaigns WHERE id=?)`, campaignId).Scan(&exists)
+	if !exists {
+		return nil, fmt.Errorf("no campaign found with given id")
+	}
+	var codes []response.CouponCode
+	err := c.DB.Raw(`SELECT code,user_id<>0 AS redeemed,user_id,order_id,redeemed_at FROM coupon_codes
+	WHERE campaign_id=? ORDER BY id`, campaignId).Scan(&codes).Error
+	return codes, err
+}
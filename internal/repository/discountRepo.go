@@ -19,16 +19,57 @@ func NewDiscountRepo(DB *gorm.DB) interfaces.DiscountRepository {
 	}
 }
 
-// AddDiscount implements interfaces.DiscountRepository.
-func (d *DiscountDatabase) AddDiscount(discount helperStruct.Discount) (response.Discount, error) {
-	var exists bool
-	d.DB.Raw(`SELECT EXISTS (select 1 from discounts where brand_id=?)`, discount.BrandId).Scan(&exists)
-	if exists {
-		return response.Discount{}, fmt.Errorf("this brand already has a discount please add discount for a different brand")
+// discountDetails lists discounts with the name of what they cover and
+// whether they are running.
+const discountDetails = `SELECT discounts.*,
+	COALESCE(products.product_name || ' ' || product_items.sku,scoped_products.product_name,brands.brandname,categories.category_name,
+	CASE WHEN discounts.scope='cart' THEN 'whole cart' END) AS scope_name,
+	CASE WHEN discounts.expiry_date<=NOW() THEN 'expired'
+	WHEN discounts.start_date>NOW() THEN 'scheduled'
+	ELSE 'live' END AS status
+	FROM discounts
+	LEFT JOIN product_items ON discounts.scope='item' AND product_items.id=discounts.scope_id
+	LEFT JOIN products ON products.id=product_items.product_id
+	LEFT JOIN products scoped_products ON discounts.scope='product' AND scoped_products.id=discounts.scope_id
+	LEFT JOIN brands ON discounts.scope='brand' AND brands.id=discounts.scope_id
+	LEFT JOIN categories ON discounts.scope='category' AND categories.id=discounts.scope_id`
+
+// discountedItems fills in the product items a live discount covers right
+// now. Cart discounts and discounts that are not running cover none.
+func (d *DiscountDatabase) discountedItems(discount *response.Discount) error {
+	discount.AffectedItems = []response.DiscountedItem{}
+	if discount.Scope == "cart" || discount.Status != "live" {
+		return nil
 	}
-	d.DB.Raw(`SELECT EXISTS (select 1 from brands where id=?)`, discount.BrandId).Scan(&exists)
+	affectedItems := `SELECT product_items.id AS product_item_id,products.product_name,product_items.sku,product_items.price,
+	` + discountAmount + ` AS saving,` + effectivePrice + ` AS discounted_price
+	FROM product_items
+	JOIN products ON products.id=product_items.product_id
+	JOIN discounts ON ` + discountCovers + `
+	` + discountsJoin + `
+	WHERE discounts.id=?
+	ORDER BY product_items.id`
+	return d.DB.Raw(affectedItems, discount.Id).Scan(&discount.AffectedItems).Error
+}
+
+// checkDiscountScope makes sure a discount covers something that exists, the
+// scopes point into the same tables as price rule scopes.
+func (d *DiscountDatabase) checkDiscountScope(discount helperStruct.Discount) error {
+	if discount.Scope == "cart" {
+		return nil
+	}
+	var exists bool
+	d.DB.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id=?)`, priceRuleScopes[discount.Scope]), discount.ScopeId).Scan(&exists)
 	if !exists {
-		return response.Discount{}, fmt.Errorf("no brand found with the given id")
+		return fmt.Errorf("no %s found with the given scope id", discount.Scope)
+	}
+	return nil
+}
+
+// AddDiscount implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) AddDiscount(discount helperStruct.Discount) (response.Discount, error) {
+	if err := d.checkDiscountScope(discount); err != nil {
+		return response.Discount{}, err
 	}
 
 	var maxId int
@@ -36,16 +77,31 @@ func (d *DiscountDatabase) AddDiscount(discount helperStruct.Discount) (response
 	if err != nil {
 		return response.Discount{}, fmt.Errorf("error retrieving maxId")
 	}
-	var newDiscount response.Discount
-	addDiscount := `INSERT INTO discounts(id,discount_percent,brand_id,expiry_date) VALUES($1,$2,$3,$4)RETURNING *`
-	err = d.DB.Exec(addDiscount, maxId+1, discount.DiscountPercent, discount.BrandId, discount.ExpiryDate).Error
+	addDiscount := `INSERT INTO discounts(id,name,scope,scope_id,type,discount_percent,amount,max_discount,min_cart_value,priority,stackable,start_date,expiry_date)
+	VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`
+	err = d.DB.Exec(addDiscount, maxId+1, discount.Name, discount.Scope, discount.ScopeId, discount.Type, discount.DiscountPercent, discount.Amount,
+		discount.MaxDiscount, discount.MinCartValue, discount.Priority, discount.Stackable, discount.StartDate, discount.ExpiryDate).Error
 	if err != nil {
 		return response.Discount{}, err
 	}
-	displayDiscount := `SELECT discounts.*,brands.brandname AS brand_name FROM discounts LEFT JOIN brands ON discounts.brand_id=brands.id WHERE discounts.brand_id=?`
-	err = d.DB.Raw(displayDiscount, discount.BrandId).Scan(&newDiscount).Error
-	return newDiscount, err
+	if err := recordPrices(d.DB, 0); err != nil {
+		return response.Discount{}, err
+	}
+	return d.displayDiscount(maxId + 1)
+}
 
+// displayDiscount returns one discount with the items it covers.
+func (d *DiscountDatabase) displayDiscount(id int) (response.Discount, error) {
+	var discount response.Discount
+	err := d.DB.Raw(discountDetails+` WHERE discounts.id=?`, id).Scan(&discount).Error
+	if err != nil {
+		return response.Discount{}, err
+	}
+	if discount.Id == 0 {
+		return response.Discount{}, fmt.Errorf("no discount found with the given id")
+	}
+	err = d.discountedItems(&discount)
+	return discount, err
 }
 
 // DeleteDiscount implements interfaces.DiscountRepository.
@@ -57,20 +113,21 @@ func (d *DiscountDatabase) DeleteDiscount(id int) error {
 	}
 	deleteDiscount := `DELETE FROM discounts WHERE id=?`
 	err := d.DB.Exec(deleteDiscount, id).Error
-	return err
+	if err != nil {
+		return err
+	}
+	return recordPrices(d.DB, 0)
 }
 
 // ListAllDiscount implements interfaces.DiscountRepository.
 func (d *DiscountDatabase) ListAllDiscount(queryParams helperStruct.QueryParams) ([]response.Discount, int, error) {
 	var discounts []response.Discount
-	listAllDiscount := `SELECT discounts.*,brands.brandname AS brand_name FROM discounts LEFT JOIN brands ON discounts.brand_id=brands.id`
 	var count int
-	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", listAllDiscount)
-	err := d.DB.Raw(getTotalCount).Scan(&count).Error
+	err := d.DB.Raw(`SELECT COUNT(*) FROM discounts`).Scan(&count).Error
 	if err != nil {
 		return []response.Discount{}, 0, err
 	}
-	listAllDiscount = fmt.Sprintf("%s ORDER BY discounts.expiry_date DESC", listAllDiscount)
+	listAllDiscount := fmt.Sprintf("%s ORDER BY discounts.expiry_date DESC,discounts.id DESC", discountDetails)
 	if queryParams.Limit != 0 && queryParams.Page != 0 {
 		listAllDiscount = fmt.Sprintf("%s LIMIT %d OFFSET %d", listAllDiscount, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
 	}
@@ -78,7 +135,15 @@ func (d *DiscountDatabase) ListAllDiscount(queryParams helperStruct.QueryParams)
 		listAllDiscount = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listAllDiscount)
 	}
 	err = d.DB.Raw(listAllDiscount).Scan(&discounts).Error
-	return discounts, count, err
+	if err != nil {
+		return discounts, count, err
+	}
+	for i := range discounts {
+		if err := d.discountedItems(&discounts[i]); err != nil {
+			return discounts, count, err
+		}
+	}
+	return discounts, count, nil
 }
 
 // UpdateDiscount implements interfaces.DiscountRepository.
@@ -88,17 +153,296 @@ func (d *DiscountDatabase) UpdateDiscount(discount helperStruct.Discount, discou
 	if !exists {
 		return response.Discount{}, fmt.Errorf("no discount found with the given id")
 	}
-	d.DB.Raw(`SELECT EXISTS (select 1 from discounts where brand_id=$1 AND id NOT IN ($2))`, discount.BrandId, discountId).Scan(&exists)
-	if exists {
-		return response.Discount{}, fmt.Errorf("this brand already has a discount please add discount for a different brand")
+	if err := d.checkDiscountScope(discount); err != nil {
+		return response.Discount{}, err
 	}
-	var updatedDiscount response.Discount
-	updateDiscount := `UPDATE discounts SET expiry_date=$1,discount_percent=$2,brand_id=$3 WHERE id=$4`
-	err := d.DB.Exec(updateDiscount, discount.ExpiryDate, discount.DiscountPercent, discount.BrandId, discountId).Error
+	updateDiscount := `UPDATE discounts SET name=$1,scope=$2,scope_id=$3,type=$4,discount_percent=$5,amount=$6,max_discount=$7,min_cart_value=$8,
+	priority=$9,stackable=$10,start_date=$11,expiry_date=$12 WHERE id=$13`
+	err := d.DB.Exec(updateDiscount, discount.Name, discount.Scope, discount.ScopeId, discount.Type, discount.DiscountPercent, discount.Amount,
+		discount.MaxDiscount, discount.MinCartValue, discount.Priority, discount.Stackable, discount.StartDate, discount.ExpiryDate, discountId).Error
 	if err != nil {
 		return response.Discount{}, err
 	}
-	displayDiscount := `SELECT discounts.*,brands.brandname AS brand_name FROM discounts LEFT JOIN brands ON discounts.brand_id=brands.id WHERE discounts.id=?`
-	err = d.DB.Raw(displayDiscount, discountId).Scan(&updatedDiscount).Error
-	return updatedDiscount, err
+	if err := recordPrices(d.DB, 0); err != nil {
+		return response.Discount{}, err
+	}
+	return d.displayDiscount(int(discountId))
+}
+
+// priceRuleDetails lists price rules with the name of what they cover, their
+// current status and how many items are on sale under them right now.
+const priceRuleDetails = `SELECT price_rules.*,
+	COALESCE(products.product_name || ' ' || product_items.sku,scoped_products.product_name,brands.brandname,categories.category_name) AS scope_name,
+	CASE WHEN NOT price_rules.is_active THEN 'inactive'
+	WHEN price_rules.ends_at<=NOW() THEN 'ended'
+	WHEN price_rules.flash_quantity>0 AND price_rules.sold_quantity>=price_rules.flash_quantity THEN 'sold_out'
+	WHEN price_rules.starts_at>NOW() THEN 'scheduled'
+	ELSE 'live' END AS status,
+	(SELECT COUNT(*) FROM product_items sale_items WHERE sale_items.sale_rule_id=price_rules.id AND sale_items.sale_ends_at>NOW()) AS affected_items
+	FROM price_rules
+	LEFT JOIN product_items ON price_rules.scope='item' AND product_items.id=price_rules.scope_id
+	LEFT JOIN products ON products.id=product_items.product_id
+	LEFT JOIN products scoped_products ON price_rules.scope='product' AND scoped_products.id=price_rules.scope_id
+	LEFT JOIN brands ON price_rules.scope='brand' AND brands.id=price_rules.scope_id
+	LEFT JOIN categories ON price_rules.scope='category' AND categories.id=price_rules.scope_id`
+
+// priceRuleScopes maps a price rule scope to the table its scope id points into.
+var priceRuleScopes = map[string]string{
+	"item":     "product_items",
+	"product":  "products",
+	"brand":    "brands",
+	"category": "categories",
+}
+
+// checkPriceRuleScope makes sure the rule covers something that exists and
+// that a fixed sale price actually is below the item's price.
+func (d *DiscountDatabase) checkPriceRuleScope(priceRule helperStruct.PriceRule) error {
+	var exists bool
+	d.DB.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id=?)`, priceRuleScopes[priceRule.Scope]), priceRule.Scope_id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no %s found with the given scope id", priceRule.Scope)
+	}
+	if priceRule.Sale_price > 0 {
+		var price float64
+		d.DB.Raw(`SELECT price FROM product_items WHERE id=?`, priceRule.Scope_id).Scan(&price)
+		if priceRule.Sale_price >= price {
+			return fmt.Errorf("sale price must be below the item price of %.2f", price)
+		}
+	}
+	return nil
+}
+
+// AddPriceRule implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) AddPriceRule(priceRule helperStruct.PriceRule) (response.PriceRule, error) {
+	if err := d.checkPriceRuleScope(priceRule); err != nil {
+		return response.PriceRule{}, err
+	}
+	var id int
+	addPriceRule := `INSERT INTO price_rules (name,scope,scope_id,discount_percent,sale_price,flash_quantity,sold_quantity,starts_at,ends_at,is_active,created_at,updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,0,$7,$8,$9,NOW(),NOW()) RETURNING id`
+	err := d.DB.Raw(addPriceRule, priceRule.Name, priceRule.Scope, priceRule.Scope_id, priceRule.Discount_percent, priceRule.Sale_price,
+		priceRule.Flash_quantity, priceRule.Starts_at, priceRule.Ends_at, *priceRule.Is_active).Scan(&id).Error
+	if err != nil {
+		return response.PriceRule{}, err
+	}
+	if err := applyPriceRules(d.DB); err != nil {
+		return response.PriceRule{}, err
+	}
+	return d.DisplayPriceRule(id)
+}
+
+// UpdatePriceRule implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) UpdatePriceRule(priceRule helperStruct.PriceRule, id int) (response.PriceRule, error) {
+	var exists bool
+	d.DB.Raw(`SELECT EXISTS (SELECT 1 FROM price_rules WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return response.PriceRule{}, fmt.Errorf("no price rule found with the given id")
+	}
+	if err := d.checkPriceRuleScope(priceRule); err != nil {
+		return response.PriceRule{}, err
+	}
+	// units already sold stay counted against the flash quantity
+	updatePriceRule := `UPDATE price_rules SET name=$1,scope=$2,scope_id=$3,discount_percent=$4,sale_price=$5,flash_quantity=$6,starts_at=$7,ends_at=$8,
+	is_active=COALESCE($9,is_active),updated_at=NOW() WHERE id=$10`
+	err := d.DB.Exec(updatePriceRule, priceRule.Name, priceRule.Scope, priceRule.Scope_id, priceRule.Discount_percent, priceRule.Sale_price,
+		priceRule.Flash_quantity, priceRule.Starts_at, priceRule.Ends_at, priceRule.Is_active, id).Error
+	if err != nil {
+		return response.PriceRule{}, err
+	}
+	if err := applyPriceRules(d.DB); err != nil {
+		return response.PriceRule{}, err
+	}
+	return d.DisplayPriceRule(id)
+}
+
+// DeletePriceRule implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) DeletePriceRule(id int) error {
+	var exists bool
+	d.DB.Raw(`SELECT EXISTS (SELECT 1 FROM price_rules WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such price rule to delete")
+	}
+	err := d.DB.Exec(`DELETE FROM price_rules WHERE id=?`, id).Error
+	if err != nil {
+		return err
+	}
+	return applyPriceRules(d.DB)
+}
+
+// ListPriceRules implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) ListPriceRules(queryParams helperStruct.QueryParams) ([]response.PriceRule, int, error) {
+	var priceRules []response.PriceRule
+	var count int
+	err := d.DB.Raw(`SELECT COUNT(*) FROM price_rules`).Scan(&count).Error
+	if err != nil {
+		return []response.PriceRule{}, 0, err
+	}
+	listPriceRules := fmt.Sprintf("%s ORDER BY price_rules.starts_at DESC,price_rules.id DESC", priceRuleDetails)
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listPriceRules = fmt.Sprintf("%s LIMIT %d OFFSET %d", listPriceRules, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	} else {
+		listPriceRules = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listPriceRules)
+	}
+	err = d.DB.Raw(listPriceRules).Scan(&priceRules).Error
+	return priceRules, count, err
+}
+
+// DisplayPriceRule implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) DisplayPriceRule(id int) (response.PriceRule, error) {
+	var priceRule response.PriceRule
+	err := d.DB.Raw(priceRuleDetails+` WHERE price_rules.id=?`, id).Scan(&priceRule).Error
+	if err == nil && priceRule.Id == 0 {
+		err = fmt.Errorf("no price rule found with the given id")
+	}
+	return priceRule, err
+}
+
+// promotionDetails lists promotions with their current status.
+const promotionDetails = `SELECT promotions.*,
+	CASE WHEN NOT promotions.is_active THEN 'inactive'
+	WHEN promotions.ends_at<=NOW() THEN 'ended'
+	WHEN promotions.starts_at>NOW() THEN 'scheduled'
+	ELSE 'live' END AS status
+	FROM promotions`
+
+// savePromotionItems replaces the items of a promotion inside tx, checking
+// each one exists.
+func savePromotionItems(tx *gorm.DB, promotionId uint, items []helperStruct.PromotionItem) error {
+	if err := tx.Exec(`DELETE FROM promotion_items WHERE promotion_id=?`, promotionId).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		var exists bool
+		tx.Raw(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE id=?)`, priceRuleScopes[item.Scope]), item.ScopeId).Scan(&exists)
+		if !exists {
+			return fmt.Errorf("no %s found with id %d for the promotion", item.Scope, item.ScopeId)
+		}
+		err := tx.Exec(`INSERT INTO promotion_items (promotion_id,role,scope,scope_id) VALUES ($1,$2,$3,$4)`, promotionId, item.Role, item.Scope, item.ScopeId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promotionItems fills in the items of a promotion.
+func (d *DiscountDatabase) promotionItems(promotion *response.Promotion) error {
+	promotion.Items = []response.PromotionItem{}
+	getItems := `SELECT promotion_items.role,promotion_items.scope,promotion_items.scope_id,
+	COALESCE(products.product_name || ' ' || product_items.sku,scoped_products.product_name,brands.brandname,categories.category_name) AS scope_name
+	FROM promotion_items
+	LEFT JOIN product_items ON promotion_items.scope='item' AND product_items.id=promotion_items.scope_id
+	LEFT JOIN products ON products.id=product_items.product_id
+	LEFT JOIN products scoped_products ON promotion_items.scope='product' AND scoped_products.id=promotion_items.scope_id
+	LEFT JOIN brands ON promotion_items.scope='brand' AND brands.id=promotion_items.scope_id
+	LEFT JOIN categories ON promotion_items.scope='category' AND categories.id=promotion_items.scope_id
+	WHERE promotion_items.promotion_id=?
+	ORDER BY promotion_items.id`
+	return d.DB.Raw(getItems, promotion.Id).Scan(&promotion.Items).Error
+}
+
+// AddPromotion implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) AddPromotion(promotion helperStruct.Promotion) (response.Promotion, error) {
+	tx := d.DB.Begin()
+	var id uint
+	addPromotion := `INSERT INTO promotions (name,type,trigger_quantity,reward_quantity,reward_percent,bundle_price,max_applications,starts_at,ends_at,is_active,created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NOW()) RETURNING id`
+	err := tx.Raw(addPromotion, promotion.Name, promotion.Type, promotion.TriggerQuantity, promotion.RewardQuantity, promotion.RewardPercent,
+		promotion.BundlePrice, promotion.MaxApplications, promotion.StartsAt, promotion.EndsAt, *promotion.IsActive).Scan(&id).Error
+	if err != nil {
+		tx.Rollback()
+		return response.Promotion{}, err
+	}
+	if err := savePromotionItems(tx, id, promotion.Items); err != nil {
+		tx.Rollback()
+		return response.Promotion{}, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return response.Promotion{}, err
+	}
+	return d.DisplayPromotion(int(id))
+}
+
+// UpdatePromotion implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) UpdatePromotion(promotion helperStruct.Promotion, id int) (response.Promotion, error) {
+	var exists bool
+	d.DB.Raw(`SELECT EXISTS (SELECT 1 FROM promotions WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return response.Promotion{}, fmt.Errorf("no promotion found with the given id")
+	}
+	tx := d.DB.Begin()
+	updatePromotion := `UPDATE promotions SET name=$1,type=$2,trigger_quantity=$3,reward_quantity=$4,reward_percent=$5,bundle_price=$6,
+	max_applications=$7,starts_at=$8,ends_at=$9,is_active=COALESCE($10,is_active) WHERE id=$11`
+	err := tx.Exec(updatePromotion, promotion.Name, promotion.Type, promotion.TriggerQuantity, promotion.RewardQuantity, promotion.RewardPercent,
+		promotion.BundlePrice, promotion.MaxApplications, promotion.StartsAt, promotion.EndsAt, promotion.IsActive, id).Error
+	if err != nil {
+		tx.Rollback()
+		return response.Promotion{}, err
+	}
+	if err := savePromotionItems(tx, uint(id), promotion.Items); err != nil {
+		tx.Rollback()
+		return response.Promotion{}, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return response.Promotion{}, err
+	}
+	return d.DisplayPromotion(id)
+}
+
+// DeletePromotion implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) DeletePromotion(id int) error {
+	var exists bool
+	d.DB.Raw(`SELECT EXISTS (SELECT 1 FROM promotions WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such promotion to delete")
+	}
+	tx := d.DB.Begin()
+	if err := tx.Exec(`DELETE FROM promotion_items WHERE promotion_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM promotions WHERE id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ListPromotions implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) ListPromotions(queryParams helperStruct.QueryParams) ([]response.Promotion, int, error) {
+	var promotions []response.Promotion
+	var count int
+	err := d.DB.Raw(`SELECT COUNT(*) FROM promotions`).Scan(&count).Error
+	if err != nil {
+		return []response.Promotion{}, 0, err
+	}
+	listPromotions := fmt.Sprintf("%s ORDER BY promotions.starts_at DESC,promotions.id DESC", promotionDetails)
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listPromotions = fmt.Sprintf("%s LIMIT %d OFFSET %d", listPromotions, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	} else {
+		listPromotions = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listPromotions)
+	}
+	if err = d.DB.Raw(listPromotions).Scan(&promotions).Error; err != nil {
+		return []response.Promotion{}, 0, err
+	}
+	for i := range promotions {
+		if err := d.promotionItems(&promotions[i]); err != nil {
+			return []response.Promotion{}, 0, err
+		}
+	}
+	return promotions, count, nil
+}
+
+// DisplayPromotion implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) DisplayPromotion(id int) (response.Promotion, error) {
+	var promotion response.Promotion
+	err := d.DB.Raw(promotionDetails+` WHERE promotions.id=?`, id).Scan(&promotion).Error
+	if err == nil && promotion.Id == 0 {
+		err = fmt.Errorf("no promotion found with the given id")
+	}
+	if err != nil {
+		return promotion, err
+	}
+	err = d.promotionItems(&promotion)
+	return promotion, err
 }
@@ -1,10 +1,17 @@
 package interfaces
 
-import "main.go/internal/common/response"
+import "main.go/internal/common/helperStruct"
 
 type CartRepository interface {
 	CreateCart(Id int) error
 	AddToCart(productId, userId int) error
 	RemoveFromCart(productId, userId int) error
-	ListCart(userId int) (response.ViewCart, error)
+	CartItems(userId int) ([]helperStruct.PricingItem, error)
+	CartDiscounts() ([]helperStruct.ApplicableDiscount, error)
+	CartPromotions() ([]helperStruct.ApplicablePromotion, error)
+	CartTier(userId int) (helperStruct.ApplicableTier, error)
+	SetFreeItems(userId int, freeUnits map[uint]int) error
+	CartCoupon(userId int) (string, string, error)
+	SetCartCoupon(userId int, couponId, codeId uint, notice string) error
+	ClearCartNotice(userId int) error
 }
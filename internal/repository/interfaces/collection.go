@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+type CollectionRepository interface {
+	AddCollection(collection helperStruct.Collection) (response.Collection, error)
+	UpdateCollection(collection helperStruct.Collection, id int) (response.Collection, error)
+	DeleteCollection(id int) error
+	ListAllCollections(queryParams helperStruct.QueryParams) ([]response.Collection, int, error)
+	DisplayCollection(id int) (response.Collection, error)
+	SetCollectionItems(id int, productItemIds []uint) error
+	CollectionItems(collection response.Collection, queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error)
+	HomeCollections() ([]response.Collection, error)
+	ActiveCollection(slug string) (response.Collection, error)
+	SetProductTags(productItemId int, tags []string) ([]string, error)
+	AddBanner(banner helperStruct.Banner) (response.Banner, error)
+	UpdateBanner(banner helperStruct.Banner, id int) (response.Banner, error)
+	DeleteBanner(id int) error
+	ListAllBanners(queryParams helperStruct.QueryParams) ([]response.Banner, int, error)
+	ActiveBanners() ([]response.Banner, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "main.go/internal/common/response"
+
+type CompareRepository interface {
+	AddToCompare(userId, productItemId, maxItems int) error
+	RemoveFromCompare(userId, productItemId int) error
+	ClearCompare(userId int) error
+	CompareItems(userId int) ([]response.ProductItem, error)
+}
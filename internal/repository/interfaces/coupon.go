@@ -13,4 +13,11 @@ type CouponRepository interface {
 	DisplayCoupon(couponId int) (response.Coupon, error)
 	EnableCoupon(couponId int) error
 	CouponFromName(couponName string) (response.Coupon, error)
+	CouponUsage(userId int, couponId uint) (helperStruct.CouponUsage, error)
+	AvailableCoupons(userId int) ([]response.Coupon, error)
+	ExistingCodes(codes []string) ([]string, error)
+	AddCampaign(campaign helperStruct.CouponCampaign, codes []string) (response.CouponCampaign, error)
+	ListCampaigns(queryParams helperStruct.QueryParams) ([]response.CouponCampaign, int, error)
+	DisplayCampaign(campaignId int) (response.CouponCampaign, error)
+	CampaignCodes(campaignId int) ([]response.CouponCode, error)
 }
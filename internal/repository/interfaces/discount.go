@@ -10,4 +10,15 @@ type DiscountRepository interface {
 	UpdateDiscount(discount helperStruct.Discount, discountId uint) (response.Discount, error)
 	DeleteDiscount(id int) error
 	ListAllDiscount(queryParams helperStruct.QueryParams) ([]response.Discount, int, error)
+	AddPriceRule(priceRule helperStruct.PriceRule) (response.PriceRule, error)
+	UpdatePriceRule(priceRule helperStruct.PriceRule, id int) (response.PriceRule, error)
+	DeletePriceRule(id int) error
+	ListPriceRules(queryParams helperStruct.QueryParams) ([]response.PriceRule, int, error)
+	DisplayPriceRule(id int) (response.PriceRule, error)
+	ApplyPriceRules() error
+	AddPromotion(promotion helperStruct.Promotion) (response.Promotion, error)
+	UpdatePromotion(promotion helperStruct.Promotion, id int) (response.Promotion, error)
+	DeletePromotion(id int) error
+	ListPromotions(queryParams helperStruct.QueryParams) ([]response.Promotion, int, error)
+	DisplayPromotion(id int) (response.Promotion, error)
 }
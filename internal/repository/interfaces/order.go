@@ -6,7 +6,7 @@ import (
 )
 
 type OrderRepository interface {
-	OrderAll(UserId, PaymentTypeid int, coupon response.Coupon) (response.ResponseOrder, error)
+	OrderAll(UserId, PaymentTypeid, walletAmount int, coupon response.Coupon, pricing response.PriceBreakdown) (response.ResponseOrder, error)
 	UserCancelOrder(orderId, userId int) error
 	ListAllOrders(userId int, queryParams helperStruct.QueryParams) ([]response.OrderResponse, int, error)
 	DisplayOrder(userId, orderId int) (response.ResponseOrder, error)
@@ -18,4 +18,6 @@ type OrderRepository interface {
 	AddOrderStatus(orderStatus helperStruct.OrderStatus) (response.OrderStatus, error)
 	UpdateOrderStatuses(orderStatus helperStruct.OrderStatus) (response.OrderStatus, error)
 	ListAllOrderStatuses() ([]response.OrderStatus, error)
+	PendingRefunds(orderId int) ([]response.PaymentRefund, error)
+	CompleteRefund(refundId uint, gatewayRef string) error
 }
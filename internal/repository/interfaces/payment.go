@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
 	"main.go/internal/domain"
 )
 
@@ -14,4 +15,7 @@ type PaymentRepository interface {
 	AddPaymentStatus(paymemtStatus helperStruct.PaymentStatus) (domain.PaymentStatus, error)
 	UpdatePaymentStatus(paymentStatus helperStruct.PaymentStatus, paymentStatusId int) error
 	ListAllPaymentStatuses() ([]domain.PaymentStatus, error)
+	CreateWalletTopUp(userId, amount int, razorpayOrderId string) (response.WalletTopUp, error)
+	WalletTopUp(razorpayOrderId string) (response.WalletTopUp, error)
+	CompleteWalletTopUp(razorpayOrderId, paymentRef string) (response.WalletTopUp, error)
 }
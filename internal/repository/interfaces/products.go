@@ -8,6 +8,7 @@ import (
 type ProductRepository interface {
 	CreateCategory(category helperStruct.Category) (response.Category, error)
 	UpdateCategory(category helperStruct.Category, id int) (response.Category, error)
+	MoveCategory(move helperStruct.MoveCategory, id int) (response.Category, error)
 	DeleteCategory(id int) error
 	ListAllCategories() ([]response.Category, error)
 	DisplayCategory(id int) (response.Category, error)
@@ -26,8 +27,14 @@ type ProductRepository interface {
 	ListAllProductItems(queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error)
 	// UploadImage(Image helperStruct.ImageHelper) (response.ImageResponse, error)
 	UploadImage(filepath string, productid int) (response.Image, error)
-	DeleteImage(id int) error
+	DeleteImage(id int) (string, error)
 	DeleteProductItem(id int) error
 	DisplayProductItem(id int) (response.DisplayProductItem, error)
 	SearchProducts(queryParams helperStruct.QueryParams, searchProducts string) ([]response.Product, error)
+	ResolveSlug(entity, slug string) (int, string, error)
+	RecordView(userId, productItemId int) error
+	RecentlyViewed(userId int, limit int) ([]response.ProductItem, error)
+	PersonalizedProducts(userId int, queryParams helperStruct.QueryParams) ([]response.Product, int, error)
+	PriceHistory(productItemId int, days int) ([]response.PricePoint, error)
+	RecordPriceChanges() error
 }
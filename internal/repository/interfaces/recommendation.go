@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+type RecommendationRepository interface {
+	RefreshRecommendations() error
+	Recommendations(productItemIds []int, kind string, limit int) ([]response.ProductItem, error)
+	CartProductItems(userId int) ([]int, error)
+	AddOverride(override helperStruct.RecommendationOverride) (response.RecommendationOverride, error)
+	DeleteOverride(id int) error
+	ListOverrides(productItemId int) ([]response.RecommendationOverride, error)
+}
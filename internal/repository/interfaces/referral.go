@@ -1,6 +1,17 @@
 package interfaces
 
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
 type ReferralRepository interface {
 	AddReferral(userId int, referralId string) error
-	ReferralOffer(referralId string, userId int) error
+	ReferralCodeExists(referralId string) (bool, error)
+	UpdateReferralCode(userId int, referralId string) error
+	ReferralOffer(referralId string, userId int) (int, error)
+	ReferralSettings() (response.ReferralSettings, error)
+	UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error)
+	ReferralDashboard(userId int) (response.ReferralDashboard, error)
+	ReleaseReferralRewards() error
 }
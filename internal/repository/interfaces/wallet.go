@@ -9,5 +9,8 @@ import (
 type WalletRepository interface {
 	CreateWallet(userId int) error
 	DisplayWallet(userId int) (response.Wallet, error)
-	WalletHistory(userid int, queryParams helperStruct.QueryParams) ([]response.WalletHistories, int, error)
+	WalletHistory(userid int, queryParams helperStruct.QueryParams) ([]response.WalletTransaction, int, error)
+	CreateWalletAdjustment(adjustment helperStruct.WalletAdjustment, adminId int, needsApproval bool) (response.WalletAdjustment, error)
+	DecideWalletAdjustment(adjustmentId, superId int, approve bool, note string) (response.WalletAdjustment, error)
+	ListWalletAdjustments(filter helperStruct.WalletAdjustmentFilter, queryParams helperStruct.QueryParams) ([]response.WalletAdjustment, int, error)
 }
@@ -1,10 +1,18 @@
 package interfaces
 
-import "main.go/internal/common/response"
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
 
 type WishlistRepository interface {
 	AddToWishlist(productId int, userId int) error
 	RemoveFromWishlist(productId int, userId int) error
 	ListAllWishlist(userId int) ([]response.Wishlist, error)
 	DisplayWishlistProduct(productId int, userId int) (response.Wishlist, error)
+	SetPriceAlert(productId int, userId int, alert helperStruct.PriceAlert) (response.PriceAlert, error)
+	RemovePriceAlert(productId int, userId int) error
+	ListPriceAlerts(userId int) ([]response.PriceAlert, error)
+	DuePriceAlerts() ([]response.PriceDrop, error)
+	MarkPriceAlertNotified(alertId int, price float64) error
 }
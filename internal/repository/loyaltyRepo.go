@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/domain"
+	"main.go/internal/repository/interfaces"
+)
+
+// pointsAreLive matches the ledger entries with points left to redeem.
+const pointsAreLive = `loyalty_points.remaining>0 AND (loyalty_points.expires_at IS NULL OR loyalty_points.expires_at>NOW())`
+
+// pointsExpiry is when points given now expire, NULL when the program keeps them forever.
+const pointsExpiry = `(SELECT CASE WHEN expiry_days>0 THEN NOW()+expiry_days*INTERVAL '1 day' END FROM loyalty_settings ORDER BY id LIMIT 1)`
+
+// tierSpend is what a user spent on the goods of orders delivered in the last
+// 12 months, it needs users in the query.
+const tierSpend = `(SELECT COALESCE(SUM(orders.order_total-orders.shipping_charge),0) FROM orders
+	WHERE orders.user_id=users.id AND orders.order_status_id=4 AND orders.order_date>NOW()-INTERVAL '12 months')`
+
+type loyaltyDatabase struct {
+	DB *gorm.DB
+}
+
+func NewLoyaltyRepo(DB *gorm.DB) interfaces.LoyaltyRepository {
+	return &loyaltyDatabase{
+		DB: DB,
+	}
+}
+
+func loyaltySettings(db *gorm.DB) (response.LoyaltySettings, error) {
+	var settings response.LoyaltySettings
+	err := db.Raw(`SELECT * FROM loyalty_settings ORDER BY id LIMIT 1`).Scan(&settings).Error
+	return settings, err
+}
+
+// consumePoints uses up to points from the live entries of a user, the ones
+// expiring first first, and returns how many it used up.
+func consumePoints(tx *gorm.DB, userId uint, points int) (int, error) {
+	var entries []domain.LoyaltyPoints
+	err := tx.Raw(`SELECT id,remaining FROM loyalty_points WHERE user_id=? AND `+pointsAreLive+`
+	ORDER BY expires_at NULLS LAST,id FOR UPDATE`, userId).Scan(&entries).Error
+	if err != nil {
+		return 0, err
+	}
+	used := 0
+	for _, entry := range entries {
+		if used == points {
+			break
+		}
+		take := entry.Remaining
+		if take > points-used {
+			take = points - used
+		}
+		if err := tx.Exec(`UPDATE loyalty_points SET remaining=remaining-? WHERE id=?`, take, entry.Id).Error; err != nil {
+			return 0, err
+		}
+		used += take
+	}
+	return used, nil
+}
+
+// redeemPoints takes the points a user pays part of an order with inside tx.
+func redeemPoints(tx *gorm.DB, userId, orderId uint, points int) error {
+	used, err := consumePoints(tx, userId, points)
+	if err != nil {
+		return fmt.Errorf("error redeeming loyalty points")
+	}
+	if used < points {
+		return fmt.Errorf("you don't have enough loyalty points")
+	}
+	err = tx.Exec(`INSERT INTO loyalty_points (user_id,order_id,type,points,created_at) VALUES ($1,$2,'redeemed',$3,NOW())`,
+		userId, orderId, -points).Error
+	if err != nil {
+		return fmt.Errorf("error redeeming loyalty points")
+	}
+	return nil
+}
+
+// refundRedeemedPoints gives back the points an order was paid with when it is
+// cancelled or returned, they expire as if they were earned again.
+func refundRedeemedPoints(tx *gorm.DB, orderId int) error {
+	err := tx.Exec(`INSERT INTO loyalty_points (user_id,order_id,type,points,remaining,expires_at,created_at)
+	SELECT user_id,order_id,'refunded',-points,-points,`+pointsExpiry+`,NOW() FROM loyalty_points
+	WHERE order_id=$1 AND type='redeemed' AND NOT EXISTS (SELECT 1 FROM loyalty_points WHERE order_id=$1 AND type='refunded')`, orderId).Error
+	if err != nil {
+		return fmt.Errorf("error refunding loyalty points")
+	}
+	return nil
+}
+
+// earnPoints credits the points a delivered order earns, once per order. What
+// was paid for the goods earns PointsPerRupee, times the multiplier of the
+// user's tier.
+func earnPoints(db *gorm.DB, orderId int) error {
+	settings, err := loyaltySettings(db)
+	if err != nil {
+		return err
+	}
+	if !settings.IsActive || settings.PointsPerRupee <= 0 {
+		return nil
+	}
+	var order domain.Orders
+	if err := db.Raw(`SELECT * FROM orders WHERE id=?`, orderId).Scan(&order).Error; err != nil {
+		return err
+	}
+	var multiplier float64
+	err = db.Raw(`SELECT COALESCE((SELECT loyalty_tiers.earn_multiplier FROM users JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id
+	WHERE users.id=?),1)`, order.UserId).Scan(&multiplier).Error
+	if err != nil {
+		return err
+	}
+	points := int(math.Floor(float64(order.OrderTotal-order.ShippingCharge) * settings.PointsPerRupee * multiplier))
+	if points <= 0 {
+		return nil
+	}
+	return db.Exec(`INSERT INTO loyalty_points (user_id,order_id,type,points,remaining,expires_at,created_at)
+	SELECT $1,$2,'earned',$3,$3,`+pointsExpiry+`,NOW()
+	WHERE NOT EXISTS (SELECT 1 FROM loyalty_points WHERE order_id=$2 AND type='earned')`, order.UserId, order.Id, points).Error
+}
+
+// reverseEarnedPoints takes back the points a returned order earned. Points
+// already spent are taken from the rest of the balance, as far as it goes.
+func reverseEarnedPoints(tx *gorm.DB, orderId int) error {
+	var earned domain.LoyaltyPoints
+	err := tx.Raw(`SELECT * FROM loyalty_points WHERE order_id=? AND type='earned' FOR UPDATE`, orderId).Scan(&earned).Error
+	if err != nil {
+		return fmt.Errorf("error reversing loyalty points")
+	}
+	var reversed bool
+	tx.Raw(`SELECT EXISTS (SELECT 1 FROM loyalty_points WHERE order_id=? AND type='reversed')`, orderId).Scan(&reversed)
+	if earned.Id == 0 || reversed {
+		return nil
+	}
+	if err := tx.Exec(`UPDATE loyalty_points SET remaining=0 WHERE id=?`, earned.Id).Error; err != nil {
+		return fmt.Errorf("error reversing loyalty points")
+	}
+	spent, err := consumePoints(tx, earned.UserId, earned.Points-earned.Remaining)
+	if err != nil {
+		return fmt.Errorf("error reversing loyalty points")
+	}
+	err = tx.Exec(`INSERT INTO loyalty_points (user_id,order_id,type,points,created_at) VALUES ($1,$2,'reversed',$3,NOW())`,
+		earned.UserId, orderId, -(earned.Remaining + spent)).Error
+	if err != nil {
+		return fmt.Errorf("error reversing loyalty points")
+	}
+	return nil
+}
+
+// ExpirePoints implements interfaces.LoyaltyRepository. It clears what is
+// left of expired points, recording one expired entry per user.
+func (l *loyaltyDatabase) ExpirePoints() error {
+	return l.DB.Exec(`WITH expiring AS (
+		SELECT id,user_id,remaining FROM loyalty_points WHERE remaining>0 AND expires_at<=NOW() FOR UPDATE
+	), cleared AS (
+		UPDATE loyalty_points SET remaining=0 FROM expiring WHERE loyalty_points.id=expiring.id
+	)
+	INSERT INTO loyalty_points (user_id,type,points,created_at)
+	SELECT user_id,'expired',-SUM(remaining),NOW() FROM expiring GROUP BY user_id`).Error
+}
+
+// RecalculateTiers implements interfaces.LoyaltyRepository. It puts every
+// user in the highest tier their spend over the last 12 months reaches, or in
+// none.
+func (l *loyaltyDatabase) RecalculateTiers() error {
+	return l.DB.Exec(`UPDATE users SET loyalty_tier_id=COALESCE((SELECT loyalty_tiers.id FROM loyalty_tiers
+	WHERE loyalty_tiers.min_spend<=` + tierSpend + ` ORDER BY loyalty_tiers.min_spend DESC,loyalty_tiers.id LIMIT 1),0)`).Error
+}
+
+// Settings implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) Settings() (response.LoyaltySettings, error) {
+	return loyaltySettings(l.DB)
+}
+
+// UpdateSettings implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) UpdateSettings(settings helperStruct.LoyaltySettings) (response.LoyaltySettings, error) {
+	updateSettings := `UPDATE loyalty_settings SET is_active=COALESCE($1,is_active),points_per_rupee=$2,rupees_per_point=$3,
+	min_redeem_points=$4,max_redeem_percent=$5,expiry_days=$6,updated_at=NOW()`
+	err := l.DB.Exec(updateSettings, settings.IsActive, settings.PointsPerRupee, settings.RupeesPerPoint, settings.MinRedeemPoints,
+		settings.MaxRedeemPercent, settings.ExpiryDays).Error
+	if err != nil {
+		return response.LoyaltySettings{}, err
+	}
+	return loyaltySettings(l.DB)
+}
+
+// AddTier implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) AddTier(tier helperStruct.LoyaltyTier) (response.LoyaltyTier, error) {
+	var exists bool
+	l.DB.Raw(`SELECT EXISTS (SELECT 1 FROM loyalty_tiers WHERE name=?)`, tier.Name).Scan(&exists)
+	if exists {
+		return response.LoyaltyTier{}, fmt.Errorf("tier is already present please add a new unique tier name")
+	}
+	var newTier response.LoyaltyTier
+	addTier := `INSERT INTO loyalty_tiers (name,min_spend,earn_multiplier,extra_discount_percent,free_shipping,priority_support,return_window_days)
+	VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING *`
+	err := l.DB.Raw(addTier, tier.Name, tier.MinSpend, tier.EarnMultiplier, tier.ExtraDiscountPercent, tier.FreeShipping,
+		tier.PrioritySupport, tier.ReturnWindowDays).Scan(&newTier).Error
+	if err != nil {
+		return response.LoyaltyTier{}, err
+	}
+	// members move to the new tier straight away instead of on the next run of the tiers job
+	err = l.RecalculateTiers()
+	return newTier, err
+}
+
+// UpdateTier implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) UpdateTier(tier helperStruct.LoyaltyTier, id int) (response.LoyaltyTier, error) {
+	var exists bool
+	l.DB.Raw(`SELECT EXISTS (SELECT 1 FROM loyalty_tiers WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return response.LoyaltyTier{}, fmt.Errorf("no tier found with the given id")
+	}
+	l.DB.Raw(`SELECT EXISTS (SELECT 1 FROM loyalty_tiers WHERE name=$1 AND id<>$2)`, tier.Name, id).Scan(&exists)
+	if exists {
+		return response.LoyaltyTier{}, fmt.Errorf("tier is already present please add a new unique tier name")
+	}
+	var updatedTier response.LoyaltyTier
+	updateTier := `UPDATE loyalty_tiers SET name=$1,min_spend=$2,earn_multiplier=$3,extra_discount_percent=$4,free_shipping=$5,
+	priority_support=$6,return_window_days=$7 WHERE id=$8 RETURNING *`
+	err := l.DB.Raw(updateTier, tier.Name, tier.MinSpend, tier.EarnMultiplier, tier.ExtraDiscountPercent, tier.FreeShipping,
+		tier.PrioritySupport, tier.ReturnWindowDays, id).Scan(&updatedTier).Error
+	if err != nil {
+		return response.LoyaltyTier{}, err
+	}
+	err = l.RecalculateTiers()
+	return updatedTier, err
+}
+
+// DeleteTier implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) DeleteTier(id int) error {
+	var exists bool
+	l.DB.Raw(`SELECT EXISTS (SELECT 1 FROM loyalty_tiers WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such tier to delete")
+	}
+	if err := l.DB.Exec(`DELETE FROM loyalty_tiers WHERE id=?`, id).Error; err != nil {
+		return err
+	}
+	return l.RecalculateTiers()
+}
+
+// ListTiers implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) ListTiers() ([]response.LoyaltyTier, error) {
+	var tiers []response.LoyaltyTier
+	err := l.DB.Raw(`SELECT * FROM loyalty_tiers ORDER BY min_spend,id`).Scan(&tiers).Error
+	return tiers, err
+}
+
+// PointsSummary implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) PointsSummary(userId int) (response.LoyaltySummary, error) {
+	var summary response.LoyaltySummary
+	getSummary := `SELECT COALESCE(SUM(remaining) FILTER (WHERE ` + pointsAreLive + `),0) AS balance,
+	COALESCE(SUM(points) FILTER (WHERE type IN ('earned','reversed')),0) AS lifetime_points,
+	COALESCE(SUM(remaining) FILTER (WHERE ` + pointsAreLive + ` AND expires_at<=NOW()+INTERVAL '30 days'),0) AS expiring_soon
+	FROM loyalty_points WHERE user_id=?`
+	err := l.DB.Raw(getSummary, userId).Scan(&summary).Error
+	if err != nil {
+		return response.LoyaltySummary{}, err
+	}
+	getTier := `SELECT COALESCE(loyalty_tiers.name,'') AS tier,` + tierSpend + ` AS spend
+	FROM users LEFT JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id WHERE users.id=?`
+	err = l.DB.Raw(getTier, userId).Scan(&summary).Error
+	return summary, err
+}
+
+// PointsHistory implements interfaces.LoyaltyRepository.
+func (l *loyaltyDatabase) PointsHistory(userId int, queryParams helperStruct.QueryParams) ([]response.LoyaltyEntry, int, error) {
+	var entries []response.LoyaltyEntry
+	var count int
+	err := l.DB.Raw(`SELECT COUNT(*) FROM loyalty_points WHERE user_id=?`, userId).Scan(&count).Error
+	if err != nil {
+		return []response.LoyaltyEntry{}, 0, err
+	}
+	pointsHistory := `SELECT id,order_id,type,points,expires_at,created_at FROM loyalty_points WHERE user_id=? ORDER BY created_at DESC,id DESC`
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		pointsHistory = fmt.Sprintf("%s LIMIT %d OFFSET %d", pointsHistory, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	} else {
+		pointsHistory = fmt.Sprintf("%s LIMIT 10 OFFSET 0", pointsHistory)
+	}
+	err = l.DB.Raw(pointsHistory, userId).Scan(&entries).Error
+	return entries, count, err
+}
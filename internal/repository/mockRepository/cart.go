@@ -0,0 +1,195 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/cart.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+)
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToCart mocks base method.
+func (m *MockCartRepository) AddToCart(productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCart", productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToCart indicates an expected call of AddToCart.
+func (mr *MockCartRepositoryMockRecorder) AddToCart(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCart", reflect.TypeOf((*MockCartRepository)(nil).AddToCart), productId, userId)
+}
+
+// CartCoupon mocks base method.
+func (m *MockCartRepository) CartCoupon(userId int) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartCoupon", userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CartCoupon indicates an expected call of CartCoupon.
+func (mr *MockCartRepositoryMockRecorder) CartCoupon(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartCoupon", reflect.TypeOf((*MockCartRepository)(nil).CartCoupon), userId)
+}
+
+// CartDiscounts mocks base method.
+func (m *MockCartRepository) CartDiscounts() ([]helperStruct.ApplicableDiscount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartDiscounts")
+	ret0, _ := ret[0].([]helperStruct.ApplicableDiscount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CartDiscounts indicates an expected call of CartDiscounts.
+func (mr *MockCartRepositoryMockRecorder) CartDiscounts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartDiscounts", reflect.TypeOf((*MockCartRepository)(nil).CartDiscounts))
+}
+
+// CartItems mocks base method.
+func (m *MockCartRepository) CartItems(userId int) ([]helperStruct.PricingItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartItems", userId)
+	ret0, _ := ret[0].([]helperStruct.PricingItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CartItems indicates an expected call of CartItems.
+func (mr *MockCartRepositoryMockRecorder) CartItems(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartItems", reflect.TypeOf((*MockCartRepository)(nil).CartItems), userId)
+}
+
+// CartPromotions mocks base method.
+func (m *MockCartRepository) CartPromotions() ([]helperStruct.ApplicablePromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartPromotions")
+	ret0, _ := ret[0].([]helperStruct.ApplicablePromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CartPromotions indicates an expected call of CartPromotions.
+func (mr *MockCartRepositoryMockRecorder) CartPromotions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartPromotions", reflect.TypeOf((*MockCartRepository)(nil).CartPromotions))
+}
+
+// CartTier mocks base method.
+func (m *MockCartRepository) CartTier(userId int) (helperStruct.ApplicableTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartTier", userId)
+	ret0, _ := ret[0].(helperStruct.ApplicableTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CartTier indicates an expected call of CartTier.
+func (mr *MockCartRepositoryMockRecorder) CartTier(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartTier", reflect.TypeOf((*MockCartRepository)(nil).CartTier), userId)
+}
+
+// ClearCartNotice mocks base method.
+func (m *MockCartRepository) ClearCartNotice(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCartNotice", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearCartNotice indicates an expected call of ClearCartNotice.
+func (mr *MockCartRepositoryMockRecorder) ClearCartNotice(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCartNotice", reflect.TypeOf((*MockCartRepository)(nil).ClearCartNotice), userId)
+}
+
+// CreateCart mocks base method.
+func (m *MockCartRepository) CreateCart(Id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCart", Id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCart indicates an expected call of CreateCart.
+func (mr *MockCartRepositoryMockRecorder) CreateCart(Id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCart", reflect.TypeOf((*MockCartRepository)(nil).CreateCart), Id)
+}
+
+// RemoveFromCart mocks base method.
+func (m *MockCartRepository) RemoveFromCart(productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCart", productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromCart indicates an expected call of RemoveFromCart.
+func (mr *MockCartRepositoryMockRecorder) RemoveFromCart(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCart", reflect.TypeOf((*MockCartRepository)(nil).RemoveFromCart), productId, userId)
+}
+
+// SetCartCoupon mocks base method.
+func (m *MockCartRepository) SetCartCoupon(userId int, couponId, codeId uint, notice string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCartCoupon", userId, couponId, codeId, notice)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCartCoupon indicates an expected call of SetCartCoupon.
+func (mr *MockCartRepositoryMockRecorder) SetCartCoupon(userId, couponId, codeId, notice interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCartCoupon", reflect.TypeOf((*MockCartRepository)(nil).SetCartCoupon), userId, couponId, codeId, notice)
+}
+
+// SetFreeItems mocks base method.
+func (m *MockCartRepository) SetFreeItems(userId int, freeUnits map[uint]int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFreeItems", userId, freeUnits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFreeItems indicates an expected call of SetFreeItems.
+func (mr *MockCartRepositoryMockRecorder) SetFreeItems(userId, freeUnits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFreeItems", reflect.TypeOf((*MockCartRepository)(nil).SetFreeItems), userId, freeUnits)
+}
@@ -0,0 +1,261 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/collection.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockCollectionRepository is a mock of CollectionRepository interface.
+type MockCollectionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionRepositoryMockRecorder
+}
+
+// MockCollectionRepositoryMockRecorder is the mock recorder for MockCollectionRepository.
+type MockCollectionRepositoryMockRecorder struct {
+	mock *MockCollectionRepository
+}
+
+// NewMockCollectionRepository creates a new mock instance.
+func NewMockCollectionRepository(ctrl *gomock.Controller) *MockCollectionRepository {
+	mock := &MockCollectionRepository{ctrl: ctrl}
+	mock.recorder = &MockCollectionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionRepository) EXPECT() *MockCollectionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ActiveBanners mocks base method.
+func (m *MockCollectionRepository) ActiveBanners() ([]response.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveBanners")
+	ret0, _ := ret[0].([]response.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveBanners indicates an expected call of ActiveBanners.
+func (mr *MockCollectionRepositoryMockRecorder) ActiveBanners() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveBanners", reflect.TypeOf((*MockCollectionRepository)(nil).ActiveBanners))
+}
+
+// ActiveCollection mocks base method.
+func (m *MockCollectionRepository) ActiveCollection(slug string) (response.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveCollection", slug)
+	ret0, _ := ret[0].(response.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveCollection indicates an expected call of ActiveCollection.
+func (mr *MockCollectionRepositoryMockRecorder) ActiveCollection(slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveCollection", reflect.TypeOf((*MockCollectionRepository)(nil).ActiveCollection), slug)
+}
+
+// AddBanner mocks base method.
+func (m *MockCollectionRepository) AddBanner(banner helperStruct.Banner) (response.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBanner", banner)
+	ret0, _ := ret[0].(response.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBanner indicates an expected call of AddBanner.
+func (mr *MockCollectionRepositoryMockRecorder) AddBanner(banner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBanner", reflect.TypeOf((*MockCollectionRepository)(nil).AddBanner), banner)
+}
+
+// AddCollection mocks base method.
+func (m *MockCollectionRepository) AddCollection(collection helperStruct.Collection) (response.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollection", collection)
+	ret0, _ := ret[0].(response.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCollection indicates an expected call of AddCollection.
+func (mr *MockCollectionRepositoryMockRecorder) AddCollection(collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollection", reflect.TypeOf((*MockCollectionRepository)(nil).AddCollection), collection)
+}
+
+// CollectionItems mocks base method.
+func (m *MockCollectionRepository) CollectionItems(collection response.Collection, queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectionItems", collection, queryParams)
+	ret0, _ := ret[0].([]response.ProductItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CollectionItems indicates an expected call of CollectionItems.
+func (mr *MockCollectionRepositoryMockRecorder) CollectionItems(collection, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectionItems", reflect.TypeOf((*MockCollectionRepository)(nil).CollectionItems), collection, queryParams)
+}
+
+// DeleteBanner mocks base method.
+func (m *MockCollectionRepository) DeleteBanner(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBanner", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBanner indicates an expected call of DeleteBanner.
+func (mr *MockCollectionRepositoryMockRecorder) DeleteBanner(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBanner", reflect.TypeOf((*MockCollectionRepository)(nil).DeleteBanner), id)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionRepository) DeleteCollection(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionRepositoryMockRecorder) DeleteCollection(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionRepository)(nil).DeleteCollection), id)
+}
+
+// DisplayCollection mocks base method.
+func (m *MockCollectionRepository) DisplayCollection(id int) (response.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayCollection", id)
+	ret0, _ := ret[0].(response.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayCollection indicates an expected call of DisplayCollection.
+func (mr *MockCollectionRepositoryMockRecorder) DisplayCollection(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayCollection", reflect.TypeOf((*MockCollectionRepository)(nil).DisplayCollection), id)
+}
+
+// HomeCollections mocks base method.
+func (m *MockCollectionRepository) HomeCollections() ([]response.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HomeCollections")
+	ret0, _ := ret[0].([]response.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HomeCollections indicates an expected call of HomeCollections.
+func (mr *MockCollectionRepositoryMockRecorder) HomeCollections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HomeCollections", reflect.TypeOf((*MockCollectionRepository)(nil).HomeCollections))
+}
+
+// ListAllBanners mocks base method.
+func (m *MockCollectionRepository) ListAllBanners(queryParams helperStruct.QueryParams) ([]response.Banner, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllBanners", queryParams)
+	ret0, _ := ret[0].([]response.Banner)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllBanners indicates an expected call of ListAllBanners.
+func (mr *MockCollectionRepositoryMockRecorder) ListAllBanners(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllBanners", reflect.TypeOf((*MockCollectionRepository)(nil).ListAllBanners), queryParams)
+}
+
+// ListAllCollections mocks base method.
+func (m *MockCollectionRepository) ListAllCollections(queryParams helperStruct.QueryParams) ([]response.Collection, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllCollections", queryParams)
+	ret0, _ := ret[0].([]response.Collection)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllCollections indicates an expected call of ListAllCollections.
+func (mr *MockCollectionRepositoryMockRecorder) ListAllCollections(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCollections", reflect.TypeOf((*MockCollectionRepository)(nil).ListAllCollections), queryParams)
+}
+
+// SetCollectionItems mocks base method.
+func (m *MockCollectionRepository) SetCollectionItems(id int, productItemIds []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCollectionItems", id, productItemIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCollectionItems indicates an expected call of SetCollectionItems.
+func (mr *MockCollectionRepositoryMockRecorder) SetCollectionItems(id, productItemIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCollectionItems", reflect.TypeOf((*MockCollectionRepository)(nil).SetCollectionItems), id, productItemIds)
+}
+
+// SetProductTags mocks base method.
+func (m *MockCollectionRepository) SetProductTags(productItemId int, tags []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductTags", productItemId, tags)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProductTags indicates an expected call of SetProductTags.
+func (mr *MockCollectionRepositoryMockRecorder) SetProductTags(productItemId, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductTags", reflect.TypeOf((*MockCollectionRepository)(nil).SetProductTags), productItemId, tags)
+}
+
+// UpdateBanner mocks base method.
+func (m *MockCollectionRepository) UpdateBanner(banner helperStruct.Banner, id int) (response.Banner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBanner", banner, id)
+	ret0, _ := ret[0].(response.Banner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBanner indicates an expected call of UpdateBanner.
+func (mr *MockCollectionRepositoryMockRecorder) UpdateBanner(banner, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBanner", reflect.TypeOf((*MockCollectionRepository)(nil).UpdateBanner), banner, id)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionRepository) UpdateCollection(collection helperStruct.Collection, id int) (response.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", collection, id)
+	ret0, _ := ret[0].(response.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionRepositoryMockRecorder) UpdateCollection(collection, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionRepository)(nil).UpdateCollection), collection, id)
+}
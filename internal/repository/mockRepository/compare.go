@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/compare.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	response "main.go/internal/common/response"
+)
+
+// MockCompareRepository is a mock of CompareRepository interface.
+type MockCompareRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCompareRepositoryMockRecorder
+}
+
+// MockCompareRepositoryMockRecorder is the mock recorder for MockCompareRepository.
+type MockCompareRepositoryMockRecorder struct {
+	mock *MockCompareRepository
+}
+
+// NewMockCompareRepository creates a new mock instance.
+func NewMockCompareRepository(ctrl *gomock.Controller) *MockCompareRepository {
+	mock := &MockCompareRepository{ctrl: ctrl}
+	mock.recorder = &MockCompareRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCompareRepository) EXPECT() *MockCompareRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToCompare mocks base method.
+func (m *MockCompareRepository) AddToCompare(userId, productItemId, maxItems int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCompare", userId, productItemId, maxItems)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToCompare indicates an expected call of AddToCompare.
+func (mr *MockCompareRepositoryMockRecorder) AddToCompare(userId, productItemId, maxItems interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCompare", reflect.TypeOf((*MockCompareRepository)(nil).AddToCompare), userId, productItemId, maxItems)
+}
+
+// ClearCompare mocks base method.
+func (m *MockCompareRepository) ClearCompare(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCompare", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearCompare indicates an expected call of ClearCompare.
+func (mr *MockCompareRepositoryMockRecorder) ClearCompare(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCompare", reflect.TypeOf((*MockCompareRepository)(nil).ClearCompare), userId)
+}
+
+// CompareItems mocks base method.
+func (m *MockCompareRepository) CompareItems(userId int) ([]response.ProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareItems", userId)
+	ret0, _ := ret[0].([]response.ProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareItems indicates an expected call of CompareItems.
+func (mr *MockCompareRepositoryMockRecorder) CompareItems(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareItems", reflect.TypeOf((*MockCompareRepository)(nil).CompareItems), userId)
+}
+
+// RemoveFromCompare mocks base method.
+func (m *MockCompareRepository) RemoveFromCompare(userId, productItemId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCompare", userId, productItemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromCompare indicates an expected call of RemoveFromCompare.
+func (mr *MockCompareRepositoryMockRecorder) RemoveFromCompare(userId, productItemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCompare", reflect.TypeOf((*MockCompareRepository)(nil).RemoveFromCompare), userId, productItemId)
+}
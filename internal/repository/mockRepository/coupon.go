@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/coupon.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockCouponRepository is a mock of CouponRepository interface.
+type MockCouponRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponRepositoryMockRecorder
+}
+
+// MockCouponRepositoryMockRecorder is the mock recorder for MockCouponRepository.
+type MockCouponRepositoryMockRecorder struct {
+	mock *MockCouponRepository
+}
+
+// NewMockCouponRepository creates a new mock instance.
+func NewMockCouponRepository(ctrl *gomock.Controller) *MockCouponRepository {
+	mock := &MockCouponRepository{ctrl: ctrl}
+	mock.recorder = &MockCouponRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponRepository) EXPECT() *MockCouponRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddCampaign mocks base method.
+func (m *MockCouponRepository) AddCampaign(campaign helperStruct.CouponCampaign, codes []string) (response.CouponCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCampaign", campaign, codes)
+	ret0, _ := ret[0].(response.CouponCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCampaign indicates an expected call of AddCampaign.
+func (mr *MockCouponRepositoryMockRecorder) AddCampaign(campaign, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCampaign", reflect.TypeOf((*MockCouponRepository)(nil).AddCampaign), campaign, codes)
+}
+
+// AddCoupon mocks base method.
+func (m *MockCouponRepository) AddCoupon(coupon helperStruct.Coupon) (response.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCoupon", coupon)
+	ret0, _ := ret[0].(response.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCoupon indicates an expected call of AddCoupon.
+func (mr *MockCouponRepositoryMockRecorder) AddCoupon(coupon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCoupon", reflect.TypeOf((*MockCouponRepository)(nil).AddCoupon), coupon)
+}
+
+// AvailableCoupons mocks base method.
+func (m *MockCouponRepository) AvailableCoupons(userId int) ([]response.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailableCoupons", userId)
+	ret0, _ := ret[0].([]response.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AvailableCoupons indicates an expected call of AvailableCoupons.
+func (mr *MockCouponRepositoryMockRecorder) AvailableCoupons(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailableCoupons", reflect.TypeOf((*MockCouponRepository)(nil).AvailableCoupons), userId)
+}
+
+// CampaignCodes mocks base method.
+func (m *MockCouponRepository) CampaignCodes(campaignId int) ([]response.CouponCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CampaignCodes", campaignId)
+	ret0, _ := ret[0].([]response.CouponCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CampaignCodes indicates an expected call of CampaignCodes.
+func (mr *MockCouponRepositoryMockRecorder) CampaignCodes(campaignId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CampaignCodes", reflect.TypeOf((*MockCouponRepository)(nil).CampaignCodes), campaignId)
+}
+
+// CouponFromName mocks base method.
+func (m *MockCouponRepository) CouponFromName(couponName string) (response.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CouponFromName", couponName)
+	ret0, _ := ret[0].(response.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CouponFromName indicates an expected call of CouponFromName.
+func (mr *MockCouponRepositoryMockRecorder) CouponFromName(couponName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CouponFromName", reflect.TypeOf((*MockCouponRepository)(nil).CouponFromName), couponName)
+}
+
+// CouponUsage mocks base method.
+func (m *MockCouponRepository) CouponUsage(userId int, couponId uint) (helperStruct.CouponUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CouponUsage", userId, couponId)
+	ret0, _ := ret[0].(helperStruct.CouponUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CouponUsage indicates an expected call of CouponUsage.
+func (mr *MockCouponRepositoryMockRecorder) CouponUsage(userId, couponId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CouponUsage", reflect.TypeOf((*MockCouponRepository)(nil).CouponUsage), userId, couponId)
+}
+
+// DisableCoupon mocks base method.
+func (m *MockCouponRepository) DisableCoupon(couponId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableCoupon", couponId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableCoupon indicates an expected call of DisableCoupon.
+func (mr *MockCouponRepositoryMockRecorder) DisableCoupon(couponId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableCoupon", reflect.TypeOf((*MockCouponRepository)(nil).DisableCoupon), couponId)
+}
+
+// DisplayCampaign mocks base method.
+func (m *MockCouponRepository) DisplayCampaign(campaignId int) (response.CouponCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayCampaign", campaignId)
+	ret0, _ := ret[0].(response.CouponCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayCampaign indicates an expected call of DisplayCampaign.
+func (mr *MockCouponRepositoryMockRecorder) DisplayCampaign(campaignId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayCampaign", reflect.TypeOf((*MockCouponRepository)(nil).DisplayCampaign), campaignId)
+}
+
+// DisplayCoupon mocks base method.
+func (m *MockCouponRepository) DisplayCoupon(couponId int) (response.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayCoupon", couponId)
+	ret0, _ := ret[0].(response.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayCoupon indicates an expected call of DisplayCoupon.
+func (mr *MockCouponRepositoryMockRecorder) DisplayCoupon(couponId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayCoupon", reflect.TypeOf((*MockCouponRepository)(nil).DisplayCoupon), couponId)
+}
+
+// EnableCoupon mocks base method.
+func (m *MockCouponRepository) EnableCoupon(couponId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableCoupon", couponId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableCoupon indicates an expected call of EnableCoupon.
+func (mr *MockCouponRepositoryMockRecorder) EnableCoupon(couponId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableCoupon", reflect.TypeOf((*MockCouponRepository)(nil).EnableCoupon), couponId)
+}
+
+// ExistingCodes mocks base method.
+func (m *MockCouponRepository) ExistingCodes(codes []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingCodes", codes)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingCodes indicates an expected call of ExistingCodes.
+func (mr *MockCouponRepositoryMockRecorder) ExistingCodes(codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingCodes", reflect.TypeOf((*MockCouponRepository)(nil).ExistingCodes), codes)
+}
+
+// ListAllCoupons mocks base method.
+func (m *MockCouponRepository) ListAllCoupons(queryParams helperStruct.QueryParams) ([]response.Coupon, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllCoupons", queryParams)
+	ret0, _ := ret[0].([]response.Coupon)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllCoupons indicates an expected call of ListAllCoupons.
+func (mr *MockCouponRepositoryMockRecorder) ListAllCoupons(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCoupons", reflect.TypeOf((*MockCouponRepository)(nil).ListAllCoupons), queryParams)
+}
+
+// ListCampaigns mocks base method.
+func (m *MockCouponRepository) ListCampaigns(queryParams helperStruct.QueryParams) ([]response.CouponCampaign, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCampaigns", queryParams)
+	ret0, _ := ret[0].([]response.CouponCampaign)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCampaigns indicates an expected call of ListCampaigns.
+func (mr *MockCouponRepositoryMockRecorder) ListCampaigns(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCampaigns", reflect.TypeOf((*MockCouponRepository)(nil).ListCampaigns), queryParams)
+}
+
+// UpdateCoupon mocks base method.
+func (m *MockCouponRepository) UpdateCoupon(coupon helperStruct.UpdateCoupon) (response.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCoupon", coupon)
+	ret0, _ := ret[0].(response.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCoupon indicates an expected call of UpdateCoupon.
+func (mr *MockCouponRepositoryMockRecorder) UpdateCoupon(coupon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCoupon", reflect.TypeOf((*MockCouponRepository)(nil).UpdateCoupon), coupon)
+}
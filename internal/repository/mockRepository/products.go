@@ -0,0 +1,485 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/products.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockProductRepository is a mock of ProductRepository interface.
+type MockProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductRepositoryMockRecorder
+}
+
+// MockProductRepositoryMockRecorder is the mock recorder for MockProductRepository.
+type MockProductRepositoryMockRecorder struct {
+	mock *MockProductRepository
+}
+
+// NewMockProductRepository creates a new mock instance.
+func NewMockProductRepository(ctrl *gomock.Controller) *MockProductRepository {
+	mock := &MockProductRepository{ctrl: ctrl}
+	mock.recorder = &MockProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddProduct mocks base method.
+func (m *MockProductRepository) AddProduct(product helperStruct.Product) (response.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProduct", product)
+	ret0, _ := ret[0].(response.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddProduct indicates an expected call of AddProduct.
+func (mr *MockProductRepositoryMockRecorder) AddProduct(product interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProduct", reflect.TypeOf((*MockProductRepository)(nil).AddProduct), product)
+}
+
+// AddProductItem mocks base method.
+func (m *MockProductRepository) AddProductItem(productItem helperStruct.ProductItem) (response.ProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddProductItem", productItem)
+	ret0, _ := ret[0].(response.ProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddProductItem indicates an expected call of AddProductItem.
+func (mr *MockProductRepositoryMockRecorder) AddProductItem(productItem interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddProductItem", reflect.TypeOf((*MockProductRepository)(nil).AddProductItem), productItem)
+}
+
+// CreateBrand mocks base method.
+func (m *MockProductRepository) CreateBrand(brand helperStruct.Brand) (response.Brand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBrand", brand)
+	ret0, _ := ret[0].(response.Brand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBrand indicates an expected call of CreateBrand.
+func (mr *MockProductRepositoryMockRecorder) CreateBrand(brand interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBrand", reflect.TypeOf((*MockProductRepository)(nil).CreateBrand), brand)
+}
+
+// CreateCategory mocks base method.
+func (m *MockProductRepository) CreateCategory(category helperStruct.Category) (response.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", category)
+	ret0, _ := ret[0].(response.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockProductRepositoryMockRecorder) CreateCategory(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockProductRepository)(nil).CreateCategory), category)
+}
+
+// DeleteBrand mocks base method.
+func (m *MockProductRepository) DeleteBrand(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBrand", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBrand indicates an expected call of DeleteBrand.
+func (mr *MockProductRepositoryMockRecorder) DeleteBrand(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBrand", reflect.TypeOf((*MockProductRepository)(nil).DeleteBrand), id)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockProductRepository) DeleteCategory(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockProductRepositoryMockRecorder) DeleteCategory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockProductRepository)(nil).DeleteCategory), id)
+}
+
+// DeleteImage mocks base method.
+func (m *MockProductRepository) DeleteImage(id int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteImage", id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteImage indicates an expected call of DeleteImage.
+func (mr *MockProductRepositoryMockRecorder) DeleteImage(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteImage", reflect.TypeOf((*MockProductRepository)(nil).DeleteImage), id)
+}
+
+// DeleteProduct mocks base method.
+func (m *MockProductRepository) DeleteProduct(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProduct", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProduct indicates an expected call of DeleteProduct.
+func (mr *MockProductRepositoryMockRecorder) DeleteProduct(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProduct", reflect.TypeOf((*MockProductRepository)(nil).DeleteProduct), id)
+}
+
+// DeleteProductItem mocks base method.
+func (m *MockProductRepository) DeleteProductItem(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProductItem", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProductItem indicates an expected call of DeleteProductItem.
+func (mr *MockProductRepositoryMockRecorder) DeleteProductItem(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProductItem", reflect.TypeOf((*MockProductRepository)(nil).DeleteProductItem), id)
+}
+
+// DisplayBrand mocks base method.
+func (m *MockProductRepository) DisplayBrand(id int) (response.Brand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayBrand", id)
+	ret0, _ := ret[0].(response.Brand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayBrand indicates an expected call of DisplayBrand.
+func (mr *MockProductRepositoryMockRecorder) DisplayBrand(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayBrand", reflect.TypeOf((*MockProductRepository)(nil).DisplayBrand), id)
+}
+
+// DisplayCategory mocks base method.
+func (m *MockProductRepository) DisplayCategory(id int) (response.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayCategory", id)
+	ret0, _ := ret[0].(response.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayCategory indicates an expected call of DisplayCategory.
+func (mr *MockProductRepositoryMockRecorder) DisplayCategory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayCategory", reflect.TypeOf((*MockProductRepository)(nil).DisplayCategory), id)
+}
+
+// DisplayProduct mocks base method.
+func (m *MockProductRepository) DisplayProduct(id int) (response.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayProduct", id)
+	ret0, _ := ret[0].(response.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayProduct indicates an expected call of DisplayProduct.
+func (mr *MockProductRepositoryMockRecorder) DisplayProduct(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayProduct", reflect.TypeOf((*MockProductRepository)(nil).DisplayProduct), id)
+}
+
+// DisplayProductItem mocks base method.
+func (m *MockProductRepository) DisplayProductItem(id int) (response.DisplayProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayProductItem", id)
+	ret0, _ := ret[0].(response.DisplayProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayProductItem indicates an expected call of DisplayProductItem.
+func (mr *MockProductRepositoryMockRecorder) DisplayProductItem(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayProductItem", reflect.TypeOf((*MockProductRepository)(nil).DisplayProductItem), id)
+}
+
+// ListAllBrands mocks base method.
+func (m *MockProductRepository) ListAllBrands(queryParams helperStruct.QueryParams) ([]response.Brand, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllBrands", queryParams)
+	ret0, _ := ret[0].([]response.Brand)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllBrands indicates an expected call of ListAllBrands.
+func (mr *MockProductRepositoryMockRecorder) ListAllBrands(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllBrands", reflect.TypeOf((*MockProductRepository)(nil).ListAllBrands), queryParams)
+}
+
+// ListAllCategories mocks base method.
+func (m *MockProductRepository) ListAllCategories() ([]response.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllCategories")
+	ret0, _ := ret[0].([]response.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllCategories indicates an expected call of ListAllCategories.
+func (mr *MockProductRepositoryMockRecorder) ListAllCategories() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCategories", reflect.TypeOf((*MockProductRepository)(nil).ListAllCategories))
+}
+
+// ListAllProductItems mocks base method.
+func (m *MockProductRepository) ListAllProductItems(queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllProductItems", queryParams)
+	ret0, _ := ret[0].([]response.ProductItem)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllProductItems indicates an expected call of ListAllProductItems.
+func (mr *MockProductRepositoryMockRecorder) ListAllProductItems(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllProductItems", reflect.TypeOf((*MockProductRepository)(nil).ListAllProductItems), queryParams)
+}
+
+// ListAllProducts mocks base method.
+func (m *MockProductRepository) ListAllProducts(queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllProducts", queryParams)
+	ret0, _ := ret[0].([]response.Product)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllProducts indicates an expected call of ListAllProducts.
+func (mr *MockProductRepositoryMockRecorder) ListAllProducts(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllProducts", reflect.TypeOf((*MockProductRepository)(nil).ListAllProducts), queryParams)
+}
+
+// MoveCategory mocks base method.
+func (m *MockProductRepository) MoveCategory(move helperStruct.MoveCategory, id int) (response.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveCategory", move, id)
+	ret0, _ := ret[0].(response.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveCategory indicates an expected call of MoveCategory.
+func (mr *MockProductRepositoryMockRecorder) MoveCategory(move, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveCategory", reflect.TypeOf((*MockProductRepository)(nil).MoveCategory), move, id)
+}
+
+// PersonalizedProducts mocks base method.
+func (m *MockProductRepository) PersonalizedProducts(userId int, queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PersonalizedProducts", userId, queryParams)
+	ret0, _ := ret[0].([]response.Product)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PersonalizedProducts indicates an expected call of PersonalizedProducts.
+func (mr *MockProductRepositoryMockRecorder) PersonalizedProducts(userId, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PersonalizedProducts", reflect.TypeOf((*MockProductRepository)(nil).PersonalizedProducts), userId, queryParams)
+}
+
+// PriceHistory mocks base method.
+func (m *MockProductRepository) PriceHistory(productItemId, days int) ([]response.PricePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PriceHistory", productItemId, days)
+	ret0, _ := ret[0].([]response.PricePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PriceHistory indicates an expected call of PriceHistory.
+func (mr *MockProductRepositoryMockRecorder) PriceHistory(productItemId, days interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PriceHistory", reflect.TypeOf((*MockProductRepository)(nil).PriceHistory), productItemId, days)
+}
+
+// RecentlyViewed mocks base method.
+func (m *MockProductRepository) RecentlyViewed(userId, limit int) ([]response.ProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecentlyViewed", userId, limit)
+	ret0, _ := ret[0].([]response.ProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecentlyViewed indicates an expected call of RecentlyViewed.
+func (mr *MockProductRepositoryMockRecorder) RecentlyViewed(userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentlyViewed", reflect.TypeOf((*MockProductRepository)(nil).RecentlyViewed), userId, limit)
+}
+
+// RecordPriceChanges mocks base method.
+func (m *MockProductRepository) RecordPriceChanges() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPriceChanges")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPriceChanges indicates an expected call of RecordPriceChanges.
+func (mr *MockProductRepositoryMockRecorder) RecordPriceChanges() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPriceChanges", reflect.TypeOf((*MockProductRepository)(nil).RecordPriceChanges))
+}
+
+// RecordView mocks base method.
+func (m *MockProductRepository) RecordView(userId, productItemId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordView", userId, productItemId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordView indicates an expected call of RecordView.
+func (mr *MockProductRepositoryMockRecorder) RecordView(userId, productItemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordView", reflect.TypeOf((*MockProductRepository)(nil).RecordView), userId, productItemId)
+}
+
+// ResolveSlug mocks base method.
+func (m *MockProductRepository) ResolveSlug(entity, slug string) (int, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSlug", entity, slug)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ResolveSlug indicates an expected call of ResolveSlug.
+func (mr *MockProductRepositoryMockRecorder) ResolveSlug(entity, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSlug", reflect.TypeOf((*MockProductRepository)(nil).ResolveSlug), entity, slug)
+}
+
+// SearchProducts mocks base method.
+func (m *MockProductRepository) SearchProducts(queryParams helperStruct.QueryParams, searchProducts string) ([]response.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchProducts", queryParams, searchProducts)
+	ret0, _ := ret[0].([]response.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchProducts indicates an expected call of SearchProducts.
+func (mr *MockProductRepositoryMockRecorder) SearchProducts(queryParams, searchProducts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchProducts", reflect.TypeOf((*MockProductRepository)(nil).SearchProducts), queryParams, searchProducts)
+}
+
+// UpdateBrand mocks base method.
+func (m *MockProductRepository) UpdateBrand(brand helperStruct.Brand, id int) (response.Brand, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBrand", brand, id)
+	ret0, _ := ret[0].(response.Brand)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBrand indicates an expected call of UpdateBrand.
+func (mr *MockProductRepositoryMockRecorder) UpdateBrand(brand, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBrand", reflect.TypeOf((*MockProductRepository)(nil).UpdateBrand), brand, id)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockProductRepository) UpdateCategory(category helperStruct.Category, id int) (response.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", category, id)
+	ret0, _ := ret[0].(response.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockProductRepositoryMockRecorder) UpdateCategory(category, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockProductRepository)(nil).UpdateCategory), category, id)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockProductRepository) UpdateProduct(product helperStruct.Product, id int) (response.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", product, id)
+	ret0, _ := ret[0].(response.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockProductRepositoryMockRecorder) UpdateProduct(product, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductRepository)(nil).UpdateProduct), product, id)
+}
+
+// UpdateProductItem mocks base method.
+func (m *MockProductRepository) UpdateProductItem(id int, productItem helperStruct.ProductItem) (response.ProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProductItem", id, productItem)
+	ret0, _ := ret[0].(response.ProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProductItem indicates an expected call of UpdateProductItem.
+func (mr *MockProductRepositoryMockRecorder) UpdateProductItem(id, productItem interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductItem", reflect.TypeOf((*MockProductRepository)(nil).UpdateProductItem), id, productItem)
+}
+
+// UploadImage mocks base method.
+func (m *MockProductRepository) UploadImage(filepath string, productid int) (response.Image, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadImage", filepath, productid)
+	ret0, _ := ret[0].(response.Image)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadImage indicates an expected call of UploadImage.
+func (mr *MockProductRepositoryMockRecorder) UploadImage(filepath, productid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadImage", reflect.TypeOf((*MockProductRepository)(nil).UploadImage), filepath, productid)
+}
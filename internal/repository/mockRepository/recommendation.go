@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/recommendation.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockRecommendationRepository is a mock of RecommendationRepository interface.
+type MockRecommendationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationRepositoryMockRecorder
+}
+
+// MockRecommendationRepositoryMockRecorder is the mock recorder for MockRecommendationRepository.
+type MockRecommendationRepositoryMockRecorder struct {
+	mock *MockRecommendationRepository
+}
+
+// NewMockRecommendationRepository creates a new mock instance.
+func NewMockRecommendationRepository(ctrl *gomock.Controller) *MockRecommendationRepository {
+	mock := &MockRecommendationRepository{ctrl: ctrl}
+	mock.recorder = &MockRecommendationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendationRepository) EXPECT() *MockRecommendationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddOverride mocks base method.
+func (m *MockRecommendationRepository) AddOverride(override helperStruct.RecommendationOverride) (response.RecommendationOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOverride", override)
+	ret0, _ := ret[0].(response.RecommendationOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOverride indicates an expected call of AddOverride.
+func (mr *MockRecommendationRepositoryMockRecorder) AddOverride(override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOverride", reflect.TypeOf((*MockRecommendationRepository)(nil).AddOverride), override)
+}
+
+// CartProductItems mocks base method.
+func (m *MockRecommendationRepository) CartProductItems(userId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CartProductItems", userId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CartProductItems indicates an expected call of CartProductItems.
+func (mr *MockRecommendationRepositoryMockRecorder) CartProductItems(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CartProductItems", reflect.TypeOf((*MockRecommendationRepository)(nil).CartProductItems), userId)
+}
+
+// DeleteOverride mocks base method.
+func (m *MockRecommendationRepository) DeleteOverride(id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOverride", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOverride indicates an expected call of DeleteOverride.
+func (mr *MockRecommendationRepositoryMockRecorder) DeleteOverride(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOverride", reflect.TypeOf((*MockRecommendationRepository)(nil).DeleteOverride), id)
+}
+
+// ListOverrides mocks base method.
+func (m *MockRecommendationRepository) ListOverrides(productItemId int) ([]response.RecommendationOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverrides", productItemId)
+	ret0, _ := ret[0].([]response.RecommendationOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverrides indicates an expected call of ListOverrides.
+func (mr *MockRecommendationRepositoryMockRecorder) ListOverrides(productItemId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverrides", reflect.TypeOf((*MockRecommendationRepository)(nil).ListOverrides), productItemId)
+}
+
+// Recommendations mocks base method.
+func (m *MockRecommendationRepository) Recommendations(productItemIds []int, kind string, limit int) ([]response.ProductItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recommendations", productItemIds, kind, limit)
+	ret0, _ := ret[0].([]response.ProductItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recommendations indicates an expected call of Recommendations.
+func (mr *MockRecommendationRepositoryMockRecorder) Recommendations(productItemIds, kind, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recommendations", reflect.TypeOf((*MockRecommendationRepository)(nil).Recommendations), productItemIds, kind, limit)
+}
+
+// RefreshRecommendations mocks base method.
+func (m *MockRecommendationRepository) RefreshRecommendations() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshRecommendations")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshRecommendations indicates an expected call of RefreshRecommendations.
+func (mr *MockRecommendationRepositoryMockRecorder) RefreshRecommendations() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshRecommendations", reflect.TypeOf((*MockRecommendationRepository)(nil).RefreshRecommendations))
+}
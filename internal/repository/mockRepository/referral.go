@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/referral.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockReferralRepository is a mock of ReferralRepository interface.
+type MockReferralRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferralRepositoryMockRecorder
+}
+
+// MockReferralRepositoryMockRecorder is the mock recorder for MockReferralRepository.
+type MockReferralRepositoryMockRecorder struct {
+	mock *MockReferralRepository
+}
+
+// NewMockReferralRepository creates a new mock instance.
+func NewMockReferralRepository(ctrl *gomock.Controller) *MockReferralRepository {
+	mock := &MockReferralRepository{ctrl: ctrl}
+	mock.recorder = &MockReferralRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferralRepository) EXPECT() *MockReferralRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddReferral mocks base method.
+func (m *MockReferralRepository) AddReferral(userId int, referralId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReferral", userId, referralId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReferral indicates an expected call of AddReferral.
+func (mr *MockReferralRepositoryMockRecorder) AddReferral(userId, referralId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReferral", reflect.TypeOf((*MockReferralRepository)(nil).AddReferral), userId, referralId)
+}
+
+// ReferralCodeExists mocks base method.
+func (m *MockReferralRepository) ReferralCodeExists(referralId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralCodeExists", referralId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralCodeExists indicates an expected call of ReferralCodeExists.
+func (mr *MockReferralRepositoryMockRecorder) ReferralCodeExists(referralId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralCodeExists", reflect.TypeOf((*MockReferralRepository)(nil).ReferralCodeExists), referralId)
+}
+
+// ReferralDashboard mocks base method.
+func (m *MockReferralRepository) ReferralDashboard(userId int) (response.ReferralDashboard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralDashboard", userId)
+	ret0, _ := ret[0].(response.ReferralDashboard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralDashboard indicates an expected call of ReferralDashboard.
+func (mr *MockReferralRepositoryMockRecorder) ReferralDashboard(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralDashboard", reflect.TypeOf((*MockReferralRepository)(nil).ReferralDashboard), userId)
+}
+
+// ReferralOffer mocks base method.
+func (m *MockReferralRepository) ReferralOffer(referralId string, userId int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralOffer", referralId, userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralOffer indicates an expected call of ReferralOffer.
+func (mr *MockReferralRepositoryMockRecorder) ReferralOffer(referralId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralOffer", reflect.TypeOf((*MockReferralRepository)(nil).ReferralOffer), referralId, userId)
+}
+
+// ReferralSettings mocks base method.
+func (m *MockReferralRepository) ReferralSettings() (response.ReferralSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralSettings")
+	ret0, _ := ret[0].(response.ReferralSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralSettings indicates an expected call of ReferralSettings.
+func (mr *MockReferralRepositoryMockRecorder) ReferralSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralSettings", reflect.TypeOf((*MockReferralRepository)(nil).ReferralSettings))
+}
+
+// ReleaseReferralRewards mocks base method.
+func (m *MockReferralRepository) ReleaseReferralRewards() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseReferralRewards")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseReferralRewards indicates an expected call of ReleaseReferralRewards.
+func (mr *MockReferralRepositoryMockRecorder) ReleaseReferralRewards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseReferralRewards", reflect.TypeOf((*MockReferralRepository)(nil).ReleaseReferralRewards))
+}
+
+// UpdateReferralCode mocks base method.
+func (m *MockReferralRepository) UpdateReferralCode(userId int, referralId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReferralCode", userId, referralId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReferralCode indicates an expected call of UpdateReferralCode.
+func (mr *MockReferralRepositoryMockRecorder) UpdateReferralCode(userId, referralId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReferralCode", reflect.TypeOf((*MockReferralRepository)(nil).UpdateReferralCode), userId, referralId)
+}
+
+// UpdateReferralSettings mocks base method.
+func (m *MockReferralRepository) UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReferralSettings", settings)
+	ret0, _ := ret[0].(response.ReferralSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReferralSettings indicates an expected call of UpdateReferralSettings.
+func (mr *MockReferralRepositoryMockRecorder) UpdateReferralSettings(settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReferralSettings", reflect.TypeOf((*MockReferralRepository)(nil).UpdateReferralSettings), settings)
+}
@@ -0,0 +1,167 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/interfaces/wishlist.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockWishlistRepository is a mock of WishlistRepository interface.
+type MockWishlistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWishlistRepositoryMockRecorder
+}
+
+// MockWishlistRepositoryMockRecorder is the mock recorder for MockWishlistRepository.
+type MockWishlistRepositoryMockRecorder struct {
+	mock *MockWishlistRepository
+}
+
+// NewMockWishlistRepository creates a new mock instance.
+func NewMockWishlistRepository(ctrl *gomock.Controller) *MockWishlistRepository {
+	mock := &MockWishlistRepository{ctrl: ctrl}
+	mock.recorder = &MockWishlistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWishlistRepository) EXPECT() *MockWishlistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddToWishlist mocks base method.
+func (m *MockWishlistRepository) AddToWishlist(productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToWishlist", productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToWishlist indicates an expected call of AddToWishlist.
+func (mr *MockWishlistRepositoryMockRecorder) AddToWishlist(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToWishlist", reflect.TypeOf((*MockWishlistRepository)(nil).AddToWishlist), productId, userId)
+}
+
+// DisplayWishlistProduct mocks base method.
+func (m *MockWishlistRepository) DisplayWishlistProduct(productId, userId int) (response.Wishlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayWishlistProduct", productId, userId)
+	ret0, _ := ret[0].(response.Wishlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayWishlistProduct indicates an expected call of DisplayWishlistProduct.
+func (mr *MockWishlistRepositoryMockRecorder) DisplayWishlistProduct(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayWishlistProduct", reflect.TypeOf((*MockWishlistRepository)(nil).DisplayWishlistProduct), productId, userId)
+}
+
+// DuePriceAlerts mocks base method.
+func (m *MockWishlistRepository) DuePriceAlerts() ([]response.PriceDrop, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DuePriceAlerts")
+	ret0, _ := ret[0].([]response.PriceDrop)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DuePriceAlerts indicates an expected call of DuePriceAlerts.
+func (mr *MockWishlistRepositoryMockRecorder) DuePriceAlerts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DuePriceAlerts", reflect.TypeOf((*MockWishlistRepository)(nil).DuePriceAlerts))
+}
+
+// ListAllWishlist mocks base method.
+func (m *MockWishlistRepository) ListAllWishlist(userId int) ([]response.Wishlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllWishlist", userId)
+	ret0, _ := ret[0].([]response.Wishlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllWishlist indicates an expected call of ListAllWishlist.
+func (mr *MockWishlistRepositoryMockRecorder) ListAllWishlist(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllWishlist", reflect.TypeOf((*MockWishlistRepository)(nil).ListAllWishlist), userId)
+}
+
+// ListPriceAlerts mocks base method.
+func (m *MockWishlistRepository) ListPriceAlerts(userId int) ([]response.PriceAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceAlerts", userId)
+	ret0, _ := ret[0].([]response.PriceAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriceAlerts indicates an expected call of ListPriceAlerts.
+func (mr *MockWishlistRepositoryMockRecorder) ListPriceAlerts(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceAlerts", reflect.TypeOf((*MockWishlistRepository)(nil).ListPriceAlerts), userId)
+}
+
+// MarkPriceAlertNotified mocks base method.
+func (m *MockWishlistRepository) MarkPriceAlertNotified(alertId int, price float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPriceAlertNotified", alertId, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPriceAlertNotified indicates an expected call of MarkPriceAlertNotified.
+func (mr *MockWishlistRepositoryMockRecorder) MarkPriceAlertNotified(alertId, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPriceAlertNotified", reflect.TypeOf((*MockWishlistRepository)(nil).MarkPriceAlertNotified), alertId, price)
+}
+
+// RemoveFromWishlist mocks base method.
+func (m *MockWishlistRepository) RemoveFromWishlist(productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromWishlist", productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromWishlist indicates an expected call of RemoveFromWishlist.
+func (mr *MockWishlistRepositoryMockRecorder) RemoveFromWishlist(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromWishlist", reflect.TypeOf((*MockWishlistRepository)(nil).RemoveFromWishlist), productId, userId)
+}
+
+// RemovePriceAlert mocks base method.
+func (m *MockWishlistRepository) RemovePriceAlert(productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePriceAlert", productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePriceAlert indicates an expected call of RemovePriceAlert.
+func (mr *MockWishlistRepositoryMockRecorder) RemovePriceAlert(productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePriceAlert", reflect.TypeOf((*MockWishlistRepository)(nil).RemovePriceAlert), productId, userId)
+}
+
+// SetPriceAlert mocks base method.
+func (m *MockWishlistRepository) SetPriceAlert(productId, userId int, alert helperStruct.PriceAlert) (response.PriceAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPriceAlert", productId, userId, alert)
+	ret0, _ := ret[0].(response.PriceAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPriceAlert indicates an expected call of SetPriceAlert.
+func (mr *MockWishlistRepositoryMockRecorder) SetPriceAlert(productId, userId, alert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPriceAlert", reflect.TypeOf((*MockWishlistRepository)(nil).SetPriceAlert), productId, userId, alert)
+}
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 	"main.go/internal/common/helperStruct"
@@ -11,6 +13,124 @@ import (
 	"main.go/internal/repository/interfaces"
 )
 
+// defaultReturnWindowDays is how long after delivery an order can be returned,
+// loyalty tiers can give their members longer.
+const defaultReturnWindowDays = 7
+
+// returnWindowDays is how many days a user has to return a delivered order,
+// it needs users in the query.
+const returnWindowDays = `(SELECT GREATEST(?,COALESCE(MAX(loyalty_tiers.return_window_days),0)) FROM loyalty_tiers WHERE loyalty_tiers.id=users.loyalty_tier_id)`
+
+// finalOrderStatuses are delivered, cancelled and returned, orders in them
+// can't be cancelled.
+var finalOrderStatuses = map[uint]bool{4: true, 5: true, 6: true}
+
+// splitRefund splits amount over payment legs in proportion to what is still
+// refundable on each, never giving a leg back more than it paid.
+func splitRefund(amount int, refundable []int) []int {
+	total := 0
+	for _, legAmount := range refundable {
+		total += legAmount
+	}
+	refunds := make([]int, len(refundable))
+	if total == 0 || amount <= 0 {
+		return refunds
+	}
+	if amount > total {
+		amount = total
+	}
+	left := amount
+	for i, legAmount := range refundable {
+		refunds[i] = amount * legAmount / total
+		left -= refunds[i]
+	}
+	// rounding leaves a few rupees over, they go to the first legs with room
+	for i := 0; left > 0 && i < len(refundable); i++ {
+		extra := refundable[i] - refunds[i]
+		if extra > left {
+			extra = left
+		}
+		refunds[i] += extra
+		left -= extra
+	}
+	return refunds
+}
+
+// refundPaymentLegs gives back amount of an order's payment inside tx, split
+// over its paid legs by splitRefund, and cancels the legs that were never
+// paid. It returns how much was refunded.
+//
+// Each leg is refunded through the method it was paid with. The wallet leg
+// is credited back to the wallet, Razorpay legs get a pending payment refund
+// that is sent to the gateway once tx commits. Cash paid on delivery has no
+// way back through the store, so COD legs are credited to the wallet. Each
+// refund is keyed by the leg and how much of it was already refunded, so
+// later refunds on a leg are recorded while a retried one is not.
+func refundPaymentLegs(tx *gorm.DB, userId, orderId, amount int, reason string) (int, error) {
+	var legs []struct {
+		PaymentTypeId  int
+		PaymentType    string
+		PaymentRef     string
+		Amount         int
+		RefundedAmount int
+	}
+	err := tx.Raw(`SELECT payment_details.payment_type_id,payment_types.type AS payment_type,payment_details.payment_ref,payment_details.amount,payment_details.refunded_amount
+	FROM payment_details LEFT JOIN payment_types ON payment_types.id=payment_details.payment_type_id
+	WHERE payment_details.orders_id=$1 AND payment_details.payment_status_id IN (4,5) AND payment_details.amount>payment_details.refunded_amount
+	ORDER BY payment_details.payment_type_id=$2 DESC,payment_details.payment_type_id`,
+		orderId, domain.WalletPaymentTypeId).Scan(&legs).Error
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving payment details")
+	}
+	refundable := make([]int, len(legs))
+	for i, leg := range legs {
+		refundable[i] = leg.Amount - leg.RefundedAmount
+	}
+	refunded := 0
+	for i, refund := range splitRefund(amount, refundable) {
+		if refund == 0 {
+			continue
+		}
+		key := fmt.Sprintf("order:%d:refund:%s:%d:%d", orderId, reason, legs[i].PaymentTypeId, legs[i].RefundedAmount)
+		switch legs[i].PaymentTypeId {
+		case domain.RazorpayPaymentTypeId:
+			insertRefund := `INSERT INTO payment_refunds (orders_id,payment_type_id,payment_ref,amount,idempotency_key,status,created_at,updated_at)
+			VALUES ($1,$2,$3,$4,$5,'pending',NOW(),NOW()) ON CONFLICT (idempotency_key) DO NOTHING`
+			err = tx.Exec(insertRefund, orderId, legs[i].PaymentTypeId, legs[i].PaymentRef, refund, key).Error
+			if err != nil {
+				return 0, fmt.Errorf("error recording payment refund")
+			}
+		default:
+			description := fmt.Sprintf("refund for %s order %d", reason, orderId)
+			if legs[i].PaymentTypeId != domain.WalletPaymentTypeId {
+				description = fmt.Sprintf("refund of the %s payment for %s order %d, credited to the wallet", legs[i].PaymentType, reason, orderId)
+			}
+			err = postWalletTransaction(tx, walletPosting{
+				UserId:      userId,
+				Type:        walletRefund,
+				Amount:      refund,
+				OrderId:     uint(orderId),
+				Key:         key,
+				Description: description,
+			})
+			if err != nil {
+				return 0, err
+			}
+		}
+		updateLeg := `UPDATE payment_details SET refunded_amount=refunded_amount+$1,payment_status_id=4,updated_at=NOW() WHERE orders_id=$2 AND payment_type_id=$3`
+		err = tx.Exec(updateLeg, refund, orderId, legs[i].PaymentTypeId).Error
+		if err != nil {
+			return 0, fmt.Errorf("error updating payment details")
+		}
+		refunded += refund
+	}
+	err = tx.Exec(`UPDATE payment_details SET payment_status_id=3,updated_at=NOW() WHERE orders_id=$1 AND payment_status_id NOT IN (4,5)`, orderId).Error
+	if err != nil {
+		return 0, fmt.Errorf("error updating payment details")
+	}
+	return refunded, nil
+}
+
 type orderDatabase struct {
 	DB *gorm.DB
 }
@@ -22,7 +142,7 @@ func NewOrderRepo(DB *gorm.DB) interfaces.OrderRepository {
 }
 
 // OrderAll implements interfaces.OrderRepository.
-func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coupon) (response.ResponseOrder, error) {
+func (c *orderDatabase) OrderAll(id int, paymentTypeid int, walletAmount int, coupon response.Coupon, pricing response.PriceBreakdown) (response.ResponseOrder, error) {
 	tx := c.DB.Begin()
 	var cart domain.Carts
 	findCart := `SELECT * FROM carts WHERE user_id=?`
@@ -31,63 +151,10 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 		tx.Rollback()
 		return response.ResponseOrder{}, err
 	}
-	if cart.Total == 0 {
-		setTotal := `UPDATE carts SET total=sub_total WHERE user_id=?`
-		err = tx.Exec(setTotal, id).Error
-		if err != nil {
-			tx.Rollback()
-			return response.ResponseOrder{}, err
-		}
-	}
-	if cart.SubTotal == 0 {
+	if len(pricing.Lines) == 0 {
 		tx.Rollback()
 		return response.ResponseOrder{}, fmt.Errorf("there are no items in cart")
 	}
-	var productDetails []response.DisplayCart
-	getProductDetails := `SELECT 
-    p.brand,
-    pr.product_name,
-    pi.sku AS product_sku,
-    pi.color,
-    pi.ram,
-    pi.battery,
-    pi.graphic_processor,
-    pi.storage,
-    ci.quantity,
-    pi.price AS price_per_unit,
-    (pi.price * ci.quantity) AS total ,
-	((pi.price*discount_percent)/100)*(ci.quantity) AS discount_price,
-	(pi.price * ci.quantity)-((pi.price*discount_percent)/100)*(ci.quantity) AS discounted_price
-   FROM 
-    cart_items ci 
-    JOIN 
-    product_items pi ON ci.product_item_id = pi.id
-    JOIN 
-    products pr ON pi.product_id = pr.id
-    JOIN 
-    products p ON pi.product_id = p.id 
-	LEFT JOIN
-	brands ON brands.brandname=p.brand
-	LEFT JOIN 
-	discounts ON discounts.brand_id=brands.id AND expiry_date>NOW()
-    WHERE 
-    ci.carts_id = $1`
-	err = tx.Raw(getProductDetails, cart.Id).Scan(&productDetails).Error
-	if err != nil {
-		tx.Rollback()
-		return response.ResponseOrder{}, err
-	}
-	var totalWithDiscount float64
-	var totalDiscountAmount float64
-	for _, item := range productDetails {
-		if item.DiscountedPrice != 0 {
-			item.Total = item.DiscountedPrice
-			totalDiscountAmount += item.DiscountPrice
-
-		}
-		totalWithDiscount += item.Total
-	}
-	cart.Total = int(totalWithDiscount)
 	var addressId int
 	findAddress := `SELECT id FROM addresses WHERE users_id=? AND is_default=true`
 	err = tx.Raw(findAddress, id).Scan(&addressId).Error
@@ -100,70 +167,59 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 		return response.ResponseOrder{}, fmt.Errorf("please add an address to complete your order")
 	}
 	if coupon.Id != 0 {
-		var exists bool
-		tx.Raw(`SELECT EXISTS (select 1 from user_coupons WHERE user_id=$1 AND coupon_id=$2)`, id, coupon.Id).Scan(&exists)
-		if coupon.Quantity == 0 {
+		// the coupon rules were checked against the cart, this only makes sure the last use isn't taken twice
+		claim := tx.Exec(`UPDATE coupons SET quantity=quantity-1 WHERE id=? AND quantity>0`, coupon.Id)
+		if claim.Error != nil {
 			tx.Rollback()
-			return response.ResponseOrder{}, fmt.Errorf("this coupon has expired")
+			return response.ResponseOrder{}, fmt.Errorf("can't add this coupon")
 		}
-		if coupon.IsDisabled {
+		if claim.RowsAffected == 0 {
 			tx.Rollback()
-			return response.ResponseOrder{}, fmt.Errorf("this coupon is disabled ")
-		}
-		if exists {
-			tx.Rollback()
-			return response.ResponseOrder{}, fmt.Errorf("can't add this coupon since you have already exhausted it's usage")
-		} else {
-			addCoupon := `INSERT INTO user_coupons(user_id,coupon_id) VALUES($1,$2)`
-			err := tx.Exec(addCoupon, id, coupon.Id).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("can't add this coupon")
-			}
-			cart.Total = cart.Total - coupon.Amount
-			updateCoupons := `UPDATE coupons SET quantity=quantity-1 WHERE id=?`
-			err = tx.Exec(updateCoupons, coupon.Id).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("can't add this coupon")
-			}
+			return response.ResponseOrder{}, fmt.Errorf("this coupon has run out")
 		}
 	}
+	orderTotal := int(pricing.Total)
 	var order domain.Orders
-	insertOrder := `INSERT INTO orders (user_id,order_date,payment_type_id,shipping_address,order_total,order_status_id,payment_status_id) 
-	              VALUES ($1,NOW(),$2,$3,$4,$5,$6) RETURNING *`
-	err = tx.Raw(insertOrder, id, paymentTypeid, addressId, cart.Total, 1, 1).Scan(&order).Error
+	insertOrder := `INSERT INTO orders (user_id,order_date,payment_type_id,shipping_address,order_total,order_status_id,payment_status_id,
+	sub_total,discount_amount,cart_discount,coupon_discount,points_redeemed,points_discount,shipping_charge,tax_amount,wallet_amount) 
+	              VALUES ($1,NOW(),$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING *`
+	err = tx.Raw(insertOrder, id, paymentTypeid, addressId, orderTotal, 1, 1,
+		int(pricing.SubTotal), int(pricing.ItemDiscount), int(pricing.CartDiscount), int(pricing.CouponDiscount),
+		pricing.PointsRedeemed, int(pricing.PointsDiscount), int(pricing.Shipping), pricing.Tax, walletAmount).Scan(&order).Error
 	if err != nil {
 		tx.Rollback()
 		return response.ResponseOrder{}, fmt.Errorf("error placing order")
 	}
-	var cartItems []helperStruct.CartItems
-	cartDetail := `select ci.product_item_id,ci.quantity,pi.price,pi.qty_in_stock,
-	            ((pi.price*discount_percent)/100)*(ci.quantity) AS discount_price,
-            	(pi.price * ci.quantity)-((pi.price*discount_percent)/100)*(ci.quantity) AS discounted_price
-	             from cart_items ci 
-	             join product_items pi on ci.product_item_id = pi.id
-				 left join products on products.id=pi.id
-				 left join brands on brands.brandname=products.brand 
-				 left join discounts on discounts.brand_id=brands.id AND expiry_date>NOW()
-				 where ci.carts_id=$1`
-	err = tx.Raw(cartDetail, cart.Id).Scan(&cartItems).Error
-	if err != nil {
-		tx.Rollback()
-		return response.ResponseOrder{}, err
+	if pricing.PointsRedeemed > 0 {
+		if err := redeemPoints(tx, order.UserId, order.Id, pricing.PointsRedeemed); err != nil {
+			tx.Rollback()
+			return response.ResponseOrder{}, err
+		}
 	}
 
-	//Add the items in the cart into the orderitems one by one
-	for _, items := range cartItems {
+	//Add the priced lines into the orderitems one by one
+	for _, line := range pricing.Lines {
 		//check whether the item is available
-		if items.Quantity > items.QtyInStock {
+		var qtyInStock int
+		err = tx.Raw(`SELECT qty_in_stock FROM product_items WHERE id=? FOR UPDATE`, line.ProductItemId).Scan(&qtyInStock).Error
+		if err != nil {
+			tx.Rollback()
+			return response.ResponseOrder{}, err
+		}
+		if line.Quantity > qtyInStock {
+			tx.Rollback()
 			return response.ResponseOrder{}, fmt.Errorf("out of stock")
 		}
-		if items.DiscountedPrice != 0 {
-			items.Price = int(items.DiscountedPrice)
+		//units bought on sale count towards the flash sale quantity
+		if line.SaleRuleId != 0 {
+			if err := claimSale(tx, int(line.SaleRuleId), line.Quantity); err != nil {
+				tx.Rollback()
+				return response.ResponseOrder{}, err
+			}
 		}
-		insetOrderItems := `INSERT INTO order_items (orders_id,product_item_id,quantity,price) VALUES($1,$2,$3,$4)`
-		err = tx.Exec(insetOrderItems, order.Id, items.ProductItemId, items.Quantity, items.Price).Error
+		insetOrderItems := `INSERT INTO order_items (orders_id,product_item_id,quantity,price,list_price,promotion,promotion_discount) VALUES($1,$2,$3,$4,$5,$6,$7)`
+		err = tx.Exec(insetOrderItems, order.Id, line.ProductItemId, line.Quantity, int(line.UnitPrice), int(line.ListPrice),
+			line.Promotion, int(line.PromotionDiscount)).Error
 
 		if err != nil {
 			tx.Rollback()
@@ -179,9 +235,9 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 	}
 
 	//Remove the items from the cart_items
-	for _, items := range cartItems {
+	for _, line := range pricing.Lines {
 		removeCartItems := `DELETE FROM cart_items WHERE carts_id =$1 AND product_item_id=$2`
-		err = tx.Exec(removeCartItems, cart.Id, items.ProductItemId).Error
+		err = tx.Exec(removeCartItems, cart.Id, line.ProductItemId).Error
 		if err != nil {
 			tx.Rollback()
 			return response.ResponseOrder{}, err
@@ -189,66 +245,56 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 	}
 
 	//Reduce the product qty in stock details
-	for _, items := range cartItems {
+	for _, line := range pricing.Lines {
 		updateQty := `UPDATE product_items SET qty_in_stock=product_items.qty_in_stock-$1 WHERE id=$2`
-		err = tx.Exec(updateQty, items.Quantity, items.ProductItemId).Error
+		err = tx.Exec(updateQty, line.Quantity, line.ProductItemId).Error
 		if err != nil {
 			tx.Rollback()
 			return response.ResponseOrder{}, err
 		}
 	}
-
-	//update the PaymentDetails table with OrdersID, OrderTotal, PaymentTypeID, PaymentStatusID
+	//record a payment leg for the wallet part and one for the rest
 	createPaymentDetails := `INSERT INTO payment_details
 		   (orders_id,
 		   order_total,
+		   amount,
 		   payment_type_id,
 		   payment_status_id,
 		   updated_at)
-		   VALUES($1,$2,$3,$4,NOW())`
-	if err = tx.Exec(createPaymentDetails, order.Id, order.OrderTotal, paymentTypeid, 1).Error; err != nil {
-		tx.Rollback()
-		return response.ResponseOrder{}, err
+		   VALUES($1,$2,$3,$4,$5,NOW())`
+	if walletAmount > 0 {
+		err = postWalletTransaction(tx, walletPosting{
+			UserId:      id,
+			Type:        walletOrderPayment,
+			Amount:      -walletAmount,
+			OrderId:     order.Id,
+			Key:         fmt.Sprintf("order:%d:payment", order.Id),
+			Description: fmt.Sprintf("payment for order %d", order.Id),
+		})
+		if errors.Is(err, errInsufficientWalletBalance) {
+			tx.Rollback()
+			return response.ResponseOrder{}, fmt.Errorf("you don't have enough amount in the wallet to complete this transaction please pay less from the wallet or choose a different payment method")
+		} else if err != nil {
+			tx.Rollback()
+			return response.ResponseOrder{}, err
+		}
+		if err = tx.Exec(createPaymentDetails, order.Id, order.OrderTotal, walletAmount, domain.WalletPaymentTypeId, 5).Error; err != nil {
+			tx.Rollback()
+			return response.ResponseOrder{}, err
+		}
 	}
-	if paymentTypeid == 3 {
-		var walletAmount int
-		getWalletAmount := `SELECT amount FROM wallets WHERE user_id=$1`
-		err = tx.Raw(getWalletAmount, id).Scan(&walletAmount).Error
-		if err != nil {
+	if remainder := orderTotal - walletAmount; remainder > 0 {
+		if err = tx.Exec(createPaymentDetails, order.Id, order.OrderTotal, remainder, paymentTypeid, 1).Error; err != nil {
 			tx.Rollback()
-			return response.ResponseOrder{}, fmt.Errorf("error retrieving amount from wallet")
+			return response.ResponseOrder{}, err
 		}
-		if walletAmount >= cart.Total {
-			insertWalletHistory := `INSERT INTO wallet_histories (recent_transaction,user_id,balance,time) VALUES ($1,$2,$3,NOW())`
-			walletHistory := fmt.Sprintf("%d - %d", walletAmount, cart.Total)
-			err = tx.Exec(insertWalletHistory, walletHistory, id, (walletAmount - cart.Total)).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("error inserting wallet history")
-			}
-			updateWallet := `UPDATE wallets SET amount=amount-$1 WHERE user_id=$2`
-			err = tx.Exec(updateWallet, cart.Total, id).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("error updating wallet amount")
-			}
-			updatePaymentStatus := `UPDATE orders SET payment_status_id=5 WHERE id=$1`
-			err = tx.Exec(updatePaymentStatus, order.Id).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("error updating payment status")
-			}
-			updatePaymentDetails := `UPDATE payment_details SET payment_status_id=5 WHERE orders_id=$1`
-			err = tx.Exec(updatePaymentDetails, order.Id).Error
-			if err != nil {
-				tx.Rollback()
-				return response.ResponseOrder{}, fmt.Errorf("error updating payment details")
-			}
-		} else {
+	} else {
+		updatePaymentStatus := `UPDATE orders SET payment_status_id=5 WHERE id=$1`
+		err = tx.Exec(updatePaymentStatus, order.Id).Error
+		if err != nil {
 			tx.Rollback()
-			return response.ResponseOrder{}, fmt.Errorf("you don't have enough amount in the wallet to complete this transaction please choose a different payment method")
+			return response.ResponseOrder{}, fmt.Errorf("error updating payment status")
 		}
-
 	}
 	var orderResponse response.OrderResponse
 	err = tx.Raw(`SELECT p.type AS payment_type,o.status AS order_status,addresses.*,orders.*,payment_statuses.status AS payment_status,
@@ -263,21 +309,41 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 		tx.Rollback()
 		return response.ResponseOrder{}, fmt.Errorf("error retrieving order information")
 	}
+	orderResponse.SubTotal = int(pricing.SubTotal)
+	orderResponse.Shipping = int(pricing.Shipping)
+	orderResponse.Tax = pricing.Tax
 	if coupon.Name != "" {
 		orderResponse.CouponCode = coupon.Name
-		orderResponse.CouponAmount = -coupon.Amount
-		orderResponse.SubTotal = cart.SubTotal
-		err := tx.Exec(`UPDATE user_coupons SET order_id=$1 WHERE coupon_id=$2`, order.Id, coupon.Id).Error
+		orderResponse.CouponAmount = -int(pricing.CouponDiscount)
+		err := tx.Exec(`INSERT INTO user_coupons(user_id,coupon_id,order_id) VALUES($1,$2,$3)`, id, coupon.Id, order.Id).Error
 		if err != nil {
 			tx.Rollback()
-			// fmt.Errorf("error updating coupons table")
-			return response.ResponseOrder{}, err
+			return response.ResponseOrder{}, fmt.Errorf("can't add this coupon")
 		}
+		if coupon.CodeId != 0 {
+			redeem := tx.Exec(`UPDATE coupon_codes SET user_id=$1,order_id=$2,redeemed_at=NOW() WHERE id=$3 AND user_id=0`, id, order.Id, coupon.CodeId)
+			if redeem.Error != nil {
+				tx.Rollback()
+				return response.ResponseOrder{}, fmt.Errorf("can't add this coupon")
+			}
+			if redeem.RowsAffected == 0 {
+				tx.Rollback()
+				return response.ResponseOrder{}, fmt.Errorf("this code has already been redeemed")
+			}
+		}
+	}
+	if err := tx.Exec(`UPDATE carts SET coupon_id=0,coupon_code_id=0,coupon_notice='' WHERE id=?`, cart.Id).Error; err != nil {
+		tx.Rollback()
+		return response.ResponseOrder{}, err
 	}
-	orderResponse.DiscountPrice = int(-totalDiscountAmount)
+	orderResponse.DiscountPrice = -int(pricing.ItemDiscount)
+	orderResponse.CartDiscount = -int(pricing.CartDiscount)
+	orderResponse.PointsDiscount = -int(pricing.PointsDiscount)
 	var responseOrder response.ResponseOrder
 	var orderProducts []response.OrderProduct
-	err = tx.Raw(`SELECT order_items.product_item_id,products.product_name,order_items.quantity FROM orders JOIN order_items ON orders.id=order_items.orders_id
+	err = tx.Raw(`SELECT order_items.product_item_id,products.product_name,order_items.quantity,
+	                COALESCE(order_items.promotion,'') AS promotion,COALESCE(order_items.promotion_discount,0) AS promotion_discount
+	                FROM orders JOIN order_items ON orders.id=order_items.orders_id
 	                JOIN products ON order_items.product_item_id=products.id
 	                WHERE user_id=$1 AND orders.id=$2`, order.UserId, order.Id).Scan(&orderProducts).Error
 	if err != nil {
@@ -296,16 +362,27 @@ func (c *orderDatabase) OrderAll(id int, paymentTypeid int, coupon response.Coup
 // UserCanceOrder implements interfaces.OrderRepository.
 func (o *orderDatabase) UserCancelOrder(orderId int, userId int) error {
 	tx := o.DB.Begin()
+	var order domain.Orders
+	err := tx.Raw(`SELECT * FROM orders WHERE id=? AND user_id=? FOR UPDATE`, orderId, userId).Scan(&order).Error
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("error retrieving order")
+	}
+	if order.Id == 0 {
+		tx.Rollback()
+		return fmt.Errorf("no order found with the given id")
+	}
+	if finalOrderStatuses[order.OrderStatusID] {
+		tx.Rollback()
+		return fmt.Errorf("this order can no longer be cancelled")
+	}
 	var items []helperStruct.CartItems
 	findProducts := `SELECT product_item_id,quantity FROM order_items WHERE orders_id=?`
-	err := tx.Raw(findProducts, orderId).Scan(&items).Error
+	err = tx.Raw(findProducts, orderId).Scan(&items).Error
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("error getting products from cart_items")
 	}
-	if len(items) == 0 {
-		return fmt.Errorf("no order found with the given id")
-	}
 	for _, item := range items {
 		updateProductItem := `UPDATE product_items SET qty_in_stock=qty_in_stock+$1 WHERE id=$2`
 		err = tx.Exec(updateProductItem, item.Quantity, item.ProductItemId).Error
@@ -321,67 +398,26 @@ func (o *orderDatabase) UserCancelOrder(orderId int, userId int) error {
 		tx.Rollback()
 		return err
 	}
-	var paymentStatusId int
-	getPaymentStatus := `SELECT payment_status_id FROM orders WHERE id=$1`
-	err = tx.Raw(getPaymentStatus, orderId).Scan(&paymentStatusId).Error
+	// the wallet and COD legs are credited to the wallet here, Razorpay legs
+	// are left pending for the usecase to send to the gateway
+	refunded, err := refundPaymentLegs(tx, userId, orderId, order.OrderTotal, "cancelled")
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("error retrieving payment status")
+		return err
 	}
-	if paymentStatusId == 5 {
-		var price int
-		getAmount := `SELECT order_total FROM orders WHERE id=$1`
-		err = tx.Raw(getAmount, orderId).Scan(&price).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error retrieving total from orders")
-		}
-		var walletAmount int
-		getWalletAmount := `SELECT amount FROM wallets WHERE user_id=$1`
-		err = tx.Raw(getWalletAmount, userId).Scan(&walletAmount).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error retrieving amount from wallet")
-		}
-		insertWalletHistory := `INSERT INTO wallet_histories (recent_transaction,user_id,balance,time) VALUES ($1,$2,$3,NOW())`
-		walletHistory := fmt.Sprintf("%d + %d", walletAmount, price)
-		err = tx.Exec(insertWalletHistory, walletHistory, userId, (walletAmount + price)).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error inserting wallet history")
-		}
-		updateWallet := `UPDATE wallets SET amount=amount+$1 WHERE user_id=$2`
-		err = tx.Exec(updateWallet, price, userId).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error updating wallet")
-		}
-		cancelOrder := `UPDATE orders SET order_status_id=5,payment_status_id=4 WHERE id=$1 AND user_id=$2`
-		err = tx.Exec(cancelOrder, orderId, userId).Error
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		updatePaymentDetails := `UPDATE payment_details SET payment_status_id=4 WHERE orders_id=$1`
-		err = tx.Exec(updatePaymentDetails, orderId).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error updating payment details")
-		}
-
-	} else {
-		cancelOrder := `UPDATE orders SET order_status_id=5,payment_status_id=3 WHERE id=$1 AND user_id=$2`
-		err = tx.Exec(cancelOrder, orderId, userId).Error
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		updatePaymentDetails := `UPDATE payment_details SET payment_status_id=3 WHERE orders_id=$1`
-		err = tx.Exec(updatePaymentDetails, orderId).Error
-		if err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error updating payment details")
-		}
+	paymentStatusId := 3
+	if refunded > 0 {
+		paymentStatusId = 4
+	}
+	cancelOrder := `UPDATE orders SET order_status_id=5,payment_status_id=$1 WHERE id=$2 AND user_id=$3`
+	err = tx.Exec(cancelOrder, paymentStatusId, orderId, userId).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err = refundRedeemedPoints(tx, orderId); err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	if err = tx.Commit().Error; err != nil {
@@ -444,7 +480,8 @@ func (o *orderDatabase) DisplayOrder(userId int, orderId int) (response.Response
 	var orderProducts []response.OrderProduct
 	var res response.ResponseOrder
 	err := o.DB.Raw(`SELECT p.type AS payment_type,o.status AS order_status,addresses.*,orders.*,payment_statuses.status AS payment_status,order_items.product_item_id AS product_item_id
-	,products.product_name,coupons.name AS coupon_code,coupons.amount AS coupon_amount
+	,products.product_name,COALESCE(coupon_codes.code,coupons.name) AS coupon_code,
+	COALESCE(NULLIF(orders.coupon_discount,0),coupons.amount,0) AS coupon_amount,orders.shipping_charge AS shipping
 	FROM orders JOIN payment_types p ON  
 	p.id=orders.payment_type_id LEFT JOIN order_statuses o ON orders.order_status_id=o.id
 	LEFT JOIN order_items ON orders.id=order_items.orders_id
@@ -453,25 +490,20 @@ func (o *orderDatabase) DisplayOrder(userId int, orderId int) (response.Response
 	LEFT JOIN payment_statuses ON orders.payment_status_id=payment_statuses.id
 	LEFT JOIN user_coupons ON orders.id=user_coupons.order_id
 	LEFT JOIN coupons ON user_coupons.coupon_id=coupons.id
+	LEFT JOIN coupon_codes ON coupon_codes.order_id=orders.id
 	WHERE orders.user_id=$1 AND orders.id=$2`, userId, orderId).Scan(&order).Error
 	if err != nil {
 		return response.ResponseOrder{}, err
 	}
-	err = o.DB.Raw(`SELECT order_items.product_item_id,products.product_name,order_items.quantity,product_items.price,
-	                (discounts.discount_percent/100)*product_items.price AS discount_price
+	//prices come from the order so the invoice shows what was charged, orders placed before list prices were kept fall back to the current price
+	err = o.DB.Raw(`SELECT order_items.product_item_id,products.product_name,order_items.quantity,
+	                COALESCE(NULLIF(order_items.list_price,0),product_items.price) AS price,
+	                GREATEST(COALESCE(NULLIF(order_items.list_price,0),product_items.price)-order_items.price,0) AS discount_price,
+	                COALESCE(order_items.promotion,'') AS promotion,COALESCE(order_items.promotion_discount,0) AS promotion_discount
 	                FROM orders JOIN order_items ON orders.id=order_items.orders_id
 	                JOIN products ON order_items.product_item_id=products.id
-					LEFT JOIN brands ON brands.brandname=products.brand
-					LEFT JOIN discounts ON discounts.brand_id=brands.id AND expiry_date>NOW()
 					LEFT JOIN product_items ON product_items.id=order_items.product_item_id
 	                WHERE user_id=$1 AND orders.id=$2`, userId, orderId).Scan(&orderProducts).Error
-	var totalPriceWithoutDiscount int
-	for _, item := range orderProducts {
-		totalPriceWithoutDiscount += (item.Price * item.Quantity)
-	}
-	order.CouponAmount = -order.CouponAmount
-	order.SubTotal = totalPriceWithoutDiscount
-	order.DiscountPrice = -(order.SubTotal - order.OrderTotal + order.CouponAmount)
 	order.Id = uint(orderId)
 	res.OrderProducts = orderProducts
 	res.OrderResponse = order
@@ -495,36 +527,39 @@ func (o *orderDatabase) ReturnOrder(userId int, orderId int) (response.ReturnOrd
 		tx.Rollback()
 		return response.ReturnOrder{}, fmt.Errorf("order is not yet delivered")
 	}
+	// orders delivered before delivery dates were kept can still be returned
+	if order.DeliveredAt != nil {
+		var windowDays int
+		err = tx.Raw(`SELECT `+returnWindowDays+` FROM users WHERE users.id=?`, defaultReturnWindowDays, userId).Scan(&windowDays).Error
+		if err != nil {
+			tx.Rollback()
+			return response.ReturnOrder{}, fmt.Errorf("error checking the return window")
+		}
+		if time.Since(*order.DeliveredAt) > time.Duration(windowDays)*24*time.Hour {
+			tx.Rollback()
+			return response.ReturnOrder{}, fmt.Errorf("orders can only be returned within %d days of delivery", windowDays)
+		}
+	}
 	updateOrderStatus := `UPDATE orders SET order_status_id=6,payment_status_id=4 WHERE id=$1 AND user_id=$2`
 	err = tx.Exec(updateOrderStatus, orderId, userId).Error
 	if err != nil {
 		tx.Rollback()
 		return response.ReturnOrder{}, fmt.Errorf("error updating order status")
 	}
-	updatePaymentDetails := `UPDATE payment_details SET payment_status_id=4 WHERE orders_id=$1`
-	err = tx.Exec(updatePaymentDetails, orderId).Error
-	if err != nil {
-		return response.ReturnOrder{}, fmt.Errorf("error updating payment_details")
-	}
-	var walletAmount int
-	getWalletAmount := `SELECT amount FROM wallets WHERE user_id=$1`
-	err = tx.Raw(getWalletAmount, userId).Scan(&walletAmount).Error
+	// as on cancelling, COD is credited to the wallet and Razorpay legs are
+	// left pending for the gateway
+	_, err = refundPaymentLegs(tx, userId, orderId, order.OrderTotal, "returned")
 	if err != nil {
 		tx.Rollback()
-		return response.ReturnOrder{}, fmt.Errorf("error retrieving amount from wallet")
+		return response.ReturnOrder{}, fmt.Errorf("error refunding the amount")
 	}
-	insertWalletHistory := `INSERT INTO wallet_histories (recent_transaction,user_id,balance,time) VALUES ($1,$2,$3,NOW())`
-	walletHistory := fmt.Sprintf("%d + %d", walletAmount, order.OrderTotal)
-	err = tx.Exec(insertWalletHistory, walletHistory, userId, (walletAmount + order.OrderTotal)).Error
-	if err != nil {
+	if err = reverseEarnedPoints(tx, orderId); err != nil {
 		tx.Rollback()
-		return response.ReturnOrder{}, fmt.Errorf("error inserting wallet history")
+		return response.ReturnOrder{}, err
 	}
-	updateWallet := `UPDATE wallets SET amount=amount+$1 WHERE user_id=$2`
-	err = tx.Exec(updateWallet, order.OrderTotal, userId).Error
-	if err != nil {
+	if err = refundRedeemedPoints(tx, orderId); err != nil {
 		tx.Rollback()
-		return response.ReturnOrder{}, fmt.Errorf("error refunding the amount")
+		return response.ReturnOrder{}, err
 	}
 	var orderResponse response.ReturnOrder
 	returnOrder := `SELECT orders.*,order_statuses.status AS order_status FROM orders JOIN order_statuses ON order_statuses.id=orders.order_status_id WHERE orders.id=?`
@@ -556,15 +591,25 @@ func (o *orderDatabase) UpdateOrderStatus(updateOrder helperStruct.UpdateOrder)
 			return response.AdminOrder{}, fmt.Errorf("error updating order status")
 		}
 	} else {
-		updateOrderStatus := `UPDATE orders SET order_status_id=$1,payment_status_id=$2 WHERE id=$3`
-		err := o.DB.Exec(updateOrderStatus, updateOrder.OrderStatusID, 5, updateOrder.OrderId).Error
+		updateOrderStatus := `UPDATE orders SET order_status_id=$1,delivered_at=NOW() WHERE id=$2`
+		err := o.DB.Exec(updateOrderStatus, updateOrder.OrderStatusID, updateOrder.OrderId).Error
 		if err != nil {
 			return response.AdminOrder{}, fmt.Errorf("error updating order status")
 		}
-		err = o.DB.Exec(`UPDATE payment_details SET payment_status_id=5 WHERE orders_id=$1`, updateOrder.OrderId).Error
+		// only cash on delivery is settled by delivering, online legs stay as they are
+		settleCOD := `UPDATE payment_details SET payment_status_id=5,updated_at=NOW() WHERE orders_id=$1 AND payment_type_id=$2 AND payment_status_id=1`
+		err = o.DB.Exec(settleCOD, updateOrder.OrderId, domain.CODPaymentTypeId).Error
 		if err != nil {
 			return response.AdminOrder{}, fmt.Errorf("error updating payment_details")
 		}
+		markPaid := `UPDATE orders SET payment_status_id=5 WHERE id=$1 AND NOT EXISTS (SELECT 1 FROM payment_details WHERE orders_id=$1 AND payment_status_id<>5)`
+		err = o.DB.Exec(markPaid, updateOrder.OrderId).Error
+		if err != nil {
+			return response.AdminOrder{}, fmt.Errorf("error updating payment status")
+		}
+		if err = earnPoints(o.DB, int(updateOrder.OrderId)); err != nil {
+			return response.AdminOrder{}, fmt.Errorf("error crediting loyalty points")
+		}
 	}
 	var adminOrder response.AdminOrder
 	selectOrder := `SELECT orders.id AS order_id,orders.payment_type_id AS payment_type_id,order_statuses.status AS order_status,payment_types.type AS payment_type,payment_statuses.status AS payment_status 
@@ -684,3 +729,24 @@ func (o *orderDatabase) UpdateOrderStatuses(orderStatus helperStruct.OrderStatus
 	err := o.DB.Raw(updateOrderStatus, orderStatus.Status, orderStatus.Id).Scan(&updatedOrderStatus).Error
 	return updatedOrderStatus, err
 }
+
+// PendingRefunds implements interfaces.OrderRepository.
+func (o *orderDatabase) PendingRefunds(orderId int) ([]response.PaymentRefund, error) {
+	var refunds []response.PaymentRefund
+	findRefunds := `SELECT id,orders_id,payment_ref,amount FROM payment_refunds WHERE orders_id=$1 AND status='pending' ORDER BY id`
+	err := o.DB.Raw(findRefunds, orderId).Scan(&refunds).Error
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending refunds")
+	}
+	return refunds, nil
+}
+
+// CompleteRefund implements interfaces.OrderRepository.
+func (o *orderDatabase) CompleteRefund(refundId uint, gatewayRef string) error {
+	completeRefund := `UPDATE payment_refunds SET status='refunded',gateway_ref=$1,updated_at=NOW() WHERE id=$2 AND status='pending'`
+	err := o.DB.Exec(completeRefund, gatewayRef, refundId).Error
+	if err != nil {
+		return fmt.Errorf("error updating payment refund")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSplitRefund(t *testing.T) {
+	tests := []struct {
+		name           string
+		amount         int
+		refundable     []int
+		expectedOutput []int
+	}{
+		{name: "single leg", amount: 500, refundable: []int{500}, expectedOutput: []int{500}},
+		{name: "whole order over wallet and online legs", amount: 1000, refundable: []int{300, 700}, expectedOutput: []int{300, 700}},
+		{name: "part of the order in proportion", amount: 500, refundable: []int{300, 700}, expectedOutput: []int{150, 350}},
+		{name: "rounding goes to the first leg", amount: 1, refundable: []int{1, 1}, expectedOutput: []int{1, 0}},
+		{name: "uneven split", amount: 10, refundable: []int{100, 200}, expectedOutput: []int{4, 6}},
+		{name: "more than was paid", amount: 2000, refundable: []int{300, 700}, expectedOutput: []int{300, 700}},
+		{name: "nothing paid", amount: 500, refundable: []int{}, expectedOutput: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, splitRefund(tt.amount, tt.refundable))
+		})
+	}
+}
+
+func TestUserCancelOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "someone else's order",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM orders WHERE id=(.+) AND user_id=(.+) FOR UPDATE$").WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "order_status_id"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("no order found with the given id"),
+		},
+		{
+			name: "delivered order",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("^SELECT \\* FROM orders WHERE id=(.+) AND user_id=(.+) FOR UPDATE$").WithArgs(7, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "order_status_id"}).AddRow(7, 1, 4))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("this order can no longer be cancelled"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error %s was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error %s was not expected when initializing a mock db session", err)
+			}
+			tt.buildStub(mock)
+			actualErr := NewOrderRepo(gormDB).UserCancelOrder(7, 1)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations %s", err)
+			}
+		})
+	}
+}
+
+func TestRefundPaymentLegs(t *testing.T) {
+	legColumns := []string{"payment_type_id", "payment_type", "payment_ref", "amount", "refunded_amount"}
+	expectWalletCredit := func(mock sqlmock.Sqlmock, amount int) {
+		mock.ExpectQuery("^SELECT \\* FROM wallets WHERE user_id=(.+) FOR UPDATE$").WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(1, 1, 100))
+		mock.ExpectQuery("^INSERT INTO wallet_transactions (.+)").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+		mock.ExpectExec("^INSERT INTO wallet_entries (.+)").WillReturnResult(sqlmock.NewResult(1, 2))
+		mock.ExpectExec("^UPDATE wallets SET amount=(.+) WHERE user_id=(.+)$").WithArgs(100+amount, 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	expectLegRefunded := func(mock sqlmock.Sqlmock, amount, paymentTypeId int) {
+		mock.ExpectExec("^UPDATE payment_details SET refunded_amount=(.+)").WithArgs(amount, 7, paymentTypeId).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	tests := []struct {
+		name           string
+		buildStub      func(mock sqlmock.Sqlmock)
+		expectedOutput int
+		expectedErr    error
+	}{
+		{
+			name: "wallet leg back to the wallet and razorpay leg through the gateway",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT payment_details.payment_type_id,(.+)").WithArgs(7, 3).
+					WillReturnRows(sqlmock.NewRows(legColumns).AddRow(3, "wallet", "", 300, 0).AddRow(2, "razorpay", "pay_1", 700, 0))
+				expectWalletCredit(mock, 300)
+				expectLegRefunded(mock, 300, 3)
+				mock.ExpectExec("^INSERT INTO payment_refunds (.+)").WithArgs(7, 2, "pay_1", 700, "order:7:refund:cancelled:2:0").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				expectLegRefunded(mock, 700, 2)
+				mock.ExpectExec("^UPDATE payment_details SET payment_status_id=3(.+)").WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+			},
+			expectedOutput: 1000,
+		},
+		{
+			name: "cash on delivery credited to the wallet",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT payment_details.payment_type_id,(.+)").WithArgs(7, 3).
+					WillReturnRows(sqlmock.NewRows(legColumns).AddRow(1, "COD", "", 1000, 0))
+				expectWalletCredit(mock, 1000)
+				expectLegRefunded(mock, 1000, 1)
+				mock.ExpectExec("^UPDATE payment_details SET payment_status_id=3(.+)").WithArgs(7).
+					WillReturnResult(sqlmock.NewResult(1, 0))
+			},
+			expectedOutput: 1000,
+		},
+		{
+			name: "razorpay refund can't be recorded",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT payment_details.payment_type_id,(.+)").WithArgs(7, 3).
+					WillReturnRows(sqlmock.NewRows(legColumns).AddRow(2, "razorpay", "pay_1", 1000, 0))
+				mock.ExpectExec("^INSERT INTO payment_refunds (.+)").WillReturnError(errors.New("db error"))
+			},
+			expectedErr: errors.New("error recording payment refund"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error %s was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error %s was not expected when initializing a mock db session", err)
+			}
+			tt.buildStub(mock)
+			actualOutput, actualErr := refundPaymentLegs(gormDB, 1, 7, 1000, "cancelled")
+			assert.Equal(t, tt.expectedOutput, actualOutput)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations %s", err)
+			}
+		})
+	}
+}
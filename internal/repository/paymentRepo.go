@@ -5,6 +5,7 @@ import (
 
 	"gorm.io/gorm"
 	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
 	"main.go/internal/domain"
 	"main.go/internal/repository/interfaces"
 )
@@ -17,10 +18,12 @@ func NewPaymentRepo(DB *gorm.DB) interfaces.PaymentRepository {
 	return &PaymentDatabase{DB}
 }
 
+// ViewPaymentDetails returns the leg of an order that isn't paid from the
+// wallet, orders paid entirely from the wallet have none.
 func (c *PaymentDatabase) ViewPaymentDetails(orderID int) (domain.PaymentDetails, error) {
 	var paymentDetails domain.PaymentDetails
-	fetchPaymentDetailsQuery := `SELECT * FROM payment_details WHERE orders_id = $1;`
-	err := c.DB.Raw(fetchPaymentDetailsQuery, orderID).Scan(&paymentDetails).Error
+	fetchPaymentDetailsQuery := `SELECT * FROM payment_details WHERE orders_id = $1 AND payment_type_id <> $2;`
+	err := c.DB.Raw(fetchPaymentDetailsQuery, orderID, domain.WalletPaymentTypeId).Scan(&paymentDetails).Error
 	fmt.Println("2", paymentDetails)
 	return paymentDetails, err
 }
@@ -30,13 +33,13 @@ func (c *PaymentDatabase) UpdatePaymentDetails(orderID int, paymentRef string) (
 	var updatePaymentQuery string
 	if paymentRef != "" {
 		updatePaymentQuery = `	UPDATE payment_details SET payment_type_id = 2, payment_status_id = 5, payment_ref = $1, updated_at = NOW()
-							WHERE orders_id = $2 RETURNING *;`
+							WHERE orders_id = $2 AND payment_type_id <> $3 RETURNING *;`
 	} else {
 		updatePaymentQuery = `UPDATE payment_details SET payment_type_id = 2,payment_status_id = 6,updated_at = NOW()
-		                       WHERE orders_id=$2 RETURNING *`
+		                       WHERE orders_id=$2 AND payment_type_id <> $3 RETURNING *`
 	}
 	tx := c.DB.Begin()
-	err := tx.Raw(updatePaymentQuery, paymentRef, orderID).Scan(&updatedPayment).Error
+	err := tx.Raw(updatePaymentQuery, paymentRef, orderID, domain.WalletPaymentTypeId).Scan(&updatedPayment).Error
 	if err != nil {
 		tx.Rollback()
 		return updatedPayment, err
@@ -132,3 +135,53 @@ func (p *PaymentDatabase) UpdatePaymentType(paymentType helperStruct.PaymentType
 	err := p.DB.Exec(updatePaymentType, paymentType.Type, paymentTypeId).Error
 	return err
 }
+
+// CreateWalletTopUp implements interfaces.PaymentRepository.
+func (p *PaymentDatabase) CreateWalletTopUp(userId, amount int, razorpayOrderId string) (response.WalletTopUp, error) {
+	var topUp response.WalletTopUp
+	createTopUp := `INSERT INTO wallet_top_ups (user_id,amount,razorpay_order_id,status,created_at) VALUES ($1,$2,$3,'created',NOW()) RETURNING *`
+	err := p.DB.Raw(createTopUp, userId, amount, razorpayOrderId).Scan(&topUp).Error
+	if err != nil {
+		return response.WalletTopUp{}, fmt.Errorf("error creating wallet top-up")
+	}
+	return topUp, nil
+}
+
+// WalletTopUp implements interfaces.PaymentRepository.
+func (p *PaymentDatabase) WalletTopUp(razorpayOrderId string) (response.WalletTopUp, error) {
+	var topUp response.WalletTopUp
+	err := p.DB.Raw(`SELECT * FROM wallet_top_ups WHERE razorpay_order_id=?`, razorpayOrderId).Scan(&topUp).Error
+	return topUp, err
+}
+
+// CompleteWalletTopUp implements interfaces.PaymentRepository.
+func (p *PaymentDatabase) CompleteWalletTopUp(razorpayOrderId, paymentRef string) (response.WalletTopUp, error) {
+	tx := p.DB.Begin()
+	var topUp response.WalletTopUp
+	completeTopUp := `UPDATE wallet_top_ups SET status='paid',payment_ref=$1,paid_at=NOW() WHERE razorpay_order_id=$2 AND status='created' RETURNING *`
+	err := tx.Raw(completeTopUp, paymentRef, razorpayOrderId).Scan(&topUp).Error
+	if err != nil {
+		tx.Rollback()
+		return response.WalletTopUp{}, err
+	}
+	if topUp.Id == 0 {
+		tx.Rollback()
+		return response.WalletTopUp{}, fmt.Errorf("this top-up has already been credited")
+	}
+	err = postWalletTransaction(tx, walletPosting{
+		UserId:      int(topUp.UserId),
+		Type:        walletTopUp,
+		Amount:      topUp.Amount,
+		Key:         "topup:" + razorpayOrderId,
+		Description: fmt.Sprintf("wallet top-up via razorpay %s", paymentRef),
+	})
+	if err != nil {
+		tx.Rollback()
+		return response.WalletTopUp{}, err
+	}
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return response.WalletTopUp{}, err
+	}
+	return topUp, nil
+}
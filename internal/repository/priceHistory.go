@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// currentPrices is the base and effective price of every product item right
+// now, discount_percent being the share of the price taken off by discounts
+// and sales together.
+const currentPrices = `SELECT product_items.id AS product_item_id,product_items.price,
+	CASE WHEN product_items.price>0 THEN (product_items.price-` + effectivePrice + `)/product_items.price*100 ELSE 0 END AS discount_percent,
+	` + effectivePrice + ` AS effective_price
+	FROM product_items
+	JOIN products ON products.id=product_items.product_id
+	` + discountsJoin
+
+// recordPrices adds a price history row for every product item whose base or
+// effective price differs from its latest recorded one. productItemId limits
+// the check to one item, 0 checks the whole catalog.
+func recordPrices(db *gorm.DB, productItemId int) error {
+	current := currentPrices
+	var args []interface{}
+	if productItemId != 0 {
+		current += ` WHERE product_items.id=?`
+		args = append(args, productItemId)
+	}
+	recordPrices := fmt.Sprintf(`INSERT INTO price_histories (product_item_id,price,discount_percent,effective_price,recorded_at)
+	SELECT current.product_item_id,current.price,current.discount_percent,current.effective_price,NOW()
+	FROM (%s) current
+	LEFT JOIN LATERAL (
+		SELECT price,effective_price FROM price_histories
+		WHERE price_histories.product_item_id=current.product_item_id
+		ORDER BY recorded_at DESC,id DESC LIMIT 1
+	) latest ON true
+	WHERE latest.price IS NULL OR latest.price<>current.price OR latest.effective_price<>current.effective_price`, current)
+	return db.Exec(recordPrices, args...).Error
+}
+
+// RecordPriceChanges implements interfaces.ProductRepository. It captures
+// price changes nobody saved explicitly, such as discounts running out.
+func (c *ProductDatabase) RecordPriceChanges() error {
+	return recordPrices(c.DB, 0)
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRecordPrices(t *testing.T) {
+	tests := []struct {
+		name          string
+		productItemId int
+		buildStub     func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:          "one product item",
+			productItemId: 3,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("^INSERT INTO price_histories (.+) WHERE product_items.id=(.+)\\) current (.+)$").WithArgs(3).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "whole catalog",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				// only items whose price moved since the latest row get a new one
+				mock.ExpectExec("^INSERT INTO price_histories (.+) WHERE latest.price IS NULL OR latest.price<>current.price OR latest.effective_price<>current.effective_price$").
+					WithoutArgs().WillReturnResult(sqlmock.NewResult(0, 12))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			assert.NoError(t, recordPrices(gormDB, tt.productItemId))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// salePrice is the sale price of a product item while its price rule is live.
+const salePrice = `(CASE WHEN product_items.sale_ends_at>NOW() THEN product_items.sale_price END)`
+
+// discountIsLive matches discounts inside their validity window.
+const discountIsLive = `discounts.start_date<=NOW() AND discounts.expiry_date>NOW()`
+
+// discountCovers matches the item level discounts that cover a product item,
+// it needs products and product_items in the query.
+const discountCovers = `((discounts.scope='brand' AND discounts.scope_id=products.brand_id)
+	OR (discounts.scope='category' AND discounts.scope_id=products.category_id)
+	OR (discounts.scope='product' AND discounts.scope_id=products.id)
+	OR (discounts.scope='item' AND discounts.scope_id=product_items.id))`
+
+// discountAmount is what a single discount takes off one unit of a product
+// item, kept within its cap and the price itself.
+const discountAmount = `LEAST(CASE WHEN discounts.type='fixed' THEN discounts.amount ELSE product_items.price*discounts.discount_percent/100 END,
+	CASE WHEN discounts.max_discount>0 THEN discounts.max_discount ELSE product_items.price END,product_items.price)`
+
+// discountsJoin works out item_discounts.amount, what live discounts take off
+// one unit of a product item: the best exclusive discount by priority or all
+// stackable ones added up, whichever saves more. It needs products joined.
+const discountsJoin = `LEFT JOIN LATERAL (
+	SELECT LEAST(GREATEST(COALESCE(MAX(applicable.amount) FILTER (WHERE NOT applicable.stackable AND applicable.place=1),0),
+		COALESCE(SUM(applicable.amount) FILTER (WHERE applicable.stackable),0)),product_items.price) AS amount
+	FROM (
+		SELECT discounts.stackable,` + discountAmount + ` AS amount,
+		ROW_NUMBER() OVER (PARTITION BY discounts.stackable ORDER BY discounts.priority DESC,` + discountAmount + ` DESC,discounts.id) AS place
+		FROM discounts WHERE ` + discountIsLive + ` AND ` + discountCovers + `
+	) applicable
+) item_discounts ON true`
+
+// effectivePrice is what a customer pays for a product item: the price after
+// its discounts, or the sale price when that is lower. Use it with discountsJoin.
+const effectivePrice = `LEAST(product_items.price-COALESCE(item_discounts.amount,0),` + salePrice + `)`
+
+// discountColumns selects how much is taken off a product item and what is
+// left to pay, both NULL when the item sells at its full price.
+const discountColumns = `NULLIF(product_items.price-` + effectivePrice + `,0) AS discount_price,
+	CASE WHEN ` + effectivePrice + `<product_items.price THEN ` + effectivePrice + ` END AS discounted_price`
+
+// saleColumns selects the sale countdown of a product item, use it with saleJoin.
+const saleColumns = salePrice + ` AS sale_price,
+	CASE WHEN product_items.sale_ends_at>NOW() THEN product_items.sale_ends_at END AS sale_ends_at,
+	CASE WHEN product_items.sale_ends_at>NOW() THEN CEIL(EXTRACT(EPOCH FROM product_items.sale_ends_at-NOW()))::bigint END AS sale_seconds_left,
+	price_rules.flash_quantity-price_rules.sold_quantity AS flash_quantity_left`
+
+const saleJoin = `LEFT JOIN price_rules ON price_rules.id=product_items.sale_rule_id AND price_rules.flash_quantity>0 AND product_items.sale_ends_at>NOW()`
+
+// ruleIsLive matches price rules that are switched on, inside their schedule and not sold out.
+const ruleIsLive = `price_rules.is_active AND price_rules.starts_at<=NOW() AND price_rules.ends_at>NOW()
+	AND (price_rules.flash_quantity=0 OR price_rules.sold_quantity<price_rules.flash_quantity)`
+
+// bestSalePrices picks, for every product item covered by a live price rule,
+// the rule that gives it the lowest price.
+const bestSalePrices = `SELECT DISTINCT ON (product_items.id) product_items.id AS product_item_id,price_rules.id AS rule_id,price_rules.ends_at,
+	CASE WHEN price_rules.sale_price>0 THEN price_rules.sale_price ELSE product_items.price*(1-price_rules.discount_percent/100) END AS sale_price
+	FROM product_items
+	JOIN products ON products.id=product_items.product_id
+	JOIN price_rules ON (price_rules.scope='item' AND price_rules.scope_id=product_items.id)
+	OR (price_rules.scope='product' AND price_rules.scope_id=products.id)
+	OR (price_rules.scope='brand' AND price_rules.scope_id=products.brand_id)
+	OR (price_rules.scope='category' AND price_rules.scope_id=products.category_id)
+	WHERE ` + ruleIsLive + `
+	ORDER BY product_items.id,sale_price,price_rules.ends_at,price_rules.id`
+
+// applyPriceRules puts the best live price rule on every product item it
+// covers and takes ended or sold out rules off again.
+func applyPriceRules(db *gorm.DB) error {
+	tx := db.Begin()
+	revert := `UPDATE product_items SET sale_price=NULL,sale_rule_id=NULL,sale_ends_at=NULL
+	WHERE sale_rule_id IS NOT NULL AND id NOT IN (SELECT product_item_id FROM (` + bestSalePrices + `) best)`
+	if err := tx.Exec(revert).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	apply := `UPDATE product_items SET sale_price=best.sale_price,sale_rule_id=best.rule_id,sale_ends_at=best.ends_at
+	FROM (` + bestSalePrices + `) best
+	WHERE product_items.id=best.product_item_id AND (product_items.sale_rule_id IS DISTINCT FROM best.rule_id
+	OR product_items.sale_price IS DISTINCT FROM best.sale_price OR product_items.sale_ends_at IS DISTINCT FROM best.ends_at)`
+	if err := tx.Exec(apply).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := recordPrices(tx, 0); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ApplyPriceRules implements interfaces.DiscountRepository.
+func (d *DiscountDatabase) ApplyPriceRules() error {
+	return applyPriceRules(d.DB)
+}
+
+// claimSale counts units sold under a price rule. It fails when a flash sale
+// has fewer units left than ordered and ends the sale once it sells out.
+func claimSale(tx *gorm.DB, ruleId, quantity int) error {
+	claim := tx.Exec(`UPDATE price_rules SET sold_quantity=sold_quantity+$1
+	WHERE id=$2 AND (flash_quantity=0 OR sold_quantity+$1<=flash_quantity)`, quantity, ruleId)
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		var left int
+		err := tx.Raw(`SELECT GREATEST(flash_quantity-sold_quantity,0) FROM price_rules WHERE id=?`, ruleId).Scan(&left).Error
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("only %d left at the flash sale price, please reduce the quantity", left)
+	}
+	endSale := `UPDATE product_items SET sale_price=NULL,sale_rule_id=NULL,sale_ends_at=NULL
+	WHERE sale_rule_id=$1 AND EXISTS (SELECT 1 FROM price_rules WHERE id=$1 AND flash_quantity>0 AND sold_quantity>=flash_quantity)`
+	return tx.Exec(endSale, ruleId).Error
+}
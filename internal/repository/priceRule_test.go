@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestApplyPriceRules(t *testing.T) {
+	revertEnded := "^UPDATE product_items SET sale_price=NULL,sale_rule_id=NULL,sale_ends_at=NULL WHERE sale_rule_id IS NOT NULL (.+)$"
+	applyBest := "^UPDATE product_items SET sale_price=best.sale_price,(.+)$"
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "rules applied and prices recorded",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(revertEnded).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(applyBest).WillReturnResult(sqlmock.NewResult(0, 5))
+				mock.ExpectExec("^INSERT INTO price_histories (.+)$").WillReturnResult(sqlmock.NewResult(0, 7))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "ended rules stay on when reverting fails",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(revertEnded).WillReturnError(errors.New("deadlock detected"))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("deadlock detected"),
+		},
+		{
+			name: "nothing is kept when recording prices fails",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(revertEnded).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(applyBest).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("^INSERT INTO price_histories (.+)$").WillReturnError(errors.New("disk full"))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("disk full"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			discountRepo := NewDiscountRepo(gormDB)
+			err = discountRepo.ApplyPriceRules()
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestClaimSale(t *testing.T) {
+	claim := "^UPDATE price_rules SET sold_quantity=sold_quantity\\+(.+)$"
+	endSale := "^UPDATE product_items SET sale_price=NULL,sale_rule_id=NULL,sale_ends_at=NULL WHERE sale_rule_id=(.+)$"
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "units left",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(claim).WithArgs(2, 9).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(endSale).WithArgs(9).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fewer units left than ordered",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(claim).WithArgs(2, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT GREATEST\\(flash_quantity-sold_quantity,0\\) FROM price_rules WHERE id=(.+)$").WithArgs(9).
+					WillReturnRows(sqlmock.NewRows([]string{"greatest"}).AddRow(1))
+			},
+			expectedErr: errors.New("only 1 left at the flash sale price, please reduce the quantity"),
+		},
+		{
+			name: "units left can't be read",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(claim).WithArgs(2, 9).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("^SELECT GREATEST\\(flash_quantity-sold_quantity,0\\) FROM price_rules WHERE id=(.+)$").WithArgs(9).
+					WillReturnError(errors.New("connection reset"))
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			err = claimSale(gormDB, 9, 2)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -22,6 +22,16 @@ func NewProductRepo(DB *gorm.DB) interfaces.ProductRepository {
 	}
 }
 
+// filterColumn maps the brand filter onto the brands table, the name is no longer stored on products.
+func filterColumn(filter string) string {
+	if filter == "brand" {
+		return "brands.brandname"
+	}
+	return filter
+}
+
+const categoryColumns = `id,category_name,parent_id,sort_order,slug,meta_title,meta_description`
+
 // CreateCategory implements interfaces.ProductRepository.
 func (c *ProductDatabase) CreateCategory(category helperStruct.Category) (response.Category, error) {
 	var newcategory response.Category
@@ -31,8 +41,22 @@ func (c *ProductDatabase) CreateCategory(category helperStruct.Category) (respon
 	if exists {
 		return newcategory, fmt.Errorf("category already exists")
 	}
-	query := `INSERT INTO categories(category_name,created_at) VALUES($1,NOW()) RETURNING id,category_name`
-	err := c.DB.Raw(query, category.Name).Scan(&newcategory).Error
+	var parentId *uint
+	if category.Parent_id != 0 {
+		c.DB.Raw(`select exists(select 1 from categories where id=?)`, category.Parent_id).Scan(&exists)
+		if !exists {
+			return newcategory, fmt.Errorf("no such parent category")
+		}
+		parentId = &category.Parent_id
+	}
+	slug, err := newSlug(c.DB, "categories", category.Slug, category.Name)
+	if err != nil {
+		return newcategory, err
+	}
+	metaTitle, metaDescription := metaDefaults(category.Meta_title, category.Meta_description, category.Name, "")
+	query := `INSERT INTO categories(category_name,parent_id,sort_order,slug,meta_title,meta_description,created_at) VALUES($1,$2,$3,$4,$5,$6,NOW())
+	RETURNING id,category_name,parent_id,sort_order,slug,meta_title,meta_description`
+	err = c.DB.Raw(query, category.Name, parentId, category.Sort_order, slug, metaTitle, metaDescription).Scan(&newcategory).Error
 	if err != nil {
 		return newcategory, err
 	}
@@ -42,15 +66,68 @@ func (c *ProductDatabase) CreateCategory(category helperStruct.Category) (respon
 // ProductCategory implements interfaces.ProductRepository.
 func (c *ProductDatabase) UpdateCategory(category helperStruct.Category, id int) (response.Category, error) {
 	var updatedCategory response.Category
-	updateQuery := `UPDATE categories SET category_name=$1,updated_at=NOW() WHERE id=$2 RETURNING id,category_name `
-	err := c.DB.Raw(updateQuery, category.Name, id).Scan(&updatedCategory).Error
+	tx := c.DB.Begin()
+	updateQuery := `UPDATE categories SET category_name=$1,sort_order=$2,
+	meta_title=COALESCE(NULLIF($3,''),meta_title),meta_description=COALESCE(NULLIF($4,''),meta_description),updated_at=NOW() WHERE id=$5 RETURNING id`
+	err := tx.Raw(updateQuery, category.Name, category.Sort_order, category.Meta_title, category.Meta_description, id).Scan(&updatedCategory).Error
 	if err != nil {
+		tx.Rollback()
 		return updatedCategory, err
 	}
 	if updatedCategory.Id == 0 {
+		tx.Rollback()
 		return updatedCategory, fmt.Errorf("no such category to update")
 	}
-	return updatedCategory, nil
+	if category.Slug != "" {
+		if err := changeSlug(tx, "category", id, category.Slug); err != nil {
+			tx.Rollback()
+			return updatedCategory, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return updatedCategory, err
+	}
+	err = c.DB.Raw(`SELECT `+categoryColumns+` FROM categories WHERE id=?`, id).Scan(&updatedCategory).Error
+	return updatedCategory, err
+}
+
+// MoveCategory implements interfaces.ProductRepository.
+func (c *ProductDatabase) MoveCategory(move helperStruct.MoveCategory, id int) (response.Category, error) {
+	var movedCategory response.Category
+	var exists bool
+	c.DB.Raw(`select exists(select 1 from categories where id=?)`, id).Scan(&exists)
+	if !exists {
+		return movedCategory, fmt.Errorf("no such category to move")
+	}
+	var parentId *uint
+	if move.Parent_id != 0 {
+		if int(move.Parent_id) == id {
+			return movedCategory, fmt.Errorf("a category can't be its own parent")
+		}
+		c.DB.Raw(`select exists(select 1 from categories where id=?)`, move.Parent_id).Scan(&exists)
+		if !exists {
+			return movedCategory, fmt.Errorf("no such parent category")
+		}
+		// the new parent must not sit below the category being moved
+		var isDescendant bool
+		err := c.DB.Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id FROM categories WHERE parent_id=$1
+			UNION ALL
+			SELECT categories.id FROM categories JOIN descendants ON categories.parent_id=descendants.id
+		)
+		SELECT EXISTS(SELECT 1 FROM descendants WHERE id=$2)`, id, move.Parent_id).Scan(&isDescendant).Error
+		if err != nil {
+			return movedCategory, err
+		}
+		if isDescendant {
+			return movedCategory, fmt.Errorf("can't move a category under one of its own subcategories")
+		}
+		parentId = &move.Parent_id
+	}
+	updateQuery := `UPDATE categories SET parent_id=$1,sort_order=$2,updated_at=NOW() WHERE id=$3 RETURNING ` + categoryColumns
+	err := c.DB.Raw(updateQuery, parentId, move.Sort_order, id).Scan(&movedCategory).Error
+	return movedCategory, err
 }
 
 // DeleteCategory implements interfaces.ProductRepository.
@@ -61,6 +138,10 @@ func (c *ProductDatabase) DeleteCategory(id int) error {
 	if !exists {
 		return err
 	}
+	c.DB.Raw(`select exists(select 1 from categories where parent_id=?)`, id).Scan(&exists)
+	if exists {
+		return fmt.Errorf("category has subcategories, move or delete them first")
+	}
 
 	errs := c.DB.Exec(`DELETE FROM categories WHERE id=?`, id).Error
 	return errs
@@ -69,7 +150,7 @@ func (c *ProductDatabase) DeleteCategory(id int) error {
 // ListAllCategories implements interfaces.ProductRepository.
 func (c *ProductDatabase) ListAllCategories() ([]response.Category, error) {
 	var categories []response.Category
-	err := c.DB.Raw(`SELECT * FROM categories`).Scan(&categories).Error
+	err := c.DB.Raw(`SELECT ` + categoryColumns + ` FROM categories ORDER BY sort_order,category_name`).Scan(&categories).Error
 	return categories, err
 }
 
@@ -82,10 +163,66 @@ func (c *ProductDatabase) DisplayCategory(id int) (response.Category, error) {
 	if !exists {
 		return category, fmt.Errorf("no such category")
 	}
-	err := c.DB.Raw(`SELECT * FROM categories WHERE id=?`, id).Scan(&category).Error
+	err := c.DB.Raw(`SELECT `+categoryColumns+` FROM categories WHERE id=?`, id).Scan(&category).Error
+	if err != nil {
+		return category, err
+	}
+	// products filed under any subcategory belong to the parent as well
+	err = c.DB.Raw(`
+	WITH RECURSIVE tree AS (
+		SELECT id FROM categories WHERE id=$1
+		UNION ALL
+		SELECT categories.id FROM categories JOIN tree ON categories.parent_id=tree.id
+	)
+	SELECT products.product_name AS name,products.description,products.id,products.brand_id,brands.brandname AS brand,products.category_id,categories.category_name,
+	products.slug,products.meta_title,products.meta_description
+	FROM products
+	JOIN categories ON products.category_id = categories.id
+	LEFT JOIN brands ON brands.id=products.brand_id
+	WHERE products.category_id IN (SELECT id FROM tree)
+	ORDER BY products.created_at DESC`, id).Scan(&category.Products).Error
 	return category, err
 }
 
+// brandCategories loads every category linked to the given brands.
+func (c *ProductDatabase) brandCategories(brandIds ...int) (map[int][]response.Category, error) {
+	var rows []struct {
+		BrandId      int
+		Id           int
+		CategoryName string
+		Slug         string
+	}
+	err := c.DB.Raw(`SELECT brand_categories.brand_id,categories.id,categories.category_name,categories.slug
+	FROM brand_categories
+	JOIN categories ON brand_categories.category_id=categories.id
+	WHERE brand_categories.brand_id IN (?)
+	ORDER BY categories.sort_order,categories.category_name`, brandIds).Scan(&rows).Error
+	categories := make(map[int][]response.Category)
+	for _, row := range rows {
+		categories[row.BrandId] = append(categories[row.BrandId], response.Category{Id: row.Id, CategoryName: row.CategoryName, Slug: row.Slug})
+	}
+	return categories, err
+}
+
+// setBrandCategories replaces the categories linked to a brand, always keeping its primary category.
+func (c *ProductDatabase) setBrandCategories(tx *gorm.DB, brandId int, primary uint, categoryIds []uint) error {
+	if err := tx.Exec(`DELETE FROM brand_categories WHERE brand_id=?`, brandId).Error; err != nil {
+		return err
+	}
+	for _, categoryId := range append([]uint{primary}, categoryIds...) {
+		var exists bool
+		tx.Raw(`select exists(select 1 from categories where id=?)`, categoryId).Scan(&exists)
+		if !exists {
+			return fmt.Errorf("no category found with id %d", categoryId)
+		}
+		err := tx.Exec(`INSERT INTO brand_categories (brand_id,category_id) VALUES (?,?) ON CONFLICT DO NOTHING`, brandId, categoryId).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateBrand implements interfaces.ProductRepository.
 func (c *ProductDatabase) CreateBrand(brand helperStruct.Brand) (response.Brand, error) {
 	var newbrand response.Brand
@@ -94,9 +231,28 @@ func (c *ProductDatabase) CreateBrand(brand helperStruct.Brand) (response.Brand,
 	if exists {
 		return newbrand, fmt.Errorf("brand is already present")
 	}
-	insertQuery := `INSERT INTO brands (brandname,description,category_id,created_at) VALUES ($1,$2,$3,NOW()) RETURNING id,brandname AS name,description,category_id`
-	err := c.DB.Raw(insertQuery, brand.Name, brand.Description, brand.Category_id).Scan(&newbrand).Error
+	if brand.Category_id == 0 && len(brand.Category_ids) > 0 {
+		brand.Category_id = brand.Category_ids[0]
+	}
+	slug, err := newSlug(c.DB, "brands", brand.Slug, brand.Name)
+	if err != nil {
+		return newbrand, err
+	}
+	metaTitle, metaDescription := metaDefaults(brand.Meta_title, brand.Meta_description, brand.Name, brand.Description)
+	tx := c.DB.Begin()
+	insertQuery := `INSERT INTO brands (brandname,description,category_id,slug,meta_title,meta_description,created_at) VALUES ($1,$2,$3,$4,$5,$6,NOW())
+	RETURNING id,brandname AS name,description,category_id,slug,meta_title,meta_description`
+	err = tx.Raw(insertQuery, brand.Name, brand.Description, brand.Category_id, slug, metaTitle, metaDescription).Scan(&newbrand).Error
+	if err != nil {
+		tx.Rollback()
+		return newbrand, err
+	}
+	err = c.setBrandCategories(tx, newbrand.Id, brand.Category_id, brand.Category_ids)
 	if err != nil {
+		tx.Rollback()
+		return newbrand, err
+	}
+	if err := tx.Commit().Error; err != nil {
 		return newbrand, err
 	}
 	selectQuery := `SELECT category_name FROM categories WHERE id=?`
@@ -104,7 +260,9 @@ func (c *ProductDatabase) CreateBrand(brand helperStruct.Brand) (response.Brand,
 	if err != nil {
 		return newbrand, fmt.Errorf("error retrieving category_name")
 	}
-	return newbrand, nil
+	categories, err := c.brandCategories(newbrand.Id)
+	newbrand.Categories = categories[newbrand.Id]
+	return newbrand, err
 
 }
 
@@ -112,19 +270,44 @@ func (c *ProductDatabase) CreateBrand(brand helperStruct.Brand) (response.Brand,
 func (c *ProductDatabase) UpdateBrand(brand helperStruct.Brand, id int) (response.Brand, error) {
 	var updatedBrand response.Brand
 
-	updateQuery := `UPDATE brands SET brandname=$1,description=$2,category_id=$3,updated_at=NOW() WHERE id=$4 RETURNING id,brandname AS name,category_id,description`
-	err := c.DB.Raw(updateQuery, brand.Name, brand.Description, brand.Category_id, id).Scan(&updatedBrand).Error
+	if brand.Category_id == 0 && len(brand.Category_ids) > 0 {
+		brand.Category_id = brand.Category_ids[0]
+	}
+	tx := c.DB.Begin()
+	updateQuery := `UPDATE brands SET brandname=$1,description=$2,category_id=$3,
+	meta_title=COALESCE(NULLIF($4,''),meta_title),meta_description=COALESCE(NULLIF($5,''),meta_description),updated_at=NOW() WHERE id=$6
+	RETURNING id,brandname AS name,category_id,description,meta_title,meta_description`
+	err := tx.Raw(updateQuery, brand.Name, brand.Description, brand.Category_id, brand.Meta_title, brand.Meta_description, id).Scan(&updatedBrand).Error
 	if err != nil {
+		tx.Rollback()
 		return updatedBrand, fmt.Errorf("error updating brand")
 	}
+	if updatedBrand.Id == 0 {
+		tx.Rollback()
+		return updatedBrand, fmt.Errorf("no such brand to update")
+	}
+	err = c.setBrandCategories(tx, id, brand.Category_id, brand.Category_ids)
+	if err != nil {
+		tx.Rollback()
+		return updatedBrand, err
+	}
+	if brand.Slug != "" {
+		if err := changeSlug(tx, "brand", id, brand.Slug); err != nil {
+			tx.Rollback()
+			return updatedBrand, err
+		}
+	}
+	tx.Raw(`SELECT slug FROM brands WHERE id=?`, id).Scan(&updatedBrand.Slug)
+	if err := tx.Commit().Error; err != nil {
+		return updatedBrand, err
+	}
 	selectQuery := `SELECT category_name FROM categories WHERE id=?`
 	err = c.DB.Raw(selectQuery, brand.Category_id).Scan(&updatedBrand.Category_name).Error
 	if err != nil {
 		return updatedBrand, fmt.Errorf("error retrieving category_name")
 	}
-	if updatedBrand.Id == 0 {
-		return updatedBrand, fmt.Errorf("no such brand to update")
-	}
+	categories, err := c.brandCategories(id)
+	updatedBrand.Categories = categories[id]
 	return updatedBrand, err
 }
 
@@ -135,15 +318,37 @@ func (c *ProductDatabase) DeleteBrand(id int) error {
 	if !exists {
 		return fmt.Errorf("no brand found with given id")
 	}
-	err := c.DB.Exec(`DELETE FROM brands WHERE id=?`, id).Error
-	return err
+	var productCount int
+	c.DB.Raw(`SELECT COUNT(*) FROM products WHERE brand_id=?`, id).Scan(&productCount)
+	if productCount > 0 {
+		return fmt.Errorf("brand is still used by %d products, move or delete them first", productCount)
+	}
+	tx := c.DB.Begin()
+	if err := tx.Exec(`DELETE FROM brand_categories WHERE brand_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM discounts WHERE scope='brand' AND scope_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM promotion_items WHERE scope='brand' AND scope_id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM brands WHERE id=?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
 }
 
 // ListAllBrands implements interfaces.ProductRepository.
 func (c *ProductDatabase) ListAllBrands(queryParams helperStruct.QueryParams) ([]response.Brand, int, error) {
 	var brands []response.Brand
 	getBrands := `
-    SELECT brands.brandname AS name,brands.id,brands.category_id,brands.description, categories.category_name
+    SELECT brands.brandname AS name,brands.id,brands.category_id,brands.description, categories.category_name,
+    brands.slug,brands.meta_title,brands.meta_description
     FROM brands
     JOIN categories ON brands.category_id = categories.id
 	
@@ -161,6 +366,17 @@ func (c *ProductDatabase) ListAllBrands(queryParams helperStruct.QueryParams) ([
 		getBrands = fmt.Sprintf("%s LIMIT 10 OFFSET 0", getBrands)
 	}
 	err = c.DB.Raw(getBrands).Scan(&brands).Error
+	if err != nil || len(brands) == 0 {
+		return brands, count, err
+	}
+	brandIds := make([]int, len(brands))
+	for i, brand := range brands {
+		brandIds[i] = brand.Id
+	}
+	categories, err := c.brandCategories(brandIds...)
+	for i := range brands {
+		brands[i].Categories = categories[brands[i].Id]
+	}
 	return brands, count, err
 }
 
@@ -168,40 +384,80 @@ func (c *ProductDatabase) ListAllBrands(queryParams helperStruct.QueryParams) ([
 func (c *ProductDatabase) DisplayBrand(id int) (response.Brand, error) {
 	var brand response.Brand
 	var exists bool
-	c.DB.Raw(`select exists(select 1 from categories where id=?)`, id).Scan(&exists)
+	c.DB.Raw(`select exists(select 1 from brands where id=?)`, id).Scan(&exists)
 	if !exists {
 		return brand, fmt.Errorf("no brand found with given id")
 	}
 
-	err := c.DB.Raw(` SELECT brands.brandname AS name,brands.id,brands.category_id,brands.description, categories.category_name
+	err := c.DB.Raw(` SELECT brands.brandname AS name,brands.id,brands.category_id,brands.description, categories.category_name,
+    brands.slug,brands.meta_title,brands.meta_description
     FROM brands
     JOIN categories ON brands.category_id = categories.id WHERE brands.id=?`, id).Scan(&brand).Error
+	if err != nil {
+		return brand, err
+	}
+	categories, err := c.brandCategories(id)
+	brand.Categories = categories[id]
 	return brand, err
 }
-func (c *ProductDatabase) AddProduct(product helperStruct.Product) (response.Product, error) {
+
+// productCategory picks the category for a product: the requested one when the
+// brand sells in it, otherwise the brand's primary category.
+func (c *ProductDatabase) productCategory(brandId, categoryId uint) (response.Brand, error) {
 	var brand response.Brand
+	err := c.DB.Raw(`
+    SELECT b.id,b.brandname AS name,b.category_id, c.category_name
+    FROM brands b
+    JOIN categories c ON b.category_id = c.id
+    WHERE b.id = ?
+`, brandId).Scan(&brand).Error
+	if err != nil {
+		return brand, err
+	}
+	if brand.Id == 0 {
+		return brand, fmt.Errorf("no brand found with given id")
+	}
+	if categoryId == 0 {
+		return brand, nil
+	}
+	var categoryName string
+	err = c.DB.Raw(`SELECT categories.category_name FROM brand_categories
+	JOIN categories ON brand_categories.category_id=categories.id
+	WHERE brand_categories.brand_id=? AND brand_categories.category_id=?`, brandId, categoryId).Scan(&categoryName).Error
+	if err != nil {
+		return brand, err
+	}
+	if categoryName == "" {
+		return brand, fmt.Errorf("brand %s is not listed in category %d", brand.Name, categoryId)
+	}
+	brand.Category_id = fmt.Sprint(categoryId)
+	brand.Category_name = categoryName
+	return brand, nil
+}
+
+func (c *ProductDatabase) AddProduct(product helperStruct.Product) (response.Product, error) {
 	var newProduct response.Product
 	var exists bool
 	c.DB.Raw(`select exists(select 1 from products where product_name=?)`, product.Name).Scan(&exists)
 	if exists {
 		return newProduct, fmt.Errorf(" product is already present")
 	}
-	err := c.DB.Raw(`
-    SELECT b.brandname AS name,b.category_id, c.category_name
-    FROM brands b
-    JOIN categories c ON b.category_id = c.id
-    WHERE b.id = ?
-`, product.Brand).Scan(&brand).Error
-	fmt.Println(brand.Name)
+	brand, err := c.productCategory(product.Brand, product.Category_id)
+	if err != nil {
+		return newProduct, err
+	}
+	slug, err := newSlug(c.DB, "products", product.Slug, product.Name)
 	if err != nil {
 		return newProduct, err
 	}
-	insertQuery := `INSERT INTO products (product_name,description,brand,category_id,created_at) VALUES ($1,$2,$3,$4,NOW())
-	RETURNING id,product_name AS name,description,brand,category_id`
-	err = c.DB.Raw(insertQuery, product.Name, product.Description, brand.Name, brand.Category_id).Scan(&newProduct).Error
+	metaTitle, metaDescription := metaDefaults(product.Meta_title, product.Meta_description, product.Name, product.Description)
+	insertQuery := `INSERT INTO products (product_name,description,brand_id,category_id,slug,meta_title,meta_description,created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,NOW())
+	RETURNING id,product_name AS name,description,brand_id,category_id,slug,meta_title,meta_description`
+	err = c.DB.Raw(insertQuery, product.Name, product.Description, product.Brand, brand.Category_id, slug, metaTitle, metaDescription).Scan(&newProduct).Error
 	if err != nil {
 		return newProduct, err
 	}
+	newProduct.Brand = brand.Name
 	newProduct.CategoryName = brand.Category_name
 	return newProduct, err
 }
@@ -214,24 +470,31 @@ func (c *ProductDatabase) UpdateProduct(product helperStruct.Product, id int) (r
 	if !exists {
 		return updatedProduct, fmt.Errorf("no  product found with given id")
 	}
-	var brand response.Brand
-
-	selectQuery := `SELECT b.*, c.category_name
-	                FROM brands b
-	                JOIN categories c ON b.category_id = c.id
-	                WHERE b.id = $1`
-
-	err := c.DB.Raw(selectQuery, product.Brand).Scan(&brand).Error
+	brand, err := c.productCategory(product.Brand, product.Category_id)
 	if err != nil {
 		return updatedProduct, err
 	}
 
-	updateQuery := `UPDATE products SET product_name=$1,description=$2,brand=$3,category_id=$4,updated_at=NOW() WHERE id=$5
-	               RETURNING id,product_name AS name,description,brand,category_id`
-	err = c.DB.Raw(updateQuery, product.Name, product.Description, brand.Name, brand.Category_id, id).Scan(&updatedProduct).Error
+	tx := c.DB.Begin()
+	updateQuery := `UPDATE products SET product_name=$1,description=$2,brand_id=$3,category_id=$4,
+	               meta_title=COALESCE(NULLIF($5,''),meta_title),meta_description=COALESCE(NULLIF($6,''),meta_description),updated_at=NOW() WHERE id=$7
+	               RETURNING id,product_name AS name,description,brand_id,category_id,meta_title,meta_description`
+	err = tx.Raw(updateQuery, product.Name, product.Description, product.Brand, brand.Category_id, product.Meta_title, product.Meta_description, id).Scan(&updatedProduct).Error
 	if err != nil {
+		tx.Rollback()
+		return updatedProduct, err
+	}
+	if product.Slug != "" {
+		if err := changeSlug(tx, "product", id, product.Slug); err != nil {
+			tx.Rollback()
+			return updatedProduct, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
 		return updatedProduct, err
 	}
+	c.DB.Raw(`SELECT slug FROM products WHERE id=?`, id).Scan(&updatedProduct.Slug)
+	updatedProduct.Brand = brand.Name
 	updatedProduct.CategoryName = brand.Category_name
 	return updatedProduct, err
 }
@@ -281,11 +544,13 @@ func (c *ProductDatabase) DeleteProduct(id int) error {
 // ListAllProducts implements interfaces.ProductRepository.
 func (c *ProductDatabase) ListAllProducts(queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
 	var products []response.Product
-	getProductDetails := `SELECT products.product_name AS name,products.description,products.id,brand, categories.category_name
+	getProductDetails := `SELECT products.product_name AS name,products.description,products.id,products.brand_id,brands.brandname AS brand,products.category_id, categories.category_name,
+	products.slug,products.meta_title,products.meta_description
 	FROM products
-	JOIN categories ON products.category_id = categories.id`
+	JOIN categories ON products.category_id = categories.id
+	LEFT JOIN brands ON brands.id=products.brand_id`
 	if queryParams.Query != "" && queryParams.Filter != "" {
-		getProductDetails = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", getProductDetails, queryParams.Filter, strings.ToLower(queryParams.Query))
+		getProductDetails = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", getProductDetails, filterColumn(queryParams.Filter), strings.ToLower(queryParams.Query))
 	}
 	var count int
 	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", getProductDetails)
@@ -321,9 +586,11 @@ func (c *ProductDatabase) DisplayProduct(id int) (response.Product, error) {
 	if !exists {
 		return product, fmt.Errorf("no product found with given id")
 	}
-	err := c.DB.Raw(`SELECT products.product_name AS name,products.description,products.id,brand, categories.category_name
+	err := c.DB.Raw(`SELECT products.product_name AS name,products.description,products.id,products.brand_id,brands.brandname AS brand,products.category_id, categories.category_name,
+	products.slug,products.meta_title,products.meta_description
 	                FROM products
 	                JOIN categories ON products.category_id = categories.id
+	                LEFT JOIN brands ON brands.id=products.brand_id
 	                WHERE products.id = ?
 	`, id).Scan(&product).Error
 	return product, err
@@ -355,16 +622,34 @@ func (c *ProductDatabase) AddProductItem(productItem helperStruct.ProductItem) (
 	if exists {
 		return newProductItem, fmt.Errorf("product_item already exists")
 	}
-	insertQuery := `INSERT INTO product_items (id,product_id,sku,qty_in_stock,color,ram,battery,screen_size,storage,price,graphic_processor,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,NOW()) 
-	RETURNING id,sku,color,qty_in_stock,battery,ram,screen_size,storage,price,graphic_processor`
-	err := c.DB.Raw(insertQuery, productItem.Product_id, productItem.Product_id, productItem.Sku, productItem.Qty, productItem.Color, productItem.Ram, productItem.Battery, productItem.Screen_size, productItem.Storage, productItem.Price, productItem.Graphic_Processor).Scan(&newProductItem).Error
+	var product struct {
+		ProductName string
+		Description string
+	}
+	c.DB.Raw(`SELECT product_name,description FROM products WHERE id=?`, productItem.Product_id).Scan(&product)
+	slug, err := newSlug(c.DB, "product_items", productItem.Slug, product.ProductName+" "+productItem.Sku)
 	if err != nil {
 		return newProductItem, err
 	}
+	metaTitle, metaDescription := metaDefaults(productItem.Meta_title, productItem.Meta_description, product.ProductName, product.Description)
+	insertQuery := `INSERT INTO product_items (id,product_id,sku,qty_in_stock,color,ram,battery,screen_size,storage,price,graphic_processor,slug,meta_title,meta_description,created_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,NOW()) 
+	RETURNING id,sku,color,qty_in_stock,battery,ram,screen_size,storage,price,graphic_processor,slug,meta_title,meta_description`
+	err = c.DB.Raw(insertQuery, productItem.Product_id, productItem.Product_id, productItem.Sku, productItem.Qty, productItem.Color, productItem.Ram, productItem.Battery, productItem.Screen_size, productItem.Storage, productItem.Price, productItem.Graphic_Processor, slug, metaTitle, metaDescription).Scan(&newProductItem).Error
+	if err != nil {
+		return newProductItem, err
+	}
+	if err := recordPrices(c.DB, int(newProductItem.Id)); err != nil {
+		return newProductItem, err
+	}
+	// live sales of its product, brand or category cover the new item too
+	if err := applyPriceRules(c.DB); err != nil {
+		return newProductItem, err
+	}
 	err = c.DB.Raw(`
-    SELECT products.*, categories.category_name
+    SELECT products.id,products.product_name,products.description,products.category_id,brands.brandname AS brand,categories.category_name
     FROM products
     JOIN categories ON products.category_id = categories.id
+    LEFT JOIN brands ON brands.id=products.brand_id
     WHERE products.id = ?
 `, productItem.Product_id).Scan(&newProductItem).Error
 
@@ -397,16 +682,38 @@ func (c *ProductDatabase) UpdateProductItem(id int, productItem helperStruct.Pro
 	if productItem.Screen_size < 0 {
 		return response.ProductItem{}, fmt.Errorf("screen_size can't have a negative value")
 	}
-	updateQuery := `UPDATE product_items SET id=$1,product_id=$2,sku=$3,qty_in_stock=$4,color=$5,ram=$6,battery=$7,screen_size=$8,storage=$9,price=$10,image=$11,graphic_processor=$12 WHERE id=$13
-	RETURNING id,sku,color,qty_in_stock,battery,ram,screen_size,price,image,graphic_processor,storage`
-	err := c.DB.Raw(updateQuery, productItem.Product_id, productItem.Product_id, productItem.Sku, productItem.Qty, productItem.Color, productItem.Ram, productItem.Battery, productItem.Screen_size, productItem.Storage, productItem.Price, productItem.Image, productItem.Graphic_Processor, id).Scan(&updatedProductItem).Error
+	tx := c.DB.Begin()
+	updateQuery := `UPDATE product_items SET id=$1,product_id=$2,sku=$3,qty_in_stock=$4,color=$5,ram=$6,battery=$7,screen_size=$8,storage=$9,price=$10,image=$11,graphic_processor=$12,
+	meta_title=COALESCE(NULLIF($13,''),meta_title),meta_description=COALESCE(NULLIF($14,''),meta_description) WHERE id=$15
+	RETURNING id,sku,color,qty_in_stock,battery,ram,screen_size,price,image,graphic_processor,storage,meta_title,meta_description`
+	err := tx.Raw(updateQuery, productItem.Product_id, productItem.Product_id, productItem.Sku, productItem.Qty, productItem.Color, productItem.Ram, productItem.Battery, productItem.Screen_size, productItem.Storage, productItem.Price, productItem.Image, productItem.Graphic_Processor, productItem.Meta_title, productItem.Meta_description, id).Scan(&updatedProductItem).Error
 	if err != nil {
+		tx.Rollback()
 		return updatedProductItem, err
 	}
+	if productItem.Slug != "" {
+		if err := changeSlug(tx, "product_item", int(productItem.Product_id), productItem.Slug); err != nil {
+			tx.Rollback()
+			return updatedProductItem, err
+		}
+	}
+	if err := recordPrices(tx, int(updatedProductItem.Id)); err != nil {
+		tx.Rollback()
+		return updatedProductItem, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return updatedProductItem, err
+	}
+	// percentage sales follow the new price
+	if err := applyPriceRules(c.DB); err != nil {
+		return updatedProductItem, err
+	}
+	c.DB.Raw(`SELECT slug FROM product_items WHERE id=?`, productItem.Product_id).Scan(&updatedProductItem.Slug)
 	err = c.DB.Raw(`
-    SELECT products.*, categories.category_name
+    SELECT products.id,products.product_name,products.description,products.category_id,brands.brandname AS brand,categories.category_name
     FROM products
     JOIN categories ON products.category_id = categories.id
+    LEFT JOIN brands ON brands.id=products.brand_id
     WHERE products.id = ?
 `, productItem.Product_id).Scan(&updatedProductItem).Error
 	return updatedProductItem, err
@@ -416,18 +723,19 @@ func (c *ProductDatabase) UpdateProductItem(id int, productItem helperStruct.Pro
 func (c *ProductDatabase) ListAllProductItems(queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error) {
 	var productItems []response.ProductItem
 	getProductItemDetails := `
-    SELECT product_items.*, products.description,products.product_name,products.brand,image_items.image,categories.category_name,
-	(discounts.discount_percent/100)*product_items.price AS discount_price,
-	product_items.price-((discounts.discount_percent/100)*product_items.price) AS discounted_price
+    SELECT product_items.*, products.description,products.product_name,brands.brandname AS brand,products.category_id,image_items.image,categories.category_name,
+	` + discountColumns + `,
+	` + saleColumns + `
     FROM product_items
     JOIN products ON product_items.product_id = products.id
     JOIN categories ON products.category_id = categories.id
-	LEFT JOIN brands ON brands.brandname=products.brand
-	LEFT JOIN discounts ON brands.id=discounts.brand_id AND expiry_date>NOW()
+	LEFT JOIN brands ON brands.id=products.brand_id
+	` + discountsJoin + `
 	LEFT JOIN image_items ON product_items.id=image_items.product_item_id AND image_items.is_default=true
+	` + saleJoin + `
 `
 	if queryParams.Query != "" && queryParams.Filter != "" {
-		getProductItemDetails = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", getProductItemDetails, queryParams.Filter, strings.ToLower(queryParams.Query))
+		getProductItemDetails = fmt.Sprintf("%s WHERE LOWER(%s) LIKE '%%%s%%'", getProductItemDetails, filterColumn(queryParams.Filter), strings.ToLower(queryParams.Query))
 	}
 	var count int
 	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s)", getProductItemDetails)
@@ -483,6 +791,54 @@ func (c *ProductDatabase) DeleteProductItem(id int) error {
 		return err
 	}
 
+	// Drop the item from collections and its tags
+	if err := tx.Exec(`DELETE FROM collection_items WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM product_item_tags WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Forget its recommendations in both directions
+	if err := tx.Exec(`DELETE FROM recommendations WHERE product_item_id = $1 OR recommended_item_id = $1`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM recommendation_overrides WHERE product_item_id = $1 OR recommended_item_id = $1`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM product_views WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM compare_items WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM price_alerts WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM price_histories WHERE product_item_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM price_rules WHERE scope = 'item' AND scope_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM discounts WHERE scope = 'item' AND scope_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Exec(`DELETE FROM promotion_items WHERE scope = 'item' AND scope_id = ?`, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete the product item itself
 	if err := tx.Exec(`DELETE FROM product_items WHERE id = ?`, id).Error; err != nil {
 		// Rollback the transaction in case of an error
@@ -503,15 +859,16 @@ func (c *ProductDatabase) DisplayProductItem(id int) (response.DisplayProductIte
 		return response.DisplayProductItem{}, fmt.Errorf("no productitem found with given id")
 	}
 	selectQuery := `
-    SELECT product_items.*, products.description,products.product_name,products.brand,image_items.image,categories.category_name,
-	(discounts.discount_percent/100)*product_items.price AS discount_price,
-	product_items.price-((discounts.discount_percent/100)*product_items.price) AS discounted_price
+    SELECT product_items.*, products.description,products.product_name,brands.brandname AS brand,products.category_id,image_items.image,categories.category_name,
+	` + discountColumns + `,
+	` + saleColumns + `
     FROM product_items
     JOIN products ON product_items.product_id = products.id
     JOIN categories ON products.category_id = categories.id
-	LEFT JOIN brands ON products.brand=brands.brandname
-	LEFT JOIN discounts ON brands.id=discounts.brand_id AND expiry_date>NOW()
+	LEFT JOIN brands ON brands.id=products.brand_id
+	` + discountsJoin + `
 	LEFT JOIN image_items ON product_items.id=image_items.product_item_id AND is_default=true
+	` + saleJoin + `
 	WHERE product_items.id=?
 `
 	err := c.DB.Raw(selectQuery, id).Scan(&productItem).Error
@@ -541,28 +898,29 @@ func (c *ProductDatabase) UploadImage(filepath string, productId int) (response.
 }
 
 // DeleteImage implements interfaces.ProductRepository.
-func (c *ProductDatabase) DeleteImage(id int) error {
-	var exists bool
-
-	c.DB.Raw(`SELECT exists (select  1 from image_items WHERE id=?)`, id).Scan(&exists)
-	if !exists {
-		return fmt.Errorf("no image found with the given id")
+func (c *ProductDatabase) DeleteImage(id int) (string, error) {
+	var image string
+	c.DB.Raw(`SELECT image FROM image_items WHERE id=?`, id).Scan(&image)
+	if image == "" {
+		return "", fmt.Errorf("no image found with the given id")
 	}
 
 	err := c.DB.Exec(`DELETE FROM image_items WHERE id=?`, id).Error
-	return err
+	return image, err
 }
 
 // SearchProducts implements interfaces.ProductRepository.
 func (c *ProductDatabase) SearchProducts(queryParams helperStruct.QueryParams, searchProducts string) ([]response.Product, error) {
 	var products []response.Product
 	search := "%" + searchProducts + "%"
-	getProductDetails := fmt.Sprintf(`SELECT products.product_name AS name,products.description,products.id,brand, categories.category_name
+	getProductDetails := fmt.Sprintf(`SELECT products.product_name AS name,products.description,products.id,products.brand_id,brands.brandname AS brand,products.category_id, categories.category_name,
+	products.slug,products.meta_title,products.meta_description
 	FROM products
 	JOIN categories ON products.category_id = categories.id
+	LEFT JOIN brands ON brands.id=products.brand_id
 	WHERE products.product_name ILIKE '%s'`, search)
 	if queryParams.Query != "" && queryParams.Filter != "" {
-		getProductDetails = fmt.Sprintf("%s AND LOWER(%s) LIKE '%%%s%%'", getProductDetails, queryParams.Filter, strings.ToLower(queryParams.Query))
+		getProductDetails = fmt.Sprintf("%s AND LOWER(%s) LIKE '%%%s%%'", getProductDetails, filterColumn(queryParams.Filter), strings.ToLower(queryParams.Query))
 	}
 	if queryParams.SortBy != "" {
 		if queryParams.SortDesc {
@@ -586,3 +944,96 @@ func (c *ProductDatabase) SearchProducts(queryParams helperStruct.QueryParams, s
 
 	return products, err
 }
+
+// ResolveSlug implements interfaces.ProductRepository.
+func (c *ProductDatabase) ResolveSlug(entity, slug string) (int, string, error) {
+	return resolveSlug(c.DB, entity, slug)
+}
+
+// viewHistorySize is how many product views are kept per user.
+const viewHistorySize = 50
+
+// RecordView implements interfaces.ProductRepository.
+func (c *ProductDatabase) RecordView(userId, productItemId int) error {
+	tx := c.DB.Begin()
+	err := tx.Exec(`INSERT INTO product_views (user_id,product_item_id,viewed_at) VALUES ($1,$2,NOW())
+	ON CONFLICT (user_id,product_item_id) DO UPDATE SET viewed_at=EXCLUDED.viewed_at`, userId, productItemId).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Exec(`DELETE FROM product_views WHERE user_id=$1 AND id NOT IN (
+		SELECT id FROM product_views WHERE user_id=$1 ORDER BY viewed_at DESC LIMIT $2)`, userId, viewHistorySize).Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// RecentlyViewed implements interfaces.ProductRepository.
+func (c *ProductDatabase) RecentlyViewed(userId int, limit int) ([]response.ProductItem, error) {
+	var productItems []response.ProductItem
+	err := c.DB.Raw(collectionItemDetails+`
+	JOIN product_views ON product_views.product_item_id=product_items.id
+	WHERE product_views.user_id=? ORDER BY product_views.viewed_at DESC LIMIT ?`, userId, limit).Scan(&productItems).Error
+	return productItems, err
+}
+
+// PersonalizedProducts implements interfaces.ProductRepository.
+func (c *ProductDatabase) PersonalizedProducts(userId int, queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
+	var products []response.Product
+	var count int
+	err := c.DB.Raw(`SELECT COUNT(*) FROM products`).Scan(&count).Error
+	if err != nil {
+		return []response.Product{}, 0, err
+	}
+	// categories and brands the user viewed, wishlisted or bought are ranked first,
+	// purchases weigh the most and views the least
+	getProductDetails := `WITH signals AS (
+		SELECT product_item_id,1.0 AS weight FROM product_views WHERE user_id=@user
+		UNION ALL
+		SELECT product_item_id,2.0 AS weight FROM wishlists WHERE user_id=@user
+		UNION ALL
+		SELECT order_items.product_item_id,3.0 AS weight FROM order_items
+		JOIN orders ON orders.id=order_items.orders_id WHERE orders.user_id=@user AND orders.order_status_id<>5
+	), affinity AS (
+		SELECT products.category_id,products.brand_id,signals.weight FROM signals
+		JOIN product_items ON product_items.id=signals.product_item_id
+		JOIN products ON products.id=product_items.product_id
+	), category_affinity AS (
+		SELECT category_id,SUM(weight) AS score FROM affinity GROUP BY category_id
+	), brand_affinity AS (
+		SELECT brand_id,SUM(weight) AS score FROM affinity GROUP BY brand_id
+	)
+	SELECT products.product_name AS name,products.description,products.id,products.brand_id,brands.brandname AS brand,products.category_id, categories.category_name,
+	products.slug,products.meta_title,products.meta_description
+	FROM products
+	JOIN categories ON products.category_id = categories.id
+	LEFT JOIN brands ON brands.id=products.brand_id
+	LEFT JOIN category_affinity ON category_affinity.category_id=products.category_id
+	LEFT JOIN brand_affinity ON brand_affinity.brand_id=products.brand_id
+	ORDER BY COALESCE(category_affinity.score,0)+COALESCE(brand_affinity.score,0)/2 DESC,products.created_at DESC`
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		getProductDetails = fmt.Sprintf("%s LIMIT %d OFFSET %d", getProductDetails, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	}
+	if queryParams.Limit == 0 || queryParams.Page == 0 {
+		getProductDetails = fmt.Sprintf("%s LIMIT 10 OFFSET 0", getProductDetails)
+	}
+	err = c.DB.Raw(getProductDetails, map[string]interface{}{"user": userId}).Scan(&products).Error
+	return products, count, err
+}
+
+// PriceHistory implements interfaces.ProductRepository.
+func (c *ProductDatabase) PriceHistory(productItemId int, days int) ([]response.PricePoint, error) {
+	var exists bool
+	c.DB.Raw(`SELECT EXISTS(SELECT 1 FROM product_items WHERE id=?)`, productItemId).Scan(&exists)
+	if !exists {
+		return nil, fmt.Errorf("no product item found with given id")
+	}
+	var history []response.PricePoint
+	err := c.DB.Raw(`SELECT price,discount_percent,effective_price,recorded_at FROM price_histories
+	WHERE product_item_id=$1 AND recorded_at>NOW()-make_interval(days => $2)
+	ORDER BY recorded_at,id`, productItemId, days).Scan(&history).Error
+	return history, err
+}
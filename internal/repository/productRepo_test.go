@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+)
+
+func TestMoveCategory(t *testing.T) {
+	categoryExists := "^select exists\\(select 1 from categories where id=(.+)\\)$"
+	tests := []struct {
+		name        string
+		id          int
+		input       helperStruct.MoveCategory
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:  "under another branch",
+			id:    2,
+			input: helperStruct.MoveCategory{Parent_id: 5, Sort_order: 1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(categoryExists).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(categoryExists).WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery("WITH RECURSIVE descendants AS (.+)").WithArgs(2, 5).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectQuery("^UPDATE categories SET parent_id=(.+) RETURNING (.+)$").WithArgs(5, 1, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "category_name", "parent_id", "sort_order"}).AddRow(2, "phones", 5, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "to the top level",
+			id:    2,
+			input: helperStruct.MoveCategory{},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(categoryExists).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery("^UPDATE categories SET parent_id=(.+) RETURNING (.+)$").WithArgs(nil, 0, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "category_name", "parent_id", "sort_order"}).AddRow(2, "phones", nil, 0))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "under itself",
+			id:    2,
+			input: helperStruct.MoveCategory{Parent_id: 2},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(categoryExists).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr: errors.New("a category can't be its own parent"),
+		},
+		{
+			name:  "under one of its subcategories",
+			id:    1,
+			input: helperStruct.MoveCategory{Parent_id: 3},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(categoryExists).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(categoryExists).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery("WITH RECURSIVE descendants AS (.+)").WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			expectedErr: errors.New("can't move a category under one of its own subcategories"),
+		},
+		{
+			name:  "unknown parent",
+			id:    2,
+			input: helperStruct.MoveCategory{Parent_id: 42},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(categoryExists).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(categoryExists).WithArgs(42).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expectedErr: errors.New("no such parent category"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			productRepo := NewProductRepo(gormDB)
+			_, err = productRepo.MoveCategory(tt.input, tt.id)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFilterColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   string
+		expected string
+	}{
+		{name: "brand is read from the brands table", filter: "brand", expected: "brands.brandname"},
+		{name: "product column", filter: "product_name", expected: "product_name"},
+		{name: "qualified column", filter: "categories.category_name", expected: "categories.category_name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filterColumn(tt.filter))
+		})
+	}
+}
+
+func TestRecordView(t *testing.T) {
+	upsertView := "^INSERT INTO product_views (.+) ON CONFLICT \\(user_id,product_item_id\\) DO UPDATE (.+)$"
+	trimHistory := "^DELETE FROM product_views WHERE user_id=(.+) LIMIT (.+)\\)$"
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "view recorded and history trimmed",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertView).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(trimHistory).WithArgs(7, viewHistorySize).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "insert fails",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertView).WithArgs(7, 3).WillReturnError(errors.New("no such product item"))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("no such product item"),
+		},
+		{
+			name: "trim fails",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(upsertView).WithArgs(7, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(trimHistory).WithArgs(7, viewHistorySize).WillReturnError(errors.New("connection reset"))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("connection reset"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			productRepo := NewProductRepo(gormDB)
+			err = productRepo.RecordView(7, 3)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPersonalizedProducts(t *testing.T) {
+	tests := []struct {
+		name        string
+		queryParams helperStruct.QueryParams
+		page        string
+	}{
+		{name: "first page by default", queryParams: helperStruct.QueryParams{}, page: "LIMIT 10 OFFSET 0"},
+		{name: "requested page", queryParams: helperStruct.QueryParams{Page: 3, Limit: 5}, page: "LIMIT 5 OFFSET 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			mock.ExpectQuery("^SELECT COUNT\\(\\*\\) FROM products$").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+			// the user's own views, wishlist and orders all feed the ranking
+			mock.ExpectQuery("^WITH signals AS (.+) ORDER BY (.+) "+tt.page+"$").WithArgs(7, 7, 7).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "category_id", "slug"}).
+					AddRow(4, "pixel 8", 3, "pixel-8").
+					AddRow(9, "galaxy s24", 3, "galaxy-s24"))
+
+			productRepo := NewProductRepo(gormDB)
+			products, count, err := productRepo.PersonalizedProducts(7, tt.queryParams)
+			assert.NoError(t, err)
+			assert.Equal(t, 12, count)
+			assert.Equal(t, 2, len(products))
+			assert.Equal(t, 4, products[0].Id)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/repository/interfaces"
+)
+
+type RecommendationDatabase struct {
+	DB *gorm.DB
+}
+
+func NewRecommendationRepo(DB *gorm.DB) interfaces.RecommendationRepository {
+	return &RecommendationDatabase{
+		DB: DB,
+	}
+}
+
+// recommendationsPerItem is how many computed recommendations are kept for each item and kind.
+const recommendationsPerItem = 20
+
+// RefreshRecommendations implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) RefreshRecommendations() error {
+	tx := r.DB.Begin()
+	if err := tx.Exec(`DELETE FROM recommendations`).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	// items that shared a delivered order, scored by the number of such orders
+	boughtTogether := `INSERT INTO recommendations (product_item_id,recommended_item_id,kind,score,updated_at)
+	SELECT product_item_id,recommended_item_id,'bought_together',score,NOW() FROM (
+		SELECT a.product_item_id,b.product_item_id AS recommended_item_id,COUNT(DISTINCT a.orders_id) AS score,
+		ROW_NUMBER() OVER (PARTITION BY a.product_item_id ORDER BY COUNT(DISTINCT a.orders_id) DESC,b.product_item_id) AS rank
+		FROM order_items a
+		JOIN order_items b ON a.orders_id=b.orders_id AND a.product_item_id<>b.product_item_id
+		JOIN orders ON orders.id=a.orders_id
+		WHERE orders.order_status_id=4
+		GROUP BY a.product_item_id,b.product_item_id
+	) co_occurrence WHERE rank<=?`
+	if err := tx.Exec(boughtTogether, recommendationsPerItem).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	// items of other products in the same category or brand, scored by how many specs they share
+	similar := `INSERT INTO recommendations (product_item_id,recommended_item_id,kind,score,updated_at)
+	SELECT product_item_id,recommended_item_id,'similar',score,NOW() FROM (
+		SELECT a.id AS product_item_id,b.id AS recommended_item_id,score,
+		ROW_NUMBER() OVER (PARTITION BY a.id ORDER BY score DESC,b.id) AS rank
+		FROM product_items a
+		JOIN products pa ON pa.id=a.product_id
+		JOIN product_items b ON b.product_id<>a.product_id
+		JOIN products pb ON pb.id=b.product_id,
+		LATERAL (SELECT
+			CASE WHEN pa.category_id=pb.category_id THEN 3 ELSE 0 END +
+			CASE WHEN pa.brand_id=pb.brand_id THEN 2 ELSE 0 END +
+			CASE WHEN ABS(a.price-b.price)<=a.price*0.2 THEN 2 ELSE 0 END +
+			CASE WHEN a.ram=b.ram THEN 1 ELSE 0 END +
+			CASE WHEN a.storage=b.storage THEN 1 ELSE 0 END +
+			CASE WHEN a.graphic_processor=b.graphic_processor THEN 1 ELSE 0 END AS score) similarity
+		WHERE pa.category_id=pb.category_id OR pa.brand_id=pb.brand_id
+	) similarity WHERE rank<=?`
+	if err := tx.Exec(similar, recommendationsPerItem).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// Recommendations implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) Recommendations(productItemIds []int, kind string, limit int) ([]response.ProductItem, error) {
+	if len(productItemIds) == 0 {
+		return []response.ProductItem{}, nil
+	}
+	// pinned items come first in the admin's order, then the computed ones by score
+	getIds := `WITH pinned AS (
+		SELECT recommended_item_id AS id,MIN(position) AS position FROM recommendation_overrides
+		WHERE product_item_id IN @ids AND kind=@kind AND action='pin' GROUP BY recommended_item_id
+	), computed AS (
+		SELECT recommended_item_id AS id,SUM(score) AS score FROM recommendations
+		WHERE product_item_id IN @ids AND kind=@kind GROUP BY recommended_item_id
+	), excluded AS (
+		SELECT recommended_item_id AS id FROM recommendation_overrides
+		WHERE product_item_id IN @ids AND kind=@kind AND action='exclude'
+	)
+	SELECT ranked.id FROM (
+		SELECT id,0 AS tier,position,0 AS score FROM pinned
+		UNION ALL
+		SELECT id,1 AS tier,0 AS position,score FROM computed WHERE id NOT IN (SELECT id FROM pinned)
+	) ranked
+	JOIN product_items ON product_items.id=ranked.id AND product_items.qty_in_stock>0
+	WHERE ranked.id NOT IN (SELECT id FROM excluded) AND ranked.id NOT IN @ids
+	ORDER BY ranked.tier,ranked.position,ranked.score DESC,ranked.id LIMIT @limit`
+	var ids []uint
+	err := r.DB.Raw(getIds, map[string]interface{}{"ids": productItemIds, "kind": kind, "limit": limit}).Scan(&ids).Error
+	if err != nil || len(ids) == 0 {
+		return []response.ProductItem{}, err
+	}
+	var items []response.ProductItem
+	err = r.DB.Raw(collectionItemDetails+` WHERE product_items.id IN ?`, ids).Scan(&items).Error
+	if err != nil {
+		return []response.ProductItem{}, err
+	}
+	byId := make(map[uint]response.ProductItem, len(items))
+	for _, item := range items {
+		byId[item.Id] = item
+	}
+	ordered := make([]response.ProductItem, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := byId[id]; ok {
+			ordered = append(ordered, item)
+		}
+	}
+	return ordered, nil
+}
+
+// CartProductItems implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) CartProductItems(userId int) ([]int, error) {
+	var ids []int
+	err := r.DB.Raw(`SELECT cart_items.product_item_id FROM cart_items
+	JOIN carts ON carts.id=cart_items.carts_id WHERE carts.user_id=?`, userId).Scan(&ids).Error
+	return ids, err
+}
+
+// AddOverride implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) AddOverride(override helperStruct.RecommendationOverride) (response.RecommendationOverride, error) {
+	var newOverride response.RecommendationOverride
+	if override.Product_item_id == override.Recommended_item_id {
+		return newOverride, fmt.Errorf("an item cannot be recommended for itself")
+	}
+	var count int
+	r.DB.Raw(`SELECT COUNT(*) FROM product_items WHERE id IN (?,?)`, override.Product_item_id, override.Recommended_item_id).Scan(&count)
+	if count != 2 {
+		return newOverride, fmt.Errorf("no product item found with given id")
+	}
+	addOverride := `INSERT INTO recommendation_overrides (product_item_id,recommended_item_id,kind,action,position,created_at)
+	VALUES ($1,$2,$3,$4,$5,NOW())
+	ON CONFLICT (product_item_id,recommended_item_id,kind) DO UPDATE SET action=EXCLUDED.action,position=EXCLUDED.position
+	RETURNING id`
+	var id int
+	err := r.DB.Raw(addOverride, override.Product_item_id, override.Recommended_item_id, override.Kind, override.Action, override.Position).Scan(&id).Error
+	if err != nil {
+		return newOverride, err
+	}
+	err = r.DB.Raw(overrideDetails+` WHERE recommendation_overrides.id=?`, id).Scan(&newOverride).Error
+	return newOverride, err
+}
+
+const overrideDetails = `SELECT recommendation_overrides.*,products.product_name || ' ' || product_items.sku AS recommended_item_name
+	FROM recommendation_overrides
+	JOIN product_items ON product_items.id=recommendation_overrides.recommended_item_id
+	JOIN products ON products.id=product_items.product_id`
+
+// DeleteOverride implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) DeleteOverride(id int) error {
+	var exists bool
+	r.DB.Raw(`SELECT EXISTS (SELECT 1 FROM recommendation_overrides WHERE id=?)`, id).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("no such override to delete")
+	}
+	return r.DB.Exec(`DELETE FROM recommendation_overrides WHERE id=?`, id).Error
+}
+
+// ListOverrides implements interfaces.RecommendationRepository.
+func (r *RecommendationDatabase) ListOverrides(productItemId int) ([]response.RecommendationOverride, error) {
+	var overrides []response.RecommendationOverride
+	listOverrides := overrideDetails
+	var args []interface{}
+	if productItemId != 0 {
+		listOverrides += ` WHERE recommendation_overrides.product_item_id=?`
+		args = append(args, productItemId)
+	}
+	listOverrides += ` ORDER BY recommendation_overrides.product_item_id,recommendation_overrides.kind,recommendation_overrides.action,recommendation_overrides.position`
+	err := r.DB.Raw(listOverrides, args...).Scan(&overrides).Error
+	return overrides, err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRecommendations(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []int
+		buildStub func(mock sqlmock.Sqlmock)
+		expected  []uint
+	}{
+		{
+			name:      "nothing to recommend for",
+			input:     []int{},
+			buildStub: func(mock sqlmock.Sqlmock) {},
+			expected:  []uint{},
+		},
+		{
+			name:  "ranked order is kept",
+			input: []int{1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^WITH pinned AS (.+) LIMIT (.+)$").WithArgs(1, "similar", 1, "similar", 1, "similar", 1, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9).AddRow(4).AddRow(7))
+				mock.ExpectQuery("(.+) WHERE product_items.id IN \\((.+)\\)$").WithArgs(9, 4, 7).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4).AddRow(7).AddRow(9))
+			},
+			expected: []uint{9, 4, 7},
+		},
+		{
+			name:  "no recommendations",
+			input: []int{1},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^WITH pinned AS (.+) LIMIT (.+)$").WithArgs(1, "similar", 1, "similar", 1, "similar", 1, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expected: []uint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			recommendationRepo := NewRecommendationRepo(gormDB)
+			items, err := recommendationRepo.Recommendations(tt.input, "similar", 6)
+			assert.NoError(t, err)
+			ids := []uint{}
+			for _, item := range items {
+				ids = append(ids, item.Id)
+			}
+			assert.Equal(t, tt.expected, ids)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"fmt"
+	"log"
 
 	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
 	"main.go/internal/repository/interfaces"
 )
 
@@ -17,6 +20,12 @@ func NewReferralRepo(DB *gorm.DB) interfaces.ReferralRepository {
 	}
 }
 
+func referralSettings(db *gorm.DB) (response.ReferralSettings, error) {
+	var settings response.ReferralSettings
+	err := db.Raw(`SELECT * FROM referral_settings ORDER BY id LIMIT 1`).Scan(&settings).Error
+	return settings, err
+}
+
 // AddReferral implements interfaces.ReferralRepository.
 func (c *ReferralDatabase) AddReferral(userId int, referralId string) error {
 	addReferral := `INSERT INTO referrals(user_id,referral_id) VALUES ($1,$2) `
@@ -27,75 +36,224 @@ func (c *ReferralDatabase) AddReferral(userId int, referralId string) error {
 	return nil
 }
 
+// ReferralCodeExists implements interfaces.ReferralRepository.
+func (r *ReferralDatabase) ReferralCodeExists(referralId string) (bool, error) {
+	var exists bool
+	err := r.DB.Raw(`SELECT EXISTS (SELECT 1 FROM referrals WHERE referral_id=?)`, referralId).Scan(&exists).Error
+	return exists, err
+}
+
+// UpdateReferralCode implements interfaces.ReferralRepository.
+func (r *ReferralDatabase) UpdateReferralCode(userId int, referralId string) error {
+	update := r.DB.Exec(`UPDATE referrals SET referral_id=$1 WHERE user_id=$2`, referralId, userId)
+	if update.Error != nil {
+		return fmt.Errorf("error updating the referral code")
+	}
+	if update.RowsAffected == 0 {
+		return r.AddReferral(userId, referralId)
+	}
+	return nil
+}
+
 // ReferralOffer implements interfaces.ReferralRepository.
-func (r *ReferralDatabase) ReferralOffer(referralId string, userId int) error {
-	tx := r.DB.Begin()
+// Nothing is credited yet, the rewards wait for the referee's first order.
+func (r *ReferralDatabase) ReferralOffer(referralId string, userId int) (int, error) {
+	settings, err := referralSettings(r.DB)
+	if err != nil {
+		return 0, err
+	}
+	if !settings.IsActive {
+		return 0, fmt.Errorf("the referral program isn't running right now")
+	}
 	var exists bool
-	tx.Raw(`SELECT EXISTS (SELECT 1 FROM user_referrals WHERE user_id=?)`, userId).Scan(&exists)
+	r.DB.Raw(`SELECT EXISTS (SELECT 1 FROM user_referrals WHERE user_id=?)`, userId).Scan(&exists)
 	if exists {
-		tx.Rollback()
-		return fmt.Errorf("you have already redeemed a referral offer please refer others to earn more exciting rewards")
+		return 0, fmt.Errorf("you have already redeemed a referral offer please refer others to earn more exciting rewards")
+	}
+	// referral codes are for new customers, not for orders already on their way
+	r.DB.Raw(`SELECT EXISTS (SELECT 1 FROM orders WHERE user_id=? AND order_status_id<>5)`, userId).Scan(&exists)
+	if exists {
+		return 0, fmt.Errorf("referral codes can only be used before your first order")
 	}
 	var referredBy uint
-	tx.Raw(`SELECT user_id FROM referrals WHERE referral_id=?`, referralId).Scan(&referredBy)
+	r.DB.Raw(`SELECT user_id FROM referrals WHERE referral_id=?`, referralId).Scan(&referredBy)
 	if referredBy == 0 {
-		tx.Rollback()
-		return fmt.Errorf("invalid referralId Please enter a valid referral id")
+		return 0, fmt.Errorf("invalid referralId Please enter a valid referral id")
 	}
 	if referredBy == uint(userId) {
-		tx.Rollback()
-		return fmt.Errorf("you can't refer yourselves")
+		return 0, fmt.Errorf("you can't refer yourselves")
 	}
-	err := tx.Exec(`INSERT INTO user_referrals (user_id,referred_by) VALUES ($1,$2)`, userId, referredBy).Error
+	err = r.DB.Exec(`INSERT INTO user_referrals (user_id,referred_by,status,referee_reward,referrer_reward,created_at) VALUES ($1,$2,'pending',$3,$4,NOW())`,
+		userId, referredBy, settings.RefereeReward, settings.ReferrerReward).Error
+	if err != nil {
+		return 0, err
+	}
+	return settings.RefereeReward, nil
+}
+
+// dueReferral is a pending referral whose referee has a delivered order past
+// its return window.
+type dueReferral struct {
+	Id             uint
+	UserId         int
+	ReferredBy     int
+	RefereeReward  int
+	ReferrerReward int
+	OrderId        uint
+}
+
+// ReleaseReferralRewards implements interfaces.ReferralRepository. It credits
+// the rewards of pending referrals whose referee has a delivered order past
+// its return window. Once a referrer has been rewarded MaxRewardsPerReferrer
+// times only the referee is. A referral that fails stays pending for the next
+// run without holding up the others.
+func (r *ReferralDatabase) ReleaseReferralRewards() error {
+	settings, err := referralSettings(r.DB)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
-	var walletAmount int
-	getWalletAmount := `SELECT amount FROM wallets WHERE user_id=$1`
-	err = tx.Raw(getWalletAmount, userId).Scan(&walletAmount).Error
+	var due []dueReferral
+	err = r.DB.Raw(`SELECT user_referrals.id,user_referrals.user_id,user_referrals.referred_by,user_referrals.referee_reward,
+	user_referrals.referrer_reward,delivered.id AS order_id
+	FROM user_referrals JOIN users ON users.id=user_referrals.user_id
+	JOIN LATERAL (
+		SELECT orders.id FROM orders WHERE orders.user_id=users.id AND orders.order_status_id=4
+		AND COALESCE(orders.delivered_at,orders.order_date)+`+returnWindowDays+`*INTERVAL '1 day'<NOW()
+		ORDER BY orders.order_date LIMIT 1
+	) delivered ON true
+	WHERE user_referrals.status='pending' ORDER BY user_referrals.id`, defaultReturnWindowDays).Scan(&due).Error
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("error retrieving amount from wallet")
+		return err
+	}
+	for _, referral := range due {
+		if err := releaseReferral(r.DB, settings, referral); err != nil {
+			log.Printf("releasing referral %d: %v", referral.Id, err)
+		}
 	}
-	insertWalletHistory := `INSERT INTO wallet_histories (recent_transaction,user_id,balance,time) VALUES ($1,$2,$3,NOW())`
-	walletHistory := fmt.Sprintf("%d + 20", walletAmount)
-	err = tx.Exec(insertWalletHistory, walletHistory, userId, (walletAmount + 20)).Error
+	return nil
+}
+
+// releaseReferral credits both sides of a referral in one transaction.
+func releaseReferral(db *gorm.DB, settings response.ReferralSettings, referral dueReferral) error {
+	tx := db.Begin()
+	// concurrent releases for the same referrer wait here, so the cap is counted once at a time
+	var referrerId int
+	err := tx.Raw(`SELECT id FROM users WHERE id=? FOR UPDATE`, referral.ReferredBy).Scan(&referrerId).Error
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("error inserting wallet history")
+		return err
 	}
-	updateWallets := `UPDATE wallets SET amount=amount+20 WHERE user_id=?`
-	err = tx.Exec(updateWallets, userId).Error
+	var rewarded int
+	err = tx.Raw(`SELECT COUNT(*) FROM user_referrals WHERE referred_by=? AND status='rewarded' AND referrer_reward>0`, referral.ReferredBy).Scan(&rewarded).Error
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	var walletAmountOfReferrer int
-	getWalletAmountOfReferrer := `SELECT amount FROM wallets WHERE user_id=$1`
-	err = tx.Raw(getWalletAmountOfReferrer, referredBy).Scan(&walletAmountOfReferrer).Error
-	if err != nil {
+	if settings.MaxRewardsPerReferrer > 0 && rewarded >= settings.MaxRewardsPerReferrer {
+		referral.ReferrerReward = 0
+	}
+	release := tx.Exec(`UPDATE user_referrals SET status='rewarded',referrer_reward=$1,order_id=$2,rewarded_at=NOW() WHERE id=$3 AND status='pending'`,
+		referral.ReferrerReward, referral.OrderId, referral.Id)
+	if release.Error != nil {
 		tx.Rollback()
-		return fmt.Errorf("error retrieving amount from wallet")
+		return release.Error
 	}
-	insertWalletHistoryforReferrer := `INSERT INTO wallet_histories (recent_transaction,user_id,balance,time) VALUES ($1,$2,$3,NOW())`
-	walletHistoryForReferrer := fmt.Sprintf("%d + 50", walletAmountOfReferrer)
-	err = tx.Exec(insertWalletHistoryforReferrer, walletHistoryForReferrer, referredBy, (walletAmountOfReferrer + 50)).Error
-	if err != nil {
+	if release.RowsAffected == 0 {
+		// released by another run
 		tx.Rollback()
-		return fmt.Errorf("error inserting wallet history")
+		return nil
 	}
-	updateWalletsForReferrer := `UPDATE wallets SET amount=amount+50 WHERE user_id=?`
-	err = tx.Exec(updateWalletsForReferrer, referredBy).Error
+	err = postWalletTransaction(tx, walletPosting{
+		UserId:      referral.UserId,
+		Type:        walletReferralBonus,
+		Amount:      referral.RefereeReward,
+		ReferralId:  referral.Id,
+		Key:         fmt.Sprintf("referral:%d:referee", referral.Id),
+		Description: "referral bonus for joining",
+	})
 	if err != nil {
 		tx.Rollback()
-		return fmt.Errorf("error updating wallet of referrer")
+		return err
 	}
+	err = postWalletTransaction(tx, walletPosting{
+		UserId:      referral.ReferredBy,
+		Type:        walletReferralBonus,
+		Amount:      referral.ReferrerReward,
+		ReferralId:  referral.Id,
+		Key:         fmt.Sprintf("referral:%d:referrer", referral.Id),
+		Description: "referral bonus for inviting a friend",
+	})
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
-	if err = tx.Commit().Error; err != nil {
-		return err
+	return tx.Commit().Error
+}
+
+// ReferralSettings implements interfaces.ReferralRepository.
+func (r *ReferralDatabase) ReferralSettings() (response.ReferralSettings, error) {
+	return referralSettings(r.DB)
+}
+
+// UpdateReferralSettings implements interfaces.ReferralRepository.
+func (r *ReferralDatabase) UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error) {
+	updateSettings := `UPDATE referral_settings SET is_active=COALESCE($1,is_active),referee_reward=$2,referrer_reward=$3,
+	max_rewards_per_referrer=$4,updated_at=NOW()`
+	err := r.DB.Exec(updateSettings, settings.IsActive, settings.RefereeReward, settings.ReferrerReward, settings.MaxRewardsPerReferrer).Error
+	if err != nil {
+		return response.ReferralSettings{}, err
 	}
-	return nil
+	return referralSettings(r.DB)
+}
+
+// ReferralDashboard implements interfaces.ReferralRepository.
+func (r *ReferralDatabase) ReferralDashboard(userId int) (response.ReferralDashboard, error) {
+	var dashboard response.ReferralDashboard
+	err := r.DB.Raw(`SELECT referral_id FROM referrals WHERE user_id=?`, userId).Scan(&dashboard.ReferralCode).Error
+	if err != nil {
+		return response.ReferralDashboard{}, err
+	}
+	dashboard.Referrals = []response.ReferralEntry{}
+	err = r.DB.Raw(`SELECT users.name,user_referrals.status,user_referrals.referrer_reward AS reward,users.created_at AS joined_at,user_referrals.rewarded_at
+	FROM user_referrals JOIN users ON users.id=user_referrals.user_id
+	WHERE user_referrals.referred_by=? ORDER BY users.created_at DESC,user_referrals.id DESC`, userId).Scan(&dashboard.Referrals).Error
+	if err != nil {
+		return response.ReferralDashboard{}, err
+	}
+	settings, err := referralSettings(r.DB)
+	if err != nil {
+		return response.ReferralDashboard{}, err
+	}
+	rewards := 0
+	for _, referral := range dashboard.Referrals {
+		dashboard.Invited++
+		if referral.Status == "rewarded" {
+			dashboard.Rewarded++
+			dashboard.Earned += referral.Reward
+			if referral.Reward > 0 {
+				rewards++
+			}
+		} else {
+			dashboard.Pending++
+		}
+	}
+	pendingRewards := dashboard.Pending
+	if settings.MaxRewardsPerReferrer > 0 {
+		left := settings.MaxRewardsPerReferrer - rewards
+		if left < 0 {
+			left = 0
+		}
+		dashboard.RewardsLeft = &left
+		if pendingRewards > left {
+			pendingRewards = left
+		}
+	}
+	// the earliest pending referrals are released first
+	for i := len(dashboard.Referrals) - 1; i >= 0 && pendingRewards > 0; i-- {
+		if dashboard.Referrals[i].Status == "pending" {
+			dashboard.PendingAmount += dashboard.Referrals[i].Reward
+			pendingRewards--
+		}
+	}
+	return dashboard, nil
 }
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestReleaseReferralRewards(t *testing.T) {
+	settings := "^SELECT \\* FROM referral_settings ORDER BY id LIMIT 1$"
+	// only referrals whose referee has a delivered order past its return window are due
+	dueReferrals := "^SELECT user_referrals.id,(.+) orders.order_status_id=4 AND COALESCE\\(orders.delivered_at,orders.order_date\\)\\+(.+)<NOW\\(\\) (.+) WHERE user_referrals.status='pending' ORDER BY user_referrals.id$"
+	lockReferrer := "^SELECT id FROM users WHERE id=(.+) FOR UPDATE$"
+	rewardedCount := "^SELECT COUNT\\(\\*\\) FROM user_referrals WHERE referred_by=(.+) AND status='rewarded' AND referrer_reward>0$"
+	release := "^UPDATE user_referrals SET status='rewarded',(.+) WHERE id=(.+) AND status='pending'$"
+	settingsRows := func(maxRewards int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "is_active", "referee_reward", "referrer_reward", "max_rewards_per_referrer"}).
+			AddRow(1, true, 20, 50, maxRewards)
+	}
+	dueRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "user_id", "referred_by", "referee_reward", "referrer_reward", "order_id"}).
+			AddRow(4, 7, 2, 20, 50, 11)
+	}
+	expectCredit := func(mock sqlmock.Sqlmock, referralId, userId, amount, balance int, key, description string) {
+		mock.ExpectQuery("^SELECT \\* FROM wallets (.+) FOR UPDATE$").WithArgs(userId).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(userId, userId, 0))
+		mock.ExpectQuery("^INSERT INTO wallet_transactions (.+)$").WithArgs(walletReferralBonus, key, 0, referralId, description).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userId + 100))
+		mock.ExpectExec("^INSERT INTO wallet_entries (.+)$").WithArgs(userId+100, userId, amount, balance, "referral_rewards", -amount).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("^UPDATE wallets SET amount=(.+)$").WithArgs(balance, userId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	tests := []struct {
+		name        string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "both sides are credited",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(0))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).WillReturnRows(dueRows())
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(rewardedCount).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+				mock.ExpectExec(release).WithArgs(50, 11, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectCredit(mock, 4, 7, 20, 20, "referral:4:referee", "referral bonus for joining")
+				expectCredit(mock, 4, 2, 50, 50, "referral:4:referrer", "referral bonus for inviting a friend")
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "referrer past the cap only credits the referee",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(3))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).WillReturnRows(dueRows())
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(rewardedCount).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+				mock.ExpectExec(release).WithArgs(0, 11, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectCredit(mock, 4, 7, 20, 20, "referral:4:referee", "referral bonus for joining")
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "referral released by another run is skipped",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(0))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).WillReturnRows(dueRows())
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(rewardedCount).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec(release).WithArgs(50, 11, 4).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "nothing due yet",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(0))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "referred_by", "referee_reward", "referrer_reward", "order_id"}))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "missing wallet keeps the referral pending",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(0))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).WillReturnRows(dueRows())
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+				mock.ExpectQuery(rewardedCount).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec(release).WithArgs(50, 11, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("^SELECT \\* FROM wallets (.+) FOR UPDATE$").WithArgs(7).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}))
+				mock.ExpectRollback()
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "a failing referral doesn't hold up the next one",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(settings).WillReturnRows(settingsRows(0))
+				mock.ExpectQuery(dueReferrals).WithArgs(defaultReturnWindowDays).
+					WillReturnRows(dueRows().AddRow(5, 8, 3, 20, 50, 12))
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(2).WillReturnError(errors.New("lock timeout"))
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectQuery(lockReferrer).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectQuery(rewardedCount).WithArgs(3).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec(release).WithArgs(50, 12, 5).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectCredit(mock, 5, 8, 20, 20, "referral:5:referee", "referral bonus for joining")
+				expectCredit(mock, 5, 3, 50, 50, "referral:5:referrer", "referral bonus for inviting a friend")
+				mock.ExpectCommit()
+			},
+			expectedErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			referralRepo := NewReferralRepo(gormDB)
+			err = referralRepo.ReleaseReferralRewards()
+			assert.Equal(t, tt.expectedErr, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
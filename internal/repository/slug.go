@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// slugTables maps the entity names used in slug_redirects to their tables.
+var slugTables = map[string]string{
+	"category":     "categories",
+	"brand":        "brands",
+	"product":      "products",
+	"product_item": "product_items",
+}
+
+// numericSlugPrefixes lists the tables whose pages take an id or a slug. Their
+// slugs can't be just a number, generated ones get a prefix instead so a
+// brand called 1984 gets brand-1984.
+var numericSlugPrefixes = map[string]string{
+	"categories":    "category",
+	"brands":        "brand",
+	"products":      "product",
+	"product_items": "item",
+	"collections":   "collection",
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lowercases the text and joins its words with hyphens.
+func slugify(text string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// numericSlug reports whether a slug would be read as an id. Catalog pages
+// take numeric path parameters as ids, so such a slug could never resolve.
+func numericSlug(slug string) bool {
+	_, err := strconv.Atoi(slug)
+	return err == nil
+}
+
+// uniqueSlug returns base, or base with a numeric suffix when another row
+// of the table already uses it. id is the row the slug is for, 0 for new rows.
+func uniqueSlug(db *gorm.DB, table, base string, id int) string {
+	base = slugify(base)
+	if base == "" {
+		base = "untitled"
+	}
+	if prefix, ok := numericSlugPrefixes[table]; ok && numericSlug(base) {
+		base = prefix + "-" + base
+	}
+	slug := base
+	for i := 2; ; i++ {
+		var exists bool
+		db.Raw(fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE slug=? AND id<>?)`, table), slug, id).Scan(&exists)
+		if !exists {
+			return slug
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// newSlug validates a slug chosen by an admin or generates one from name.
+func newSlug(db *gorm.DB, table, requested, name string) (string, error) {
+	if requested == "" {
+		return uniqueSlug(db, table, name, 0), nil
+	}
+	if requested != slugify(requested) {
+		return "", fmt.Errorf("slug may only contain lowercase letters, digits and single hyphens")
+	}
+	if _, ok := numericSlugPrefixes[table]; ok && numericSlug(requested) {
+		return "", fmt.Errorf("slug cannot be only digits, it would be read as an id")
+	}
+	var exists bool
+	db.Raw(fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE slug=?)`, table), requested).Scan(&exists)
+	if exists {
+		return "", fmt.Errorf("slug %s is already in use", requested)
+	}
+	return requested, nil
+}
+
+// metaDefaults fills meta tags left empty by the admin from the entity itself.
+func metaDefaults(title, description, name, text string) (string, string) {
+	if title == "" {
+		title = name
+	}
+	if description == "" {
+		description = text
+		if runes := []rune(description); len(runes) > 160 {
+			description = strings.TrimSpace(string(runes[:157])) + "..."
+		}
+	}
+	return title, description
+}
+
+// changeSlug gives a row a new slug chosen by an admin and keeps the old one
+// as a redirect so existing links still resolve.
+func changeSlug(tx *gorm.DB, entity string, id int, slug string) error {
+	table := slugTables[entity]
+	if slug != slugify(slug) || slug == "" {
+		return fmt.Errorf("slug may only contain lowercase letters, digits and single hyphens")
+	}
+	if numericSlug(slug) {
+		return fmt.Errorf("slug cannot be only digits, it would be read as an id")
+	}
+	var current string
+	tx.Raw(fmt.Sprintf(`SELECT COALESCE(slug,'') FROM %s WHERE id=?`, table), id).Scan(&current)
+	if current == slug {
+		return nil
+	}
+	var exists bool
+	tx.Raw(fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE slug=? AND id<>?)`, table), slug, id).Scan(&exists)
+	if exists {
+		return fmt.Errorf("slug %s is already in use", slug)
+	}
+	// the new slug wins over any redirect that used to point elsewhere
+	if err := tx.Exec(`DELETE FROM slug_redirects WHERE entity=? AND old_slug=?`, entity, slug).Error; err != nil {
+		return err
+	}
+	if current != "" {
+		err := tx.Exec(`INSERT INTO slug_redirects (entity,old_slug,entity_id,created_at) VALUES (?,?,?,NOW())
+		ON CONFLICT (entity,old_slug) DO UPDATE SET entity_id=EXCLUDED.entity_id`, entity, current, id).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Exec(fmt.Sprintf(`UPDATE %s SET slug=? WHERE id=?`, table), slug, id).Error
+}
+
+// resolveSlug finds the row for a slug. When the slug is an old one, the
+// current slug is returned as well so the caller can redirect.
+func resolveSlug(db *gorm.DB, entity, slug string) (int, string, error) {
+	table, ok := slugTables[entity]
+	if !ok {
+		return 0, "", fmt.Errorf("unknown entity %s", entity)
+	}
+	var id int
+	db.Raw(fmt.Sprintf(`SELECT id FROM %s WHERE slug=?`, table), slug).Scan(&id)
+	if id != 0 {
+		return id, "", nil
+	}
+	var redirect struct {
+		EntityId int
+		Slug     string
+	}
+	db.Raw(fmt.Sprintf(`SELECT slug_redirects.entity_id,%s.slug FROM slug_redirects
+	JOIN %s ON %s.id=slug_redirects.entity_id
+	WHERE slug_redirects.entity=? AND slug_redirects.old_slug=?`, table, table, table), entity, slug).Scan(&redirect)
+	if redirect.EntityId == 0 {
+		return 0, "", fmt.Errorf("no %s found with slug %s", strings.ReplaceAll(entity, "_", " "), slug)
+	}
+	return redirect.EntityId, redirect.Slug, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "words", input: "Apple iPhone 15", expected: "apple-iphone-15"},
+		{name: "punctuation runs", input: "Men's  T-Shirts & Tops!", expected: "men-s-t-shirts-tops"},
+		{name: "leading and trailing separators", input: "  --Sale-- ", expected: "sale"},
+		{name: "no usable characters", input: "★★★", expected: ""},
+		{name: "digits only", input: "1984", expected: "1984"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, slugify(tt.input))
+		})
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	slugTaken := "^SELECT EXISTS\\(SELECT 1 FROM brands WHERE slug=(.+) AND id<>(.+)\\)$"
+	tests := []struct {
+		name      string
+		table     string
+		input     string
+		id        int
+		buildStub func(mock sqlmock.Sqlmock)
+		expected  string
+	}{
+		{
+			name:  "free slug",
+			table: "brands",
+			input: "Apple",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(slugTaken).WithArgs("apple", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "apple",
+		},
+		{
+			name:  "taken slug gets a suffix",
+			table: "brands",
+			input: "Apple",
+			id:    4,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(slugTaken).WithArgs("apple", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(slugTaken).WithArgs("apple-2", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectQuery(slugTaken).WithArgs("apple-3", 4).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "apple-3",
+		},
+		{
+			name:  "name without usable characters",
+			table: "brands",
+			input: "★★★",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(slugTaken).WithArgs("untitled", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "untitled",
+		},
+		{
+			name:  "numeric name is prefixed",
+			table: "brands",
+			input: "1984",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(slugTaken).WithArgs("brand-1984", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "brand-1984",
+		},
+		{
+			name:  "numeric collection title is prefixed",
+			table: "collections",
+			input: "2024",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT EXISTS\\(SELECT 1 FROM collections (.+)\\)$").WithArgs("collection-2024", 0).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "collection-2024",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			assert.Equal(t, tt.expected, uniqueSlug(gormDB, tt.table, tt.input, tt.id))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNewSlug(t *testing.T) {
+	tests := []struct {
+		name        string
+		requested   string
+		buildStub   func(mock sqlmock.Sqlmock)
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:      "free slug",
+			requested: "apple",
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT EXISTS\\(SELECT 1 FROM brands WHERE slug=(.+)\\)$").WithArgs("apple").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			expected: "apple",
+		},
+		{
+			name:        "invalid characters",
+			requested:   "Apple Inc",
+			buildStub:   func(mock sqlmock.Sqlmock) {},
+			expectedErr: errors.New("slug may only contain lowercase letters, digits and single hyphens"),
+		},
+		{
+			name:        "digits only",
+			requested:   "1984",
+			buildStub:   func(mock sqlmock.Sqlmock) {},
+			expectedErr: errors.New("slug cannot be only digits, it would be read as an id"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			assert.NoError(t, err)
+			tt.buildStub(mock)
+
+			slug, err := newSlug(gormDB, "brands", tt.requested, "Apple")
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expected, slug)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
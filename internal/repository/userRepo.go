@@ -118,6 +118,11 @@ func (c *userDatabase) ViewUserProfile(id int) (response.UserProfile, error) {
 	if err != nil {
 		return userProfile, err
 	}
+	var tier response.LoyaltyTier
+	err = c.DB.Raw(`SELECT loyalty_tiers.* FROM users JOIN loyalty_tiers ON loyalty_tiers.id=users.loyalty_tier_id WHERE users.id=?`, id).Scan(&tier).Error
+	if tier.Id != 0 {
+		userProfile.Tier = &tier
+	}
 	return userProfile, err
 }
 
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"gorm.io/gorm"
 	"main.go/internal/common/helperStruct"
@@ -20,6 +22,115 @@ func NewWalletRepo(DB *gorm.DB) interfaces.WalletRepository {
 	}
 }
 
+// Wallet transaction types.
+const (
+	walletOrderPayment   = "order_payment"
+	walletRefund         = "refund"
+	walletReferralBonus  = "referral_bonus"
+	walletAdjustment     = "admin_adjustment"
+	walletTopUp          = "top_up"
+	walletOpeningBalance = "opening_balance"
+)
+
+// walletContraAccounts is the store account on the other side of each type of
+// wallet transaction.
+var walletContraAccounts = map[string]string{
+	walletOrderPayment:   "sales",
+	walletRefund:         "sales",
+	walletReferralBonus:  "referral_rewards",
+	walletAdjustment:     "adjustments",
+	walletTopUp:          "payment_gateway",
+	walletOpeningBalance: "opening_balances",
+}
+
+var errInsufficientWalletBalance = errors.New("not enough balance in the wallet")
+
+// walletPosting is a movement on a user's wallet, Amount is negative for debits.
+type walletPosting struct {
+	UserId      int
+	Type        string
+	Amount      int
+	OrderId     uint
+	ReferralId  uint
+	Key         string
+	Description string
+}
+
+// postWalletTransaction records posting in the ledger inside tx and moves
+// wallets.amount with it. A posting whose key is already in the ledger is
+// skipped so retries never move money twice.
+func postWalletTransaction(tx *gorm.DB, posting walletPosting) error {
+	if posting.Amount == 0 {
+		return nil
+	}
+	contraAccount, ok := walletContraAccounts[posting.Type]
+	if !ok {
+		return fmt.Errorf("unknown wallet transaction type %s", posting.Type)
+	}
+	var wallet domain.Wallet
+	err := tx.Raw(`SELECT * FROM wallets WHERE user_id=$1 FOR UPDATE`, posting.UserId).Scan(&wallet).Error
+	if err != nil {
+		return fmt.Errorf("error retrieving amount from wallet")
+	}
+	if wallet.Id == 0 {
+		return fmt.Errorf("wallet not found")
+	}
+	var transactionId uint
+	insertTransaction := `INSERT INTO wallet_transactions (type,idempotency_key,order_id,referral_id,description,created_at)
+	VALUES ($1,$2,NULLIF($3,0),NULLIF($4,0),$5,NOW()) ON CONFLICT (idempotency_key) DO NOTHING RETURNING id`
+	err = tx.Raw(insertTransaction, posting.Type, posting.Key, posting.OrderId, posting.ReferralId, posting.Description).Scan(&transactionId).Error
+	if err != nil {
+		return fmt.Errorf("error recording wallet transaction")
+	}
+	if transactionId == 0 {
+		return nil
+	}
+	balance := wallet.Amount + posting.Amount
+	if balance < 0 {
+		return errInsufficientWalletBalance
+	}
+	insertEntries := `INSERT INTO wallet_entries (transaction_id,account,user_id,amount,balance,created_at)
+	VALUES ($1,'wallet',$2,$3,$4,NOW()),($1,$5,NULL,$6,0,NOW())`
+	err = tx.Exec(insertEntries, transactionId, posting.UserId, posting.Amount, balance, contraAccount, -posting.Amount).Error
+	if err != nil {
+		return fmt.Errorf("error inserting wallet entries")
+	}
+	err = tx.Exec(`UPDATE wallets SET amount=$1 WHERE user_id=$2`, balance, posting.UserId).Error
+	if err != nil {
+		return fmt.Errorf("error updating wallet")
+	}
+	return nil
+}
+
+// ReconcileWallets compares every wallet's amount with the sum of its ledger
+// entries and lists transactions whose entries don't add up to zero. With fix
+// set mismatched wallets are reset to their ledger balance.
+func ReconcileWallets(db *gorm.DB, fix bool) (response.WalletReconciliation, error) {
+	var reconciliation response.WalletReconciliation
+	err := db.Raw(`SELECT wallets.user_id,wallets.amount AS wallet_amount,COALESCE(ledger.balance,0) AS ledger_balance
+	FROM wallets LEFT JOIN (
+		SELECT user_id,SUM(amount) AS balance FROM wallet_entries WHERE account='wallet' GROUP BY user_id
+	) ledger ON ledger.user_id=wallets.user_id
+	WHERE wallets.amount<>COALESCE(ledger.balance,0) ORDER BY wallets.user_id`).Scan(&reconciliation.Mismatches).Error
+	if err != nil {
+		return response.WalletReconciliation{}, err
+	}
+	err = db.Raw(`SELECT transaction_id FROM wallet_entries GROUP BY transaction_id HAVING SUM(amount)<>0 ORDER BY transaction_id`).
+		Scan(&reconciliation.UnbalancedTransactions).Error
+	if err != nil {
+		return response.WalletReconciliation{}, err
+	}
+	if fix {
+		for _, mismatch := range reconciliation.Mismatches {
+			err = db.Exec(`UPDATE wallets SET amount=$1 WHERE user_id=$2`, mismatch.LedgerBalance, mismatch.UserId).Error
+			if err != nil {
+				return reconciliation, err
+			}
+		}
+	}
+	return reconciliation, nil
+}
+
 // CreateWallet implements interfaces.WalletRepository.
 func (w *walletRepository) CreateWallet(userId int) error {
 	// Fetch the maximum existing id from the table
@@ -49,22 +160,158 @@ func (w *walletRepository) DisplayWallet(userId int) (response.Wallet, error) {
 }
 
 // WalletHistory implements interfaces.WalletRepository.
-func (w *walletRepository) WalletHistory(userid int, queryParams helperStruct.QueryParams) ([]response.WalletHistories, int, error) {
-	var walletHistories []response.WalletHistories
-	walletHistory := `SELECT * FROM wallet_histories WHERE user_id=?`
+func (w *walletRepository) WalletHistory(userid int, queryParams helperStruct.QueryParams) ([]response.WalletTransaction, int, error) {
 	var count int
-	getTotalCount := fmt.Sprintf("SELECT COUNT(*) FROM (%s%d)", walletHistory[:len(walletHistory)-1], userid)
-	err := w.DB.Raw(getTotalCount).Scan(&count).Error
+	err := w.DB.Raw(`SELECT COUNT(*) FROM wallet_entries WHERE account='wallet' AND user_id=?`, userid).Scan(&count).Error
 	if err != nil {
-		return []response.WalletHistories{}, 0, err
+		return []response.WalletTransaction{}, 0, err
 	}
-	walletHistory = fmt.Sprintf("%s ORDER BY time DESC", walletHistory)
+	walletHistory := `SELECT t.id,t.type,t.order_id,t.description,e.amount,e.balance,e.created_at
+	FROM wallet_entries e JOIN wallet_transactions t ON t.id=e.transaction_id
+	WHERE e.account='wallet' AND e.user_id=? ORDER BY e.id DESC`
 	if queryParams.Limit != 0 && queryParams.Page != 0 {
 		walletHistory = fmt.Sprintf("%s LIMIT %d OFFSET %d", walletHistory, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
 	}
 	if queryParams.Limit == 0 || queryParams.Page == 0 {
 		walletHistory = fmt.Sprintf("%s LIMIT 10 OFFSET 0", walletHistory)
 	}
+	var walletHistories []response.WalletTransaction
 	err = w.DB.Raw(walletHistory, userid).Scan(&walletHistories).Error
 	return walletHistories, count, err
 }
+
+// walletAdjustmentSelect reads adjustments with the names of the user and the
+// admin who made them, it aliases wallet_adjustments as a.
+const walletAdjustmentSelect = `SELECT a.*,users.name AS user_name,admins.name AS admin_name FROM wallet_adjustments a
+	JOIN users ON users.id=a.user_id LEFT JOIN admins ON admins.id=a.created_by`
+
+// postWalletAdjustment moves the money of an approved adjustment inside tx.
+func postWalletAdjustment(tx *gorm.DB, adjustment domain.WalletAdjustments) error {
+	err := postWalletTransaction(tx, walletPosting{
+		UserId:      int(adjustment.UserId),
+		Type:        walletAdjustment,
+		Amount:      adjustment.Amount,
+		Key:         fmt.Sprintf("adjustment:%d", adjustment.Id),
+		Description: fmt.Sprintf("%s (ref %s)", adjustment.Reason, adjustment.Reference),
+	})
+	if errors.Is(err, errInsufficientWalletBalance) {
+		return fmt.Errorf("the user's wallet doesn't have enough balance for this debit")
+	}
+	return err
+}
+
+func findWalletAdjustment(db *gorm.DB, adjustmentId uint) (response.WalletAdjustment, error) {
+	var adjustment response.WalletAdjustment
+	err := db.Raw(walletAdjustmentSelect+` WHERE a.id=?`, adjustmentId).Scan(&adjustment).Error
+	return adjustment, err
+}
+
+// CreateWalletAdjustment implements interfaces.WalletRepository.
+func (w *walletRepository) CreateWalletAdjustment(adjustment helperStruct.WalletAdjustment, adminId int, needsApproval bool) (response.WalletAdjustment, error) {
+	var exists bool
+	w.DB.Raw(`SELECT EXISTS (SELECT 1 FROM wallets WHERE user_id=?)`, adjustment.UserId).Scan(&exists)
+	if !exists {
+		return response.WalletAdjustment{}, fmt.Errorf("no wallet found for the given user")
+	}
+	tx := w.DB.Begin()
+	var newAdjustment domain.WalletAdjustments
+	if needsApproval {
+		insertAdjustment := `INSERT INTO wallet_adjustments (user_id,amount,reason,reference,status,created_by,created_at)
+		VALUES ($1,$2,$3,$4,'pending',$5,NOW()) RETURNING *`
+		err := tx.Raw(insertAdjustment, adjustment.UserId, adjustment.Amount, adjustment.Reason, adjustment.Reference, adminId).Scan(&newAdjustment).Error
+		if err != nil {
+			tx.Rollback()
+			return response.WalletAdjustment{}, fmt.Errorf("error adding wallet adjustment")
+		}
+	} else {
+		insertAdjustment := `INSERT INTO wallet_adjustments (user_id,amount,reason,reference,status,created_by,created_at,decided_at)
+		VALUES ($1,$2,$3,$4,'approved',$5,NOW(),NOW()) RETURNING *`
+		err := tx.Raw(insertAdjustment, adjustment.UserId, adjustment.Amount, adjustment.Reason, adjustment.Reference, adminId).Scan(&newAdjustment).Error
+		if err != nil {
+			tx.Rollback()
+			return response.WalletAdjustment{}, fmt.Errorf("error adding wallet adjustment")
+		}
+		if err = postWalletAdjustment(tx, newAdjustment); err != nil {
+			tx.Rollback()
+			return response.WalletAdjustment{}, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return response.WalletAdjustment{}, err
+	}
+	return findWalletAdjustment(w.DB, newAdjustment.Id)
+}
+
+// DecideWalletAdjustment implements interfaces.WalletRepository.
+func (w *walletRepository) DecideWalletAdjustment(adjustmentId, superId int, approve bool, note string) (response.WalletAdjustment, error) {
+	tx := w.DB.Begin()
+	var adjustment domain.WalletAdjustments
+	err := tx.Raw(`SELECT * FROM wallet_adjustments WHERE id=? FOR UPDATE`, adjustmentId).Scan(&adjustment).Error
+	if err != nil {
+		tx.Rollback()
+		return response.WalletAdjustment{}, err
+	}
+	if adjustment.Id == 0 {
+		tx.Rollback()
+		return response.WalletAdjustment{}, fmt.Errorf("no wallet adjustment found with the given id")
+	}
+	if adjustment.Status != "pending" {
+		tx.Rollback()
+		return response.WalletAdjustment{}, fmt.Errorf("this adjustment has already been %s", adjustment.Status)
+	}
+	status := "rejected"
+	if approve {
+		status = "approved"
+	}
+	decide := `UPDATE wallet_adjustments SET status=$1,decided_by=$2,note=$3,decided_at=NOW() WHERE id=$4`
+	err = tx.Exec(decide, status, superId, note, adjustmentId).Error
+	if err != nil {
+		tx.Rollback()
+		return response.WalletAdjustment{}, fmt.Errorf("error updating wallet adjustment")
+	}
+	if approve {
+		if err = postWalletAdjustment(tx, adjustment); err != nil {
+			tx.Rollback()
+			return response.WalletAdjustment{}, err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		tx.Rollback()
+		return response.WalletAdjustment{}, err
+	}
+	return findWalletAdjustment(w.DB, adjustment.Id)
+}
+
+// ListWalletAdjustments implements interfaces.WalletRepository.
+func (w *walletRepository) ListWalletAdjustments(filter helperStruct.WalletAdjustmentFilter, queryParams helperStruct.QueryParams) ([]response.WalletAdjustment, int, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "a.status=?")
+		args = append(args, filter.Status)
+	}
+	if filter.UserId != 0 {
+		conditions = append(conditions, "a.user_id=?")
+		args = append(args, filter.UserId)
+	}
+	listAdjustments := walletAdjustmentSelect
+	if len(conditions) > 0 {
+		listAdjustments = fmt.Sprintf("%s WHERE %s", listAdjustments, strings.Join(conditions, " AND "))
+	}
+	var count int
+	err := w.DB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM (%s) adjustments", listAdjustments), args...).Scan(&count).Error
+	if err != nil {
+		return []response.WalletAdjustment{}, 0, err
+	}
+	listAdjustments = fmt.Sprintf("%s ORDER BY a.id DESC", listAdjustments)
+	if queryParams.Limit != 0 && queryParams.Page != 0 {
+		listAdjustments = fmt.Sprintf("%s LIMIT %d OFFSET %d", listAdjustments, queryParams.Limit, (queryParams.Page-1)*queryParams.Limit)
+	}
+	if queryParams.Limit == 0 || queryParams.Page == 0 {
+		listAdjustments = fmt.Sprintf("%s LIMIT 10 OFFSET 0", listAdjustments)
+	}
+	var adjustments []response.WalletAdjustment
+	err = w.DB.Raw(listAdjustments, args...).Scan(&adjustments).Error
+	return adjustments, count, err
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestPostWalletTransaction(t *testing.T) {
+	posting := walletPosting{
+		UserId:      1,
+		Type:        walletRefund,
+		Amount:      500,
+		OrderId:     7,
+		Key:         "order:7:refund",
+		Description: "refund for cancelled order 7",
+	}
+	tests := []struct {
+		name        string
+		input       walletPosting
+		buildStub   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name:  "credit is posted to both accounts",
+			input: posting,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT \\* FROM wallets (.+) FOR UPDATE$").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(1, 1, 100))
+				mock.ExpectQuery("^INSERT INTO wallet_transactions (.+)$").WithArgs(walletRefund, "order:7:refund", 7, 0, "refund for cancelled order 7").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+				mock.ExpectExec("^INSERT INTO wallet_entries (.+)$").WithArgs(3, 1, 500, 600, "sales", -500).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("^UPDATE wallets SET amount=(.+)$").WithArgs(600, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "replayed key moves nothing",
+			input: posting,
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT \\* FROM wallets (.+) FOR UPDATE$").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(1, 1, 600))
+				mock.ExpectQuery("^INSERT INTO wallet_transactions (.+)$").WithArgs(walletRefund, "order:7:refund", 7, 0, "refund for cancelled order 7").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "debit beyond the balance",
+			input: walletPosting{
+				UserId:  1,
+				Type:    walletOrderPayment,
+				Amount:  -700,
+				OrderId: 8,
+				Key:     "order:8:payment",
+			},
+			buildStub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("^SELECT \\* FROM wallets (.+) FOR UPDATE$").WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount"}).AddRow(1, 1, 600))
+				mock.ExpectQuery("^INSERT INTO wallet_transactions (.+)$").WithArgs(walletOrderPayment, "order:8:payment", 8, 0, "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+			},
+			expectedErr: errInsufficientWalletBalance,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error %s was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+			if err != nil {
+				t.Fatalf("an error %s was not expected when initializing a mock db session", err)
+			}
+			tt.buildStub(mock)
+			actualErr := postWalletTransaction(gormDB, tt.input)
+			assert.Equal(t, tt.expectedErr, actualErr)
+			err = mock.ExpectationsWereMet()
+			if err != nil {
+				t.Errorf("Unfulfilled expectations %s", err)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"gorm.io/gorm"
+	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	"main.go/internal/repository/interfaces"
 )
@@ -40,14 +41,20 @@ func (w *WishlistDatabase) RemoveFromWishlist(productId int, userId int) error {
 	}
 	removeFromWishlist := `DELETE FROM wishlists WHERE product_item_id=$1 AND user_id=$2`
 	err := w.DB.Exec(removeFromWishlist, productId, userId).Error
+	if err != nil {
+		return err
+	}
+	// an alert only makes sense while the item is wishlisted
+	err = w.DB.Exec(`DELETE FROM price_alerts WHERE product_item_id=$1 AND user_id=$2`, productId, userId).Error
 	return err
 }
 
 // ListAllWishlist implements interfaces.WishlistRepository.
 func (w *WishlistDatabase) ListAllWishlist(userId int) ([]response.Wishlist, error) {
 	var wishlists []response.Wishlist
-	listAllWishlist := `SELECT wishlists.*, products.product_name,product_items.ram,product_items.storage,products.brand,product_items.color,product_items.graphic_processor,product_items.price AS price_per_unit,product_items.battery
+	listAllWishlist := `SELECT wishlists.*, products.product_name,product_items.ram,product_items.storage,brands.brandname AS brand,product_items.color,product_items.graphic_processor,product_items.price AS price_per_unit,product_items.battery
 	FROM wishlists LEFT JOIN products on wishlists.product_item_id=products.id
+	LEFT JOIN brands ON brands.id=products.brand_id
 	LEFT JOIN product_items ON wishlists.product_item_id=product_items.id
 	WHERE user_id=?`
 	err := w.DB.Raw(listAllWishlist, userId).Scan(&wishlists).Error
@@ -62,10 +69,77 @@ func (w *WishlistDatabase) DisplayWishlistProduct(productId int, userId int) (re
 		return response.Wishlist{}, fmt.Errorf("this product is not present in the wishlist ")
 	}
 	var wishlist response.Wishlist
-	listAllWishlist := `SELECT wishlists.*, products.product_name,product_items.ram,product_items.storage,products.brand,product_items.color,product_items.graphic_processor,product_items.price AS price_per_unit,product_items.battery
+	listAllWishlist := `SELECT wishlists.*, products.product_name,product_items.ram,product_items.storage,brands.brandname AS brand,product_items.color,product_items.graphic_processor,product_items.price AS price_per_unit,product_items.battery
 	FROM wishlists LEFT JOIN products on wishlists.product_item_id=products.id
+	LEFT JOIN brands ON brands.id=products.brand_id
 	LEFT JOIN product_items ON wishlists.product_item_id=product_items.id
 	WHERE user_id=$1 AND wishlists.product_item_id=$2`
 	err := w.DB.Raw(listAllWishlist, userId, productId).Scan(&wishlist).Error
 	return wishlist, err
 }
+
+const priceAlertDetails = `SELECT price_alerts.id,price_alerts.product_item_id,products.product_name,product_items.sku,
+	price_alerts.target_price,current.effective_price AS current_price,price_alerts.last_notified_price,price_alerts.notified_at
+	FROM price_alerts
+	JOIN product_items ON product_items.id=price_alerts.product_item_id
+	JOIN products ON products.id=product_items.product_id
+	JOIN (` + currentPrices + `) current ON current.product_item_id=price_alerts.product_item_id`
+
+// SetPriceAlert implements interfaces.WishlistRepository.
+func (w *WishlistDatabase) SetPriceAlert(productId int, userId int, alert helperStruct.PriceAlert) (response.PriceAlert, error) {
+	var priceAlert response.PriceAlert
+	var exists bool
+	w.DB.Raw(`SELECT EXISTS (select 1 from wishlists where product_item_id=$1 AND user_id=$2)`, productId, userId).Scan(&exists)
+	if !exists {
+		return priceAlert, fmt.Errorf("add the product to your wishlist before setting a price alert")
+	}
+	// changing the target starts the alert over
+	setPriceAlert := `INSERT INTO price_alerts (user_id,product_item_id,target_price,created_at) VALUES ($1,$2,$3,NOW())
+	ON CONFLICT (user_id,product_item_id) DO UPDATE SET target_price=EXCLUDED.target_price,last_notified_price=NULL,notified_at=NULL`
+	err := w.DB.Exec(setPriceAlert, userId, productId, alert.Target_price).Error
+	if err != nil {
+		return priceAlert, err
+	}
+	err = w.DB.Raw(priceAlertDetails+` WHERE price_alerts.user_id=$1 AND price_alerts.product_item_id=$2`, userId, productId).Scan(&priceAlert).Error
+	return priceAlert, err
+}
+
+// RemovePriceAlert implements interfaces.WishlistRepository.
+func (w *WishlistDatabase) RemovePriceAlert(productId int, userId int) error {
+	var exists bool
+	w.DB.Raw(`SELECT EXISTS (select 1 from price_alerts where product_item_id=$1 AND user_id=$2)`, productId, userId).Scan(&exists)
+	if !exists {
+		return fmt.Errorf("there is no price alert for this product")
+	}
+	err := w.DB.Exec(`DELETE FROM price_alerts WHERE product_item_id=$1 AND user_id=$2`, productId, userId).Error
+	return err
+}
+
+// ListPriceAlerts implements interfaces.WishlistRepository.
+func (w *WishlistDatabase) ListPriceAlerts(userId int) ([]response.PriceAlert, error) {
+	var priceAlerts []response.PriceAlert
+	err := w.DB.Raw(priceAlertDetails+` WHERE price_alerts.user_id=? ORDER BY price_alerts.created_at DESC`, userId).Scan(&priceAlerts).Error
+	return priceAlerts, err
+}
+
+// DuePriceAlerts implements interfaces.WishlistRepository.
+func (w *WishlistDatabase) DuePriceAlerts() ([]response.PriceDrop, error) {
+	var priceDrops []response.PriceDrop
+	// users are told again only when the price falls below the one they were last told about
+	err := w.DB.Raw(`SELECT price_alerts.id AS alert_id,users.email,products.product_name,product_items.sku,
+	price_alerts.target_price,current.effective_price AS current_price
+	FROM price_alerts
+	JOIN users ON users.id=price_alerts.user_id
+	JOIN product_items ON product_items.id=price_alerts.product_item_id
+	JOIN products ON products.id=product_items.product_id
+	JOIN (` + currentPrices + `) current ON current.product_item_id=price_alerts.product_item_id
+	WHERE current.effective_price<=price_alerts.target_price
+	AND (price_alerts.last_notified_price IS NULL OR current.effective_price<price_alerts.last_notified_price)`).Scan(&priceDrops).Error
+	return priceDrops, err
+}
+
+// MarkPriceAlertNotified implements interfaces.WishlistRepository.
+func (w *WishlistDatabase) MarkPriceAlertNotified(alertId int, price float64) error {
+	err := w.DB.Exec(`UPDATE price_alerts SET last_notified_price=$1,notified_at=NOW() WHERE id=$2`, price, alertId).Error
+	return err
+}
@@ -1,6 +1,10 @@
 package usecase
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	"main.go/internal/repository/interfaces"
@@ -17,8 +21,62 @@ func NewDiscountUseCase(dicountRepo interfaces.DiscountRepository) services.Disc
 	}
 }
 
+// validateDiscount checks a discount covers something, takes a sensible
+// amount off and has a validity window, filling in the defaults.
+func validateDiscount(discount *helperStruct.Discount) error {
+	if discount.Scope == "" && discount.BrandId != 0 {
+		discount.Scope, discount.ScopeId = "brand", discount.BrandId
+	}
+	switch discount.Scope {
+	case "brand", "category", "product", "item":
+		if discount.ScopeId == 0 {
+			return fmt.Errorf("scope id is required")
+		}
+		if discount.MinCartValue != 0 {
+			return fmt.Errorf("a minimum cart value only applies to cart discounts")
+		}
+	case "cart":
+		discount.ScopeId = 0
+	default:
+		return fmt.Errorf("scope must be brand, category, product, item or cart")
+	}
+	if discount.Type == "" {
+		discount.Type = "percentage"
+	}
+	switch discount.Type {
+	case "percentage":
+		if discount.DiscountPercent <= 0 || discount.DiscountPercent > 100 {
+			return fmt.Errorf("discount percent must be between 0 and 100")
+		}
+		discount.Amount = 0
+	case "fixed":
+		if discount.Amount <= 0 {
+			return fmt.Errorf("a fixed discount needs an amount")
+		}
+		discount.DiscountPercent, discount.MaxDiscount = 0, 0
+	default:
+		return fmt.Errorf("type must be percentage or fixed")
+	}
+	if discount.MaxDiscount < 0 || discount.MinCartValue < 0 {
+		return fmt.Errorf("max discount and min cart value cannot be negative")
+	}
+	if discount.StartDate.IsZero() {
+		discount.StartDate = time.Now()
+	}
+	if discount.ExpiryDate.IsZero() {
+		return fmt.Errorf("expiry date is required")
+	}
+	if !discount.ExpiryDate.After(discount.StartDate) {
+		return fmt.Errorf("discount must expire after it starts")
+	}
+	return nil
+}
+
 // AddDiscount implements interfaces.DiscountUseCase.
 func (d *DiscouneUseCase) AddDiscount(discount helperStruct.Discount) (response.Discount, error) {
+	if err := validateDiscount(&discount); err != nil {
+		return response.Discount{}, err
+	}
 	newDiscount, err := d.discountRepo.AddDiscount(discount)
 	return newDiscount, err
 }
@@ -37,6 +95,194 @@ func (d *DiscouneUseCase) ListAllDiscount(queryParams helperStruct.QueryParams)
 
 // UpdateDiscount implements interfaces.DiscountUseCase.
 func (d *DiscouneUseCase) UpdateDiscount(discount helperStruct.Discount, discountId uint) (response.Discount, error) {
+	if err := validateDiscount(&discount); err != nil {
+		return response.Discount{}, err
+	}
 	updatedDiscount, err := d.discountRepo.UpdateDiscount(discount, discountId)
 	return updatedDiscount, err
 }
+
+// validatePriceRule checks that a price rule has a scope, exactly one kind of
+// price change and a schedule that makes sense.
+func validatePriceRule(priceRule *helperStruct.PriceRule) error {
+	if strings.TrimSpace(priceRule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch priceRule.Scope {
+	case "item", "product", "brand", "category":
+	default:
+		return fmt.Errorf("scope must be item, product, brand or category")
+	}
+	if priceRule.Scope_id == 0 {
+		return fmt.Errorf("scope id is required")
+	}
+	if priceRule.Discount_percent < 0 || priceRule.Discount_percent >= 100 {
+		return fmt.Errorf("discount percent must be between 0 and 100")
+	}
+	if priceRule.Sale_price < 0 {
+		return fmt.Errorf("sale price cannot be negative")
+	}
+	if (priceRule.Discount_percent == 0) == (priceRule.Sale_price == 0) {
+		return fmt.Errorf("give either a discount percent or a sale price")
+	}
+	if priceRule.Sale_price > 0 && priceRule.Scope != "item" {
+		return fmt.Errorf("a fixed sale price can only be set for a single item")
+	}
+	if priceRule.Flash_quantity < 0 {
+		return fmt.Errorf("flash quantity cannot be negative")
+	}
+	if priceRule.Starts_at.IsZero() || priceRule.Ends_at.IsZero() {
+		return fmt.Errorf("price rules need a start and an end")
+	}
+	if !priceRule.Ends_at.After(priceRule.Starts_at) {
+		return fmt.Errorf("price rule must end after it starts")
+	}
+	return nil
+}
+
+// AddPriceRule implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) AddPriceRule(priceRule helperStruct.PriceRule) (response.PriceRule, error) {
+	if err := validatePriceRule(&priceRule); err != nil {
+		return response.PriceRule{}, err
+	}
+	if priceRule.Is_active == nil {
+		active := true
+		priceRule.Is_active = &active
+	}
+	newPriceRule, err := d.discountRepo.AddPriceRule(priceRule)
+	return newPriceRule, err
+}
+
+// UpdatePriceRule implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) UpdatePriceRule(priceRule helperStruct.PriceRule, id int) (response.PriceRule, error) {
+	if err := validatePriceRule(&priceRule); err != nil {
+		return response.PriceRule{}, err
+	}
+	updatedPriceRule, err := d.discountRepo.UpdatePriceRule(priceRule, id)
+	return updatedPriceRule, err
+}
+
+// DeletePriceRule implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) DeletePriceRule(id int) error {
+	err := d.discountRepo.DeletePriceRule(id)
+	return err
+}
+
+// ListPriceRules implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) ListPriceRules(queryParams helperStruct.QueryParams) ([]response.PriceRule, int, error) {
+	priceRules, totalCount, err := d.discountRepo.ListPriceRules(queryParams)
+	return priceRules, totalCount, err
+}
+
+// DisplayPriceRule implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) DisplayPriceRule(id int) (response.PriceRule, error) {
+	priceRule, err := d.discountRepo.DisplayPriceRule(id)
+	return priceRule, err
+}
+
+// validatePromotion checks that a promotion has what its type needs: triggers
+// and rewards for buy_get, at least two items and a price for a bundle.
+func validatePromotion(promotion *helperStruct.Promotion) error {
+	if strings.TrimSpace(promotion.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if promotion.StartsAt.IsZero() || promotion.EndsAt.IsZero() {
+		return fmt.Errorf("promotions need a start and an end")
+	}
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return fmt.Errorf("promotion must end after it starts")
+	}
+	if promotion.MaxApplications < 0 {
+		return fmt.Errorf("max applications cannot be negative")
+	}
+	roles := map[string]int{}
+	seen := map[helperStruct.PromotionItem]bool{}
+	for _, item := range promotion.Items {
+		switch item.Scope {
+		case "item", "product", "brand", "category":
+		default:
+			return fmt.Errorf("scope must be item, product, brand or category")
+		}
+		if item.ScopeId == 0 {
+			return fmt.Errorf("scope id is required")
+		}
+		if item.Role != "trigger" && item.Scope != "item" {
+			return fmt.Errorf("%s items must be single items", item.Role)
+		}
+		if seen[item] {
+			return fmt.Errorf("%s %s %d is listed twice", item.Role, item.Scope, item.ScopeId)
+		}
+		seen[item] = true
+		roles[item.Role]++
+	}
+	switch promotion.Type {
+	case "buy_get":
+		if promotion.TriggerQuantity == 0 {
+			promotion.TriggerQuantity = 1
+		}
+		if promotion.RewardQuantity == 0 {
+			promotion.RewardQuantity = 1
+		}
+		if promotion.TriggerQuantity < 0 || promotion.RewardQuantity < 0 {
+			return fmt.Errorf("trigger and reward quantities must be positive")
+		}
+		if promotion.RewardPercent <= 0 || promotion.RewardPercent > 100 {
+			return fmt.Errorf("reward percent must be above 0 and at most 100")
+		}
+		if roles["trigger"] == 0 || roles["reward"] == 0 || roles["bundle"] > 0 {
+			return fmt.Errorf("a buy_get promotion needs trigger and reward items")
+		}
+		promotion.BundlePrice = 0
+	case "bundle":
+		if promotion.BundlePrice <= 0 {
+			return fmt.Errorf("a bundle needs a bundle price")
+		}
+		if roles["bundle"] < 2 || roles["trigger"] > 0 || roles["reward"] > 0 {
+			return fmt.Errorf("a bundle needs at least two bundle items")
+		}
+		promotion.TriggerQuantity, promotion.RewardQuantity, promotion.RewardPercent = 1, 1, 0
+	default:
+		return fmt.Errorf("type must be buy_get or bundle")
+	}
+	return nil
+}
+
+// AddPromotion implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) AddPromotion(promotion helperStruct.Promotion) (response.Promotion, error) {
+	if err := validatePromotion(&promotion); err != nil {
+		return response.Promotion{}, err
+	}
+	if promotion.IsActive == nil {
+		active := true
+		promotion.IsActive = &active
+	}
+	newPromotion, err := d.discountRepo.AddPromotion(promotion)
+	return newPromotion, err
+}
+
+// UpdatePromotion implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) UpdatePromotion(promotion helperStruct.Promotion, id int) (response.Promotion, error) {
+	if err := validatePromotion(&promotion); err != nil {
+		return response.Promotion{}, err
+	}
+	updatedPromotion, err := d.discountRepo.UpdatePromotion(promotion, id)
+	return updatedPromotion, err
+}
+
+// DeletePromotion implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) DeletePromotion(id int) error {
+	err := d.discountRepo.DeletePromotion(id)
+	return err
+}
+
+// ListPromotions implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) ListPromotions(queryParams helperStruct.QueryParams) ([]response.Promotion, int, error) {
+	promotions, totalCount, err := d.discountRepo.ListPromotions(queryParams)
+	return promotions, totalCount, err
+}
+
+// DisplayPromotion implements interfaces.DiscountUseCase.
+func (d *DiscouneUseCase) DisplayPromotion(id int) (response.Promotion, error) {
+	promotion, err := d.discountRepo.DisplayPromotion(id)
+	return promotion, err
+}
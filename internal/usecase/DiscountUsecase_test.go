@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/common/helperStruct"
+)
+
+func TestValidatePriceRule(t *testing.T) {
+	starts := time.Date(2024, 11, 29, 0, 0, 0, 0, time.UTC)
+	ends := starts.Add(48 * time.Hour)
+	rule := func(change func(*helperStruct.PriceRule)) helperStruct.PriceRule {
+		priceRule := helperStruct.PriceRule{Name: "black friday", Scope: "category", Scope_id: 2, Discount_percent: 20, Starts_at: starts, Ends_at: ends}
+		change(&priceRule)
+		return priceRule
+	}
+	testData := []struct {
+		name          string
+		input         helperStruct.PriceRule
+		expectedError error
+	}{
+		{name: "percentage off a category", input: rule(func(r *helperStruct.PriceRule) {})},
+		{
+			name: "flash sale price on one item",
+			input: rule(func(r *helperStruct.PriceRule) {
+				r.Scope, r.Discount_percent, r.Sale_price, r.Flash_quantity = "item", 0, 499, 50
+			}),
+		},
+		{name: "no name", input: rule(func(r *helperStruct.PriceRule) { r.Name = "  " }), expectedError: errors.New("name is required")},
+		{name: "unknown scope", input: rule(func(r *helperStruct.PriceRule) { r.Scope = "store" }), expectedError: errors.New("scope must be item, product, brand or category")},
+		{name: "no scope id", input: rule(func(r *helperStruct.PriceRule) { r.Scope_id = 0 }), expectedError: errors.New("scope id is required")},
+		{name: "whole price off", input: rule(func(r *helperStruct.PriceRule) { r.Discount_percent = 100 }), expectedError: errors.New("discount percent must be between 0 and 100")},
+		{
+			name:          "percentage and sale price",
+			input:         rule(func(r *helperStruct.PriceRule) { r.Scope, r.Sale_price = "item", 499 }),
+			expectedError: errors.New("give either a discount percent or a sale price"),
+		},
+		{
+			name:          "neither percentage nor sale price",
+			input:         rule(func(r *helperStruct.PriceRule) { r.Discount_percent = 0 }),
+			expectedError: errors.New("give either a discount percent or a sale price"),
+		},
+		{
+			name:          "sale price on a category",
+			input:         rule(func(r *helperStruct.PriceRule) { r.Discount_percent, r.Sale_price = 0, 499 }),
+			expectedError: errors.New("a fixed sale price can only be set for a single item"),
+		},
+		{name: "negative flash quantity", input: rule(func(r *helperStruct.PriceRule) { r.Flash_quantity = -1 }), expectedError: errors.New("flash quantity cannot be negative")},
+		{name: "no end", input: rule(func(r *helperStruct.PriceRule) { r.Ends_at = time.Time{} }), expectedError: errors.New("price rules need a start and an end")},
+		{name: "ends when it starts", input: rule(func(r *helperStruct.PriceRule) { r.Ends_at = starts }), expectedError: errors.New("price rule must end after it starts")},
+		{name: "ends before it starts", input: rule(func(r *helperStruct.PriceRule) { r.Ends_at = starts.Add(-time.Hour) }), expectedError: errors.New("price rule must end after it starts")},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedError, validatePriceRule(&tt.input))
+		})
+	}
+}
@@ -1,18 +1,26 @@
 package usecase
 
 import (
+	"fmt"
+
+	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
 )
 
 type cartUseCase struct {
-	cartRepo interfaces.CartRepository
+	cartRepo   interfaces.CartRepository
+	couponRepo interfaces.CouponRepository
+	pricing    pricingPolicy
 }
 
-func NewCartUseCase(cartRepo interfaces.CartRepository) services.CartUseCase {
+func NewCartUseCase(cartRepo interfaces.CartRepository, couponRepo interfaces.CouponRepository, cfg config.Config) services.CartUseCase {
 	return &cartUseCase{
-		cartRepo: cartRepo,
+		cartRepo:   cartRepo,
+		couponRepo: couponRepo,
+		pricing:    newPricingPolicy(cfg),
 	}
 }
 
@@ -25,17 +33,202 @@ func (c *cartUseCase) CreateCart(Id int) error {
 // AddToCart implements interfaces.CartUseCase.
 func (c *cartUseCase) AddToCart(productId int, usersId int) error {
 	err := c.cartRepo.AddToCart(productId, usersId)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = c.priceUserCart(usersId)
 	return err
 }
 
 // RemoveFromCart implements interfaces.CartUseCase.
 func (c *cartUseCase) RemoveFromCart(productId int, userId int) error {
 	err := c.cartRepo.RemoveFromCart(productId, userId)
+	if err != nil {
+		return err
+	}
+	_, _, _, err = c.priceUserCart(userId)
 	return err
 }
 
+// loadCartOffers loads the cart discounts and promotions running now and the
+// benefits of the user's loyalty tier.
+func loadCartOffers(cartRepo interfaces.CartRepository, userId int) (cartOffers, error) {
+	var offers cartOffers
+	var err error
+	if offers.discounts, err = cartRepo.CartDiscounts(); err != nil {
+		return offers, err
+	}
+	if offers.promotions, err = cartRepo.CartPromotions(); err != nil {
+		return offers, err
+	}
+	offers.tier, err = cartRepo.CartTier(userId)
+	return offers, err
+}
+
+// loadCartItems loads a user's cart items after adding or taking out the
+// free rewards of the promotions running now.
+func loadCartItems(cartRepo interfaces.CartRepository, userId int, offers cartOffers) ([]helperStruct.PricingItem, error) {
+	items, err := cartRepo.CartItems(userId)
+	if err != nil {
+		return nil, err
+	}
+	changes := freeItemChanges(items, offers.promotions)
+	if len(changes) == 0 {
+		return items, nil
+	}
+	if err := cartRepo.SetFreeItems(userId, changes); err != nil {
+		return nil, err
+	}
+	return cartRepo.CartItems(userId)
+}
+
+// priceUserCart prices a user's cart with the coupon applied to it. A coupon
+// that no longer applies is taken off the cart, the reason is kept on the
+// cart until it is next shown and returned as the notice.
+func (c *cartUseCase) priceUserCart(userId int) ([]helperStruct.PricingItem, response.PriceBreakdown, string, error) {
+	offers, err := loadCartOffers(c.cartRepo, userId)
+	if err != nil {
+		return nil, response.PriceBreakdown{}, "", err
+	}
+	items, err := loadCartItems(c.cartRepo, userId, offers)
+	if err != nil {
+		return nil, response.PriceBreakdown{}, "", err
+	}
+	pricing := priceCart(items, offers, response.Coupon{}, pointsRedemption{}, c.pricing)
+	couponName, notice, err := c.cartRepo.CartCoupon(userId)
+	if err != nil || couponName == "" {
+		return items, pricing, notice, err
+	}
+	coupon, err := c.couponRepo.CouponFromName(couponName)
+	if err == nil && coupon.Id == 0 {
+		err = fmt.Errorf("it no longer exists")
+	}
+	if err == nil {
+		err = couponUsable(c.couponRepo, userId, coupon, items, pricing)
+	}
+	if err != nil {
+		notice = fmt.Sprintf("coupon %s was removed, %s", couponName, err.Error())
+		if err := c.cartRepo.SetCartCoupon(userId, 0, 0, notice); err != nil {
+			return nil, response.PriceBreakdown{}, "", err
+		}
+		return items, pricing, notice, nil
+	}
+	return items, priceCart(items, offers, coupon, pointsRedemption{}, c.pricing), notice, nil
+}
+
 // ListCart implements interfaces.CartUseCase.
 func (c *cartUseCase) ListCart(userId int) (response.ViewCart, error) {
-	viewCart, err := c.cartRepo.ListCart(userId)
-	return viewCart, err
+	items, pricing, notice, err := c.priceUserCart(userId)
+	if err != nil {
+		return response.ViewCart{}, err
+	}
+	if notice != "" {
+		// the notice is shown once
+		if err := c.cartRepo.ClearCartNotice(userId); err != nil {
+			return response.ViewCart{}, err
+		}
+	}
+	viewCart := response.ViewCart{
+		CartItems:      []response.DisplayCart{},
+		SubTotal:       pricing.SubTotal,
+		Discount:       pricing.ItemDiscount,
+		CartDiscount:   pricing.CartDiscount,
+		Coupon:         pricing.CouponCode,
+		CouponDiscount: pricing.CouponDiscount,
+		CouponNotice:   notice,
+		Shipping:       pricing.Shipping,
+		Tax:            pricing.Tax,
+		CartTotal:      pricing.Total,
+		Explanation:    pricing.Explanation,
+	}
+	for i, item := range items {
+		line := pricing.Lines[i]
+		cartItem := response.DisplayCart{
+			ProductName:       item.ProductName,
+			Brand:             item.Brand,
+			Color:             item.Color,
+			Ram:               item.Ram,
+			Battery:           item.Battery,
+			Storage:           item.Storage,
+			Graphic_Processor: item.Graphic_Processor,
+			Quantity:          line.Quantity,
+			PricePerUnit:      line.ListPrice,
+			DiscountPrice:     line.Discount,
+			Promotion:         line.Promotion,
+			PromotionDiscount: line.PromotionDiscount,
+			FreeQuantity:      item.FreeQuantity,
+			Total:             line.Total,
+		}
+		if line.Discount > 0 {
+			cartItem.DiscountedPrice = line.Total
+		}
+		viewCart.CartItems = append(viewCart.CartItems, cartItem)
+	}
+	return viewCart, nil
+}
+
+// ApplyCoupon implements interfaces.CartUseCase.
+func (c *cartUseCase) ApplyCoupon(userId int, couponName string) (response.ViewCart, error) {
+	coupon, err := c.couponRepo.CouponFromName(couponName)
+	if err != nil {
+		return response.ViewCart{}, err
+	}
+	if coupon.Id == 0 {
+		return response.ViewCart{}, fmt.Errorf("invalid coupon code")
+	}
+	items, pricing, _, err := c.priceUserCart(userId)
+	if err != nil {
+		return response.ViewCart{}, err
+	}
+	// the coupon is checked against the cart without the one it replaces
+	if pricing.CouponCode != "" {
+		offers, err := loadCartOffers(c.cartRepo, userId)
+		if err != nil {
+			return response.ViewCart{}, err
+		}
+		pricing = priceCart(items, offers, response.Coupon{}, pointsRedemption{}, c.pricing)
+	}
+	if err := couponUsable(c.couponRepo, userId, coupon, items, pricing); err != nil {
+		return response.ViewCart{}, err
+	}
+	if err := c.cartRepo.SetCartCoupon(userId, coupon.Id, coupon.CodeId, ""); err != nil {
+		return response.ViewCart{}, err
+	}
+	return c.ListCart(userId)
+}
+
+// RemoveCoupon implements interfaces.CartUseCase.
+func (c *cartUseCase) RemoveCoupon(userId int) (response.ViewCart, error) {
+	couponName, _, err := c.cartRepo.CartCoupon(userId)
+	if err != nil {
+		return response.ViewCart{}, err
+	}
+	if couponName == "" {
+		return response.ViewCart{}, fmt.Errorf("there is no coupon applied to the cart")
+	}
+	if err := c.cartRepo.SetCartCoupon(userId, 0, 0, ""); err != nil {
+		return response.ViewCart{}, err
+	}
+	return c.ListCart(userId)
+}
+
+// AvailableCoupons implements interfaces.CartUseCase.
+func (c *cartUseCase) AvailableCoupons(userId int) ([]response.CouponOffer, error) {
+	offers, err := loadCartOffers(c.cartRepo, userId)
+	if err != nil {
+		return nil, err
+	}
+	items, err := loadCartItems(c.cartRepo, userId, offers)
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := userCoupons(c.couponRepo, userId, items, offers, c.pricing)
+	if err != nil {
+		return nil, err
+	}
+	couponOffers := []response.CouponOffer{}
+	for _, r := range ranked {
+		couponOffers = append(couponOffers, r.offer())
+	}
+	return couponOffers, nil
 }
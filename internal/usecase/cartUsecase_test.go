@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+var testCartItems = []helperStruct.PricingItem{
+	{ProductItemId: 1, BrandId: 3, CategoryId: 2, ProductName: "laptop", Quantity: 1, Price: 50000},
+}
+
+// testCoupon is a fixed 500 off coupon valid for a year around now.
+func testCoupon(change func(*response.Coupon)) response.Coupon {
+	coupon := response.Coupon{Id: 1, Name: "welcome", Type: "fixed", Amount: 500, Quantity: 10, UsageLimitPerUser: 1,
+		ValidFrom: time.Now().AddDate(0, -6, 0), ValidUntil: time.Now().AddDate(0, 6, 0)}
+	change(&coupon)
+	return coupon
+}
+
+// expectCart stubs the offers and items of user 7's cart.
+func expectCart(cartRepo mock_interfaces.MockCartRepository, items []helperStruct.PricingItem) {
+	cartRepo.EXPECT().CartDiscounts().AnyTimes().Return(nil, nil)
+	cartRepo.EXPECT().CartPromotions().AnyTimes().Return(nil, nil)
+	cartRepo.EXPECT().CartTier(7).AnyTimes().Return(helperStruct.ApplicableTier{}, nil)
+	cartRepo.EXPECT().CartItems(7).AnyTimes().Return(items, nil)
+}
+
+func TestApplyCoupon(t *testing.T) {
+	testData := []struct {
+		name           string
+		couponName     string
+		buildStub      func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository)
+		expectedCoupon string
+		expectedSaving float64
+		expectedError  error
+	}{
+		{
+			name:       "valid coupon",
+			couponName: "welcome",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				expectCart(cartRepo, testCartItems)
+				couponRepo.EXPECT().CouponFromName("welcome").Times(2).Return(testCoupon(func(c *response.Coupon) {}), nil)
+				couponRepo.EXPECT().CouponUsage(7, uint(1)).Times(2).Return(helperStruct.CouponUsage{}, nil)
+				gomock.InOrder(
+					cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "", nil),
+					cartRepo.EXPECT().SetCartCoupon(7, uint(1), uint(0), "").Times(1).Return(nil),
+					cartRepo.EXPECT().CartCoupon(7).Times(1).Return("welcome", "", nil),
+				)
+			},
+			expectedCoupon: "welcome",
+			expectedSaving: 500,
+		},
+		{
+			name:       "unknown coupon",
+			couponName: "nosuchcode",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				couponRepo.EXPECT().CouponFromName("nosuchcode").Times(1).Return(response.Coupon{}, nil)
+			},
+			expectedError: errors.New("invalid coupon code"),
+		},
+		{
+			name:       "expired coupon",
+			couponName: "welcome",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				expectCart(cartRepo, testCartItems)
+				couponRepo.EXPECT().CouponFromName("welcome").Times(1).Return(testCoupon(func(c *response.Coupon) {
+					c.ValidFrom = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+					c.ValidUntil = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				}), nil)
+				couponRepo.EXPECT().CouponUsage(7, uint(1)).Times(1).Return(helperStruct.CouponUsage{}, nil)
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "", nil)
+			},
+			expectedError: errors.New("this coupon expired on 01 Jan 2024 00:00"),
+		},
+		{
+			name:       "empty cart",
+			couponName: "welcome",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				expectCart(cartRepo, nil)
+				couponRepo.EXPECT().CouponFromName("welcome").Times(1).Return(testCoupon(func(c *response.Coupon) {}), nil)
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "", nil)
+			},
+			expectedError: errors.New("your cart is empty"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cartRepo := mock_interfaces.NewMockCartRepository(ctrl)
+			couponRepo := mock_interfaces.NewMockCouponRepository(ctrl)
+			tt.buildStub(*cartRepo, *couponRepo)
+			cartUseCase := NewCartUseCase(cartRepo, couponRepo, config.Config{})
+			viewCart, err := cartUseCase.ApplyCoupon(7, tt.couponName)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedCoupon, viewCart.Coupon)
+			assert.Equal(t, tt.expectedSaving, viewCart.CouponDiscount)
+		})
+	}
+}
+
+func TestRemoveCoupon(t *testing.T) {
+	testData := []struct {
+		name          string
+		buildStub     func(cartRepo mock_interfaces.MockCartRepository)
+		expectedError error
+	}{
+		{
+			name: "coupon applied",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository) {
+				expectCart(cartRepo, testCartItems)
+				gomock.InOrder(
+					cartRepo.EXPECT().CartCoupon(7).Times(1).Return("welcome", "", nil),
+					cartRepo.EXPECT().SetCartCoupon(7, uint(0), uint(0), "").Times(1).Return(nil),
+					cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "", nil),
+				)
+			},
+		},
+		{
+			name: "no coupon applied",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository) {
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "", nil)
+			},
+			expectedError: errors.New("there is no coupon applied to the cart"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cartRepo := mock_interfaces.NewMockCartRepository(ctrl)
+			couponRepo := mock_interfaces.NewMockCouponRepository(ctrl)
+			tt.buildStub(*cartRepo)
+			cartUseCase := NewCartUseCase(cartRepo, couponRepo, config.Config{})
+			viewCart, err := cartUseCase.RemoveCoupon(7)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, "", viewCart.Coupon)
+			assert.Equal(t, float64(0), viewCart.CouponDiscount)
+		})
+	}
+}
+
+func TestCouponNotice(t *testing.T) {
+	testData := []struct {
+		name           string
+		buildStub      func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository)
+		expectedNotice string
+	}{
+		{
+			name: "cart fell below the minimum value",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("welcome", "", nil)
+				couponRepo.EXPECT().CouponFromName("welcome").Times(1).Return(testCoupon(func(c *response.Coupon) { c.MinCartValue = 100000 }), nil)
+				couponRepo.EXPECT().CouponUsage(7, uint(1)).Times(1).Return(helperStruct.CouponUsage{}, nil)
+				notice := "coupon welcome was removed, this coupon needs a cart value of at least 100000, add items worth 50000 more"
+				cartRepo.EXPECT().SetCartCoupon(7, uint(0), uint(0), notice).Times(1).Return(nil)
+			},
+			expectedNotice: "coupon welcome was removed, this coupon needs a cart value of at least 100000, add items worth 50000 more",
+		},
+		{
+			name: "coupon was deleted",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("welcome", "", nil)
+				couponRepo.EXPECT().CouponFromName("welcome").Times(1).Return(response.Coupon{}, nil)
+				cartRepo.EXPECT().SetCartCoupon(7, uint(0), uint(0), "coupon welcome was removed, it no longer exists").Times(1).Return(nil)
+			},
+			expectedNotice: "coupon welcome was removed, it no longer exists",
+		},
+		{
+			name: "notice left from an earlier change",
+			buildStub: func(cartRepo mock_interfaces.MockCartRepository, couponRepo mock_interfaces.MockCouponRepository) {
+				cartRepo.EXPECT().CartCoupon(7).Times(1).Return("", "coupon welcome was removed, this coupon has run out", nil)
+			},
+			expectedNotice: "coupon welcome was removed, this coupon has run out",
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			cartRepo := mock_interfaces.NewMockCartRepository(ctrl)
+			couponRepo := mock_interfaces.NewMockCouponRepository(ctrl)
+			expectCart(*cartRepo, testCartItems)
+			tt.buildStub(*cartRepo, *couponRepo)
+			// the notice is shown once and cleared
+			cartRepo.EXPECT().ClearCartNotice(7).Times(1).Return(nil)
+			cartUseCase := NewCartUseCase(cartRepo, couponRepo, config.Config{})
+			viewCart, err := cartUseCase.ListCart(7)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.expectedNotice, viewCart.CouponNotice)
+			assert.Equal(t, "", viewCart.Coupon)
+			assert.Equal(t, float64(50000), viewCart.CartTotal)
+		})
+	}
+}
+
+func TestAvailableCoupons(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cartRepo := mock_interfaces.NewMockCartRepository(ctrl)
+	couponRepo := mock_interfaces.NewMockCouponRepository(ctrl)
+	expectCart(*cartRepo, testCartItems)
+	couponRepo.EXPECT().AvailableCoupons(7).Times(1).Return([]response.Coupon{
+		testCoupon(func(c *response.Coupon) {}),
+		testCoupon(func(c *response.Coupon) {
+			c.Id, c.Name, c.Type, c.DiscountPercent, c.MaxDiscount = 2, "tenpercent", "percentage", 10, 3000
+		}),
+		testCoupon(func(c *response.Coupon) { c.Id, c.Name, c.Amount = 3, "usedup", 5000 }),
+		testCoupon(func(c *response.Coupon) { c.Id, c.Name, c.Amount, c.MinCartValue = 4, "bigcart", 8000, 100000 }),
+	}, nil)
+	couponRepo.EXPECT().CouponUsage(7, uint(1)).Times(1).Return(helperStruct.CouponUsage{}, nil)
+	couponRepo.EXPECT().CouponUsage(7, uint(2)).Times(1).Return(helperStruct.CouponUsage{}, nil)
+	couponRepo.EXPECT().CouponUsage(7, uint(3)).Times(1).Return(helperStruct.CouponUsage{TimesUsed: 1}, nil)
+	couponRepo.EXPECT().CouponUsage(7, uint(4)).Times(1).Return(helperStruct.CouponUsage{}, nil)
+	cartUseCase := NewCartUseCase(cartRepo, couponRepo, config.Config{})
+	couponOffers, err := cartUseCase.AvailableCoupons(7)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(couponOffers))
+	assert.Equal(t, "tenpercent", couponOffers[0].Coupon)
+	assert.Equal(t, float64(3000), couponOffers[0].Saving)
+	assert.Equal(t, float64(47000), couponOffers[0].CartTotal)
+	assert.Equal(t, "welcome", couponOffers[1].Coupon)
+	assert.Equal(t, float64(500), couponOffers[1].Saving)
+}
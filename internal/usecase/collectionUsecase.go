@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	"main.go/internal/repository/interfaces"
+	services "main.go/internal/usecase/interface"
+)
+
+type CollectionUsecase struct {
+	collectionRepo interfaces.CollectionRepository
+	storage        storage.Storage
+}
+
+func NewCollectionUsecase(collectionRepo interfaces.CollectionRepository, storage storage.Storage) services.CollectionUsecase {
+	return &CollectionUsecase{
+		collectionRepo: collectionRepo,
+		storage:        storage,
+	}
+}
+
+// homeSectionSize is how many items a homepage section shows when the collection has no item limit.
+const homeSectionSize = 10
+
+var ruleAttributes = map[string]bool{
+	"color": true, "ram": true, "storage": true, "battery": true, "screen_size": true, "graphic_processor": true,
+}
+
+// validateCollection fills in defaults and checks that the rule fields make sense for the collection type.
+func validateCollection(collection *helperStruct.Collection) error {
+	if strings.TrimSpace(collection.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if collection.Type == "" {
+		collection.Type = "manual"
+	}
+	collection.Tag = strings.ToLower(strings.TrimSpace(collection.Tag))
+	if collection.Starts_at != nil && collection.Ends_at != nil && !collection.Ends_at.After(*collection.Starts_at) {
+		return fmt.Errorf("collection must end after it starts")
+	}
+	if collection.Item_limit < 0 {
+		return fmt.Errorf("item limit cannot be negative")
+	}
+	switch collection.Type {
+	case "manual":
+		if collection.Min_price != 0 || collection.Max_price != 0 || collection.Brand_id != 0 || collection.Attribute != "" || collection.Tag != "" {
+			return fmt.Errorf("manual collections cannot have rules")
+		}
+	case "rule":
+		if collection.Min_price < 0 || collection.Max_price < 0 {
+			return fmt.Errorf("prices cannot be negative")
+		}
+		if collection.Max_price != 0 && collection.Max_price < collection.Min_price {
+			return fmt.Errorf("max price must not be less than min price")
+		}
+		if collection.Attribute != "" && !ruleAttributes[collection.Attribute] {
+			return fmt.Errorf("unsupported attribute %s", collection.Attribute)
+		}
+		if (collection.Attribute == "") != (collection.Attribute_value == "") {
+			return fmt.Errorf("attribute and attribute value must be given together")
+		}
+		if collection.Min_price == 0 && collection.Max_price == 0 && collection.Brand_id == 0 && collection.Attribute == "" && collection.Tag == "" {
+			return fmt.Errorf("rule collections need at least one rule")
+		}
+	default:
+		return fmt.Errorf("collection type must be manual or rule")
+	}
+	switch collection.Sort_by {
+	case "", "newest", "price_asc", "price_desc":
+	default:
+		return fmt.Errorf("sort by must be newest, price_asc or price_desc")
+	}
+	return nil
+}
+
+// validateBanner fills in defaults and checks the schedule.
+func validateBanner(banner *helperStruct.Banner) error {
+	if strings.TrimSpace(banner.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if banner.Starts_at.IsZero() || banner.Ends_at.IsZero() {
+		return fmt.Errorf("banners need a start and an end")
+	}
+	if !banner.Ends_at.After(banner.Starts_at) {
+		return fmt.Errorf("banner must end after it starts")
+	}
+	if banner.Placement == "" {
+		banner.Placement = "home"
+	}
+	return nil
+}
+
+// bannerImage resolves a stored banner image; absolute URLs are served as they are.
+func (c *CollectionUsecase) bannerImage(image string) string {
+	if image == "" || strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return image
+	}
+	url, err := c.storage.URL(image)
+	if err != nil {
+		return ""
+	}
+	return url
+}
+
+// resolveItems turns the stored images of product item cards into URLs and adds their canonical links.
+func resolveItems(store storage.Storage, items []response.ProductItem) []response.ProductItem {
+	for i := range items {
+		if items[i].Image != "" {
+			url, err := store.URL(items[i].Image)
+			if err != nil {
+				url = ""
+			}
+			items[i].Image = url
+		}
+		items[i].CanonicalUrl = canonicalURL("product_item", items[i].Slug)
+	}
+	return items
+}
+
+// AddCollection implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) AddCollection(collection helperStruct.Collection) (response.Collection, error) {
+	if err := validateCollection(&collection); err != nil {
+		return response.Collection{}, err
+	}
+	if collection.Is_active == nil {
+		active := true
+		collection.Is_active = &active
+	}
+	newCollection, err := c.collectionRepo.AddCollection(collection)
+	return newCollection, err
+}
+
+// UpdateCollection implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) UpdateCollection(collection helperStruct.Collection, id int) (response.Collection, error) {
+	if err := validateCollection(&collection); err != nil {
+		return response.Collection{}, err
+	}
+	updatedCollection, err := c.collectionRepo.UpdateCollection(collection, id)
+	return updatedCollection, err
+}
+
+// DeleteCollection implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) DeleteCollection(id int) error {
+	err := c.collectionRepo.DeleteCollection(id)
+	return err
+}
+
+// ListAllCollections implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) ListAllCollections(queryParams helperStruct.QueryParams) ([]response.Collection, int, error) {
+	collections, totalCount, err := c.collectionRepo.ListAllCollections(queryParams)
+	return collections, totalCount, err
+}
+
+// DisplayCollection implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) DisplayCollection(id int) (response.Collection, error) {
+	collection, err := c.collectionRepo.DisplayCollection(id)
+	if err != nil {
+		return collection, err
+	}
+	items, _, err := c.collectionRepo.CollectionItems(collection, helperStruct.QueryParams{Page: 1, Limit: 100})
+	collection.Items = resolveItems(c.storage, items)
+	return collection, err
+}
+
+// SetCollectionItems implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) SetCollectionItems(id int, items helperStruct.CollectionItems) (response.Collection, error) {
+	seen := make(map[uint]bool)
+	for _, productItemId := range items.Product_item_ids {
+		if seen[productItemId] {
+			return response.Collection{}, fmt.Errorf("product item %d is listed more than once", productItemId)
+		}
+		seen[productItemId] = true
+	}
+	if err := c.collectionRepo.SetCollectionItems(id, items.Product_item_ids); err != nil {
+		return response.Collection{}, err
+	}
+	return c.DisplayCollection(id)
+}
+
+// SetProductTags implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) SetProductTags(productItemId int, tags helperStruct.ProductTags) ([]string, error) {
+	var cleaned []string
+	for _, tag := range tags.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	savedTags, err := c.collectionRepo.SetProductTags(productItemId, cleaned)
+	return savedTags, err
+}
+
+// AddBanner implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) AddBanner(banner helperStruct.Banner) (response.Banner, error) {
+	if err := validateBanner(&banner); err != nil {
+		return response.Banner{}, err
+	}
+	if banner.Is_active == nil {
+		active := true
+		banner.Is_active = &active
+	}
+	newBanner, err := c.collectionRepo.AddBanner(banner)
+	newBanner.Image = c.bannerImage(newBanner.Image)
+	return newBanner, err
+}
+
+// UpdateBanner implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) UpdateBanner(banner helperStruct.Banner, id int) (response.Banner, error) {
+	if err := validateBanner(&banner); err != nil {
+		return response.Banner{}, err
+	}
+	updatedBanner, err := c.collectionRepo.UpdateBanner(banner, id)
+	updatedBanner.Image = c.bannerImage(updatedBanner.Image)
+	return updatedBanner, err
+}
+
+// DeleteBanner implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) DeleteBanner(id int) error {
+	err := c.collectionRepo.DeleteBanner(id)
+	return err
+}
+
+// ListAllBanners implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) ListAllBanners(queryParams helperStruct.QueryParams) ([]response.Banner, int, error) {
+	banners, totalCount, err := c.collectionRepo.ListAllBanners(queryParams)
+	for i := range banners {
+		banners[i].Image = c.bannerImage(banners[i].Image)
+	}
+	return banners, totalCount, err
+}
+
+// Home implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) Home() (response.Home, error) {
+	var home response.Home
+	banners, err := c.collectionRepo.ActiveBanners()
+	if err != nil {
+		return home, err
+	}
+	for i := range banners {
+		banners[i].Image = c.bannerImage(banners[i].Image)
+	}
+	home.Banners = banners
+	collections, err := c.collectionRepo.HomeCollections()
+	if err != nil {
+		return home, err
+	}
+	for _, collection := range collections {
+		size := homeSectionSize
+		if collection.ItemLimit > 0 && collection.ItemLimit < size {
+			size = collection.ItemLimit
+		}
+		items, _, err := c.collectionRepo.CollectionItems(collection, helperStruct.QueryParams{Page: 1, Limit: size})
+		if err != nil {
+			return home, err
+		}
+		// empty sections are left off the homepage
+		if len(items) == 0 {
+			continue
+		}
+		collection.Items = resolveItems(c.storage, items)
+		home.Sections = append(home.Sections, collection)
+	}
+	return home, nil
+}
+
+// StorefrontCollection implements interfaces.CollectionUsecase.
+func (c *CollectionUsecase) StorefrontCollection(slug string, queryParams helperStruct.QueryParams) (response.Collection, int, error) {
+	collection, err := c.collectionRepo.ActiveCollection(slug)
+	if err != nil {
+		return collection, 0, err
+	}
+	items, totalCount, err := c.collectionRepo.CollectionItems(collection, queryParams)
+	collection.Items = resolveItems(c.storage, items)
+	return collection, totalCount, err
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestValidateCollection(t *testing.T) {
+	start := time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC)
+	end := start.Add(7 * 24 * time.Hour)
+	testData := []struct {
+		name          string
+		input         helperStruct.Collection
+		expectedType  string
+		expectedError error
+	}{
+		{name: "manual by default", input: helperStruct.Collection{Title: "staff picks"}, expectedType: "manual"},
+		{name: "scheduled", input: helperStruct.Collection{Title: "republic day", Starts_at: &start, Ends_at: &end}, expectedType: "manual"},
+		{name: "open ended", input: helperStruct.Collection{Title: "new arrivals", Starts_at: &start}, expectedType: "manual"},
+		{name: "ends before it starts", input: helperStruct.Collection{Title: "republic day", Starts_at: &end, Ends_at: &start}, expectedError: errors.New("collection must end after it starts")},
+		{name: "ends when it starts", input: helperStruct.Collection{Title: "republic day", Starts_at: &start, Ends_at: &start}, expectedError: errors.New("collection must end after it starts")},
+		{name: "no title", input: helperStruct.Collection{Title: " "}, expectedError: errors.New("title is required")},
+		{name: "manual with rules", input: helperStruct.Collection{Title: "staff picks", Tag: "gaming"}, expectedError: errors.New("manual collections cannot have rules")},
+		{name: "price rule", input: helperStruct.Collection{Title: "under 20k", Type: "rule", Max_price: 20000}, expectedType: "rule"},
+		{name: "rule without rules", input: helperStruct.Collection{Title: "everything", Type: "rule"}, expectedError: errors.New("rule collections need at least one rule")},
+		{name: "inverted price range", input: helperStruct.Collection{Title: "mid range", Type: "rule", Min_price: 30000, Max_price: 20000}, expectedError: errors.New("max price must not be less than min price")},
+		{name: "unknown attribute", input: helperStruct.Collection{Title: "heavy", Type: "rule", Attribute: "weight", Attribute_value: "2kg"}, expectedError: errors.New("unsupported attribute weight")},
+		{name: "attribute without a value", input: helperStruct.Collection{Title: "red", Type: "rule", Attribute: "color"}, expectedError: errors.New("attribute and attribute value must be given together")},
+		{name: "unknown sort", input: helperStruct.Collection{Title: "gaming", Type: "rule", Tag: "gaming", Sort_by: "rating"}, expectedError: errors.New("sort by must be newest, price_asc or price_desc")},
+		{name: "unknown type", input: helperStruct.Collection{Title: "gaming", Type: "smart"}, expectedError: errors.New("collection type must be manual or rule")},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCollection(&tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			if err == nil {
+				assert.Equal(t, tt.expectedType, tt.input.Type)
+			}
+		})
+	}
+}
+
+func TestValidateBanner(t *testing.T) {
+	start := time.Date(2024, 1, 26, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	testData := []struct {
+		name              string
+		input             helperStruct.Banner
+		expectedPlacement string
+		expectedError     error
+	}{
+		{name: "home by default", input: helperStruct.Banner{Title: "sale", Starts_at: start, Ends_at: end}, expectedPlacement: "home"},
+		{name: "placement kept", input: helperStruct.Banner{Title: "sale", Placement: "cart", Starts_at: start, Ends_at: end}, expectedPlacement: "cart"},
+		{name: "no start", input: helperStruct.Banner{Title: "sale", Ends_at: end}, expectedError: errors.New("banners need a start and an end")},
+		{name: "no end", input: helperStruct.Banner{Title: "sale", Starts_at: start}, expectedError: errors.New("banners need a start and an end")},
+		{name: "ends before it starts", input: helperStruct.Banner{Title: "sale", Starts_at: end, Ends_at: start}, expectedError: errors.New("banner must end after it starts")},
+		{name: "no title", input: helperStruct.Banner{Starts_at: start, Ends_at: end}, expectedError: errors.New("title is required")},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBanner(&tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			if err == nil {
+				assert.Equal(t, tt.expectedPlacement, tt.input.Placement)
+			}
+		})
+	}
+}
+
+func TestBannerImage(t *testing.T) {
+	collectionUsecase := &CollectionUsecase{storage: storage.NewLocalStorage(storage.LocalUploadDir, "/uploads")}
+	testData := []struct {
+		name           string
+		image          string
+		expectedOutput string
+	}{
+		{name: "stored image", image: "banners/1-sale.jpg", expectedOutput: "/uploads/banners/1-sale.jpg"},
+		{name: "absolute url", image: "https://cdn.example.com/sale.jpg", expectedOutput: "https://cdn.example.com/sale.jpg"},
+		{name: "no image", image: "", expectedOutput: ""},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, collectionUsecase.bannerImage(tt.image))
+		})
+	}
+}
+
+func TestHome(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	collectionRepo := mock_interfaces.NewMockCollectionRepository(ctrl)
+	collectionUsecase := NewCollectionUsecase(collectionRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+
+	deals := response.Collection{Id: 1, Title: "deals", ItemLimit: 4}
+	empty := response.Collection{Id: 2, Title: "coming soon"}
+	arrivals := response.Collection{Id: 3, Title: "new arrivals"}
+	collectionRepo.EXPECT().ActiveBanners().Times(1).Return([]response.Banner{{Id: 1, Title: "sale", Image: "banners/1-sale.jpg"}}, nil)
+	collectionRepo.EXPECT().HomeCollections().Times(1).Return([]response.Collection{deals, empty, arrivals}, nil)
+	collectionRepo.EXPECT().CollectionItems(deals, helperStruct.QueryParams{Page: 1, Limit: 4}).Times(1).
+		Return([]response.ProductItem{{Id: 1, Slug: "iphone-15"}}, 1, nil)
+	collectionRepo.EXPECT().CollectionItems(empty, helperStruct.QueryParams{Page: 1, Limit: homeSectionSize}).Times(1).
+		Return([]response.ProductItem{}, 0, nil)
+	collectionRepo.EXPECT().CollectionItems(arrivals, helperStruct.QueryParams{Page: 1, Limit: homeSectionSize}).Times(1).
+		Return([]response.ProductItem{{Id: 2, Image: "products/2/1-front.jpg"}}, 1, nil)
+
+	home, err := collectionUsecase.Home()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "/uploads/banners/1-sale.jpg", home.Banners[0].Image)
+	assert.Equal(t, 2, len(home.Sections))
+	assert.Equal(t, "deals", home.Sections[0].Title)
+	assert.Equal(t, "/home/iphone-15", home.Sections[0].Items[0].CanonicalUrl)
+	assert.Equal(t, "new arrivals", home.Sections[1].Title)
+	assert.Equal(t, "/uploads/products/2/1-front.jpg", home.Sections[1].Items[0].Image)
+}
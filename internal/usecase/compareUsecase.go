@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"strconv"
+
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	"main.go/internal/repository/interfaces"
+	services "main.go/internal/usecase/interface"
+)
+
+type CompareUsecase struct {
+	compareRepo interfaces.CompareRepository
+	storage     storage.Storage
+}
+
+func NewCompareUsecase(compareRepo interfaces.CompareRepository, storage storage.Storage) services.CompareUsecase {
+	return &CompareUsecase{
+		compareRepo: compareRepo,
+		storage:     storage,
+	}
+}
+
+// compareLimit is how many product items a user can compare at once.
+const compareLimit = 4
+
+// compareAttributes are the spec rows of the comparison, in display order.
+var compareAttributes = []struct {
+	name  string
+	value func(response.ProductItem) string
+}{
+	{"Brand", func(p response.ProductItem) string { return p.Brand }},
+	{"Color", func(p response.ProductItem) string { return p.Color }},
+	{"Ram", func(p response.ProductItem) string { return strconv.Itoa(p.Ram) }},
+	{"Storage", func(p response.ProductItem) string { return strconv.Itoa(p.Storage) }},
+	{"Battery", func(p response.ProductItem) string { return strconv.Itoa(p.Battery) }},
+	{"Screen size", func(p response.ProductItem) string { return strconv.FormatFloat(p.ScreenSize, 'f', -1, 64) }},
+	{"Graphic processor", func(p response.ProductItem) string { return p.Graphic_Processor }},
+}
+
+// AddToCompare implements interfaces.CompareUsecase.
+func (cm *CompareUsecase) AddToCompare(userId, productItemId int) error {
+	err := cm.compareRepo.AddToCompare(userId, productItemId, compareLimit)
+	return err
+}
+
+// RemoveFromCompare implements interfaces.CompareUsecase.
+func (cm *CompareUsecase) RemoveFromCompare(userId, productItemId int) error {
+	err := cm.compareRepo.RemoveFromCompare(userId, productItemId)
+	return err
+}
+
+// ClearCompare implements interfaces.CompareUsecase.
+func (cm *CompareUsecase) ClearCompare(userId int) error {
+	err := cm.compareRepo.ClearCompare(userId)
+	return err
+}
+
+// Compare implements interfaces.CompareUsecase.
+func (cm *CompareUsecase) Compare(userId int) (response.Comparison, error) {
+	var comparison response.Comparison
+	productItems, err := cm.compareRepo.CompareItems(userId)
+	if err != nil {
+		return comparison, err
+	}
+	productItems = resolveItems(cm.storage, productItems)
+	for _, item := range productItems {
+		comparison.CategoryName = item.CategoryName
+		discountedPrice := item.Price
+		if item.DiscountPrice != 0 {
+			discountedPrice = item.DiscountedPrice
+		}
+		comparison.Items = append(comparison.Items, response.ComparedItem{
+			Id:              item.Id,
+			ProductName:     item.ProductName,
+			Brand:           item.Brand,
+			Sku:             item.Sku,
+			Slug:            item.Slug,
+			CanonicalUrl:    item.CanonicalUrl,
+			Image:           item.Image,
+			Price:           item.Price,
+			DiscountPrice:   item.DiscountPrice,
+			DiscountedPrice: discountedPrice,
+			QtyInStock:      item.QtyInStock,
+			InStock:         item.QtyInStock > 0,
+		})
+	}
+	for _, attribute := range compareAttributes {
+		row := response.CompareRow{Attribute: attribute.name}
+		for _, item := range productItems {
+			value := attribute.value(item)
+			if len(row.Values) > 0 && value != row.Values[0] {
+				row.Different = true
+			}
+			row.Values = append(row.Values, value)
+		}
+		comparison.Attributes = append(comparison.Attributes, row)
+	}
+	return comparison, nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestAddToCompareLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	compareRepo := mock_interfaces.NewMockCompareRepository(ctrl)
+	compareRepo.EXPECT().AddToCompare(7, 3, compareLimit).Times(1).Return(nil)
+	compareUsecase := NewCompareUsecase(compareRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+	assert.Equal(t, nil, compareUsecase.AddToCompare(7, 3))
+}
+
+func TestCompare(t *testing.T) {
+	testData := []struct {
+		name              string
+		productItems      []response.ProductItem
+		expectedDifferent map[string]bool
+		expectedPrices    []float64
+		expectedInStock   []bool
+	}{
+		{
+			name: "two phones",
+			productItems: []response.ProductItem{
+				{Id: 1, CategoryName: "phones", Brand: "google", Color: "black", Ram: 8, Storage: 128, Price: 700, QtyInStock: 3},
+				{Id: 2, CategoryName: "phones", Brand: "samsung", Color: "black", Ram: 12, Storage: 128, Price: 800, DiscountPrice: 50, DiscountedPrice: 750},
+			},
+			expectedDifferent: map[string]bool{"Brand": true, "Ram": true},
+			expectedPrices:    []float64{700, 750},
+			expectedInStock:   []bool{true, false},
+		},
+		{
+			name: "a single item never differs",
+			productItems: []response.ProductItem{
+				{Id: 1, CategoryName: "phones", Brand: "google", Price: 700, QtyInStock: 1},
+			},
+			expectedDifferent: map[string]bool{},
+			expectedPrices:    []float64{700},
+			expectedInStock:   []bool{true},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			compareRepo := mock_interfaces.NewMockCompareRepository(ctrl)
+			compareRepo.EXPECT().CompareItems(7).Times(1).Return(tt.productItems, nil)
+			compareUsecase := NewCompareUsecase(compareRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+			comparison, err := compareUsecase.Compare(7)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "phones", comparison.CategoryName)
+			assert.Equal(t, len(compareAttributes), len(comparison.Attributes))
+			for _, row := range comparison.Attributes {
+				assert.Equal(t, tt.expectedDifferent[row.Attribute], row.Different)
+				assert.Equal(t, len(tt.productItems), len(row.Values))
+			}
+			for i, item := range comparison.Items {
+				assert.Equal(t, tt.expectedPrices[i], item.DiscountedPrice)
+				assert.Equal(t, tt.expectedInStock[i], item.InStock)
+			}
+		})
+	}
+}
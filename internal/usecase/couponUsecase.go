@@ -1,7 +1,12 @@
 package usecase
 
 import (
+	"crypto/rand"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
@@ -19,13 +24,198 @@ func NewCouponUsecase(couponRepo interfaces.CouponRepository) services.CouponUse
 	}
 }
 
-// AddCoupon implements interfaces.CouponUsecase.
-func (c *CouponUsecase) AddCoupon(coupon helperStruct.Coupon) (response.Coupon, error) {
-	if coupon.Amount < 0 {
-		return response.Coupon{}, fmt.Errorf("amount can't have a negative value")
+// validateCoupon checks the value, limits and validity window of a coupon,
+// filling in the defaults.
+func validateCoupon(coupon *helperStruct.Coupon) error {
+	if strings.TrimSpace(coupon.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if coupon.Type == "" {
+		coupon.Type = "fixed"
+	}
+	switch coupon.Type {
+	case "fixed":
+		if coupon.Amount <= 0 {
+			return fmt.Errorf("a fixed coupon needs an amount")
+		}
+		coupon.DiscountPercent, coupon.MaxDiscount = 0, 0
+	case "percentage":
+		if coupon.DiscountPercent <= 0 || coupon.DiscountPercent > 100 {
+			return fmt.Errorf("discount percent must be between 0 and 100")
+		}
+		coupon.Amount = 0
+	default:
+		return fmt.Errorf("type must be fixed or percentage")
+	}
+	if coupon.MaxDiscount < 0 || coupon.MinCartValue < 0 {
+		return fmt.Errorf("max discount and min cart value can't have a negative value")
 	}
 	if coupon.Quantity < 0 {
-		return response.Coupon{}, fmt.Errorf("quantity can't have a negative value")
+		return fmt.Errorf("quantity can't have a negative value")
+	}
+	if coupon.UsageLimitPerUser < 0 {
+		return fmt.Errorf("usage limit per user can't have a negative value")
+	}
+	if coupon.UsageLimitPerUser == 0 {
+		coupon.UsageLimitPerUser = 1
+	}
+	if coupon.ValidFrom.IsZero() {
+		coupon.ValidFrom = time.Now()
+	}
+	if coupon.ValidUntil.IsZero() {
+		return fmt.Errorf("valid until is required")
+	}
+	if !coupon.ValidUntil.After(coupon.ValidFrom) {
+		return fmt.Errorf("coupon must be valid until after it becomes valid")
+	}
+	for _, restriction := range coupon.Restrictions {
+		switch restriction.Scope {
+		case "brand", "category", "item":
+		default:
+			return fmt.Errorf("restrictions can only be to a brand, category or item")
+		}
+		if restriction.ScopeId == 0 {
+			return fmt.Errorf("restrictions need a scope id")
+		}
+	}
+	return nil
+}
+
+// couponCovers reports whether a coupon applies to an item of the cart.
+func couponCovers(coupon response.Coupon, item helperStruct.PricingItem) bool {
+	if len(coupon.Restrictions) == 0 {
+		return true
+	}
+	for _, restriction := range coupon.Restrictions {
+		switch {
+		case restriction.Scope == "brand" && restriction.ScopeId == item.BrandId,
+			restriction.Scope == "category" && restriction.ScopeId == item.CategoryId,
+			restriction.Scope == "item" && restriction.ScopeId == item.ProductItemId:
+			return true
+		}
+	}
+	return false
+}
+
+// checkCoupon makes sure a user may use a coupon on their priced cart and
+// tells them why not otherwise.
+func checkCoupon(coupon response.Coupon, usage helperStruct.CouponUsage, items []helperStruct.PricingItem, pricing response.PriceBreakdown, now time.Time) error {
+	if coupon.IsDisabled {
+		return fmt.Errorf("this coupon is disabled")
+	}
+	if now.Before(coupon.ValidFrom) {
+		return fmt.Errorf("this coupon can only be used from %s", coupon.ValidFrom.Format("02 Jan 2006 15:04"))
+	}
+	if !now.Before(coupon.ValidUntil) {
+		return fmt.Errorf("this coupon expired on %s", coupon.ValidUntil.Format("02 Jan 2006 15:04"))
+	}
+	if coupon.CodeRedeemed {
+		return fmt.Errorf("this code has already been redeemed")
+	}
+	if coupon.Quantity <= 0 {
+		return fmt.Errorf("this coupon has run out")
+	}
+	if usage.TimesUsed >= coupon.UsageLimitPerUser {
+		if coupon.UsageLimitPerUser == 1 {
+			return fmt.Errorf("you have already used this coupon")
+		}
+		return fmt.Errorf("you have already used this coupon the maximum of %d times", coupon.UsageLimitPerUser)
+	}
+	if coupon.FirstOrderOnly && usage.PastOrders > 0 {
+		return fmt.Errorf("this coupon is only valid on your first order")
+	}
+	goods := pricing.SubTotal - pricing.ItemDiscount - pricing.CartDiscount
+	if goods < float64(coupon.MinCartValue) {
+		return fmt.Errorf("this coupon needs a cart value of at least %d, add items worth %.0f more", coupon.MinCartValue, float64(coupon.MinCartValue)-goods)
+	}
+	covered := false
+	for _, item := range items {
+		covered = covered || couponCovers(coupon, item)
+	}
+	if !covered {
+		return fmt.Errorf("this coupon does not apply to any item in your cart")
+	}
+	return nil
+}
+
+// couponUsable checks a coupon against its rules, the user's history with
+// it and the priced cart it is meant for.
+func couponUsable(couponRepo interfaces.CouponRepository, userId int, coupon response.Coupon, items []helperStruct.PricingItem, pricing response.PriceBreakdown) error {
+	if len(items) == 0 {
+		return fmt.Errorf("your cart is empty")
+	}
+	usage, err := couponRepo.CouponUsage(userId, coupon.Id)
+	if err != nil {
+		return err
+	}
+	return checkCoupon(coupon, usage, items, pricing, time.Now())
+}
+
+// rankedCoupon is a coupon the user can use with the cart priced with it.
+type rankedCoupon struct {
+	coupon  response.Coupon
+	pricing response.PriceBreakdown
+}
+
+func (r rankedCoupon) offer() response.CouponOffer {
+	return response.CouponOffer{
+		Coupon:     r.coupon.Name,
+		Saving:     r.pricing.CouponDiscount,
+		CartTotal:  r.pricing.Total,
+		Reward:     r.coupon.Reward,
+		ValidUntil: r.coupon.ValidUntil,
+	}
+}
+
+// rankCoupons prices the cart with each coupon that passes checkCoupon and
+// returns them from the biggest saving down. Coupons that save nothing are
+// left out, and on equal savings the one running out sooner comes first.
+func rankCoupons(coupons []response.Coupon, usages []helperStruct.CouponUsage, items []helperStruct.PricingItem, offers cartOffers, policy pricingPolicy, now time.Time) []rankedCoupon {
+	ranked := []rankedCoupon{}
+	if len(items) == 0 {
+		return ranked
+	}
+	withoutCoupon := priceCart(items, offers, response.Coupon{}, pointsRedemption{}, policy)
+	for i, coupon := range coupons {
+		if checkCoupon(coupon, usages[i], items, withoutCoupon, now) != nil {
+			continue
+		}
+		pricing := priceCart(items, offers, coupon, pointsRedemption{}, policy)
+		if pricing.CouponDiscount > 0 {
+			ranked = append(ranked, rankedCoupon{coupon: coupon, pricing: pricing})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].pricing.CouponDiscount != ranked[j].pricing.CouponDiscount {
+			return ranked[i].pricing.CouponDiscount > ranked[j].pricing.CouponDiscount
+		}
+		return ranked[i].coupon.ValidUntil.Before(ranked[j].coupon.ValidUntil)
+	})
+	return ranked
+}
+
+// userCoupons ranks every coupon available to userId against their cart items.
+func userCoupons(couponRepo interfaces.CouponRepository, userId int, items []helperStruct.PricingItem, offers cartOffers, policy pricingPolicy) ([]rankedCoupon, error) {
+	if len(items) == 0 {
+		return []rankedCoupon{}, nil
+	}
+	coupons, err := couponRepo.AvailableCoupons(userId)
+	if err != nil {
+		return nil, err
+	}
+	usages := make([]helperStruct.CouponUsage, len(coupons))
+	for i, coupon := range coupons {
+		if usages[i], err = couponRepo.CouponUsage(userId, coupon.Id); err != nil {
+			return nil, err
+		}
+	}
+	return rankCoupons(coupons, usages, items, offers, policy, time.Now()), nil
+}
+
+// AddCoupon implements interfaces.CouponUsecase.
+func (c *CouponUsecase) AddCoupon(coupon helperStruct.Coupon) (response.Coupon, error) {
+	if err := validateCoupon(&coupon); err != nil {
+		return response.Coupon{}, err
 	}
 	newCoupon, err := c.couponRepo.AddCoupon(coupon)
 	return newCoupon, err
@@ -33,11 +223,8 @@ func (c *CouponUsecase) AddCoupon(coupon helperStruct.Coupon) (response.Coupon,
 
 // UpdateCoupon implements interfaces.CouponUsecase.
 func (c *CouponUsecase) UpdateCoupon(coupon helperStruct.UpdateCoupon) (response.Coupon, error) {
-	if coupon.Amount < 0 {
-		return response.Coupon{}, fmt.Errorf("amount can't have a negative value")
-	}
-	if coupon.Quantity < 0 {
-		return response.Coupon{}, fmt.Errorf("quantity can't have a negative value")
+	if err := validateCoupon(&coupon.Coupon); err != nil {
+		return response.Coupon{}, err
 	}
 	updatedCoupon, err := c.couponRepo.UpdateCoupon(coupon)
 	return updatedCoupon, err
@@ -66,3 +253,106 @@ func (c *CouponUsecase) EnableCoupon(couponId int) error {
 	err := c.couponRepo.EnableCoupon(couponId)
 	return err
 }
+
+// codeAlphabet leaves out characters that are easily mixed up, like 0 and O.
+// Its 32 characters split a random byte evenly.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+var codePrefix = regexp.MustCompile(`^[A-Z0-9-]{0,12}$`)
+
+// generateCodes makes count distinct codes of prefix followed by length random
+// characters, leaving out the codes in seen. The new codes are added to seen.
+func generateCodes(prefix string, length, count int, seen map[string]bool) ([]string, error) {
+	codes := make([]string, 0, count)
+	random := make([]byte, length)
+	for len(codes) < count {
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		code := []byte(prefix)
+		for _, b := range random {
+			code = append(code, codeAlphabet[int(b)%len(codeAlphabet)])
+		}
+		if !seen[string(code)] {
+			seen[string(code)] = true
+			codes = append(codes, string(code))
+		}
+	}
+	return codes, nil
+}
+
+// AddCampaign implements interfaces.CouponUsecase.
+func (c *CouponUsecase) AddCampaign(campaign helperStruct.CouponCampaign) (response.CouponCampaign, error) {
+	campaign.Prefix = strings.ToUpper(strings.TrimSpace(campaign.Prefix))
+	if !codePrefix.MatchString(campaign.Prefix) {
+		return response.CouponCampaign{}, fmt.Errorf("prefix can have up to 12 letters, digits or dashes")
+	}
+	if campaign.CodeLength == 0 {
+		campaign.CodeLength = 8
+	}
+	if campaign.CodeLength < 6 || campaign.CodeLength > 16 {
+		return response.CouponCampaign{}, fmt.Errorf("code length must be between 6 and 16")
+	}
+	if campaign.Count < 1 || campaign.Count > 10000 {
+		return response.CouponCampaign{}, fmt.Errorf("a campaign can have between 1 and 10000 codes")
+	}
+	// every code is used once, so the campaign coupon has as many uses as codes
+	campaign.Quantity = campaign.Count
+	if err := validateCoupon(&campaign.Coupon); err != nil {
+		return response.CouponCampaign{}, err
+	}
+	// every code generated so far, replacements never repeat a kept or taken code
+	seen := make(map[string]bool, campaign.Count)
+	codes, err := generateCodes(campaign.Prefix, campaign.CodeLength, campaign.Count, seen)
+	if err != nil {
+		return response.CouponCampaign{}, err
+	}
+	// replace codes that are already taken until none are
+	for attempt := 0; ; attempt++ {
+		taken, err := c.couponRepo.ExistingCodes(codes)
+		if err != nil {
+			return response.CouponCampaign{}, err
+		}
+		if len(taken) == 0 {
+			break
+		}
+		if attempt == 5 {
+			return response.CouponCampaign{}, fmt.Errorf("could not generate unique codes, try a longer code length")
+		}
+		isTaken := make(map[string]bool, len(taken))
+		for _, code := range taken {
+			isTaken[code] = true
+		}
+		var unique []string
+		for _, code := range codes {
+			if !isTaken[code] {
+				unique = append(unique, code)
+			}
+		}
+		replacements, err := generateCodes(campaign.Prefix, campaign.CodeLength, len(taken), seen)
+		if err != nil {
+			return response.CouponCampaign{}, err
+		}
+		codes = append(unique, replacements...)
+	}
+	newCampaign, err := c.couponRepo.AddCampaign(campaign, codes)
+	return newCampaign, err
+}
+
+// ListCampaigns implements interfaces.CouponUsecase.
+func (c *CouponUsecase) ListCampaigns(queryParams helperStruct.QueryParams) ([]response.CouponCampaign, int, error) {
+	campaigns, totalCount, err := c.couponRepo.ListCampaigns(queryParams)
+	return campaigns, totalCount, err
+}
+
+// DisplayCampaign implements interfaces.CouponUsecase.
+func (c *CouponUsecase) DisplayCampaign(campaignId int) (response.CouponCampaign, error) {
+	campaign, err := c.couponRepo.DisplayCampaign(campaignId)
+	return campaign, err
+}
+
+// CampaignCodes implements interfaces.CouponUsecase.
+func (c *CouponUsecase) CampaignCodes(campaignId int) ([]response.CouponCode, error) {
+	codes, err := c.couponRepo.CampaignCodes(campaignId)
+	return codes, err
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestGenerateCodes(t *testing.T) {
+	codes, err := generateCodes("DIWALI-", 8, 2000, map[string]bool{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2000, len(codes))
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Equal(t, 15, len(code))
+		assert.Equal(t, "DIWALI-", code[:7])
+		for _, r := range code[7:] {
+			assert.NotEqual(t, -1, strings.IndexRune(codeAlphabet, r))
+		}
+		assert.Equal(t, false, seen[code])
+		seen[code] = true
+	}
+}
+
+func TestGenerateCodesLeavesOutSeen(t *testing.T) {
+	// one character codes can only be told apart from the 31 others
+	seen := map[string]bool{"XA": true}
+	codes, err := generateCodes("X", 1, len(codeAlphabet)-1, seen)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, len(codeAlphabet)-1, len(codes))
+	for _, code := range codes {
+		assert.NotEqual(t, "XA", code)
+	}
+	assert.Equal(t, len(codeAlphabet), len(seen))
+}
+
+func TestAddCampaign(t *testing.T) {
+	campaign := helperStruct.CouponCampaign{
+		Prefix: "diwali-",
+		Count:  50,
+		Coupon: helperStruct.Coupon{Name: "diwali", Amount: 100, ValidUntil: time.Now().AddDate(0, 1, 0)},
+	}
+	testData := []struct {
+		name      string
+		buildStub func(couponRepo mock_interfaces.MockCouponRepository)
+	}{
+		{
+			name: "no code taken",
+			buildStub: func(couponRepo mock_interfaces.MockCouponRepository) {
+				couponRepo.EXPECT().ExistingCodes(gomock.Any()).Times(1).Return(nil, nil)
+			},
+		},
+		{
+			name: "taken codes are replaced by new ones",
+			buildStub: func(couponRepo mock_interfaces.MockCouponRepository) {
+				var taken []string
+				gomock.InOrder(
+					couponRepo.EXPECT().ExistingCodes(gomock.Any()).Times(1).DoAndReturn(func(codes []string) ([]string, error) {
+						taken = codes[:3]
+						return taken, nil
+					}),
+					couponRepo.EXPECT().ExistingCodes(gomock.Any()).Times(1).DoAndReturn(func(codes []string) ([]string, error) {
+						for _, code := range codes {
+							for _, takenCode := range taken {
+								assert.NotEqual(t, takenCode, code)
+							}
+						}
+						return nil, nil
+					}),
+				)
+			},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			couponRepo := mock_interfaces.NewMockCouponRepository(ctrl)
+			tt.buildStub(*couponRepo)
+			var added []string
+			couponRepo.EXPECT().AddCampaign(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(campaign helperStruct.CouponCampaign, codes []string) (response.CouponCampaign, error) {
+					added = codes
+					return response.CouponCampaign{}, nil
+				})
+			couponUsecase := NewCouponUsecase(couponRepo)
+			_, err := couponUsecase.AddCampaign(campaign)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, 50, len(added))
+			distinct := map[string]bool{}
+			for _, code := range added {
+				assert.Equal(t, "DIWALI-", code[:7])
+				distinct[code] = true
+			}
+			assert.Equal(t, 50, len(distinct))
+		})
+	}
+}
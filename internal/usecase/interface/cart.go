@@ -7,4 +7,7 @@ type CartUseCase interface {
 	AddToCart(productId, usersId int) error
 	RemoveFromCart(productId, userId int) error
 	ListCart(userId int) (response.ViewCart, error)
+	ApplyCoupon(userId int, couponName string) (response.ViewCart, error)
+	RemoveCoupon(userId int) (response.ViewCart, error)
+	AvailableCoupons(userId int) ([]response.CouponOffer, error)
 }
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+type CollectionUsecase interface {
+	AddCollection(collection helperStruct.Collection) (response.Collection, error)
+	UpdateCollection(collection helperStruct.Collection, id int) (response.Collection, error)
+	DeleteCollection(id int) error
+	ListAllCollections(queryParams helperStruct.QueryParams) ([]response.Collection, int, error)
+	DisplayCollection(id int) (response.Collection, error)
+	SetCollectionItems(id int, items helperStruct.CollectionItems) (response.Collection, error)
+	SetProductTags(productItemId int, tags helperStruct.ProductTags) ([]string, error)
+	AddBanner(banner helperStruct.Banner) (response.Banner, error)
+	UpdateBanner(banner helperStruct.Banner, id int) (response.Banner, error)
+	DeleteBanner(id int) error
+	ListAllBanners(queryParams helperStruct.QueryParams) ([]response.Banner, int, error)
+	Home() (response.Home, error)
+	StorefrontCollection(slug string, queryParams helperStruct.QueryParams) (response.Collection, int, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "main.go/internal/common/response"
+
+type CompareUsecase interface {
+	AddToCompare(userId, productItemId int) error
+	RemoveFromCompare(userId, productItemId int) error
+	ClearCompare(userId int) error
+	Compare(userId int) (response.Comparison, error)
+}
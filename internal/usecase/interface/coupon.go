@@ -12,4 +12,8 @@ type CouponUsecase interface {
 	ListAllCoupons(queryParams helperStruct.QueryParams) ([]response.Coupon, int, error)
 	DisplayCoupon(couponId int) (response.Coupon, error)
 	EnableCoupon(couponId int) error
+	AddCampaign(campaign helperStruct.CouponCampaign) (response.CouponCampaign, error)
+	ListCampaigns(queryParams helperStruct.QueryParams) ([]response.CouponCampaign, int, error)
+	DisplayCampaign(campaignId int) (response.CouponCampaign, error)
+	CampaignCodes(campaignId int) ([]response.CouponCode, error)
 }
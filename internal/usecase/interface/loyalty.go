@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+type LoyaltyUseCase interface {
+	Settings() (response.LoyaltySettings, error)
+	UpdateSettings(settings helperStruct.LoyaltySettings) (response.LoyaltySettings, error)
+	AddTier(tier helperStruct.LoyaltyTier) (response.LoyaltyTier, error)
+	UpdateTier(tier helperStruct.LoyaltyTier, id int) (response.LoyaltyTier, error)
+	DeleteTier(id int) error
+	ListTiers() ([]response.LoyaltyTier, error)
+	PointsSummary(userId int) (response.LoyaltySummary, error)
+	PointsHistory(userId int, queryParams helperStruct.QueryParams) ([]response.LoyaltyEntry, int, error)
+}
@@ -6,7 +6,7 @@ import (
 )
 
 type OrderUseCase interface {
-	OrderAll(id, paymentTypeId int, CouponName string) (response.ResponseOrder, error)
+	OrderAll(id, paymentTypeId int, CouponName string, autoApplyCoupon bool, redeemPoints, walletAmount int) (response.ResponseOrder, error)
 	UserCancelOrder(orderId, userId int) error
 	ListAllOrders(userId int, queryParams helperStruct.QueryParams) ([]response.OrderResponse, int, error)
 	Displayorder(userId, orderId int) (response.ResponseOrder, error)
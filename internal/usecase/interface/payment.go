@@ -15,4 +15,6 @@ type PaymentUseCase interface {
 	AddPaymentStatus(paymentStatus helperStruct.PaymentStatus) (domain.PaymentStatus, error)
 	UpdatePayemntStatus(paymentStatus helperStruct.PaymentStatus, paymentStatusId int) error
 	ListAllPaymentStatuses() ([]domain.PaymentStatus, error)
+	CreateWalletTopUp(userId, amount int) (response.WalletTopUp, error)
+	VerifyWalletTopUp(topUpVerifier helperStruct.TopUpVerification) (response.WalletTopUp, error)
 }
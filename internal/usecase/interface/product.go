@@ -8,6 +8,7 @@ import (
 type ProductUsecase interface {
 	CreateCategory(category helperStruct.Category) (response.Category, error)
 	UpdateCategory(category helperStruct.Category, id int) (response.Category, error)
+	MoveCategory(move helperStruct.MoveCategory, id int) (response.Category, error)
 	DeleteCategory(id int) error
 	ListAllCategories() ([]response.Category, error)
 	DisplayCategory(id int) (response.Category, error)
@@ -25,9 +26,13 @@ type ProductUsecase interface {
 	UpdateProductItem(id int, productItem helperStruct.ProductItem) (response.ProductItem, error)
 	ListAllProductItems(queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error)
 	DeleteProductItem(id int) error
-	// ImageUpload(image helperStruct.ImageHelper) (response.ImageResponse, error)
-	UploadImage(filepath string, productid int) (response.Image, error)
+	UploadImage(image helperStruct.ImageHelper) (response.Image, error)
 	DeleteImage(id int) error
 	DisplayProductItem(id int) (response.DisplayProductItem, error)
 	SearchProducts(queryParams helperStruct.QueryParams, searchProducts string) ([]response.Product, error)
+	ResolveSlug(entity, slug string) (int, string, error)
+	RecordView(userId, productItemId int) error
+	RecentlyViewed(userId int) ([]response.ProductItem, error)
+	PersonalizedProducts(userId int, queryParams helperStruct.QueryParams) ([]response.Product, int, error)
+	PriceHistory(productItemId int, days int) (response.PriceHistory, error)
 }
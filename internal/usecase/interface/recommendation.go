@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
+type RecommendationUsecase interface {
+	RefreshRecommendations() error
+	ProductItemRecommendations(productItemId int) (response.Recommendations, error)
+	CartRecommendations(userId int) (response.Recommendations, error)
+	AddOverride(override helperStruct.RecommendationOverride) (response.RecommendationOverride, error)
+	DeleteOverride(id int) error
+	ListOverrides(productItemId int) ([]response.RecommendationOverride, error)
+}
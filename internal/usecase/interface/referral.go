@@ -1,6 +1,16 @@
 package interfaces
 
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
+
 type ReferralUseCase interface {
-	AddReferral(userId int, userMobile string) error
-	ReferralOffer(userId int, referralId string) error
+	AddReferral(userId int) error
+	CheckReferralCode(referralId string) error
+	RegenerateReferralCode(userId int) (response.ReferralCode, error)
+	ReferralOffer(userId int, referralId string) (int, error)
+	ReferralSettings() (response.ReferralSettings, error)
+	UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error)
+	ReferralDashboard(userId int) (response.ReferralDashboard, error)
 }
@@ -8,5 +8,9 @@ import (
 type WalletUseCase interface {
 	CreateWallet(userId int) error
 	DisplayWallet(userId int) (response.Wallet, error)
-	WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletHistories, int, error)
+	WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletTransaction, int, error)
+	AdjustWallet(adjustment helperStruct.WalletAdjustment, adminId int) (response.WalletAdjustment, error)
+	ApproveWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error)
+	RejectWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error)
+	ListWalletAdjustments(filter helperStruct.WalletAdjustmentFilter, queryParams helperStruct.QueryParams) ([]response.WalletAdjustment, int, error)
 }
@@ -1,10 +1,16 @@
 package interfaces
 
-import "main.go/internal/common/response"
+import (
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+)
 
 type WishlistUseCase interface {
 	AddToWishlist(productId, userId int) error
 	RemoveFromWishlist(productId, userId int) error
 	ListAllWishlist(userId int) ([]response.Wishlist, error)
 	DisplayWishlistProduct(productId, userId int) (response.Wishlist, error)
+	SetPriceAlert(productId, userId int, alert helperStruct.PriceAlert) (response.PriceAlert, error)
+	RemovePriceAlert(productId, userId int) error
+	ListPriceAlerts(userId int) ([]response.PriceAlert, error)
 }
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/repository/interfaces"
+	services "main.go/internal/usecase/interface"
+)
+
+type loyaltyUseCase struct {
+	loyaltyRepo interfaces.LoyaltyRepository
+}
+
+func NewLoyaltyUseCase(loyaltyRepo interfaces.LoyaltyRepository) services.LoyaltyUseCase {
+	return &loyaltyUseCase{
+		loyaltyRepo: loyaltyRepo,
+	}
+}
+
+// nextTier fills in the first tier the spend of a summary doesn't reach yet
+// and what is left to spend for it, tiers are sorted by their minimum spend.
+func nextTier(summary *response.LoyaltySummary, tiers []response.LoyaltyTier) {
+	summary.NextTier, summary.SpendToNextTier = "", 0
+	for _, tier := range tiers {
+		if summary.Spend < float64(tier.MinSpend) {
+			summary.NextTier = tier.Name
+			summary.SpendToNextTier = float64(tier.MinSpend) - summary.Spend
+			return
+		}
+	}
+}
+
+// pointsToRedeem checks the points a user wants to pay with against the
+// program and their balance.
+func pointsToRedeem(loyaltyRepo interfaces.LoyaltyRepository, userId, points int) (pointsRedemption, error) {
+	if points == 0 {
+		return pointsRedemption{}, nil
+	}
+	if points < 0 {
+		return pointsRedemption{}, fmt.Errorf("points to redeem cannot be negative")
+	}
+	settings, err := loyaltyRepo.Settings()
+	if err != nil {
+		return pointsRedemption{}, err
+	}
+	if !settings.IsActive {
+		return pointsRedemption{}, fmt.Errorf("loyalty points can't be redeemed right now")
+	}
+	if points < settings.MinRedeemPoints {
+		return pointsRedemption{}, fmt.Errorf("redeem at least %d points", settings.MinRedeemPoints)
+	}
+	summary, err := loyaltyRepo.PointsSummary(userId)
+	if err != nil {
+		return pointsRedemption{}, err
+	}
+	if points > summary.Balance {
+		return pointsRedemption{}, fmt.Errorf("you only have %d loyalty points", summary.Balance)
+	}
+	return pointsRedemption{Points: points, RupeesPerPoint: settings.RupeesPerPoint, MaxPercent: settings.MaxRedeemPercent}, nil
+}
+
+func validateLoyaltySettings(settings helperStruct.LoyaltySettings) error {
+	if settings.PointsPerRupee < 0 {
+		return fmt.Errorf("points per rupee cannot be negative")
+	}
+	if settings.RupeesPerPoint <= 0 {
+		return fmt.Errorf("rupees per point must be above 0")
+	}
+	if settings.MinRedeemPoints < 0 {
+		return fmt.Errorf("minimum points to redeem cannot be negative")
+	}
+	if settings.MaxRedeemPercent < 0 || settings.MaxRedeemPercent > 100 {
+		return fmt.Errorf("max redeem percent must be between 0 and 100")
+	}
+	if settings.ExpiryDays < 0 {
+		return fmt.Errorf("expiry days cannot be negative")
+	}
+	return nil
+}
+
+func validateLoyaltyTier(tier helperStruct.LoyaltyTier) error {
+	if strings.TrimSpace(tier.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if tier.MinSpend < 0 {
+		return fmt.Errorf("minimum spend cannot be negative")
+	}
+	if tier.EarnMultiplier < 1 {
+		return fmt.Errorf("earn multiplier must be at least 1")
+	}
+	if tier.ExtraDiscountPercent < 0 || tier.ExtraDiscountPercent > 100 {
+		return fmt.Errorf("extra discount percent must be between 0 and 100")
+	}
+	if tier.ReturnWindowDays < 0 {
+		return fmt.Errorf("return window days cannot be negative")
+	}
+	return nil
+}
+
+// Settings implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) Settings() (response.LoyaltySettings, error) {
+	settings, err := l.loyaltyRepo.Settings()
+	return settings, err
+}
+
+// UpdateSettings implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) UpdateSettings(settings helperStruct.LoyaltySettings) (response.LoyaltySettings, error) {
+	if err := validateLoyaltySettings(settings); err != nil {
+		return response.LoyaltySettings{}, err
+	}
+	updatedSettings, err := l.loyaltyRepo.UpdateSettings(settings)
+	return updatedSettings, err
+}
+
+// AddTier implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) AddTier(tier helperStruct.LoyaltyTier) (response.LoyaltyTier, error) {
+	if tier.EarnMultiplier == 0 {
+		tier.EarnMultiplier = 1
+	}
+	if err := validateLoyaltyTier(tier); err != nil {
+		return response.LoyaltyTier{}, err
+	}
+	newTier, err := l.loyaltyRepo.AddTier(tier)
+	return newTier, err
+}
+
+// UpdateTier implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) UpdateTier(tier helperStruct.LoyaltyTier, id int) (response.LoyaltyTier, error) {
+	if tier.EarnMultiplier == 0 {
+		tier.EarnMultiplier = 1
+	}
+	if err := validateLoyaltyTier(tier); err != nil {
+		return response.LoyaltyTier{}, err
+	}
+	updatedTier, err := l.loyaltyRepo.UpdateTier(tier, id)
+	return updatedTier, err
+}
+
+// DeleteTier implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) DeleteTier(id int) error {
+	err := l.loyaltyRepo.DeleteTier(id)
+	return err
+}
+
+// ListTiers implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) ListTiers() ([]response.LoyaltyTier, error) {
+	tiers, err := l.loyaltyRepo.ListTiers()
+	return tiers, err
+}
+
+// PointsSummary implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) PointsSummary(userId int) (response.LoyaltySummary, error) {
+	summary, err := l.loyaltyRepo.PointsSummary(userId)
+	if err != nil {
+		return response.LoyaltySummary{}, err
+	}
+	settings, err := l.loyaltyRepo.Settings()
+	if err != nil {
+		return response.LoyaltySummary{}, err
+	}
+	summary.Value = float64(summary.Balance) * settings.RupeesPerPoint
+	tiers, err := l.loyaltyRepo.ListTiers()
+	if err != nil {
+		return response.LoyaltySummary{}, err
+	}
+	nextTier(&summary, tiers)
+	return summary, nil
+}
+
+// PointsHistory implements interfaces.LoyaltyUseCase.
+func (l *loyaltyUseCase) PointsHistory(userId int, queryParams helperStruct.QueryParams) ([]response.LoyaltyEntry, int, error) {
+	entries, totalCount, err := l.loyaltyRepo.PointsHistory(userId, queryParams)
+	return entries, totalCount, err
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
+)
+
+func TestPointsRedemption(t *testing.T) {
+	phone := helperStruct.PricingItem{ProductItemId: 1, ProductName: "phone", Quantity: 1, Price: 1000}
+	testData := []struct {
+		name     string
+		points   pointsRedemption
+		coupon   response.Coupon
+		policy   pricingPolicy
+		redeemed int
+		discount float64
+		total    float64
+	}{
+		{
+			name:     "points pay at the configured rate",
+			points:   pointsRedemption{Points: 150, RupeesPerPoint: 1, MaxPercent: 20},
+			redeemed: 150,
+			discount: 150,
+			total:    850,
+		},
+		{
+			name:     "capped at the share of the order points may pay for",
+			points:   pointsRedemption{Points: 500, RupeesPerPoint: 1, MaxPercent: 20},
+			redeemed: 200,
+			discount: 200,
+			total:    800,
+		},
+		{
+			name:     "capped after the coupon",
+			points:   pointsRedemption{Points: 500, RupeesPerPoint: 1, MaxPercent: 20},
+			coupon:   response.Coupon{Name: "FLAT500", Type: "fixed", Amount: 500},
+			redeemed: 100,
+			discount: 100,
+			total:    400,
+		},
+		{
+			name:     "whole rupees only",
+			points:   pointsRedemption{Points: 10, RupeesPerPoint: 0.25},
+			redeemed: 8,
+			discount: 2,
+			total:    998,
+		},
+		{
+			name:     "no more than the order",
+			points:   pointsRedemption{Points: 5000, RupeesPerPoint: 1},
+			redeemed: 1000,
+			discount: 1000,
+			total:    0,
+		},
+		{
+			name:     "shipping is charged on what the goods are worth",
+			points:   pointsRedemption{Points: 200, RupeesPerPoint: 1, MaxPercent: 20},
+			policy:   pricingPolicy{ShippingFee: 40, FreeShippingAbove: 1000},
+			redeemed: 200,
+			discount: 200,
+			total:    800,
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := priceCart([]helperStruct.PricingItem{phone}, cartOffers{}, tt.coupon, tt.points, tt.policy)
+			assert.Equal(t, tt.redeemed, breakdown.PointsRedeemed)
+			assert.Equal(t, tt.discount, breakdown.PointsDiscount)
+			assert.Equal(t, tt.total, breakdown.Total)
+		})
+	}
+}
+
+func TestNextTier(t *testing.T) {
+	tiers := []response.LoyaltyTier{{Name: "silver", MinSpend: 5000}, {Name: "gold", MinSpend: 20000}}
+	testData := []struct {
+		name   string
+		spend  float64
+		next   string
+		toNext float64
+	}{
+		{name: "below every tier", spend: 1000, next: "silver", toNext: 4000},
+		{name: "reaches a tier", spend: 5000, next: "gold", toNext: 15000},
+		{name: "top tier", spend: 25000},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := response.LoyaltySummary{Spend: tt.spend}
+			nextTier(&summary, tiers)
+			assert.Equal(t, tt.next, summary.NextTier)
+			assert.Equal(t, tt.toNext, summary.SpendToNextTier)
+		})
+	}
+}
+
+func TestMemberBenefits(t *testing.T) {
+	phone := helperStruct.PricingItem{ProductItemId: 1, ProductName: "phone", Quantity: 1, Price: 1000}
+	policy := newPricingPolicy(config.Config{SHIPPINGFEE: 40, FREESHIPPINGABOVE: 2000})
+	testData := []struct {
+		name          string
+		tier          helperStruct.ApplicableTier
+		cartDiscounts []helperStruct.ApplicableDiscount
+		cartDiscount  float64
+		shipping      float64
+		total         float64
+	}{
+		{name: "no tier", shipping: 40, total: 1040},
+		{name: "extra discount", tier: helperStruct.ApplicableTier{Name: "gold", ExtraDiscountPercent: 5}, cartDiscount: 50, shipping: 40, total: 990},
+		{
+			name:          "extra discount after the cart discounts",
+			tier:          helperStruct.ApplicableTier{Name: "gold", ExtraDiscountPercent: 5},
+			cartDiscounts: []helperStruct.ApplicableDiscount{percentOff("sale", 10)},
+			cartDiscount:  145,
+			shipping:      40,
+			total:         895,
+		},
+		{name: "free shipping", tier: helperStruct.ApplicableTier{Name: "platinum", FreeShipping: true}, total: 1000},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := priceCart([]helperStruct.PricingItem{phone}, cartOffers{discounts: tt.cartDiscounts, tier: tt.tier}, response.Coupon{}, pointsRedemption{}, policy)
+			assert.Equal(t, tt.cartDiscount, breakdown.CartDiscount)
+			assert.Equal(t, tt.shipping, breakdown.Shipping)
+			assert.Equal(t, tt.total, breakdown.Total)
+		})
+	}
+}
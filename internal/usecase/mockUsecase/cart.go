@@ -48,6 +48,36 @@ func (mr *MockCartUseCaseMockRecorder) AddToCart(productId, usersId interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCart", reflect.TypeOf((*MockCartUseCase)(nil).AddToCart), productId, usersId)
 }
 
+// ApplyCoupon mocks base method.
+func (m *MockCartUseCase) ApplyCoupon(userId int, couponName string) (response.ViewCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCoupon", userId, couponName)
+	ret0, _ := ret[0].(response.ViewCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyCoupon indicates an expected call of ApplyCoupon.
+func (mr *MockCartUseCaseMockRecorder) ApplyCoupon(userId, couponName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCoupon", reflect.TypeOf((*MockCartUseCase)(nil).ApplyCoupon), userId, couponName)
+}
+
+// AvailableCoupons mocks base method.
+func (m *MockCartUseCase) AvailableCoupons(userId int) ([]response.CouponOffer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailableCoupons", userId)
+	ret0, _ := ret[0].([]response.CouponOffer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AvailableCoupons indicates an expected call of AvailableCoupons.
+func (mr *MockCartUseCaseMockRecorder) AvailableCoupons(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailableCoupons", reflect.TypeOf((*MockCartUseCase)(nil).AvailableCoupons), userId)
+}
+
 // CreateCart mocks base method.
 func (m *MockCartUseCase) CreateCart(Id int) error {
 	m.ctrl.T.Helper()
@@ -77,6 +107,21 @@ func (mr *MockCartUseCaseMockRecorder) ListCart(userId interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCart", reflect.TypeOf((*MockCartUseCase)(nil).ListCart), userId)
 }
 
+// RemoveCoupon mocks base method.
+func (m *MockCartUseCase) RemoveCoupon(userId int) (response.ViewCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCoupon", userId)
+	ret0, _ := ret[0].(response.ViewCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveCoupon indicates an expected call of RemoveCoupon.
+func (mr *MockCartUseCaseMockRecorder) RemoveCoupon(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCoupon", reflect.TypeOf((*MockCartUseCase)(nil).RemoveCoupon), userId)
+}
+
 // RemoveFromCart mocks base method.
 func (m *MockCartUseCase) RemoveFromCart(productId, userId int) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,202 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/usecase/interface/order.go
+
+// Package mock_interfaces is a generated GoMock package.
+package mock_interfaces
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
+)
+
+// MockOrderUseCase is a mock of OrderUseCase interface.
+type MockOrderUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderUseCaseMockRecorder
+}
+
+// MockOrderUseCaseMockRecorder is the mock recorder for MockOrderUseCase.
+type MockOrderUseCaseMockRecorder struct {
+	mock *MockOrderUseCase
+}
+
+// NewMockOrderUseCase creates a new mock instance.
+func NewMockOrderUseCase(ctrl *gomock.Controller) *MockOrderUseCase {
+	mock := &MockOrderUseCase{ctrl: ctrl}
+	mock.recorder = &MockOrderUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderUseCase) EXPECT() *MockOrderUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AddOrderStatus mocks base method.
+func (m *MockOrderUseCase) AddOrderStatus(orderStatus helperStruct.OrderStatus) (response.OrderStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrderStatus", orderStatus)
+	ret0, _ := ret[0].(response.OrderStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrderStatus indicates an expected call of AddOrderStatus.
+func (mr *MockOrderUseCaseMockRecorder) AddOrderStatus(orderStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrderStatus", reflect.TypeOf((*MockOrderUseCase)(nil).AddOrderStatus), orderStatus)
+}
+
+// DisplayOrderForAdmin mocks base method.
+func (m *MockOrderUseCase) DisplayOrderForAdmin(orderId int) (response.AdminOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisplayOrderForAdmin", orderId)
+	ret0, _ := ret[0].(response.AdminOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisplayOrderForAdmin indicates an expected call of DisplayOrderForAdmin.
+func (mr *MockOrderUseCaseMockRecorder) DisplayOrderForAdmin(orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayOrderForAdmin", reflect.TypeOf((*MockOrderUseCase)(nil).DisplayOrderForAdmin), orderId)
+}
+
+// Displayorder mocks base method.
+func (m *MockOrderUseCase) Displayorder(userId, orderId int) (response.ResponseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Displayorder", userId, orderId)
+	ret0, _ := ret[0].(response.ResponseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Displayorder indicates an expected call of Displayorder.
+func (mr *MockOrderUseCaseMockRecorder) Displayorder(userId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Displayorder", reflect.TypeOf((*MockOrderUseCase)(nil).Displayorder), userId, orderId)
+}
+
+// ListAllOrderStatuses mocks base method.
+func (m *MockOrderUseCase) ListAllOrderStatuses() ([]response.OrderStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllOrderStatuses")
+	ret0, _ := ret[0].([]response.OrderStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllOrderStatuses indicates an expected call of ListAllOrderStatuses.
+func (mr *MockOrderUseCaseMockRecorder) ListAllOrderStatuses() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllOrderStatuses", reflect.TypeOf((*MockOrderUseCase)(nil).ListAllOrderStatuses))
+}
+
+// ListAllOrders mocks base method.
+func (m *MockOrderUseCase) ListAllOrders(userId int, queryParams helperStruct.QueryParams) ([]response.OrderResponse, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllOrders", userId, queryParams)
+	ret0, _ := ret[0].([]response.OrderResponse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllOrders indicates an expected call of ListAllOrders.
+func (mr *MockOrderUseCaseMockRecorder) ListAllOrders(userId, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllOrders", reflect.TypeOf((*MockOrderUseCase)(nil).ListAllOrders), userId, queryParams)
+}
+
+// ListAllOrdersForAdmin mocks base method.
+func (m *MockOrderUseCase) ListAllOrdersForAdmin(queryParams helperStruct.QueryParams) ([]response.AdminOrder, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllOrdersForAdmin", queryParams)
+	ret0, _ := ret[0].([]response.AdminOrder)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAllOrdersForAdmin indicates an expected call of ListAllOrdersForAdmin.
+func (mr *MockOrderUseCaseMockRecorder) ListAllOrdersForAdmin(queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllOrdersForAdmin", reflect.TypeOf((*MockOrderUseCase)(nil).ListAllOrdersForAdmin), queryParams)
+}
+
+// OrderAll mocks base method.
+func (m *MockOrderUseCase) OrderAll(id, paymentTypeId int, CouponName string, autoApplyCoupon bool, redeemPoints, walletAmount int) (response.ResponseOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OrderAll", id, paymentTypeId, CouponName, autoApplyCoupon, redeemPoints, walletAmount)
+	ret0, _ := ret[0].(response.ResponseOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderAll indicates an expected call of OrderAll.
+func (mr *MockOrderUseCaseMockRecorder) OrderAll(id, paymentTypeId, CouponName, autoApplyCoupon, redeemPoints, walletAmount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderAll", reflect.TypeOf((*MockOrderUseCase)(nil).OrderAll), id, paymentTypeId, CouponName, autoApplyCoupon, redeemPoints, walletAmount)
+}
+
+// ReturnOrder mocks base method.
+func (m *MockOrderUseCase) ReturnOrder(userId, orderId int) (response.ReturnOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnOrder", userId, orderId)
+	ret0, _ := ret[0].(response.ReturnOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnOrder indicates an expected call of ReturnOrder.
+func (mr *MockOrderUseCaseMockRecorder) ReturnOrder(userId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnOrder", reflect.TypeOf((*MockOrderUseCase)(nil).ReturnOrder), userId, orderId)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderUseCase) UpdateOrderStatus(updateOrder helperStruct.UpdateOrder) (response.AdminOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", updateOrder)
+	ret0, _ := ret[0].(response.AdminOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderUseCaseMockRecorder) UpdateOrderStatus(updateOrder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderUseCase)(nil).UpdateOrderStatus), updateOrder)
+}
+
+// UpdateOrderStatuses mocks base method.
+func (m *MockOrderUseCase) UpdateOrderStatuses(orderStatus helperStruct.OrderStatus) (response.OrderStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatuses", orderStatus)
+	ret0, _ := ret[0].(response.OrderStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatuses indicates an expected call of UpdateOrderStatuses.
+func (mr *MockOrderUseCaseMockRecorder) UpdateOrderStatuses(orderStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatuses", reflect.TypeOf((*MockOrderUseCase)(nil).UpdateOrderStatuses), orderStatus)
+}
+
+// UserCancelOrder mocks base method.
+func (m *MockOrderUseCase) UserCancelOrder(orderId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserCancelOrder", orderId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserCancelOrder indicates an expected call of UserCancelOrder.
+func (mr *MockOrderUseCaseMockRecorder) UserCancelOrder(orderId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserCancelOrder", reflect.TypeOf((*MockOrderUseCase)(nil).UserCancelOrder), orderId, userId)
+}
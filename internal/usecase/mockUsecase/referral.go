@@ -8,6 +8,8 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	helperStruct "main.go/internal/common/helperStruct"
+	response "main.go/internal/common/response"
 )
 
 // MockReferralUseCase is a mock of ReferralUseCase interface.
@@ -34,29 +36,104 @@ func (m *MockReferralUseCase) EXPECT() *MockReferralUseCaseMockRecorder {
 }
 
 // AddReferral mocks base method.
-func (m *MockReferralUseCase) AddReferral(userId int, userMobile string) error {
+func (m *MockReferralUseCase) AddReferral(userId int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddReferral", userId, userMobile)
+	ret := m.ctrl.Call(m, "AddReferral", userId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddReferral indicates an expected call of AddReferral.
-func (mr *MockReferralUseCaseMockRecorder) AddReferral(userId, userMobile interface{}) *gomock.Call {
+func (mr *MockReferralUseCaseMockRecorder) AddReferral(userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReferral", reflect.TypeOf((*MockReferralUseCase)(nil).AddReferral), userId, userMobile)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReferral", reflect.TypeOf((*MockReferralUseCase)(nil).AddReferral), userId)
 }
 
-// ReferralOffer mocks base method.
-func (m *MockReferralUseCase) ReferralOffer(userId int, referralId string) error {
+// CheckReferralCode mocks base method.
+func (m *MockReferralUseCase) CheckReferralCode(referralId string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReferralOffer", userId, referralId)
+	ret := m.ctrl.Call(m, "CheckReferralCode", referralId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CheckReferralCode indicates an expected call of CheckReferralCode.
+func (mr *MockReferralUseCaseMockRecorder) CheckReferralCode(referralId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckReferralCode", reflect.TypeOf((*MockReferralUseCase)(nil).CheckReferralCode), referralId)
+}
+
+// ReferralDashboard mocks base method.
+func (m *MockReferralUseCase) ReferralDashboard(userId int) (response.ReferralDashboard, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralDashboard", userId)
+	ret0, _ := ret[0].(response.ReferralDashboard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralDashboard indicates an expected call of ReferralDashboard.
+func (mr *MockReferralUseCaseMockRecorder) ReferralDashboard(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralDashboard", reflect.TypeOf((*MockReferralUseCase)(nil).ReferralDashboard), userId)
+}
+
+// ReferralOffer mocks base method.
+func (m *MockReferralUseCase) ReferralOffer(userId int, referralId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralOffer", userId, referralId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // ReferralOffer indicates an expected call of ReferralOffer.
 func (mr *MockReferralUseCaseMockRecorder) ReferralOffer(userId, referralId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralOffer", reflect.TypeOf((*MockReferralUseCase)(nil).ReferralOffer), userId, referralId)
 }
+
+// ReferralSettings mocks base method.
+func (m *MockReferralUseCase) ReferralSettings() (response.ReferralSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferralSettings")
+	ret0, _ := ret[0].(response.ReferralSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferralSettings indicates an expected call of ReferralSettings.
+func (mr *MockReferralUseCaseMockRecorder) ReferralSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferralSettings", reflect.TypeOf((*MockReferralUseCase)(nil).ReferralSettings))
+}
+
+// RegenerateReferralCode mocks base method.
+func (m *MockReferralUseCase) RegenerateReferralCode(userId int) (response.ReferralCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateReferralCode", userId)
+	ret0, _ := ret[0].(response.ReferralCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateReferralCode indicates an expected call of RegenerateReferralCode.
+func (mr *MockReferralUseCaseMockRecorder) RegenerateReferralCode(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateReferralCode", reflect.TypeOf((*MockReferralUseCase)(nil).RegenerateReferralCode), userId)
+}
+
+// UpdateReferralSettings mocks base method.
+func (m *MockReferralUseCase) UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReferralSettings", settings)
+	ret0, _ := ret[0].(response.ReferralSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReferralSettings indicates an expected call of UpdateReferralSettings.
+func (mr *MockReferralUseCaseMockRecorder) UpdateReferralSettings(settings interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReferralSettings", reflect.TypeOf((*MockReferralUseCase)(nil).UpdateReferralSettings), settings)
+}
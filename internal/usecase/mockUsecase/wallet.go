@@ -35,6 +35,36 @@ func (m *MockWalletUseCase) EXPECT() *MockWalletUseCaseMockRecorder {
 	return m.recorder
 }
 
+// AdjustWallet mocks base method.
+func (m *MockWalletUseCase) AdjustWallet(adjustment helperStruct.WalletAdjustment, adminId int) (response.WalletAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustWallet", adjustment, adminId)
+	ret0, _ := ret[0].(response.WalletAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustWallet indicates an expected call of AdjustWallet.
+func (mr *MockWalletUseCaseMockRecorder) AdjustWallet(adjustment, adminId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustWallet", reflect.TypeOf((*MockWalletUseCase)(nil).AdjustWallet), adjustment, adminId)
+}
+
+// ApproveWalletAdjustment mocks base method.
+func (m *MockWalletUseCase) ApproveWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveWalletAdjustment", adjustmentId, superId, decision)
+	ret0, _ := ret[0].(response.WalletAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveWalletAdjustment indicates an expected call of ApproveWalletAdjustment.
+func (mr *MockWalletUseCaseMockRecorder) ApproveWalletAdjustment(adjustmentId, superId, decision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveWalletAdjustment", reflect.TypeOf((*MockWalletUseCase)(nil).ApproveWalletAdjustment), adjustmentId, superId, decision)
+}
+
 // CreateWallet mocks base method.
 func (m *MockWalletUseCase) CreateWallet(userId int) error {
 	m.ctrl.T.Helper()
@@ -64,11 +94,42 @@ func (mr *MockWalletUseCaseMockRecorder) DisplayWallet(userId interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisplayWallet", reflect.TypeOf((*MockWalletUseCase)(nil).DisplayWallet), userId)
 }
 
+// ListWalletAdjustments mocks base method.
+func (m *MockWalletUseCase) ListWalletAdjustments(filter helperStruct.WalletAdjustmentFilter, queryParams helperStruct.QueryParams) ([]response.WalletAdjustment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWalletAdjustments", filter, queryParams)
+	ret0, _ := ret[0].([]response.WalletAdjustment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListWalletAdjustments indicates an expected call of ListWalletAdjustments.
+func (mr *MockWalletUseCaseMockRecorder) ListWalletAdjustments(filter, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWalletAdjustments", reflect.TypeOf((*MockWalletUseCase)(nil).ListWalletAdjustments), filter, queryParams)
+}
+
+// RejectWalletAdjustment mocks base method.
+func (m *MockWalletUseCase) RejectWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectWalletAdjustment", adjustmentId, superId, decision)
+	ret0, _ := ret[0].(response.WalletAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectWalletAdjustment indicates an expected call of RejectWalletAdjustment.
+func (mr *MockWalletUseCaseMockRecorder) RejectWalletAdjustment(adjustmentId, superId, decision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectWalletAdjustment", reflect.TypeOf((*MockWalletUseCase)(nil).RejectWalletAdjustment), adjustmentId, superId, decision)
+}
+
 // WalletHistory mocks base method.
-func (m *MockWalletUseCase) WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletHistories, int, error) {
+func (m *MockWalletUseCase) WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletTransaction, int, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WalletHistory", userId, queryParams)
-	ret0, _ := ret[0].([]response.WalletHistories)
+	ret0, _ := ret[0].([]response.WalletTransaction)
 	ret1, _ := ret[1].(int)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
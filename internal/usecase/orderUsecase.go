@@ -2,45 +2,146 @@ package usecase
 
 import (
 	"fmt"
+	"log"
 
+	"github.com/razorpay/razorpay-go"
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
+	"main.go/internal/domain"
+	"main.go/internal/infrastructure/config"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
 )
 
 type OrderUseCase struct {
-	orderRepo  interfaces.OrderRepository
-	couponRepo interfaces.CouponRepository
+	orderRepo   interfaces.OrderRepository
+	couponRepo  interfaces.CouponRepository
+	cartRepo    interfaces.CartRepository
+	loyaltyRepo interfaces.LoyaltyRepository
+	pricing     pricingPolicy
+	cfg         config.Config
 }
 
-func NewOrderUseCase(orderRepo interfaces.OrderRepository, couponRepo interfaces.CouponRepository) services.OrderUseCase {
+func NewOrderUseCase(orderRepo interfaces.OrderRepository, couponRepo interfaces.CouponRepository, cartRepo interfaces.CartRepository,
+	loyaltyRepo interfaces.LoyaltyRepository, cfg config.Config) services.OrderUseCase {
 	return &OrderUseCase{
-		orderRepo:  orderRepo,
-		couponRepo: couponRepo,
+		orderRepo:   orderRepo,
+		couponRepo:  couponRepo,
+		cartRepo:    cartRepo,
+		loyaltyRepo: loyaltyRepo,
+		pricing:     newPricingPolicy(cfg),
+		cfg:         cfg,
 	}
 }
 
 // OrderAll implements interfaces.OrderUseCase.
-func (o *OrderUseCase) OrderAll(id int, paymentTypeId int, CouponName string) (response.ResponseOrder, error) {
-	coupon, _ := o.couponRepo.CouponFromName(CouponName)
-	if coupon.Id == 0 && CouponName != "" {
-		return response.ResponseOrder{}, fmt.Errorf("invalid coupon code")
+// Without a coupon named or applied to the cart, autoApplyCoupon applies the
+// coupon that saves the most. redeemPoints loyalty points pay for part of
+// the order, as far as the program allows. walletAmount is paid from the
+// wallet and the rest through paymentTypeId.
+func (o *OrderUseCase) OrderAll(id int, paymentTypeId int, CouponName string, autoApplyCoupon bool, redeemPoints, walletAmount int) (response.ResponseOrder, error) {
+	var coupon response.Coupon
+	var err error
+	points, err := pointsToRedeem(o.loyaltyRepo, id, redeemPoints)
+	if err != nil {
+		return response.ResponseOrder{}, err
 	}
-	order, err := o.orderRepo.OrderAll(id, paymentTypeId, coupon)
-	return order, err
+	if CouponName != "" {
+		coupon, _ = o.couponRepo.CouponFromName(CouponName)
+		if coupon.Id == 0 {
+			return response.ResponseOrder{}, fmt.Errorf("invalid coupon code")
+		}
+	} else {
+		// without a coupon code the one applied to the cart is used
+		couponName, _, err := o.cartRepo.CartCoupon(id)
+		if err != nil {
+			return response.ResponseOrder{}, err
+		}
+		if couponName != "" {
+			if coupon, err = o.couponRepo.CouponFromName(couponName); err != nil {
+				return response.ResponseOrder{}, err
+			}
+			if coupon.Id == 0 {
+				return response.ResponseOrder{}, fmt.Errorf("the coupon on your cart no longer exists, please remove it")
+			}
+		}
+	}
+	offers, err := loadCartOffers(o.cartRepo, id)
+	if err != nil {
+		return response.ResponseOrder{}, err
+	}
+	items, err := loadCartItems(o.cartRepo, id, offers)
+	if err != nil {
+		return response.ResponseOrder{}, err
+	}
+	if coupon.Id == 0 && autoApplyCoupon {
+		ranked, err := userCoupons(o.couponRepo, id, items, offers, o.pricing)
+		if err != nil {
+			return response.ResponseOrder{}, err
+		}
+		if len(ranked) > 0 {
+			coupon = ranked[0].coupon
+		}
+	}
+	pricing := priceCart(items, offers, coupon, points, o.pricing)
+	if coupon.Id != 0 && len(items) > 0 {
+		if err := couponUsable(o.couponRepo, id, coupon, items, pricing); err != nil {
+			return response.ResponseOrder{}, err
+		}
+	}
+	paymentTypeId, walletAmount, err = splitPayment(paymentTypeId, walletAmount, int(pricing.Total))
+	if err != nil {
+		return response.ResponseOrder{}, err
+	}
+	order, err := o.orderRepo.OrderAll(id, paymentTypeId, walletAmount, coupon, pricing)
+	if err != nil {
+		return order, err
+	}
+	order.Pricing = &pricing
+	return order, nil
+}
+
+// splitPayment works out how much of an order's total is paid from the wallet.
+// Paying with the wallet payment type pays it all from the wallet, and a
+// wallet amount covering the whole total turns the order into a wallet order.
+func splitPayment(paymentTypeId, walletAmount, orderTotal int) (int, int, error) {
+	if walletAmount < 0 {
+		return 0, 0, fmt.Errorf("wallet amount cannot be negative")
+	}
+	if paymentTypeId == domain.WalletPaymentTypeId || (walletAmount > 0 && walletAmount >= orderTotal) {
+		return domain.WalletPaymentTypeId, orderTotal, nil
+	}
+	return paymentTypeId, walletAmount, nil
 }
 
 // UserCancelOrder implements interfaces.OrderUseCase.
+// The wallet and COD legs are refunded to the wallet by the repository,
+// Razorpay legs are refunded through Razorpay.
 func (o *OrderUseCase) UserCancelOrder(orderId int, userId int) error {
 	err := o.orderRepo.UserCancelOrder(orderId, userId)
-	return err
+	if err != nil {
+		return err
+	}
+	o.issueRefunds(orderId)
+	return nil
 }
 
 // Displayorder implements interfaces.OrderUseCase.
 func (o *OrderUseCase) Displayorder(userId int, orderId int) (response.ResponseOrder, error) {
 	order, err := o.orderRepo.DisplayOrder(userId, orderId)
-	return order, err
+	if err != nil {
+		return order, err
+	}
+	pricing := priceOrder(order, o.pricing)
+	order.OrderResponse.SubTotal = int(pricing.SubTotal)
+	order.OrderResponse.DiscountPrice = -int(pricing.ItemDiscount)
+	order.OrderResponse.CartDiscount = -int(pricing.CartDiscount)
+	order.OrderResponse.CouponAmount = -int(pricing.CouponDiscount)
+	order.OrderResponse.PointsDiscount = -int(pricing.PointsDiscount)
+	order.OrderResponse.Shipping = int(pricing.Shipping)
+	order.OrderResponse.Tax = pricing.Tax
+	order.Pricing = &pricing
+	return order, nil
 }
 
 // ListAllOrders implements interfaces.OrderUseCase.
@@ -50,9 +151,40 @@ func (o *OrderUseCase) ListAllOrders(userId int, queryParams helperStruct.QueryP
 }
 
 // ReturnOrder implements interfaces.OrderUseCase.
+// Refunds go back the same way as on cancelling.
 func (o *OrderUseCase) ReturnOrder(userId int, orderId int) (response.ReturnOrder, error) {
 	returnOrder, err := o.orderRepo.ReturnOrder(userId, orderId)
-	return returnOrder, err
+	if err != nil {
+		return returnOrder, err
+	}
+	o.issueRefunds(orderId)
+	return returnOrder, nil
+}
+
+// issueRefunds sends an order's pending payment refunds to Razorpay. The
+// order is already cancelled or returned, so a refund the gateway turns
+// down is logged and stays pending to be sent again.
+func (o *OrderUseCase) issueRefunds(orderId int) {
+	refunds, err := o.orderRepo.PendingRefunds(orderId)
+	if err != nil {
+		log.Printf("listing pending refunds for order %d: %v", orderId, err)
+		return
+	}
+	if len(refunds) == 0 {
+		return
+	}
+	client := razorpay.NewClient(o.cfg.RAZORPAYID, o.cfg.RAZORPAYSECRET)
+	for _, refund := range refunds {
+		body, err := client.Payment.Refund(refund.PaymentRef, refund.Amount*100, nil, nil)
+		if err != nil {
+			log.Printf("refunding payment %s for order %d: %v", refund.PaymentRef, orderId, err)
+			continue
+		}
+		gatewayRef, _ := body["id"].(string)
+		if err = o.orderRepo.CompleteRefund(refund.Id, gatewayRef); err != nil {
+			log.Printf("completing refund %d for order %d: %v", refund.Id, orderId, err)
+		}
+	}
 }
 
 // UpdateOrderStatus implements interfaces.OrderUseCase.
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/domain"
+)
+
+func TestSplitPayment(t *testing.T) {
+	testData := []struct {
+		name                  string
+		paymentTypeId         int
+		walletAmount          int
+		orderTotal            int
+		expectedPaymentTypeId int
+		expectedWalletAmount  int
+		expectErr             bool
+	}{
+		{name: "cash on delivery", paymentTypeId: 1, walletAmount: 0, orderTotal: 1000, expectedPaymentTypeId: 1, expectedWalletAmount: 0},
+		{name: "wallet pays it all", paymentTypeId: domain.WalletPaymentTypeId, walletAmount: 0, orderTotal: 1000, expectedPaymentTypeId: domain.WalletPaymentTypeId, expectedWalletAmount: 1000},
+		{name: "part from the wallet", paymentTypeId: 2, walletAmount: 300, orderTotal: 1000, expectedPaymentTypeId: 2, expectedWalletAmount: 300},
+		{name: "wallet amount covers the total", paymentTypeId: 1, walletAmount: 1500, orderTotal: 1000, expectedPaymentTypeId: domain.WalletPaymentTypeId, expectedWalletAmount: 1000},
+		{name: "negative wallet amount", paymentTypeId: 1, walletAmount: -1, orderTotal: 1000, expectErr: true},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentTypeId, walletAmount, err := splitPayment(tt.paymentTypeId, tt.walletAmount, tt.orderTotal)
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expectedPaymentTypeId, paymentTypeId)
+			assert.Equal(t, tt.expectedWalletAmount, walletAmount)
+		})
+	}
+}
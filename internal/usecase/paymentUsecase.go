@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/razorpay/razorpay-go"
@@ -32,7 +35,10 @@ func (c *PaymentUseCase) CreateRazorpayPayment(orderId int) (response.OrderRespo
 		return response.OrderResponse{}, "", 0, err
 	}
 
-	if paymentDetails.PaymentStatusId == 3 {
+	if paymentDetails.OrdersId == 0 {
+		return response.OrderResponse{}, "", 0, fmt.Errorf("nothing left to pay online for this order")
+	}
+	if paymentDetails.PaymentStatusId == 3 || paymentDetails.PaymentStatusId == 5 {
 		return response.OrderResponse{}, "", 0, fmt.Errorf("payment already completed")
 	}
 	userId, err := c.orderRepo.UserIdFromOrder(orderId)
@@ -53,7 +59,7 @@ func (c *PaymentUseCase) CreateRazorpayPayment(orderId int) (response.OrderRespo
 	client := razorpay.NewClient(c.cfg.RAZORPAYID, c.cfg.RAZORPAYSECRET)
 
 	data := map[string]interface{}{
-		"amount":   order.OrderResponse.OrderTotal * 100,
+		"amount":   paymentDetails.Amount * 100,
 		"currency": "INR",
 		"receipt":  "test_receipt_id",
 	}
@@ -77,7 +83,7 @@ func (c *PaymentUseCase) UpdatePaymentDetails(paymentVerifier helperStruct.Payme
 		return fmt.Errorf("no order found")
 	}
 
-	if paymentDetails.OrderTotal != int(paymentVerifier.Total) {
+	if paymentDetails.Amount != int(paymentVerifier.Total) {
 		return fmt.Errorf("payment amount and order amount does not match")
 	}
 	updatedPayment, err := c.paymentRepo.UpdatePaymentDetails(paymentVerifier.OrderID, paymentVerifier.PaymentRef)
@@ -90,6 +96,74 @@ func (c *PaymentUseCase) UpdatePaymentDetails(paymentVerifier helperStruct.Payme
 	return nil
 }
 
+// Top-up limits used when WALLET_TOPUP_MIN or WALLET_TOPUP_MAX aren't set.
+const (
+	defaultWalletTopUpMin = 100
+	defaultWalletTopUpMax = 10000
+)
+
+// walletTopUpLimits returns the smallest and largest amount a wallet can be
+// topped up with at once.
+func walletTopUpLimits(cfg config.Config) (int, int) {
+	min, max := cfg.WALLETTOPUPMIN, cfg.WALLETTOPUPMAX
+	if min <= 0 {
+		min = defaultWalletTopUpMin
+	}
+	if max <= 0 {
+		max = defaultWalletTopUpMax
+	}
+	return min, max
+}
+
+// verifyRazorpaySignature checks the signature Razorpay's checkout returns
+// for a payment against the order it was made for.
+func verifyRazorpaySignature(razorpayOrderId, paymentRef, signature, secret string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(razorpayOrderId + "|" + paymentRef))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// CreateWalletTopUp implements interfaces.PaymentUseCase.
+func (c *PaymentUseCase) CreateWalletTopUp(userId, amount int) (response.WalletTopUp, error) {
+	min, max := walletTopUpLimits(c.cfg)
+	if amount < min || amount > max {
+		return response.WalletTopUp{}, fmt.Errorf("wallet can be topped up with rs.%d to rs.%d at a time", min, max)
+	}
+	client := razorpay.NewClient(c.cfg.RAZORPAYID, c.cfg.RAZORPAYSECRET)
+	data := map[string]interface{}{
+		"amount":   amount * 100,
+		"currency": "INR",
+		"receipt":  fmt.Sprintf("wallet_topup_%d", userId),
+	}
+	body, err := client.Order.Create(data, nil)
+	if err != nil {
+		return response.WalletTopUp{}, err
+	}
+	razorpayID, ok := body["id"].(string)
+	if !ok {
+		return response.WalletTopUp{}, fmt.Errorf("error creating razorpay order")
+	}
+	topUp, err := c.paymentRepo.CreateWalletTopUp(userId, amount, razorpayID)
+	return topUp, err
+}
+
+// VerifyWalletTopUp implements interfaces.PaymentUseCase.
+func (c *PaymentUseCase) VerifyWalletTopUp(topUpVerifier helperStruct.TopUpVerification) (response.WalletTopUp, error) {
+	topUp, err := c.paymentRepo.WalletTopUp(topUpVerifier.RazorpayOrderId)
+	if err != nil {
+		return response.WalletTopUp{}, err
+	}
+	if topUp.Id == 0 {
+		return response.WalletTopUp{}, fmt.Errorf("no top-up found")
+	}
+	if topUpVerifier.PaymentRef == "" || !verifyRazorpaySignature(topUpVerifier.RazorpayOrderId, topUpVerifier.PaymentRef, topUpVerifier.Signature, c.cfg.RAZORPAYSECRET) {
+		return response.WalletTopUp{}, fmt.Errorf("payment could not be verified")
+	}
+	completedTopUp, err := c.paymentRepo.CompleteWalletTopUp(topUpVerifier.RazorpayOrderId, topUpVerifier.PaymentRef)
+	return completedTopUp, err
+}
+
 // AddPaymentStatus implements interfaces.PaymentUseCase.
 func (p *PaymentUseCase) AddPaymentStatus(paymentStatus helperStruct.PaymentStatus) (domain.PaymentStatus, error) {
 	newPaymentStatus, err := p.paymentRepo.AddPaymentStatus(paymentStatus)
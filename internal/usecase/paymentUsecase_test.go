@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/infrastructure/config"
+)
+
+func TestVerifyRazorpaySignature(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("order_1|pay_1"))
+	signature := hex.EncodeToString(mac.Sum(nil))
+	testData := []struct {
+		name            string
+		razorpayOrderId string
+		paymentRef      string
+		signature       string
+		expectedOutput  bool
+	}{
+		{name: "signed by razorpay", razorpayOrderId: "order_1", paymentRef: "pay_1", signature: signature, expectedOutput: true},
+		{name: "payment for another order", razorpayOrderId: "order_2", paymentRef: "pay_1", signature: signature, expectedOutput: false},
+		{name: "made up payment", razorpayOrderId: "order_1", paymentRef: "pay_2", signature: signature, expectedOutput: false},
+		{name: "no signature", razorpayOrderId: "order_1", paymentRef: "pay_1", signature: "", expectedOutput: false},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, verifyRazorpaySignature(tt.razorpayOrderId, tt.paymentRef, tt.signature, "secret"))
+		})
+	}
+}
+
+func TestWalletTopUpLimits(t *testing.T) {
+	testData := []struct {
+		name        string
+		cfg         config.Config
+		expectedMin int
+		expectedMax int
+	}{
+		{name: "defaults", cfg: config.Config{}, expectedMin: defaultWalletTopUpMin, expectedMax: defaultWalletTopUpMax},
+		{name: "configured", cfg: config.Config{WALLETTOPUPMIN: 50, WALLETTOPUPMAX: 2000}, expectedMin: 50, expectedMax: 2000},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			min, max := walletTopUpLimits(tt.cfg)
+			assert.Equal(t, tt.expectedMin, min)
+			assert.Equal(t, tt.expectedMax, max)
+		})
+	}
+}
@@ -0,0 +1,599 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
+)
+
+// pricingPolicy holds the storewide settings the pricing engine applies on
+// top of item prices.
+type pricingPolicy struct {
+	TaxPercent        float64 //GST already included in every price
+	ShippingFee       float64
+	FreeShippingAbove float64 //orders worth this much ship free, 0 charges every order
+}
+
+// defaultTaxPercent is the GST included in every price.
+const defaultTaxPercent = 18
+
+// newPricingPolicy reads the shipping charges from config, without a
+// SHIPPING_FEE every order ships free.
+func newPricingPolicy(cfg config.Config) pricingPolicy {
+	return pricingPolicy{
+		TaxPercent:        defaultTaxPercent,
+		ShippingFee:       cfg.SHIPPINGFEE,
+		FreeShippingAbove: cfg.FREESHIPPINGABOVE,
+	}
+}
+
+// shipping is what an order worth goods pays for delivery.
+func (p pricingPolicy) shipping(goods float64) float64 {
+	if p.ShippingFee <= 0 || goods <= 0 {
+		return 0
+	}
+	if p.FreeShippingAbove > 0 && goods >= p.FreeShippingAbove {
+		return 0
+	}
+	return p.ShippingFee
+}
+
+func roundPaise(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// discountOff works out what one discount takes off base, kept within its
+// cap and base itself.
+func discountOff(base float64, discount helperStruct.ApplicableDiscount) float64 {
+	off := base * discount.DiscountPercent / 100
+	if discount.Type == "fixed" {
+		off = discount.Amount
+	}
+	if discount.MaxDiscount > 0 {
+		off = math.Min(off, discount.MaxDiscount)
+	}
+	return math.Max(math.Min(off, base), 0)
+}
+
+// describeDiscount names a discount for the explanation trail.
+func describeDiscount(discount helperStruct.ApplicableDiscount) string {
+	description := fmt.Sprintf("%g%% off", discount.DiscountPercent)
+	if discount.Type == "fixed" {
+		description = fmt.Sprintf("%.2f off", discount.Amount)
+	} else if discount.MaxDiscount > 0 {
+		description += fmt.Sprintf(" up to %.2f", discount.MaxDiscount)
+	}
+	if discount.Name != "" {
+		description = discount.Name + " " + description
+	}
+	return description
+}
+
+// bestDiscount stacks discounts on base the way the catalog does: the
+// exclusive discount with the highest priority, or all stackable discounts
+// added up, whichever saves more. It returns the amount taken off and what
+// made it up.
+func bestDiscount(base float64, discounts []helperStruct.ApplicableDiscount) (float64, string) {
+	var exclusive, stacked float64
+	var best *helperStruct.ApplicableDiscount
+	var stackedNames []string
+	for i, discount := range discounts {
+		off := discountOff(base, discount)
+		if discount.Stackable {
+			if off > 0 {
+				stacked += off
+				stackedNames = append(stackedNames, describeDiscount(discount))
+			}
+			continue
+		}
+		// the highest priority wins, the bigger saving breaks ties
+		if best == nil || discount.Priority > best.Priority || (discount.Priority == best.Priority && off > exclusive) {
+			exclusive, best = off, &discounts[i]
+		}
+	}
+	if stacked > exclusive {
+		return math.Min(stacked, base), strings.Join(stackedNames, " + ")
+	}
+	if best == nil || exclusive == 0 {
+		return 0, ""
+	}
+	return exclusive, describeDiscount(*best)
+}
+
+// priceLine works out what one cart line costs. The unit price is the lowest
+// of the list price, the price after the item's discounts and a live sale
+// price, rounded to whole rupees since orders are kept in rupees.
+func priceLine(item helperStruct.PricingItem) (response.PricedLine, []response.PricingStep) {
+	line := response.PricedLine{
+		ProductItemId: item.ProductItemId,
+		ProductName:   item.ProductName,
+		Quantity:      item.Quantity,
+		ListPrice:     item.Price,
+		UnitPrice:     item.Price,
+	}
+	var steps []response.PricingStep
+	reason := ""
+	if off, description := bestDiscount(item.Price, item.Discounts); off > 0 {
+		line.UnitPrice = item.Price - off
+		reason = description
+	}
+	if item.SalePrice > 0 && item.SalePrice < line.UnitPrice {
+		line.UnitPrice = item.SalePrice
+		line.SaleRuleId = item.SaleRuleId
+		reason = fmt.Sprintf("sale %s at %.2f", item.SaleName, item.SalePrice)
+	}
+	line.UnitPrice = math.Round(line.UnitPrice)
+	line.Discount = (line.ListPrice - line.UnitPrice) * float64(line.Quantity)
+	line.Total = line.UnitPrice * float64(line.Quantity)
+	steps = append(steps, response.PricingStep{
+		ProductItemId: line.ProductItemId,
+		Description:   fmt.Sprintf("%s: %d x %.2f", line.ProductName, line.Quantity, line.ListPrice),
+		Amount:        line.ListPrice * float64(line.Quantity),
+	})
+	if line.Discount > 0 {
+		steps = append(steps, response.PricingStep{
+			ProductItemId: line.ProductItemId,
+			Description:   fmt.Sprintf("%s: %s", line.ProductName, reason),
+			Amount:        -line.Discount,
+		})
+	}
+	return line, steps
+}
+
+// settle adds up the priced lines of a breakdown and applies the coupon,
+// shipping and the tax included in the total. A cart discount has to be on
+// the breakdown, with its explanation, before settling, and so do the
+// loyalty points redeemed.
+func settle(breakdown *response.PriceBreakdown, couponCode string, couponAmount, shipping, taxPercent float64) {
+	breakdown.SubTotal, breakdown.ItemDiscount = 0, 0
+	for _, line := range breakdown.Lines {
+		breakdown.SubTotal += line.ListPrice * float64(line.Quantity)
+		breakdown.ItemDiscount += line.Discount
+	}
+	goods := breakdown.SubTotal - breakdown.ItemDiscount - breakdown.CartDiscount
+	if couponCode != "" {
+		breakdown.CouponCode = couponCode
+		// a coupon never takes the order below zero
+		breakdown.CouponDiscount = math.Min(couponAmount, goods)
+		description := fmt.Sprintf("coupon %s", couponCode)
+		if breakdown.CouponDiscount < couponAmount {
+			description = fmt.Sprintf("coupon %s, capped at the order value", couponCode)
+		}
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{Description: description, Amount: -breakdown.CouponDiscount})
+		goods -= breakdown.CouponDiscount
+	}
+	if breakdown.PointsDiscount > 0 {
+		breakdown.PointsDiscount = math.Min(breakdown.PointsDiscount, goods)
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+			Description: fmt.Sprintf("%d loyalty points", breakdown.PointsRedeemed),
+			Amount:      -breakdown.PointsDiscount,
+		})
+		goods -= breakdown.PointsDiscount
+	}
+	breakdown.Shipping = shipping
+	if shipping > 0 {
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{Description: "shipping", Amount: shipping})
+	} else if len(breakdown.Lines) > 0 {
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{Description: "free shipping"})
+	}
+	if taxPercent > 0 {
+		breakdown.Tax = roundPaise(goods * taxPercent / (100 + taxPercent))
+		if breakdown.Tax > 0 {
+			breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+				Description: fmt.Sprintf("includes %g%% GST of %.2f", taxPercent, breakdown.Tax),
+			})
+		}
+	}
+	breakdown.Total = goods + shipping
+}
+
+// cartDiscount takes the best of the cart discounts the goods qualify for
+// off the order, rounded to whole rupees.
+func cartDiscount(breakdown *response.PriceBreakdown, goods float64, discounts []helperStruct.ApplicableDiscount) {
+	var eligible []helperStruct.ApplicableDiscount
+	for _, discount := range discounts {
+		if goods > 0 && goods >= discount.MinCartValue {
+			eligible = append(eligible, discount)
+		}
+	}
+	off, description := bestDiscount(goods, eligible)
+	breakdown.CartDiscount = math.Round(off)
+	if breakdown.CartDiscount > 0 {
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+			Description: "cart discount: " + description,
+			Amount:      -breakdown.CartDiscount,
+		})
+	}
+}
+
+// cartOffers are the cart discounts and promotions running when a cart is
+// priced and the benefits of the tier its owner is in.
+type cartOffers struct {
+	discounts  []helperStruct.ApplicableDiscount
+	promotions []helperStruct.ApplicablePromotion
+	tier       helperStruct.ApplicableTier
+}
+
+// memberDiscount takes the extra discount of a loyalty tier off what is left
+// after the cart discounts, rounded to whole rupees. It is kept with the cart
+// discount so placed orders add up the same way.
+func memberDiscount(breakdown *response.PriceBreakdown, goods float64, tier helperStruct.ApplicableTier) float64 {
+	if tier.ExtraDiscountPercent <= 0 || goods <= 0 {
+		return 0
+	}
+	off := math.Min(math.Round(goods*tier.ExtraDiscountPercent/100), goods)
+	if off > 0 {
+		breakdown.CartDiscount += off
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+			Description: fmt.Sprintf("%s member discount: %g%% off", tier.Name, tier.ExtraDiscountPercent),
+			Amount:      -off,
+		})
+	}
+	return off
+}
+
+// pointsRedemption is the loyalty points a user redeems on an order and the
+// rate the program pays them out at.
+type pointsRedemption struct {
+	Points         int
+	RupeesPerPoint float64
+	MaxPercent     float64 //share of the goods points may pay for, 0 is no limit
+}
+
+// value is what the points take off goods, in whole rupees, and the points
+// that uses up. Points beyond the limit stay with the user.
+func (r pointsRedemption) value(goods float64) (float64, int) {
+	if r.Points <= 0 || r.RupeesPerPoint <= 0 || goods <= 0 {
+		return 0, 0
+	}
+	limit := goods
+	if r.MaxPercent > 0 {
+		limit = math.Min(limit, goods*r.MaxPercent/100)
+	}
+	rupees := math.Floor(math.Min(float64(r.Points)*r.RupeesPerPoint, limit))
+	points := int(math.Ceil(rupees/r.RupeesPerPoint - 1e-9))
+	if points > r.Points {
+		points = r.Points
+	}
+	return rupees, points
+}
+
+// priceCart prices the items in a cart, the offers running, the coupon
+// applied to it and the loyalty points redeemed. An empty coupon name means
+// no coupon.
+func priceCart(items []helperStruct.PricingItem, offers cartOffers, coupon response.Coupon, points pointsRedemption, policy pricingPolicy) response.PriceBreakdown {
+	breakdown := response.PriceBreakdown{
+		Lines:       []response.PricedLine{},
+		Explanation: []response.PricingStep{},
+	}
+	for _, item := range items {
+		line, steps := priceLine(item)
+		breakdown.Lines = append(breakdown.Lines, line)
+		breakdown.Explanation = append(breakdown.Explanation, steps...)
+	}
+	steps, _ := applyPromotions(items, breakdown.Lines, offers.promotions)
+	breakdown.Explanation = append(breakdown.Explanation, steps...)
+	goods := 0.0
+	for _, line := range breakdown.Lines {
+		goods += line.Total
+	}
+	cartDiscount(&breakdown, goods, offers.discounts)
+	goods -= breakdown.CartDiscount
+	goods -= memberDiscount(&breakdown, goods, offers.tier)
+	couponAmount := 0.0
+	if coupon.Name != "" {
+		couponAmount = couponValue(coupon, items, breakdown.Lines, goods)
+	}
+	// points pay for goods, they don't make an order ship free
+	goods -= math.Min(couponAmount, goods)
+	breakdown.PointsDiscount, breakdown.PointsRedeemed = points.value(goods)
+	shipping := policy.shipping(goods)
+	if offers.tier.FreeShipping {
+		shipping = 0
+	}
+	settle(&breakdown, coupon.Name, couponAmount, shipping, policy.TaxPercent)
+	return breakdown
+}
+
+// promotionCovers tells whether item is one of the promotion's items in role.
+func promotionCovers(promotion helperStruct.ApplicablePromotion, role string, item helperStruct.PricingItem) bool {
+	for _, promotionItem := range promotion.Items {
+		if promotionItem.Role != role {
+			continue
+		}
+		switch {
+		case promotionItem.Scope == "item" && promotionItem.ScopeId == item.ProductItemId,
+			promotionItem.Scope == "product" && promotionItem.ScopeId == item.ProductId,
+			promotionItem.Scope == "brand" && promotionItem.ScopeId == item.BrandId,
+			promotionItem.Scope == "category" && promotionItem.ScopeId == item.CategoryId:
+			return true
+		}
+	}
+	return false
+}
+
+// promotionRun applies promotions to priced lines. used counts the units of
+// each line a promotion already took as a trigger, reward or bundle item, so
+// no unit counts towards two promotions.
+type promotionRun struct {
+	items     []helperStruct.PricingItem
+	lines     []response.PricedLine
+	used      []int
+	steps     []response.PricingStep
+	freeUnits map[uint]int //free reward units the promotions call for, by item
+}
+
+func (r *promotionRun) available(i int) int {
+	return r.lines[i].Quantity - r.used[i]
+}
+
+// take uses up to units of line i and returns how many it got.
+func (r *promotionRun) take(i, units int) int {
+	if units > r.available(i) {
+		units = r.available(i)
+	}
+	r.used[i] += units
+	return units
+}
+
+// discount takes off from line i for promotion.
+func (r *promotionRun) discount(i int, promotion string, off float64, description string) {
+	line := &r.lines[i]
+	off = math.Min(off, line.Total)
+	if off <= 0 {
+		return
+	}
+	line.Discount += off
+	line.PromotionDiscount += off
+	line.Total -= off
+	if line.Promotion == "" {
+		line.Promotion = promotion
+	} else if !strings.Contains(line.Promotion, promotion) {
+		line.Promotion += ", " + promotion
+	}
+	r.steps = append(r.steps, response.PricingStep{
+		ProductItemId: line.ProductItemId,
+		Description:   fmt.Sprintf("%s: %s", line.ProductName, description),
+		Amount:        -off,
+	})
+}
+
+// buyGet applies a buy-x-get-y promotion. When a reward item also sets the
+// promotion off, its rewarded units are part of the group, so buy 2 get 1
+// free of the same item needs 3 units. Such rewards are never added to the
+// cart for the customer.
+func (r *promotionRun) buyGet(promotion helperStruct.ApplicablePromotion) {
+	triggers, group, sameItem := 0, promotion.TriggerQuantity, false
+	for i, item := range r.items {
+		if promotionCovers(promotion, "trigger", item) {
+			triggers += r.available(i)
+			if promotionCovers(promotion, "reward", item) {
+				sameItem = true
+			}
+		}
+	}
+	if sameItem {
+		group += promotion.RewardQuantity
+	}
+	if group <= 0 {
+		return
+	}
+	applications := triggers / group
+	if promotion.MaxApplications > 0 && applications > promotion.MaxApplications {
+		applications = promotion.MaxApplications
+	}
+	if applications == 0 {
+		return
+	}
+	// rewards go first so the units left of a shared item are the triggers
+	for _, reward := range promotion.Items {
+		if reward.Role != "reward" {
+			continue
+		}
+		units := applications * promotion.RewardQuantity
+		if promotion.RewardPercent >= 100 && !sameItem {
+			r.freeUnits[reward.ScopeId] += units
+		}
+		for i, item := range r.items {
+			if item.ProductItemId != reward.ScopeId {
+				continue
+			}
+			rewarded := r.take(i, units)
+			if rewarded == 0 {
+				continue
+			}
+			off := math.Round(r.lines[i].UnitPrice * float64(rewarded) * math.Min(promotion.RewardPercent, 100) / 100)
+			description := fmt.Sprintf("%s, %d at %g%% off", promotion.Name, rewarded, promotion.RewardPercent)
+			if promotion.RewardPercent >= 100 {
+				description = fmt.Sprintf("%s, %d free", promotion.Name, rewarded)
+			}
+			r.discount(i, promotion.Name, off, description)
+		}
+	}
+	needed := applications * promotion.TriggerQuantity
+	for i, item := range r.items {
+		if needed > 0 && promotionCovers(promotion, "trigger", item) {
+			needed -= r.take(i, needed)
+		}
+	}
+}
+
+// bundle sells one of each bundle item together for the bundle price, the
+// saving is shared out over the items by their price.
+func (r *promotionRun) bundle(promotion helperStruct.ApplicablePromotion) {
+	var members []int
+	applications := -1
+	for _, bundleItem := range promotion.Items {
+		if bundleItem.Role != "bundle" {
+			continue
+		}
+		member := -1
+		for i, item := range r.items {
+			if item.ProductItemId == bundleItem.ScopeId {
+				member = i
+			}
+		}
+		if member == -1 {
+			return
+		}
+		members = append(members, member)
+		if applications == -1 || r.available(member) < applications {
+			applications = r.available(member)
+		}
+	}
+	if promotion.MaxApplications > 0 && applications > promotion.MaxApplications {
+		applications = promotion.MaxApplications
+	}
+	if len(members) == 0 || applications <= 0 {
+		return
+	}
+	regular := 0.0
+	for _, i := range members {
+		regular += r.lines[i].UnitPrice
+	}
+	saving := math.Round(regular - promotion.BundlePrice)
+	if saving <= 0 {
+		return
+	}
+	shared := 0.0
+	for k, i := range members {
+		share := math.Round(saving * r.lines[i].UnitPrice / regular)
+		if k == len(members)-1 {
+			share = saving - shared
+		}
+		shared += share
+		r.take(i, applications)
+		r.discount(i, promotion.Name, share*float64(applications),
+			fmt.Sprintf("%s, %d bundle at %.2f", promotion.Name, applications, promotion.BundlePrice))
+	}
+}
+
+// applyPromotions takes the promotions off the priced lines in the order
+// given. It returns the explanation steps and the free reward units the
+// promotions call for by item, whether or not the cart holds them.
+func applyPromotions(items []helperStruct.PricingItem, lines []response.PricedLine, promotions []helperStruct.ApplicablePromotion) ([]response.PricingStep, map[uint]int) {
+	run := promotionRun{items: items, lines: lines, used: make([]int, len(lines)), freeUnits: map[uint]int{}}
+	for _, promotion := range promotions {
+		switch promotion.Type {
+		case "buy_get":
+			run.buyGet(promotion)
+		case "bundle":
+			run.bundle(promotion)
+		}
+	}
+	return run.steps, run.freeUnits
+}
+
+// freeItemChanges works out how many free reward units of each item a cart
+// should hold on top of the units the customer added, and returns the items
+// where that differs from what the cart holds now.
+func freeItemChanges(items []helperStruct.PricingItem, promotions []helperStruct.ApplicablePromotion) map[uint]int {
+	var own []helperStruct.PricingItem
+	var lines []response.PricedLine
+	for _, item := range items {
+		item.Quantity -= item.FreeQuantity
+		if item.Quantity <= 0 {
+			continue
+		}
+		line, _ := priceLine(item)
+		own = append(own, item)
+		lines = append(lines, line)
+	}
+	_, wanted := applyPromotions(own, lines, promotions)
+	changes := map[uint]int{}
+	inCart := map[uint]bool{}
+	for _, item := range items {
+		inCart[item.ProductItemId] = true
+		if wanted[item.ProductItemId] != item.FreeQuantity {
+			changes[item.ProductItemId] = wanted[item.ProductItemId]
+		}
+	}
+	for id, units := range wanted {
+		if !inCart[id] && units > 0 {
+			changes[id] = units
+		}
+	}
+	return changes
+}
+
+// couponValue works out what a coupon takes off the goods it covers, rounded
+// to whole rupees. A coupon restricted to some items only counts those lines.
+func couponValue(coupon response.Coupon, items []helperStruct.PricingItem, lines []response.PricedLine, goods float64) float64 {
+	covered := goods
+	if len(coupon.Restrictions) > 0 {
+		covered = 0
+		for i, item := range items {
+			if couponCovers(coupon, item) {
+				covered += lines[i].Total
+			}
+		}
+		covered = math.Min(covered, goods)
+	}
+	if coupon.Type != "percentage" {
+		// the value of a coupon covering the whole order is capped when settling
+		if covered < goods {
+			return math.Min(float64(coupon.Amount), covered)
+		}
+		return float64(coupon.Amount)
+	}
+	off := covered * coupon.DiscountPercent / 100
+	if coupon.MaxDiscount > 0 {
+		off = math.Min(off, float64(coupon.MaxDiscount))
+	}
+	return math.Round(off)
+}
+
+// priceOrder rebuilds the breakdown of a placed order from the prices it was
+// placed at, so order pages and invoices add up the same way the cart did.
+func priceOrder(order response.ResponseOrder, policy pricingPolicy) response.PriceBreakdown {
+	breakdown := response.PriceBreakdown{
+		Lines:       []response.PricedLine{},
+		Explanation: []response.PricingStep{},
+	}
+	for _, product := range order.OrderProducts {
+		line := response.PricedLine{
+			ProductItemId: product.ProductItemId,
+			ProductName:   product.ProductName,
+			Quantity:      product.Quantity,
+			ListPrice:     float64(product.Price),
+			UnitPrice:     float64(product.Price) - product.DiscountPrice,
+			Discount:      product.DiscountPrice * float64(product.Quantity),
+		}
+		line.Total = line.UnitPrice * float64(line.Quantity)
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+			ProductItemId: line.ProductItemId,
+			Description:   fmt.Sprintf("%s: %d x %.2f", line.ProductName, line.Quantity, line.ListPrice),
+			Amount:        line.ListPrice * float64(line.Quantity),
+		})
+		if line.Discount > 0 {
+			breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+				ProductItemId: line.ProductItemId,
+				Description:   fmt.Sprintf("%s: discount at checkout", line.ProductName),
+				Amount:        -line.Discount,
+			})
+		}
+		if product.PromotionDiscount > 0 {
+			line.Promotion, line.PromotionDiscount = product.Promotion, product.PromotionDiscount
+			line.Discount += line.PromotionDiscount
+			line.Total -= line.PromotionDiscount
+			breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{
+				ProductItemId: line.ProductItemId,
+				Description:   fmt.Sprintf("%s: %s", line.ProductName, line.Promotion),
+				Amount:        -line.PromotionDiscount,
+			})
+		}
+		breakdown.Lines = append(breakdown.Lines, line)
+	}
+	if order.OrderResponse.CartDiscount > 0 {
+		breakdown.CartDiscount = float64(order.OrderResponse.CartDiscount)
+		breakdown.Explanation = append(breakdown.Explanation, response.PricingStep{Description: "cart discount", Amount: -breakdown.CartDiscount})
+	}
+	breakdown.PointsRedeemed = order.OrderResponse.PointsRedeemed
+	breakdown.PointsDiscount = float64(order.OrderResponse.PointsDiscount)
+	settle(&breakdown, order.OrderResponse.CouponCode, float64(order.OrderResponse.CouponAmount), float64(order.OrderResponse.Shipping), policy.TaxPercent)
+	return breakdown
+}
@@ -0,0 +1,642 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
+)
+
+func percentOff(name string, percent float64) helperStruct.ApplicableDiscount {
+	return helperStruct.ApplicableDiscount{Name: name, Type: "percentage", DiscountPercent: percent}
+}
+
+func TestPriceLine(t *testing.T) {
+	testData := []struct {
+		name              string
+		input             helperStruct.PricingItem
+		expectedOutput    response.PricedLine
+		expectedSteps     int
+		expectedReasonHas string
+	}{
+		{
+			name:  "full price",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Quantity: 2, Price: 50000},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 2, ListPrice: 50000, UnitPrice: 50000, Discount: 0, Total: 100000,
+			},
+			expectedSteps: 1,
+		},
+		{
+			name: "percentage discount",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Brand: "dell", Quantity: 2, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{percentOff("dell days", 10)}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 2, ListPrice: 50000, UnitPrice: 45000, Discount: 10000, Total: 90000,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: dell days 10% off",
+		},
+		{
+			name: "sale beats discount",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Brand: "dell", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{percentOff("dell days", 10)}, SalePrice: 40000, SaleRuleId: 7, SaleName: "midnight"},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 40000, Discount: 10000, Total: 40000, SaleRuleId: 7,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: sale midnight at 40000.00",
+		},
+		{
+			name: "discount beats sale",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Brand: "dell", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{percentOff("dell days", 30)}, SalePrice: 40000, SaleRuleId: 7, SaleName: "midnight"},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 35000, Discount: 15000, Total: 35000,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: dell days 30% off",
+		},
+		{
+			name:  "sale above the list price is ignored",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "mouse", Quantity: 1, Price: 500, SalePrice: 600, SaleRuleId: 3},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "mouse", Quantity: 1, ListPrice: 500, UnitPrice: 500, Discount: 0, Total: 500,
+			},
+			expectedSteps: 1,
+		},
+		{
+			name: "unit price is rounded to whole rupees",
+			input: helperStruct.PricingItem{ProductItemId: 2, ProductName: "mouse", Quantity: 3, Price: 999,
+				Discounts: []helperStruct.ApplicableDiscount{percentOff("", 15)}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 2, ProductName: "mouse", Quantity: 3, ListPrice: 999, UnitPrice: 849, Discount: 450, Total: 2547,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "mouse: 15% off",
+		},
+		{
+			name: "percentage discount is capped",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{{Name: "monsoon", Type: "percentage", DiscountPercent: 20, MaxDiscount: 3000}}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 47000, Discount: 3000, Total: 47000,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: monsoon 20% off up to 3000.00",
+		},
+		{
+			name: "highest priority exclusive discount wins over a bigger one",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{
+					{Name: "clearance", Type: "percentage", DiscountPercent: 20},
+					{Name: "partner", Type: "fixed", Amount: 2000, Priority: 5},
+				}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 48000, Discount: 2000, Total: 48000,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: partner 2000.00 off",
+		},
+		{
+			name: "stackable discounts add up when they save more",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{
+					{Name: "brand", Type: "percentage", DiscountPercent: 5},
+					{Name: "category", Type: "percentage", DiscountPercent: 5, Stackable: true},
+					{Name: "item", Type: "fixed", Amount: 1000, Stackable: true},
+				}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 46500, Discount: 3500, Total: 46500,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: category 5% off + item 1000.00 off",
+		},
+		{
+			name: "exclusive discount wins over a smaller stack",
+			input: helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Quantity: 1, Price: 50000,
+				Discounts: []helperStruct.ApplicableDiscount{
+					{Name: "festive", Type: "percentage", DiscountPercent: 10},
+					{Name: "category", Type: "percentage", DiscountPercent: 5, Stackable: true},
+					{Name: "item", Type: "fixed", Amount: 1000, Stackable: true},
+				}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 1, ProductName: "laptop", Quantity: 1, ListPrice: 50000, UnitPrice: 45000, Discount: 5000, Total: 45000,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "laptop: festive 10% off",
+		},
+		{
+			name: "a fixed discount never takes the price below zero",
+			input: helperStruct.PricingItem{ProductItemId: 2, ProductName: "cable", Quantity: 1, Price: 300,
+				Discounts: []helperStruct.ApplicableDiscount{{Name: "freebie", Type: "fixed", Amount: 500}}},
+			expectedOutput: response.PricedLine{
+				ProductItemId: 2, ProductName: "cable", Quantity: 1, ListPrice: 300, UnitPrice: 0, Discount: 300, Total: 0,
+			},
+			expectedSteps:     2,
+			expectedReasonHas: "cable: freebie 500.00 off",
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			line, steps := priceLine(tt.input)
+			assert.Equal(t, tt.expectedOutput, line)
+			assert.Equal(t, tt.expectedSteps, len(steps))
+			if tt.expectedReasonHas != "" {
+				assert.Equal(t, tt.expectedReasonHas, steps[1].Description)
+				assert.Equal(t, -tt.expectedOutput.Discount, steps[1].Amount)
+			}
+		})
+	}
+}
+
+func TestPriceCart(t *testing.T) {
+	laptop := helperStruct.PricingItem{ProductItemId: 1, ProductName: "laptop", Brand: "dell", Quantity: 1, Price: 50000,
+		Discounts: []helperStruct.ApplicableDiscount{percentOff("dell days", 10)}}
+	bigSpender := helperStruct.ApplicableDiscount{Name: "big spender", Type: "percentage", DiscountPercent: 5, MinCartValue: 50000}
+	mouse := helperStruct.PricingItem{ProductItemId: 2, ProductName: "mouse", Quantity: 2, Price: 500}
+	testData := []struct {
+		name           string
+		items          []helperStruct.PricingItem
+		cartDiscounts  []helperStruct.ApplicableDiscount
+		coupon         response.Coupon
+		policy         pricingPolicy
+		expectedOutput response.PriceBreakdown
+	}{
+		{
+			name:   "empty cart",
+			policy: pricingPolicy{TaxPercent: 18, ShippingFee: 100},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 0, ItemDiscount: 0, Shipping: 0, Tax: 0, Total: 0,
+			},
+		},
+		{
+			name:   "discounted items with tax included",
+			items:  []helperStruct.PricingItem{laptop, mouse},
+			policy: pricingPolicy{TaxPercent: 18},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, Shipping: 0, Tax: 7016.95, Total: 46000,
+			},
+		},
+		{
+			name:   "coupon comes off after item discounts",
+			items:  []helperStruct.PricingItem{laptop, mouse},
+			coupon: response.Coupon{Id: 1, Name: "welcome", Amount: 1000},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, CouponCode: "welcome", CouponDiscount: 1000, Total: 45000,
+			},
+		},
+		{
+			name:   "coupon is capped at the order value",
+			items:  []helperStruct.PricingItem{mouse},
+			coupon: response.Coupon{Id: 1, Name: "big", Amount: 5000},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 1000, ItemDiscount: 0, CouponCode: "big", CouponDiscount: 1000, Total: 0,
+			},
+		},
+		{
+			name:   "shipping below the free shipping threshold",
+			items:  []helperStruct.PricingItem{mouse},
+			policy: pricingPolicy{ShippingFee: 99, FreeShippingAbove: 5000},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 1000, Shipping: 99, Total: 1099,
+			},
+		},
+		{
+			name:   "free shipping above the threshold",
+			items:  []helperStruct.PricingItem{laptop},
+			policy: pricingPolicy{ShippingFee: 99, FreeShippingAbove: 5000},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 50000, ItemDiscount: 5000, Shipping: 0, Total: 45000,
+			},
+		},
+		{
+			name:   "coupon taking the order below the threshold brings shipping back",
+			items:  []helperStruct.PricingItem{{ProductItemId: 3, ProductName: "bag", Quantity: 1, Price: 5200}},
+			coupon: response.Coupon{Id: 2, Name: "bag500", Amount: 500},
+			policy: pricingPolicy{ShippingFee: 99, FreeShippingAbove: 5000},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 5200, CouponCode: "bag500", CouponDiscount: 500, Shipping: 99, Total: 4799,
+			},
+		},
+		{
+			name:   "tax is worked out on the goods after the coupon, not on shipping",
+			items:  []helperStruct.PricingItem{{ProductItemId: 3, ProductName: "bag", Quantity: 1, Price: 1280}},
+			coupon: response.Coupon{Id: 2, Name: "bag100", Amount: 100},
+			policy: pricingPolicy{TaxPercent: 18, ShippingFee: 50},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 1280, CouponCode: "bag100", CouponDiscount: 100, Shipping: 50, Tax: 180, Total: 1230,
+			},
+		},
+		{
+			name:          "cart discount needs the minimum cart value after item discounts",
+			items:         []helperStruct.PricingItem{laptop, mouse},
+			cartDiscounts: []helperStruct.ApplicableDiscount{bigSpender},
+			policy:        pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, Total: 46000,
+			},
+		},
+		{
+			name:          "cart discount comes off before the coupon",
+			items:         []helperStruct.PricingItem{{ProductItemId: 4, ProductName: "phone", Quantity: 1, Price: 60000}},
+			cartDiscounts: []helperStruct.ApplicableDiscount{bigSpender},
+			coupon:        response.Coupon{Id: 1, Name: "welcome", Amount: 1000},
+			policy:        pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 60000, CartDiscount: 3000, CouponCode: "welcome", CouponDiscount: 1000, Total: 56000,
+			},
+		},
+		{
+			name:  "cart discounts stack like item discounts",
+			items: []helperStruct.PricingItem{{ProductItemId: 4, ProductName: "phone", Quantity: 1, Price: 60000}},
+			cartDiscounts: []helperStruct.ApplicableDiscount{bigSpender,
+				{Name: "app", Type: "fixed", Amount: 2000, Stackable: true},
+				{Name: "weekend", Type: "fixed", Amount: 1500, Stackable: true}},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 60000, CartDiscount: 3500, Total: 56500,
+			},
+		},
+		{
+			name:   "percentage coupon is capped",
+			items:  []helperStruct.PricingItem{laptop, mouse},
+			coupon: response.Coupon{Id: 3, Name: "tenoff", Type: "percentage", DiscountPercent: 10, MaxDiscount: 2500},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, CouponCode: "tenoff", CouponDiscount: 2500, Total: 43500,
+			},
+		},
+		{
+			name:  "restricted coupon only counts the items it covers",
+			items: []helperStruct.PricingItem{laptop, mouse},
+			coupon: response.Coupon{Id: 4, Name: "mice", Type: "percentage", DiscountPercent: 50,
+				Restrictions: []response.CouponRestriction{{Scope: "item", ScopeId: 2}}},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, CouponCode: "mice", CouponDiscount: 500, Total: 45500,
+			},
+		},
+		{
+			name:  "restricted fixed coupon is capped at the items it covers",
+			items: []helperStruct.PricingItem{laptop, mouse},
+			coupon: response.Coupon{Id: 5, Name: "mouse2k", Amount: 2000,
+				Restrictions: []response.CouponRestriction{{Scope: "item", ScopeId: 2}}},
+			policy: pricingPolicy{},
+			expectedOutput: response.PriceBreakdown{
+				SubTotal: 51000, ItemDiscount: 5000, CouponCode: "mouse2k", CouponDiscount: 1000, Total: 45000,
+			},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := priceCart(tt.items, cartOffers{discounts: tt.cartDiscounts}, tt.coupon, pointsRedemption{}, tt.policy)
+			assert.Equal(t, len(tt.items), len(breakdown.Lines))
+			assert.Equal(t, tt.expectedOutput.SubTotal, breakdown.SubTotal)
+			assert.Equal(t, tt.expectedOutput.ItemDiscount, breakdown.ItemDiscount)
+			assert.Equal(t, tt.expectedOutput.CartDiscount, breakdown.CartDiscount)
+			assert.Equal(t, tt.expectedOutput.CouponCode, breakdown.CouponCode)
+			assert.Equal(t, tt.expectedOutput.CouponDiscount, breakdown.CouponDiscount)
+			assert.Equal(t, tt.expectedOutput.Shipping, breakdown.Shipping)
+			assert.Equal(t, tt.expectedOutput.Tax, breakdown.Tax)
+			assert.Equal(t, tt.expectedOutput.Total, breakdown.Total)
+			// the explanation trail adds up to the total
+			var explained float64
+			for _, step := range breakdown.Explanation {
+				explained += step.Amount
+			}
+			assert.Equal(t, breakdown.Total, explained)
+		})
+	}
+}
+
+func TestShippingFromConfig(t *testing.T) {
+	testData := []struct {
+		name     string
+		cfg      config.Config
+		goods    float64
+		shipping float64
+	}{
+		{name: "no shipping fee configured", goods: 1000, shipping: 0},
+		{name: "below the free shipping threshold", cfg: config.Config{SHIPPINGFEE: 40, FREESHIPPINGABOVE: 2000}, goods: 1000, shipping: 40},
+		{name: "at the free shipping threshold", cfg: config.Config{SHIPPINGFEE: 40, FREESHIPPINGABOVE: 2000}, goods: 2000, shipping: 0},
+		{name: "no threshold charges every order", cfg: config.Config{SHIPPINGFEE: 40}, goods: 5000, shipping: 40},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newPricingPolicy(tt.cfg)
+			assert.Equal(t, float64(defaultTaxPercent), policy.TaxPercent)
+			assert.Equal(t, tt.shipping, policy.shipping(tt.goods))
+		})
+	}
+}
+
+func TestPriceOrderMatchesCart(t *testing.T) {
+	testData := []struct {
+		name          string
+		items         []helperStruct.PricingItem
+		cartDiscounts []helperStruct.ApplicableDiscount
+		coupon        response.Coupon
+		policy        pricingPolicy
+	}{
+		{
+			name: "discounts and coupon",
+			items: []helperStruct.PricingItem{
+				{ProductItemId: 1, ProductName: "laptop", Quantity: 1, Price: 50000, Discounts: []helperStruct.ApplicableDiscount{percentOff("", 10)}},
+				{ProductItemId: 2, ProductName: "mouse", Quantity: 3, Price: 999, Discounts: []helperStruct.ApplicableDiscount{percentOff("", 15)}},
+			},
+			cartDiscounts: []helperStruct.ApplicableDiscount{{Name: "app", Type: "percentage", DiscountPercent: 2, MaxDiscount: 500}},
+			coupon:        response.Coupon{Id: 1, Name: "welcome", Amount: 1000},
+			policy:        pricingPolicy{TaxPercent: defaultTaxPercent},
+		},
+		{
+			name: "sale with shipping",
+			items: []helperStruct.PricingItem{
+				{ProductItemId: 2, ProductName: "mouse", Quantity: 2, Price: 999, SalePrice: 799, SaleRuleId: 4, SaleName: "flash"},
+			},
+			policy: pricingPolicy{TaxPercent: 18, ShippingFee: 99, FreeShippingAbove: 5000},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := priceCart(tt.items, cartOffers{discounts: tt.cartDiscounts}, tt.coupon, pointsRedemption{}, tt.policy)
+			// an order keeps the list and paid price of every line, the cart discount, the coupon and the shipping
+			order := response.ResponseOrder{
+				OrderResponse: response.OrderResponse{
+					CouponCode:   tt.coupon.Name,
+					CartDiscount: int(cart.CartDiscount),
+					CouponAmount: int(cart.CouponDiscount),
+					Shipping:     int(cart.Shipping),
+				},
+			}
+			for _, line := range cart.Lines {
+				order.OrderProducts = append(order.OrderProducts, response.OrderProduct{
+					ProductItemId: line.ProductItemId,
+					ProductName:   line.ProductName,
+					Quantity:      line.Quantity,
+					Price:         int(line.ListPrice),
+					DiscountPrice: line.ListPrice - line.UnitPrice,
+				})
+			}
+			invoice := priceOrder(order, tt.policy)
+			assert.Equal(t, cart.SubTotal, invoice.SubTotal)
+			assert.Equal(t, cart.ItemDiscount, invoice.ItemDiscount)
+			assert.Equal(t, cart.CartDiscount, invoice.CartDiscount)
+			assert.Equal(t, cart.CouponDiscount, invoice.CouponDiscount)
+			assert.Equal(t, cart.Shipping, invoice.Shipping)
+			assert.Equal(t, cart.Tax, invoice.Tax)
+			assert.Equal(t, cart.Total, invoice.Total)
+		})
+	}
+}
+
+func TestCheckCoupon(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	valid := response.Coupon{Id: 1, Name: "welcome", Amount: 500, Quantity: 10, UsageLimitPerUser: 1,
+		ValidFrom: now.AddDate(0, 0, -7), ValidUntil: now.AddDate(0, 0, 7)}
+	dell := helperStruct.PricingItem{ProductItemId: 1, BrandId: 3, CategoryId: 2, ProductName: "laptop", Quantity: 1, Price: 50000}
+	withCoupon := func(change func(*response.Coupon)) response.Coupon {
+		coupon := valid
+		change(&coupon)
+		return coupon
+	}
+	testData := []struct {
+		name           string
+		coupon         response.Coupon
+		usage          helperStruct.CouponUsage
+		expectedOutput string
+	}{
+		{name: "valid coupon", coupon: valid},
+		{name: "disabled", coupon: withCoupon(func(c *response.Coupon) { c.IsDisabled = true }), expectedOutput: "this coupon is disabled"},
+		{
+			name:           "not valid yet",
+			coupon:         withCoupon(func(c *response.Coupon) { c.ValidFrom = now.Add(time.Hour) }),
+			expectedOutput: "this coupon can only be used from 01 Jun 2024 13:00",
+		},
+		{
+			name:           "expired",
+			coupon:         withCoupon(func(c *response.Coupon) { c.ValidUntil = now }),
+			expectedOutput: "this coupon expired on 01 Jun 2024 12:00",
+		},
+		{name: "run out", coupon: withCoupon(func(c *response.Coupon) { c.Quantity = 0 }), expectedOutput: "this coupon has run out"},
+		{name: "used once already", coupon: valid, usage: helperStruct.CouponUsage{TimesUsed: 1}, expectedOutput: "you have already used this coupon"},
+		{
+			name:   "used fewer times than allowed",
+			coupon: withCoupon(func(c *response.Coupon) { c.UsageLimitPerUser = 3 }),
+			usage:  helperStruct.CouponUsage{TimesUsed: 2},
+		},
+		{
+			name:           "used as many times as allowed",
+			coupon:         withCoupon(func(c *response.Coupon) { c.UsageLimitPerUser = 3 }),
+			usage:          helperStruct.CouponUsage{TimesUsed: 3},
+			expectedOutput: "you have already used this coupon the maximum of 3 times",
+		},
+		{
+			name:           "first order only",
+			coupon:         withCoupon(func(c *response.Coupon) { c.FirstOrderOnly = true }),
+			usage:          helperStruct.CouponUsage{PastOrders: 2},
+			expectedOutput: "this coupon is only valid on your first order",
+		},
+		{
+			name:           "below the minimum cart value",
+			coupon:         withCoupon(func(c *response.Coupon) { c.MinCartValue = 60000 }),
+			expectedOutput: "this coupon needs a cart value of at least 60000, add items worth 10000 more",
+		},
+		{
+			name: "restricted to a brand in the cart",
+			coupon: withCoupon(func(c *response.Coupon) {
+				c.Restrictions = []response.CouponRestriction{{Scope: "category", ScopeId: 9}, {Scope: "brand", ScopeId: 3}}
+			}),
+		},
+		{
+			name: "restricted to a category not in the cart",
+			coupon: withCoupon(func(c *response.Coupon) {
+				c.Restrictions = []response.CouponRestriction{{Scope: "category", ScopeId: 9}}
+			}),
+			expectedOutput: "this coupon does not apply to any item in your cart",
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []helperStruct.PricingItem{dell}
+			err := checkCoupon(tt.coupon, tt.usage, items, priceCart(items, cartOffers{}, response.Coupon{}, pointsRedemption{}, pricingPolicy{}), now)
+			if tt.expectedOutput == "" {
+				assert.Equal(t, nil, err)
+				return
+			}
+			assert.NotEqual(t, nil, err)
+			assert.Equal(t, tt.expectedOutput, err.Error())
+		})
+	}
+}
+
+func TestRankCoupons(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	coupon := func(id uint, name string, change func(*response.Coupon)) response.Coupon {
+		c := response.Coupon{Id: id, Name: name, Type: "fixed", Quantity: 10, UsageLimitPerUser: 1,
+			ValidFrom: now.AddDate(0, 0, -7), ValidUntil: now.AddDate(0, 0, 7)}
+		change(&c)
+		return c
+	}
+	dell := helperStruct.PricingItem{ProductItemId: 1, BrandId: 3, CategoryId: 2, ProductName: "laptop", Quantity: 1, Price: 50000}
+	coupons := []response.Coupon{
+		coupon(1, "flat500", func(c *response.Coupon) { c.Amount = 500 }),
+		coupon(2, "tenpercent", func(c *response.Coupon) { c.Type, c.DiscountPercent, c.MaxDiscount = "percentage", 10, 3000 }),
+		coupon(3, "usedup", func(c *response.Coupon) { c.Amount = 5000 }),
+		coupon(4, "bigcart", func(c *response.Coupon) { c.Amount = 8000; c.MinCartValue = 100000 }),
+		coupon(5, "reward500", func(c *response.Coupon) { c.Amount = 500; c.Reward = true; c.ValidUntil = now.AddDate(0, 0, 1) }),
+		coupon(6, "otherbrand", func(c *response.Coupon) {
+			c.Amount = 4000
+			c.Restrictions = []response.CouponRestriction{{Scope: "brand", ScopeId: 7}}
+		}),
+	}
+	usages := make([]helperStruct.CouponUsage, len(coupons))
+	usages[2].TimesUsed = 1
+
+	ranked := rankCoupons(coupons, usages, []helperStruct.PricingItem{dell}, cartOffers{}, pricingPolicy{}, now)
+	var names []string
+	for _, r := range ranked {
+		names = append(names, r.offer().Coupon)
+	}
+	assert.Equal(t, []string{"tenpercent", "reward500", "flat500"}, names)
+	assert.Equal(t, float64(3000), ranked[0].offer().Saving)
+	assert.Equal(t, true, ranked[1].offer().Reward)
+	assert.Equal(t, 0, len(rankCoupons(coupons, usages, nil, cartOffers{}, pricingPolicy{}, now)))
+}
+
+func TestPromotions(t *testing.T) {
+	laptop := helperStruct.PricingItem{ProductItemId: 1, ProductId: 1, CategoryId: 2, ProductName: "laptop", Quantity: 1, Price: 50000}
+	mouse := helperStruct.PricingItem{ProductItemId: 5, ProductId: 4, CategoryId: 7, ProductName: "mouse", Quantity: 1, Price: 500}
+	bag := helperStruct.PricingItem{ProductItemId: 6, ProductId: 5, CategoryId: 7, ProductName: "bag", Quantity: 1, Price: 2000}
+	withQuantity := func(item helperStruct.PricingItem, quantity int) helperStruct.PricingItem {
+		item.Quantity = quantity
+		return item
+	}
+	freeMouse := helperStruct.ApplicablePromotion{Id: 1, Name: "free mouse", Type: "buy_get", TriggerQuantity: 1, RewardQuantity: 1, RewardPercent: 100,
+		Items: []helperStruct.PromotionItem{{Role: "trigger", Scope: "category", ScopeId: 2}, {Role: "reward", Scope: "item", ScopeId: 5}}}
+	halfBag := helperStruct.ApplicablePromotion{Id: 2, Name: "half price bag", Type: "buy_get", TriggerQuantity: 1, RewardQuantity: 1, RewardPercent: 50,
+		Items: []helperStruct.PromotionItem{{Role: "trigger", Scope: "product", ScopeId: 1}, {Role: "reward", Scope: "item", ScopeId: 6}}}
+	twoPlusOne := helperStruct.ApplicablePromotion{Id: 3, Name: "buy 2 get 1", Type: "buy_get", TriggerQuantity: 2, RewardQuantity: 1, RewardPercent: 100,
+		Items: []helperStruct.PromotionItem{{Role: "trigger", Scope: "item", ScopeId: 5}, {Role: "reward", Scope: "item", ScopeId: 5}}}
+	workKit := helperStruct.ApplicablePromotion{Id: 4, Name: "work kit", Type: "bundle", BundlePrice: 50000,
+		Items: []helperStruct.PromotionItem{{Role: "bundle", Scope: "item", ScopeId: 1}, {Role: "bundle", Scope: "item", ScopeId: 6}, {Role: "bundle", Scope: "item", ScopeId: 5}}}
+	testData := []struct {
+		name       string
+		items      []helperStruct.PricingItem
+		promotions []helperStruct.ApplicablePromotion
+		discounts  []float64 //promotion discount of each line
+		total      float64
+	}{
+		{
+			name:       "mouse free with a laptop",
+			items:      []helperStruct.PricingItem{laptop, mouse},
+			promotions: []helperStruct.ApplicablePromotion{freeMouse},
+			discounts:  []float64{0, 500},
+			total:      50000,
+		},
+		{
+			name:       "only one of two mice is free",
+			items:      []helperStruct.PricingItem{laptop, withQuantity(mouse, 2)},
+			promotions: []helperStruct.ApplicablePromotion{freeMouse},
+			discounts:  []float64{0, 500},
+			total:      50500,
+		},
+		{
+			name:       "capped applications",
+			items:      []helperStruct.PricingItem{withQuantity(laptop, 3), withQuantity(mouse, 3)},
+			promotions: []helperStruct.ApplicablePromotion{func() helperStruct.ApplicablePromotion { p := freeMouse; p.MaxApplications = 2; return p }()},
+			discounts:  []float64{0, 1000},
+			total:      150500,
+		},
+		{
+			name:       "reward at a discount",
+			items:      []helperStruct.PricingItem{laptop, bag},
+			promotions: []helperStruct.ApplicablePromotion{halfBag},
+			discounts:  []float64{0, 1000},
+			total:      51000,
+		},
+		{
+			name:       "a laptop only triggers one promotion",
+			items:      []helperStruct.PricingItem{laptop, mouse, bag},
+			promotions: []helperStruct.ApplicablePromotion{freeMouse, halfBag},
+			discounts:  []float64{0, 500, 0},
+			total:      52000,
+		},
+		{
+			name:       "buy 2 get 1 of the same item",
+			items:      []helperStruct.PricingItem{withQuantity(mouse, 3)},
+			promotions: []helperStruct.ApplicablePromotion{twoPlusOne},
+			discounts:  []float64{500},
+			total:      1000,
+		},
+		{
+			name:       "buy 2 get 1 needs three units",
+			items:      []helperStruct.PricingItem{withQuantity(mouse, 2)},
+			promotions: []helperStruct.ApplicablePromotion{twoPlusOne},
+			discounts:  []float64{0},
+			total:      1000,
+		},
+		{
+			name:       "bundle saving shared by price",
+			items:      []helperStruct.PricingItem{laptop, bag, mouse},
+			promotions: []helperStruct.ApplicablePromotion{workKit},
+			discounts:  []float64{2381, 95, 24},
+			total:      50000,
+		},
+		{
+			name:       "bundle needs every item",
+			items:      []helperStruct.PricingItem{laptop, mouse},
+			promotions: []helperStruct.ApplicablePromotion{workKit},
+			discounts:  []float64{0, 0},
+			total:      50500,
+		},
+		{
+			name:       "bundle applies once per set",
+			items:      []helperStruct.PricingItem{withQuantity(laptop, 2), bag, withQuantity(mouse, 2)},
+			promotions: []helperStruct.ApplicablePromotion{workKit},
+			discounts:  []float64{2381, 95, 24},
+			total:      100500,
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := priceCart(tt.items, cartOffers{promotions: tt.promotions}, response.Coupon{}, pointsRedemption{}, pricingPolicy{})
+			for i, line := range breakdown.Lines {
+				assert.Equal(t, tt.discounts[i], line.PromotionDiscount)
+				assert.Equal(t, line.PromotionDiscount, line.Discount)
+			}
+			assert.Equal(t, tt.total, breakdown.Total)
+		})
+	}
+}
+
+func TestFreeItemChanges(t *testing.T) {
+	laptop := helperStruct.PricingItem{ProductItemId: 1, CategoryId: 2, ProductName: "laptop", Quantity: 1, Price: 50000}
+	freeMouse := helperStruct.ApplicablePromotion{Id: 1, Name: "free mouse", Type: "buy_get", TriggerQuantity: 1, RewardQuantity: 1, RewardPercent: 100,
+		Items: []helperStruct.PromotionItem{{Role: "trigger", Scope: "category", ScopeId: 2}, {Role: "reward", Scope: "item", ScopeId: 5}}}
+	freeMouseItem := helperStruct.PricingItem{ProductItemId: 5, CategoryId: 7, ProductName: "mouse", Quantity: 1, FreeQuantity: 1, Price: 500}
+	testData := []struct {
+		name     string
+		items    []helperStruct.PricingItem
+		expected map[uint]int
+	}{
+		{name: "adds the free mouse", items: []helperStruct.PricingItem{laptop}, expected: map[uint]int{5: 1}},
+		{name: "nothing to change", items: []helperStruct.PricingItem{laptop, freeMouseItem}, expected: map[uint]int{}},
+		{name: "takes the free mouse out with the laptop", items: []helperStruct.PricingItem{freeMouseItem}, expected: map[uint]int{5: 0}},
+		{
+			name:     "a mouse the customer added stays paid for",
+			items:    []helperStruct.PricingItem{laptop, {ProductItemId: 5, CategoryId: 7, ProductName: "mouse", Quantity: 1, Price: 500}},
+			expected: map[uint]int{5: 1},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, freeItemChanges(tt.items, []helperStruct.ApplicablePromotion{freeMouse}))
+		})
+	}
+}
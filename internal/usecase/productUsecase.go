@@ -1,20 +1,38 @@
 package usecase
 
 import (
+	"fmt"
+	"log"
+
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
 )
 
 type ProductUsecase struct {
 	productRepo interfaces.ProductRepository
+	storage     storage.Storage
 }
 
-func NewProductUsecase(productRepo interfaces.ProductRepository) services.ProductUsecase {
+func NewProductUsecase(productRepo interfaces.ProductRepository, storage storage.Storage) services.ProductUsecase {
 	return &ProductUsecase{
 		productRepo: productRepo,
+		storage:     storage,
+	}
+}
+
+// imageURL turns a stored image key into a URL the client can load.
+func (cr *ProductUsecase) imageURL(key string) string {
+	if key == "" {
+		return ""
 	}
+	url, err := cr.storage.URL(key)
+	if err != nil {
+		return ""
+	}
+	return url
 }
 
 // CreateCategory implements interfaces.ProductUsecase.
@@ -29,6 +47,17 @@ func (cr *ProductUsecase) UpdateCategory(category helperStruct.Category, id int)
 	return updatedCategory, err
 }
 
+// MoveCategory implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) MoveCategory(move helperStruct.MoveCategory, id int) (response.Category, error) {
+	movedCategory, err := cr.productRepo.MoveCategory(move, id)
+	if err != nil {
+		return movedCategory, err
+	}
+	categories, err := cr.categoryIndex()
+	movedCategory.Breadcrumbs = breadcrumbs(categories, movedCategory.Id)
+	return movedCategory, err
+}
+
 // DeleteCategory implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) DeleteCategory(id int) error {
 	err := cr.productRepo.DeleteCategory(id)
@@ -38,13 +67,32 @@ func (cr *ProductUsecase) DeleteCategory(id int) error {
 // ListAllCategories implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) ListAllCategories() ([]response.Category, error) {
 	categories, err := cr.productRepo.ListAllCategories()
-	return categories, err
+	return categoryTree(categories), err
 }
 
 // DisplayCategory implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) DisplayCategory(id int) (response.Category, error) {
 	category, err := cr.productRepo.DisplayCategory(id)
-	return category, err
+	if err != nil {
+		return category, err
+	}
+	all, err := cr.productRepo.ListAllCategories()
+	if err != nil {
+		return category, err
+	}
+	index := indexCategories(all)
+	category.Breadcrumbs = breadcrumbs(index, category.Id)
+	category.CanonicalUrl = canonicalURL("category", category.Slug)
+	for _, child := range all {
+		if child.ParentId == category.Id {
+			category.Children = append(category.Children, child)
+		}
+	}
+	for i := range category.Products {
+		category.Products[i].Breadcrumbs = breadcrumbs(index, category.Products[i].CategoryId)
+		category.Products[i].CanonicalUrl = canonicalURL("product", category.Products[i].Slug)
+	}
+	return category, nil
 }
 
 // CreateBrand implements interfaces.ProductUsecase.
@@ -68,12 +116,16 @@ func (cr *ProductUsecase) DeleteBrand(id int) error {
 // ListAllBrands implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) ListAllBrands(queryParams helperStruct.QueryParams) ([]response.Brand, int, error) {
 	allBrands, totalCount, err := cr.productRepo.ListAllBrands(queryParams)
+	for i := range allBrands {
+		allBrands[i].CanonicalUrl = canonicalURL("brand", allBrands[i].Slug)
+	}
 	return allBrands, totalCount, err
 }
 
 // DisplayBrand implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) DisplayBrand(id int) (response.Brand, error) {
 	brand, err := cr.productRepo.DisplayBrand(id)
+	brand.CanonicalUrl = canonicalURL("brand", brand.Slug)
 	return brand, err
 }
 
@@ -98,12 +150,26 @@ func (cr *ProductUsecase) DeletProduct(id int) error {
 // ListAllProducts implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) ListAllProducts(queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
 	products, totalCount, err := cr.productRepo.ListAllProducts(queryParams)
+	if err != nil {
+		return products, totalCount, err
+	}
+	categories, err := cr.categoryIndex()
+	for i := range products {
+		products[i].Breadcrumbs = breadcrumbs(categories, products[i].CategoryId)
+		products[i].CanonicalUrl = canonicalURL("product", products[i].Slug)
+	}
 	return products, totalCount, err
 }
 
 // DisplayProduct implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) DisplayProduct(id int) (response.Product, error) {
 	product, err := cr.productRepo.DisplayProduct(id)
+	if err != nil {
+		return product, err
+	}
+	categories, err := cr.categoryIndex()
+	product.Breadcrumbs = breadcrumbs(categories, product.CategoryId)
+	product.CanonicalUrl = canonicalURL("product", product.Slug)
 	return product, err
 }
 
@@ -122,6 +188,15 @@ func (cr *ProductUsecase) UpdateProductItem(id int, productItem helperStruct.Pro
 // ListAllProductItems implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) ListAllProductItems(queryParams helperStruct.QueryParams) ([]response.ProductItem, int, error) {
 	productItems, totalCount, err := cr.productRepo.ListAllProductItems(queryParams)
+	if err != nil {
+		return productItems, totalCount, err
+	}
+	categories, err := cr.categoryIndex()
+	for i := range productItems {
+		productItems[i].Image = cr.imageURL(productItems[i].Image)
+		productItems[i].Breadcrumbs = breadcrumbs(categories, productItems[i].CategoryId)
+		productItems[i].CanonicalUrl = canonicalURL("product_item", productItems[i].Slug)
+	}
 	return productItems, totalCount, err
 }
 
@@ -135,37 +210,180 @@ func (cr *ProductUsecase) DeleteProductItem(id int) error {
 func (cr *ProductUsecase) DisplayProductItem(id int) (response.DisplayProductItem, error) {
 
 	productItem, err := cr.productRepo.DisplayProductItem(id)
+	if err != nil {
+		return productItem, err
+	}
+	productItem.ProductSpecs.Image = cr.imageURL(productItem.ProductSpecs.Image)
+	for i := range productItem.Images {
+		productItem.Images[i].Image = cr.imageURL(productItem.Images[i].Image)
+	}
+	categories, err := cr.categoryIndex()
+	productItem.ProductSpecs.Breadcrumbs = breadcrumbs(categories, productItem.ProductSpecs.CategoryId)
+	productItem.ProductSpecs.CanonicalUrl = canonicalURL("product_item", productItem.ProductSpecs.Slug)
 	return productItem, err
 }
 
-// // ImageUpload implements interfaces.ProductUsecase.
-// func (cr *ProductUsecase) ImageUpload(image helperStruct.ImageHelper) (response.ImageResponse, error) {
-
-// 	// Read the file content
-// 	fileBytes, err := io.ReadAll(image.ImageFile)
-// 	if err != nil {
-// 		return response.ImageResponse{}, err
-// 	}
-// 	image.ImageData = fileBytes
-// 	newImage, err := cr.productRepo.UploadImage(image)
-// 	return newImage, err
-
-// }
 // -------------------------- Upload-Image --------------------------//
 
-func (c *ProductUsecase) UploadImage(filepath string, productId int) (response.Image, error) {
-	image, err := c.productRepo.UploadImage(filepath, productId)
-	return image, err
+// UploadImage implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) UploadImage(image helperStruct.ImageHelper) (response.Image, error) {
+	key := storage.ImageKey(int(image.ProductItemId), image.FileName)
+	err := cr.storage.Upload(key, image.ImageFile, image.ImageSize, image.ImageType)
+	if err != nil {
+		return response.Image{}, fmt.Errorf("error uploading image: %v", err)
+	}
+	newImage, err := cr.productRepo.UploadImage(key, int(image.ProductItemId))
+	if err != nil {
+		// do not leave an orphaned object behind when the row could not be written
+		cr.storage.Delete(key)
+		return response.Image{}, err
+	}
+	newImage.Image = cr.imageURL(newImage.Image)
+	return newImage, nil
 }
 
 // DeleteImage implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) DeleteImage(id int) error {
-	err := cr.productRepo.DeleteImage(id)
-	return err
+	key, err := cr.productRepo.DeleteImage(id)
+	if err != nil {
+		return err
+	}
+	// the image is already gone from the catalogue, a leftover object is only
+	// wasted space so it doesn't fail the request
+	if err := cr.storage.Delete(key); err != nil {
+		log.Printf("deleting image object %s: %v", key, err)
+	}
+	return nil
 }
 
 // SearchProducts implements interfaces.ProductUsecase.
 func (cr *ProductUsecase) SearchProducts(queryParams helperStruct.QueryParams, searchProducts string) ([]response.Product, error) {
 	products, err := cr.productRepo.SearchProducts(queryParams, searchProducts)
+	if err != nil {
+		return products, err
+	}
+	categories, err := cr.categoryIndex()
+	for i := range products {
+		products[i].Breadcrumbs = breadcrumbs(categories, products[i].CategoryId)
+		products[i].CanonicalUrl = canonicalURL("product", products[i].Slug)
+	}
 	return products, err
 }
+
+// ResolveSlug implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) ResolveSlug(entity, slug string) (int, string, error) {
+	return cr.productRepo.ResolveSlug(entity, slug)
+}
+
+// recentlyViewedSize is how many items the recently viewed endpoint returns.
+const recentlyViewedSize = 20
+
+// RecordView implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) RecordView(userId, productItemId int) error {
+	return cr.productRepo.RecordView(userId, productItemId)
+}
+
+// RecentlyViewed implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) RecentlyViewed(userId int) ([]response.ProductItem, error) {
+	productItems, err := cr.productRepo.RecentlyViewed(userId, recentlyViewedSize)
+	return resolveItems(cr.storage, productItems), err
+}
+
+// PersonalizedProducts implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) PersonalizedProducts(userId int, queryParams helperStruct.QueryParams) ([]response.Product, int, error) {
+	products, totalCount, err := cr.productRepo.PersonalizedProducts(userId, queryParams)
+	if err != nil {
+		return products, totalCount, err
+	}
+	categories, err := cr.categoryIndex()
+	for i := range products {
+		products[i].Breadcrumbs = breadcrumbs(categories, products[i].CategoryId)
+		products[i].CanonicalUrl = canonicalURL("product", products[i].Slug)
+	}
+	return products, totalCount, err
+}
+
+// PriceHistory implements interfaces.ProductUsecase.
+func (cr *ProductUsecase) PriceHistory(productItemId int, days int) (response.PriceHistory, error) {
+	priceHistory := response.PriceHistory{ProductItemId: productItemId}
+	if days <= 0 {
+		days = 90
+	}
+	history, err := cr.productRepo.PriceHistory(productItemId, days)
+	if err != nil {
+		return priceHistory, err
+	}
+	priceHistory.History = history
+	for i, point := range history {
+		if i == 0 || point.EffectivePrice < priceHistory.LowestPrice {
+			priceHistory.LowestPrice = point.EffectivePrice
+		}
+		if point.EffectivePrice > priceHistory.HighestPrice {
+			priceHistory.HighestPrice = point.EffectivePrice
+		}
+		priceHistory.CurrentPrice = point.EffectivePrice
+	}
+	return priceHistory, nil
+}
+
+// canonicalURL is the storefront path an entity is served under.
+func canonicalURL(entity, slug string) string {
+	if slug == "" {
+		return ""
+	}
+	switch entity {
+	case "category":
+		return "/home/categories/" + slug
+	case "brand":
+		return "/home/brands/" + slug
+	case "product":
+		return "/home/products/" + slug
+	default:
+		return "/home/" + slug
+	}
+}
+
+// categoryIndex loads every category keyed by id for breadcrumb lookups.
+func (cr *ProductUsecase) categoryIndex() (map[int]response.Category, error) {
+	categories, err := cr.productRepo.ListAllCategories()
+	return indexCategories(categories), err
+}
+
+func indexCategories(categories []response.Category) map[int]response.Category {
+	index := make(map[int]response.Category, len(categories))
+	for _, category := range categories {
+		index[category.Id] = category
+	}
+	return index
+}
+
+// breadcrumbs returns the path from the top level category down to the given one.
+func breadcrumbs(categories map[int]response.Category, id int) []response.Breadcrumb {
+	var path []response.Breadcrumb
+	for id != 0 && len(path) <= len(categories) {
+		category, ok := categories[id]
+		if !ok {
+			break
+		}
+		path = append([]response.Breadcrumb{{Id: category.Id, Name: category.CategoryName}}, path...)
+		id = category.ParentId
+	}
+	return path
+}
+
+// categoryTree nests a flat, already ordered category list under its parents.
+func categoryTree(categories []response.Category) []response.Category {
+	children := make(map[int][]response.Category)
+	for _, category := range categories {
+		children[category.ParentId] = append(children[category.ParentId], category)
+	}
+	var build func(parentId int) []response.Category
+	build = func(parentId int) []response.Category {
+		nodes := children[parentId]
+		for i := range nodes {
+			nodes[i].Children = build(nodes[i].Id)
+		}
+		return nodes
+	}
+	return build(0)
+}
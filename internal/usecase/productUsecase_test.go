@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+// a small catalog: electronics > phones > android, electronics > laptops, books
+var testCategories = []response.Category{
+	{Id: 1, CategoryName: "electronics"},
+	{Id: 2, CategoryName: "phones", ParentId: 1},
+	{Id: 3, CategoryName: "android", ParentId: 2},
+	{Id: 4, CategoryName: "laptops", ParentId: 1},
+	{Id: 5, CategoryName: "books"},
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	index := indexCategories(testCategories)
+	testData := []struct {
+		name           string
+		categories     map[int]response.Category
+		id             int
+		expectedOutput []response.Breadcrumb
+	}{
+		{name: "top level category", categories: index, id: 5, expectedOutput: []response.Breadcrumb{{Id: 5, Name: "books"}}},
+		{
+			name:       "nested category",
+			categories: index,
+			id:         3,
+			expectedOutput: []response.Breadcrumb{
+				{Id: 1, Name: "electronics"},
+				{Id: 2, Name: "phones"},
+				{Id: 3, Name: "android"},
+			},
+		},
+		{name: "no category", categories: index, id: 0, expectedOutput: nil},
+		{name: "unknown category", categories: index, id: 42, expectedOutput: nil},
+		{
+			name: "parent missing from the index",
+			categories: indexCategories([]response.Category{
+				{Id: 2, CategoryName: "phones", ParentId: 1},
+				{Id: 3, CategoryName: "android", ParentId: 2},
+			}),
+			id:             3,
+			expectedOutput: []response.Breadcrumb{{Id: 2, Name: "phones"}, {Id: 3, Name: "android"}},
+		},
+		{
+			name: "a cycle doesn't loop forever",
+			categories: indexCategories([]response.Category{
+				{Id: 1, CategoryName: "a", ParentId: 2},
+				{Id: 2, CategoryName: "b", ParentId: 1},
+			}),
+			id: 1,
+			expectedOutput: []response.Breadcrumb{
+				{Id: 1, Name: "a"},
+				{Id: 2, Name: "b"},
+				{Id: 1, Name: "a"},
+			},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, breadcrumbs(tt.categories, tt.id))
+		})
+	}
+}
+
+func TestCategoryTree(t *testing.T) {
+	tree := categoryTree(testCategories)
+	assert.Equal(t, 2, len(tree))
+	assert.Equal(t, "electronics", tree[0].CategoryName)
+	assert.Equal(t, "books", tree[1].CategoryName)
+	assert.Equal(t, 0, len(tree[1].Children))
+
+	electronics := tree[0].Children
+	assert.Equal(t, 2, len(electronics))
+	assert.Equal(t, "phones", electronics[0].CategoryName)
+	assert.Equal(t, "laptops", electronics[1].CategoryName)
+	assert.Equal(t, 1, len(electronics[0].Children))
+	assert.Equal(t, "android", electronics[0].Children[0].CategoryName)
+
+	assert.Equal(t, 0, len(categoryTree(nil)))
+}
+
+func TestPriceHistory(t *testing.T) {
+	testData := []struct {
+		name           string
+		days           int
+		expectedDays   int
+		history        []response.PricePoint
+		expectedOutput response.PriceHistory
+	}{
+		{
+			name:         "price went down and back up",
+			days:         30,
+			expectedDays: 30,
+			history: []response.PricePoint{
+				{Price: 1000, EffectivePrice: 1000},
+				{Price: 1000, DiscountPercent: 20, EffectivePrice: 800},
+				{Price: 1100, EffectivePrice: 1100},
+				{Price: 1100, DiscountPercent: 10, EffectivePrice: 990},
+			},
+			expectedOutput: response.PriceHistory{ProductItemId: 3, CurrentPrice: 990, LowestPrice: 800, HighestPrice: 1100},
+		},
+		{
+			name:           "no history in the window",
+			days:           0,
+			expectedDays:   90,
+			expectedOutput: response.PriceHistory{ProductItemId: 3},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			productRepo := mock_interfaces.NewMockProductRepository(ctrl)
+			productRepo.EXPECT().PriceHistory(3, tt.expectedDays).Times(1).Return(tt.history, nil)
+			productUsecase := NewProductUsecase(productRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+			priceHistory, err := productUsecase.PriceHistory(3, tt.days)
+			assert.Equal(t, nil, err)
+			tt.expectedOutput.History = tt.history
+			assert.Equal(t, tt.expectedOutput, priceHistory)
+		})
+	}
+}
+
+// failingStorage is a store whose deletes always fail.
+type failingStorage struct {
+	storage.Storage
+}
+
+func (failingStorage) Delete(key string) error {
+	return fmt.Errorf("storage unavailable")
+}
+
+func TestDeleteImage(t *testing.T) {
+	testData := []struct {
+		name        string
+		storage     storage.Storage
+		buildStub   func(productRepo mock_interfaces.MockProductRepository)
+		expectedErr error
+	}{
+		{
+			name:    "row and object deleted",
+			storage: storage.NewLocalStorage(t.TempDir(), "/uploads"),
+			buildStub: func(productRepo mock_interfaces.MockProductRepository) {
+				productRepo.EXPECT().DeleteImage(4).Times(1).Return("products/4.jpg", nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "storage failure after the row is deleted",
+			storage: failingStorage{},
+			buildStub: func(productRepo mock_interfaces.MockProductRepository) {
+				productRepo.EXPECT().DeleteImage(4).Times(1).Return("products/4.jpg", nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:    "row not deleted",
+			storage: failingStorage{},
+			buildStub: func(productRepo mock_interfaces.MockProductRepository) {
+				productRepo.EXPECT().DeleteImage(4).Times(1).Return("", fmt.Errorf("image not found"))
+			},
+			expectedErr: fmt.Errorf("image not found"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			productRepo := mock_interfaces.NewMockProductRepository(ctrl)
+			tt.buildStub(*productRepo)
+			productUsecase := NewProductUsecase(productRepo, tt.storage)
+			err := productUsecase.DeleteImage(4)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
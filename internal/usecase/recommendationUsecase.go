@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"fmt"
+
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	"main.go/internal/repository/interfaces"
+	services "main.go/internal/usecase/interface"
+)
+
+type RecommendationUsecase struct {
+	recommendationRepo interfaces.RecommendationRepository
+	storage            storage.Storage
+}
+
+func NewRecommendationUsecase(recommendationRepo interfaces.RecommendationRepository, storage storage.Storage) services.RecommendationUsecase {
+	return &RecommendationUsecase{
+		recommendationRepo: recommendationRepo,
+		storage:            storage,
+	}
+}
+
+// recommendationLimit is how many items each recommendation list shows.
+const recommendationLimit = 6
+
+func (r *RecommendationUsecase) recommendations(productItemIds []int) (response.Recommendations, error) {
+	var recommendations response.Recommendations
+	boughtTogether, err := r.recommendationRepo.Recommendations(productItemIds, "bought_together", recommendationLimit)
+	if err != nil {
+		return recommendations, err
+	}
+	similar, err := r.recommendationRepo.Recommendations(productItemIds, "similar", recommendationLimit)
+	if err != nil {
+		return recommendations, err
+	}
+	recommendations.FrequentlyBoughtTogether = resolveItems(r.storage, boughtTogether)
+	recommendations.SimilarProducts = resolveItems(r.storage, similar)
+	return recommendations, nil
+}
+
+// RefreshRecommendations implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) RefreshRecommendations() error {
+	err := r.recommendationRepo.RefreshRecommendations()
+	return err
+}
+
+// ProductItemRecommendations implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) ProductItemRecommendations(productItemId int) (response.Recommendations, error) {
+	return r.recommendations([]int{productItemId})
+}
+
+// CartRecommendations implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) CartRecommendations(userId int) (response.Recommendations, error) {
+	productItemIds, err := r.recommendationRepo.CartProductItems(userId)
+	if err != nil {
+		return response.Recommendations{}, err
+	}
+	return r.recommendations(productItemIds)
+}
+
+// AddOverride implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) AddOverride(override helperStruct.RecommendationOverride) (response.RecommendationOverride, error) {
+	if override.Product_item_id == 0 || override.Recommended_item_id == 0 {
+		return response.RecommendationOverride{}, fmt.Errorf("product item and recommended item are required")
+	}
+	if override.Kind != "bought_together" && override.Kind != "similar" {
+		return response.RecommendationOverride{}, fmt.Errorf("kind must be bought_together or similar")
+	}
+	if override.Action != "pin" && override.Action != "exclude" {
+		return response.RecommendationOverride{}, fmt.Errorf("action must be pin or exclude")
+	}
+	newOverride, err := r.recommendationRepo.AddOverride(override)
+	return newOverride, err
+}
+
+// DeleteOverride implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) DeleteOverride(id int) error {
+	err := r.recommendationRepo.DeleteOverride(id)
+	return err
+}
+
+// ListOverrides implements interfaces.RecommendationUsecase.
+func (r *RecommendationUsecase) ListOverrides(productItemId int) ([]response.RecommendationOverride, error) {
+	overrides, err := r.recommendationRepo.ListOverrides(productItemId)
+	return overrides, err
+}
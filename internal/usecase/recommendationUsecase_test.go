@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/storage"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestAddOverride(t *testing.T) {
+	testData := []struct {
+		name          string
+		input         helperStruct.RecommendationOverride
+		buildStub     func(recommendationRepo mock_interfaces.MockRecommendationRepository)
+		expectedError error
+	}{
+		{
+			name:  "pin",
+			input: helperStruct.RecommendationOverride{Product_item_id: 1, Recommended_item_id: 2, Kind: "bought_together", Action: "pin"},
+			buildStub: func(recommendationRepo mock_interfaces.MockRecommendationRepository) {
+				recommendationRepo.EXPECT().AddOverride(gomock.Any()).Times(1).Return(response.RecommendationOverride{Id: 1}, nil)
+			},
+		},
+		{
+			name:          "no recommended item",
+			input:         helperStruct.RecommendationOverride{Product_item_id: 1, Kind: "similar", Action: "pin"},
+			buildStub:     func(recommendationRepo mock_interfaces.MockRecommendationRepository) {},
+			expectedError: errors.New("product item and recommended item are required"),
+		},
+		{
+			name:          "unknown kind",
+			input:         helperStruct.RecommendationOverride{Product_item_id: 1, Recommended_item_id: 2, Kind: "upsell", Action: "pin"},
+			buildStub:     func(recommendationRepo mock_interfaces.MockRecommendationRepository) {},
+			expectedError: errors.New("kind must be bought_together or similar"),
+		},
+		{
+			name:          "unknown action",
+			input:         helperStruct.RecommendationOverride{Product_item_id: 1, Recommended_item_id: 2, Kind: "similar", Action: "boost"},
+			buildStub:     func(recommendationRepo mock_interfaces.MockRecommendationRepository) {},
+			expectedError: errors.New("action must be pin or exclude"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			recommendationRepo := mock_interfaces.NewMockRecommendationRepository(ctrl)
+			tt.buildStub(*recommendationRepo)
+			recommendationUsecase := NewRecommendationUsecase(recommendationRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+			_, err := recommendationUsecase.AddOverride(tt.input)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestCartRecommendations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	recommendationRepo := mock_interfaces.NewMockRecommendationRepository(ctrl)
+	recommendationUsecase := NewRecommendationUsecase(recommendationRepo, storage.NewLocalStorage(storage.LocalUploadDir, "/uploads"))
+
+	recommendationRepo.EXPECT().CartProductItems(7).Times(1).Return([]int{1, 2}, nil)
+	recommendationRepo.EXPECT().Recommendations([]int{1, 2}, "bought_together", recommendationLimit).Times(1).
+		Return([]response.ProductItem{{Id: 3, Slug: "phone-case", Image: "products/3/1-case.jpg"}}, nil)
+	recommendationRepo.EXPECT().Recommendations([]int{1, 2}, "similar", recommendationLimit).Times(1).
+		Return([]response.ProductItem{}, nil)
+
+	recommendations, err := recommendationUsecase.CartRecommendations(7)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(recommendations.FrequentlyBoughtTogether))
+	assert.Equal(t, "/uploads/products/3/1-case.jpg", recommendations.FrequentlyBoughtTogether[0].Image)
+	assert.Equal(t, "/home/phone-case", recommendations.FrequentlyBoughtTogether[0].CanonicalUrl)
+	assert.Equal(t, 0, len(recommendations.SimilarProducts))
+}
@@ -2,30 +2,138 @@ package usecase
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
 )
 
 type ReferralUseCase struct {
 	referralRepo interfaces.ReferralRepository
+	cfg          config.Config
 }
 
-func NewReferralUsecase(referralRepo interfaces.ReferralRepository) services.ReferralUseCase {
+func NewReferralUsecase(referralRepo interfaces.ReferralRepository, cfg config.Config) services.ReferralUseCase {
 	return &ReferralUseCase{
 		referralRepo: referralRepo,
+		cfg:          cfg,
 	}
 }
 
+const referralCodeLength = 8
+
+// normalizeReferralCode lets users type codes in any case, codes are stored
+// in upper case.
+func normalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// newReferralCode draws random codes until one isn't taken.
+func newReferralCode(referralRepo interfaces.ReferralRepository) (string, error) {
+	seen := map[string]bool{}
+	for attempt := 0; attempt < 5; attempt++ {
+		codes, err := generateCodes("", referralCodeLength, 1, seen)
+		if err != nil {
+			return "", err
+		}
+		taken, err := referralRepo.ReferralCodeExists(codes[0])
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return codes[0], nil
+		}
+	}
+	return "", fmt.Errorf("couldn't generate a unique referral code, please try again")
+}
+
+// referralLink is the signup link that fills in the given code.
+func referralLink(cfg config.Config, code string) string {
+	signupURL := cfg.SIGNUPURL
+	if signupURL == "" {
+		signupURL = "/user/signup"
+	}
+	return signupURL + "?ref=" + url.QueryEscape(code)
+}
+
 // AddReferral implements interfaces.ReferralUseCase.
-func (r *ReferralUseCase) AddReferral(userId int, userMobile string) error {
-	referralCode := fmt.Sprintf("pc%s/%d4u", userMobile, userId)
-	err := r.referralRepo.AddReferral(userId, string(referralCode))
+func (r *ReferralUseCase) AddReferral(userId int) error {
+	referralCode, err := newReferralCode(r.referralRepo)
+	if err != nil {
+		return err
+	}
+	err = r.referralRepo.AddReferral(userId, referralCode)
 	return err
 }
 
+// CheckReferralCode implements interfaces.ReferralUseCase.
+func (r *ReferralUseCase) CheckReferralCode(referralId string) error {
+	settings, err := r.referralRepo.ReferralSettings()
+	if err != nil {
+		return err
+	}
+	if !settings.IsActive {
+		return fmt.Errorf("the referral program isn't running right now")
+	}
+	exists, err := r.referralRepo.ReferralCodeExists(normalizeReferralCode(referralId))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("invalid referral code")
+	}
+	return nil
+}
+
+// RegenerateReferralCode implements interfaces.ReferralUseCase.
+func (r *ReferralUseCase) RegenerateReferralCode(userId int) (response.ReferralCode, error) {
+	referralCode, err := newReferralCode(r.referralRepo)
+	if err != nil {
+		return response.ReferralCode{}, err
+	}
+	err = r.referralRepo.UpdateReferralCode(userId, referralCode)
+	if err != nil {
+		return response.ReferralCode{}, err
+	}
+	return response.ReferralCode{Code: referralCode, Link: referralLink(r.cfg, referralCode)}, nil
+}
+
 // ReferralOffer implements interfaces.ReferralUseCase.
-func (r *ReferralUseCase) ReferralOffer(userId int, referralId string) error {
-	err := r.referralRepo.ReferralOffer(referralId, userId)
-	return err
+func (r *ReferralUseCase) ReferralOffer(userId int, referralId string) (int, error) {
+	reward, err := r.referralRepo.ReferralOffer(normalizeReferralCode(referralId), userId)
+	return reward, err
+}
+
+// ReferralSettings implements interfaces.ReferralUseCase.
+func (r *ReferralUseCase) ReferralSettings() (response.ReferralSettings, error) {
+	settings, err := r.referralRepo.ReferralSettings()
+	return settings, err
+}
+
+// UpdateReferralSettings implements interfaces.ReferralUseCase.
+func (r *ReferralUseCase) UpdateReferralSettings(settings helperStruct.ReferralSettings) (response.ReferralSettings, error) {
+	if settings.RefereeReward < 0 || settings.ReferrerReward < 0 {
+		return response.ReferralSettings{}, fmt.Errorf("rewards cannot be negative")
+	}
+	if settings.MaxRewardsPerReferrer < 0 {
+		return response.ReferralSettings{}, fmt.Errorf("max rewards per referrer cannot be negative")
+	}
+	updatedSettings, err := r.referralRepo.UpdateReferralSettings(settings)
+	return updatedSettings, err
+}
+
+// ReferralDashboard implements interfaces.ReferralUseCase.
+func (r *ReferralUseCase) ReferralDashboard(userId int) (response.ReferralDashboard, error) {
+	dashboard, err := r.referralRepo.ReferralDashboard(userId)
+	if err != nil {
+		return response.ReferralDashboard{}, err
+	}
+	if dashboard.ReferralCode != "" {
+		dashboard.ReferralLink = referralLink(r.cfg, dashboard.ReferralCode)
+	}
+	return dashboard, nil
 }
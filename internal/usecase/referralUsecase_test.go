@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestNormalizeReferralCode(t *testing.T) {
+	testData := []struct {
+		name           string
+		input          string
+		expectedOutput string
+	}{
+		{name: "stored code", input: "AB23CD45", expectedOutput: "AB23CD45"},
+		{name: "typed in lower case", input: "ab23cd45", expectedOutput: "AB23CD45"},
+		{name: "pasted with spaces", input: "  Ab23cD45\n", expectedOutput: "AB23CD45"},
+		{name: "empty", input: "", expectedOutput: ""},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, normalizeReferralCode(tt.input))
+		})
+	}
+}
+
+func TestNewReferralCode(t *testing.T) {
+	testData := []struct {
+		name          string
+		buildStub     func(referralRepo mock_interfaces.MockReferralRepository)
+		expectedError error
+	}{
+		{
+			name: "first code is free",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(1).Return(false, nil)
+			},
+		},
+		{
+			name: "retries after a collision",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				gomock.InOrder(
+					referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(2).Return(true, nil),
+					referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(1).Return(false, nil),
+				)
+			},
+		},
+		{
+			name: "every attempt collides",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(5).Return(true, nil)
+			},
+			expectedError: errors.New("couldn't generate a unique referral code, please try again"),
+		},
+		{
+			name: "repository error",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(1).Return(false, errors.New("connection refused"))
+			},
+			expectedError: errors.New("connection refused"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			referralRepo := mock_interfaces.NewMockReferralRepository(ctrl)
+			tt.buildStub(*referralRepo)
+			code, err := newReferralCode(referralRepo)
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, referralCodeLength, len(code))
+				assert.Equal(t, code, normalizeReferralCode(code))
+			}
+		})
+	}
+}
+
+func TestReferralLink(t *testing.T) {
+	testData := []struct {
+		name           string
+		cfg            config.Config
+		code           string
+		expectedOutput string
+	}{
+		{name: "no signup url configured", code: "AB23CD45", expectedOutput: "/user/signup?ref=AB23CD45"},
+		{name: "configured signup url", cfg: config.Config{SIGNUPURL: "https://shop.example.com/signup"}, code: "AB23CD45", expectedOutput: "https://shop.example.com/signup?ref=AB23CD45"},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, referralLink(tt.cfg, tt.code))
+		})
+	}
+}
+
+func TestSignupReferralCode(t *testing.T) {
+	active := response.ReferralSettings{IsActive: true}
+	testData := []struct {
+		name          string
+		code          string
+		buildStub     func(referralRepo mock_interfaces.MockReferralRepository)
+		expectedError error
+	}{
+		{
+			name: "code typed in lower case",
+			code: " ab23cd45 ",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralSettings().Times(1).Return(active, nil)
+				referralRepo.EXPECT().ReferralCodeExists("AB23CD45").Times(1).Return(true, nil)
+				referralRepo.EXPECT().ReferralOffer("AB23CD45", 7).Times(1).Return(20, nil)
+			},
+		},
+		{
+			name: "unknown code",
+			code: "ZZZZZZZZ",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralSettings().Times(1).Return(active, nil)
+				referralRepo.EXPECT().ReferralCodeExists("ZZZZZZZZ").Times(1).Return(false, nil)
+			},
+			expectedError: errors.New("invalid referral code"),
+		},
+		{
+			name: "program switched off",
+			code: "AB23CD45",
+			buildStub: func(referralRepo mock_interfaces.MockReferralRepository) {
+				referralRepo.EXPECT().ReferralSettings().Times(1).Return(response.ReferralSettings{}, nil)
+			},
+			expectedError: errors.New("the referral program isn't running right now"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			referralRepo := mock_interfaces.NewMockReferralRepository(ctrl)
+			tt.buildStub(*referralRepo)
+			referralUseCase := NewReferralUsecase(referralRepo, config.Config{})
+			err := referralUseCase.CheckReferralCode(tt.code)
+			assert.Equal(t, tt.expectedError, err)
+			if err != nil {
+				return
+			}
+			reward, err := referralUseCase.ReferralOffer(7, tt.code)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, 20, reward)
+		})
+	}
+}
+
+func TestRegenerateReferralCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	referralRepo := mock_interfaces.NewMockReferralRepository(ctrl)
+	referralRepo.EXPECT().ReferralCodeExists(gomock.Any()).Times(1).Return(false, nil)
+	referralRepo.EXPECT().UpdateReferralCode(7, gomock.Any()).Times(1).Return(nil)
+	referralUseCase := NewReferralUsecase(referralRepo, config.Config{SIGNUPURL: "https://shop.example.com/signup"})
+	code, err := referralUseCase.RegenerateReferralCode(7)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "https://shop.example.com/signup?ref="+code.Code, code.Link)
+}
@@ -1,22 +1,44 @@
 package usecase
 
 import (
+	"fmt"
+	"strings"
+
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
+	"main.go/internal/infrastructure/config"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
 )
 
 type walletUseCase struct {
 	walletRepo interfaces.WalletRepository
+	cfg        config.Config
 }
 
-func NewWalletUseCase(walletRepo interfaces.WalletRepository) services.WalletUseCase {
+func NewWalletUseCase(walletRepo interfaces.WalletRepository, cfg config.Config) services.WalletUseCase {
 	return &walletUseCase{
 		walletRepo: walletRepo,
+		cfg:        cfg,
 	}
 }
 
+// defaultWalletAdjustmentLimit is used when WALLET_ADJUSTMENT_LIMIT isn't set.
+const defaultWalletAdjustmentLimit = 1000
+
+// adjustmentNeedsApproval reports whether an adjustment of amount, credit or
+// debit, is above what an admin can make without a superadmin.
+func adjustmentNeedsApproval(cfg config.Config, amount int) bool {
+	limit := cfg.WALLETADJUSTMENTLIMIT
+	if limit <= 0 {
+		limit = defaultWalletAdjustmentLimit
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+	return amount > limit
+}
+
 // CreateWallet implements interfaces.WalletUseCase.
 func (w *walletUseCase) CreateWallet(userId int) error {
 	err := w.walletRepo.CreateWallet(userId)
@@ -30,7 +52,48 @@ func (w *walletUseCase) DisplayWallet(userId int) (response.Wallet, error) {
 }
 
 // WalletHistory implements interfaces.WalletUseCase.
-func (w *walletUseCase) WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletHistories, int, error) {
+func (w *walletUseCase) WalletHistory(userId int, queryParams helperStruct.QueryParams) ([]response.WalletTransaction, int, error) {
 	walletHistory, totalCount, err := w.walletRepo.WalletHistory(userId, queryParams)
 	return walletHistory, totalCount, err
 }
+
+// AdjustWallet implements interfaces.WalletUseCase.
+func (w *walletUseCase) AdjustWallet(adjustment helperStruct.WalletAdjustment, adminId int) (response.WalletAdjustment, error) {
+	adjustment.Reason = strings.TrimSpace(adjustment.Reason)
+	adjustment.Reference = strings.TrimSpace(adjustment.Reference)
+	if adjustment.Amount == 0 {
+		return response.WalletAdjustment{}, fmt.Errorf("amount cannot be zero")
+	}
+	if adjustment.Reason == "" || adjustment.Reference == "" {
+		return response.WalletAdjustment{}, fmt.Errorf("a reason and a reference are needed for every adjustment")
+	}
+	newAdjustment, err := w.walletRepo.CreateWalletAdjustment(adjustment, adminId, adjustmentNeedsApproval(w.cfg, adjustment.Amount))
+	return newAdjustment, err
+}
+
+// ApproveWalletAdjustment implements interfaces.WalletUseCase.
+func (w *walletUseCase) ApproveWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error) {
+	adjustment, err := w.walletRepo.DecideWalletAdjustment(adjustmentId, superId, true, strings.TrimSpace(decision.Note))
+	return adjustment, err
+}
+
+// RejectWalletAdjustment implements interfaces.WalletUseCase.
+func (w *walletUseCase) RejectWalletAdjustment(adjustmentId, superId int, decision helperStruct.WalletAdjustmentDecision) (response.WalletAdjustment, error) {
+	decision.Note = strings.TrimSpace(decision.Note)
+	if decision.Note == "" {
+		return response.WalletAdjustment{}, fmt.Errorf("please give a reason for rejecting the adjustment")
+	}
+	adjustment, err := w.walletRepo.DecideWalletAdjustment(adjustmentId, superId, false, decision.Note)
+	return adjustment, err
+}
+
+// ListWalletAdjustments implements interfaces.WalletUseCase.
+func (w *walletUseCase) ListWalletAdjustments(filter helperStruct.WalletAdjustmentFilter, queryParams helperStruct.QueryParams) ([]response.WalletAdjustment, int, error) {
+	switch filter.Status {
+	case "", "pending", "approved", "rejected":
+	default:
+		return []response.WalletAdjustment{}, 0, fmt.Errorf("status must be pending, approved or rejected")
+	}
+	adjustments, totalCount, err := w.walletRepo.ListWalletAdjustments(filter, queryParams)
+	return adjustments, totalCount, err
+}
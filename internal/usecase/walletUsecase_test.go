@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"main.go/internal/infrastructure/config"
+)
+
+func TestAdjustmentNeedsApproval(t *testing.T) {
+	testData := []struct {
+		name           string
+		cfg            config.Config
+		amount         int
+		expectedOutput bool
+	}{
+		{name: "credit under the default limit", cfg: config.Config{}, amount: 500, expectedOutput: false},
+		{name: "credit at the limit", cfg: config.Config{}, amount: defaultWalletAdjustmentLimit, expectedOutput: false},
+		{name: "credit above the limit", cfg: config.Config{}, amount: defaultWalletAdjustmentLimit + 1, expectedOutput: true},
+		{name: "debit above the limit", cfg: config.Config{}, amount: -(defaultWalletAdjustmentLimit + 1), expectedOutput: true},
+		{name: "configured limit", cfg: config.Config{WALLETADJUSTMENTLIMIT: 200}, amount: 500, expectedOutput: true},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedOutput, adjustmentNeedsApproval(tt.cfg, tt.amount))
+		})
+	}
+}
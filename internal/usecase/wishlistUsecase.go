@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"fmt"
+
+	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	"main.go/internal/repository/interfaces"
 	services "main.go/internal/usecase/interface"
@@ -40,3 +43,24 @@ func (w *wishlistUseCase) DisplayWishlistProduct(productId int, userId int) (res
 	wishlist, err := w.wishlistRepo.DisplayWishlistProduct(productId, userId)
 	return wishlist, err
 }
+
+// SetPriceAlert implements interfaces.WishlistUseCase.
+func (w *wishlistUseCase) SetPriceAlert(productId, userId int, alert helperStruct.PriceAlert) (response.PriceAlert, error) {
+	if alert.Target_price <= 0 {
+		return response.PriceAlert{}, fmt.Errorf("target price must be greater than zero")
+	}
+	priceAlert, err := w.wishlistRepo.SetPriceAlert(productId, userId, alert)
+	return priceAlert, err
+}
+
+// RemovePriceAlert implements interfaces.WishlistUseCase.
+func (w *wishlistUseCase) RemovePriceAlert(productId, userId int) error {
+	err := w.wishlistRepo.RemovePriceAlert(productId, userId)
+	return err
+}
+
+// ListPriceAlerts implements interfaces.WishlistUseCase.
+func (w *wishlistUseCase) ListPriceAlerts(userId int) ([]response.PriceAlert, error) {
+	priceAlerts, err := w.wishlistRepo.ListPriceAlerts(userId)
+	return priceAlerts, err
+}
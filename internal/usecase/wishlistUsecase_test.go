@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/assert/v2"
+	"github.com/golang/mock/gomock"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	mock_interfaces "main.go/internal/repository/mockRepository"
+)
+
+func TestSetPriceAlert(t *testing.T) {
+	testData := []struct {
+		name          string
+		alert         helperStruct.PriceAlert
+		buildStub     func(wishlistRepo mock_interfaces.MockWishlistRepository)
+		expectedError error
+	}{
+		{
+			name:  "target below the current price",
+			alert: helperStruct.PriceAlert{Target_price: 750},
+			buildStub: func(wishlistRepo mock_interfaces.MockWishlistRepository) {
+				wishlistRepo.EXPECT().SetPriceAlert(3, 7, helperStruct.PriceAlert{Target_price: 750}).Times(1).
+					Return(response.PriceAlert{ProductItemId: 3, TargetPrice: 750, CurrentPrice: 800}, nil)
+			},
+		},
+		{
+			name:          "zero target",
+			alert:         helperStruct.PriceAlert{},
+			buildStub:     func(wishlistRepo mock_interfaces.MockWishlistRepository) {},
+			expectedError: errors.New("target price must be greater than zero"),
+		},
+		{
+			name:          "negative target",
+			alert:         helperStruct.PriceAlert{Target_price: -10},
+			buildStub:     func(wishlistRepo mock_interfaces.MockWishlistRepository) {},
+			expectedError: errors.New("target price must be greater than zero"),
+		},
+		{
+			name:  "item not wishlisted",
+			alert: helperStruct.PriceAlert{Target_price: 750},
+			buildStub: func(wishlistRepo mock_interfaces.MockWishlistRepository) {
+				wishlistRepo.EXPECT().SetPriceAlert(3, 7, helperStruct.PriceAlert{Target_price: 750}).Times(1).
+					Return(response.PriceAlert{}, errors.New("add the product to your wishlist before setting a price alert"))
+			},
+			expectedError: errors.New("add the product to your wishlist before setting a price alert"),
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			wishlistRepo := mock_interfaces.NewMockWishlistRepository(ctrl)
+			tt.buildStub(*wishlistRepo)
+			wishlistUseCase := NewWishlistUseCase(wishlistRepo)
+			_, err := wishlistUseCase.SetPriceAlert(3, 7, tt.alert)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
@@ -5,18 +5,21 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	services "main.go/internal/usecase/interface"
 	"main.go/internal/web/handlerUtil"
 )
 
 type CartHandler struct {
-	cartUsecase services.CartUseCase
+	cartUsecase           services.CartUseCase
+	recommendationUsecase services.RecommendationUsecase
 }
 
-func NewCartHandler(cartUsecase services.CartUseCase) *CartHandler {
+func NewCartHandler(cartUsecase services.CartUseCase, recommendationUsecase services.RecommendationUsecase) *CartHandler {
 	return &CartHandler{
-		cartUsecase: cartUsecase,
+		cartUsecase:           cartUsecase,
+		recommendationUsecase: recommendationUsecase,
 	}
 }
 func (cr *CartHandler) AddToCart(c *gin.Context) {
@@ -128,6 +131,9 @@ func (cr *CartHandler) ListCart(c *gin.Context) {
 		})
 		return
 	}
+	if recommendations, err := cr.recommendationUsecase.CartRecommendations(userId); err == nil {
+		viewCart.Recommendations = &recommendations
+	}
 	c.JSON(http.StatusOK, response.Response{
 		StatusCode: 200,
 		Message:    "cart displayed successfully",
@@ -135,3 +141,98 @@ func (cr *CartHandler) ListCart(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (cr *CartHandler) ApplyCoupon(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving user id from context",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var couponName helperStruct.CouponName
+	err = c.BindJSON(&couponName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	viewCart, err := cr.cartUsecase.ApplyCoupon(userId, couponName.CouponName)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "can't apply coupon",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "coupon applied to cart",
+		Data:       viewCart,
+		Errors:     nil,
+	})
+}
+func (cr *CartHandler) RemoveCoupon(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving user id from context",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	viewCart, err := cr.cartUsecase.RemoveCoupon(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "can't remove coupon",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "coupon removed from cart",
+		Data:       viewCart,
+		Errors:     nil,
+	})
+}
+func (cr *CartHandler) AvailableCoupons(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving user id from context",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	offers, err := cr.cartUsecase.AvailableCoupons(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "can't list coupons",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "coupons ranked by saving",
+		Data:       offers,
+		Errors:     nil,
+	})
+}
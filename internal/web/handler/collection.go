@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	services "main.go/internal/usecase/interface"
+)
+
+type CollectionHandler struct {
+	collectionUsecase services.CollectionUsecase
+}
+
+func NewCollectionHandler(collectionUsecase services.CollectionUsecase) *CollectionHandler {
+	return &CollectionHandler{
+		collectionUsecase: collectionUsecase,
+	}
+}
+
+// noOfPages works out the page count for a listing the same way as the other list handlers.
+func noOfPages(totalCount, limit int) int {
+	if limit == 0 {
+		limit = 10
+	}
+	pages := totalCount / limit
+	if pages == 0 {
+		return 1
+	} else if totalCount%limit != 0 {
+		pages++
+	}
+	return pages
+}
+
+func (cl *CollectionHandler) AddCollection(c *gin.Context) {
+	var collection helperStruct.Collection
+	err := c.BindJSON(&collection)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newCollection, err := cl.collectionUsecase.AddCollection(collection)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding collection",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection added successfully",
+		Data:       newCollection,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) UpdateCollection(c *gin.Context) {
+	collectionId, err := strconv.Atoi(c.Param("collection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var collection helperStruct.Collection
+	err = c.BindJSON(&collection)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedCollection, err := cl.collectionUsecase.UpdateCollection(collection, collectionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating collection",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection updated successfully",
+		Data:       updatedCollection,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) DeleteCollection(c *gin.Context) {
+	collectionId, err := strconv.Atoi(c.Param("collection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = cl.collectionUsecase.DeleteCollection(collectionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting collection",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) ListAllCollections(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	collections, totalCount, err := cl.collectionUsecase.ListAllCollections(queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying collections",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Collections []response.Collection
+		NoOfPages   int
+	}{
+		Collections: collections,
+		NoOfPages:   noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collections displayed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) DisplayCollection(c *gin.Context) {
+	collectionId, err := strconv.Atoi(c.Param("collection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	collection, err := cl.collectionUsecase.DisplayCollection(collectionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying collection",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection displayed successfully",
+		Data:       collection,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) SetCollectionItems(c *gin.Context) {
+	collectionId, err := strconv.Atoi(c.Param("collection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var items helperStruct.CollectionItems
+	err = c.BindJSON(&items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	collection, err := cl.collectionUsecase.SetCollectionItems(collectionId, items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error setting collection items",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection items set successfully",
+		Data:       collection,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) SetProductTags(c *gin.Context) {
+	productItemId, err := strconv.Atoi(c.Param("productItem_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var tags helperStruct.ProductTags
+	err = c.BindJSON(&tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	savedTags, err := cl.collectionUsecase.SetProductTags(productItemId, tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error setting tags",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "tags set successfully",
+		Data:       savedTags,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) AddBanner(c *gin.Context) {
+	var banner helperStruct.Banner
+	err := c.BindJSON(&banner)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newBanner, err := cl.collectionUsecase.AddBanner(banner)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding banner",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "banner added successfully",
+		Data:       newBanner,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) UpdateBanner(c *gin.Context) {
+	bannerId, err := strconv.Atoi(c.Param("banner_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var banner helperStruct.Banner
+	err = c.BindJSON(&banner)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedBanner, err := cl.collectionUsecase.UpdateBanner(banner, bannerId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating banner",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "banner updated successfully",
+		Data:       updatedBanner,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) DeleteBanner(c *gin.Context) {
+	bannerId, err := strconv.Atoi(c.Param("banner_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = cl.collectionUsecase.DeleteBanner(bannerId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting banner",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "banner deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) ListAllBanners(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	banners, totalCount, err := cl.collectionUsecase.ListAllBanners(queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying banners",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Banners   []response.Banner
+		NoOfPages int
+	}{
+		Banners:   banners,
+		NoOfPages: noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "banners displayed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) Home(c *gin.Context) {
+	home, err := cl.collectionUsecase.Home()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying home",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "home displayed successfully",
+		Data:       home,
+		Errors:     nil,
+	})
+}
+func (cl *CollectionHandler) StorefrontCollection(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	collection, totalCount, err := cl.collectionUsecase.StorefrontCollection(c.Param("slug"), queryParams)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Response{
+			StatusCode: 404,
+			Message:    "collection not found",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Collection response.Collection
+		NoOfPages  int
+	}{
+		Collection: collection,
+		NoOfPages:  noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "collection displayed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/common/response"
+	services "main.go/internal/usecase/interface"
+	"main.go/internal/web/handlerUtil"
+)
+
+type CompareHandler struct {
+	compareUsecase services.CompareUsecase
+}
+
+func NewCompareHandler(compareUsecase services.CompareUsecase) *CompareHandler {
+	return &CompareHandler{
+		compareUsecase: compareUsecase,
+	}
+}
+func (cm *CompareHandler) AddToCompare(c *gin.Context) {
+	paramId := c.Param("product_item_id")
+	productItemId, err := strconv.Atoi(paramId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = cm.compareUsecase.AddToCompare(userId, productItemId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding to compare list",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "product added to compare list",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (cm *CompareHandler) RemoveFromCompare(c *gin.Context) {
+	paramId := c.Param("product_item_id")
+	productItemId, err := strconv.Atoi(paramId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = cm.compareUsecase.RemoveFromCompare(userId, productItemId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error removing from compare list",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "product removed from compare list",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (cm *CompareHandler) ClearCompare(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = cm.compareUsecase.ClearCompare(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error clearing compare list",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "compare list cleared",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (cm *CompareHandler) Compare(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	comparison, err := cm.compareUsecase.Compare(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error comparing products",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	if len(comparison.Items) == 0 {
+		c.JSON(http.StatusOK, response.Response{
+			StatusCode: 200,
+			Message:    "there are no items in compare list",
+			Data:       nil,
+			Errors:     nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "products compared successfully",
+		Data:       comparison,
+		Errors:     nil,
+	})
+}
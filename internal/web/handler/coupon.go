@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/csv"
 	"net/http"
 	"strconv"
 
@@ -228,3 +229,141 @@ func (cu *CouponHandler) EnableCoupon(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (cu *CouponHandler) AddCampaign(c *gin.Context) {
+	var campaign helperStruct.CouponCampaign
+	err := c.BindJSON(&campaign)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newCampaign, err := cu.couponUsecase.AddCampaign(campaign)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding campaign",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "campaign added successfully",
+		Data:       newCampaign,
+		Errors:     nil,
+	})
+}
+func (cu *CouponHandler) ListCampaigns(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.SortBy = c.Query("sort_by")
+	queryParams.Query = c.Query("query")
+	if c.Query("sort_desc") != "" {
+		queryParams.SortDesc = true
+	}
+	campaigns, totalCount, err := cu.couponUsecase.ListCampaigns(queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error listing campaigns",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Campaigns []response.CouponCampaign
+		NoOfPages int
+	}{
+		Campaigns: campaigns,
+		NoOfPages: noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "campaigns listed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (cu *CouponHandler) DisplayCampaign(c *gin.Context) {
+	paramId := c.Param("campaign_id")
+	campaignId, err := strconv.Atoi(paramId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	campaign, err := cu.couponUsecase.DisplayCampaign(campaignId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying campaign",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "campaign displayed successfully",
+		Data:       campaign,
+		Errors:     nil,
+	})
+}
+func (cu *CouponHandler) DownloadCampaignCodes(c *gin.Context) {
+	paramId := c.Param("campaign_id")
+	campaignId, err := strconv.Atoi(paramId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	codes, err := cu.couponUsecase.CampaignCodes(campaignId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "cant get campaign codes",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment;filename=campaign-"+paramId+"-codes.csv")
+
+	wr := csv.NewWriter(c.Writer)
+	headers := []string{"Code", "Redeemed", "UserId", "OrderId", "RedeemedAt"}
+	if err := wr.Write(headers); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	for _, code := range codes {
+		redeemedAt := ""
+		if code.RedeemedAt != nil {
+			redeemedAt = code.RedeemedAt.Format("2006-01-02 15:04:05")
+		}
+		row := []string{code.Code, strconv.FormatBool(code.Redeemed), strconv.Itoa(int(code.UserId)), strconv.Itoa(int(code.OrderId)), redeemedAt}
+		if err := wr.Write(row); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+	wr.Flush()
+	if err := wr.Error(); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+	}
+}
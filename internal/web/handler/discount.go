@@ -154,3 +154,307 @@ func (d *DiscountHandler) ListAllDiscounts(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (d *DiscountHandler) AddPriceRule(c *gin.Context) {
+	var priceRule helperStruct.PriceRule
+	err := c.BindJSON(&priceRule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newPriceRule, err := d.discountUsecase.AddPriceRule(priceRule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding price rule",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price rule added successfully",
+		Data:       newPriceRule,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) UpdatePriceRule(c *gin.Context) {
+	priceRuleId, err := strconv.Atoi(c.Param("price_rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var priceRule helperStruct.PriceRule
+	err = c.BindJSON(&priceRule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedPriceRule, err := d.discountUsecase.UpdatePriceRule(priceRule, priceRuleId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating price rule",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price rule updated successfully",
+		Data:       updatedPriceRule,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) DeletePriceRule(c *gin.Context) {
+	priceRuleId, err := strconv.Atoi(c.Param("price_rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = d.discountUsecase.DeletePriceRule(priceRuleId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting price rule",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price rule deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) ListPriceRules(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	priceRules, totalCount, err := d.discountUsecase.ListPriceRules(queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying price rules",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		PriceRules []response.PriceRule
+		NoOfPages  int
+	}{
+		PriceRules: priceRules,
+		NoOfPages:  noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price rules displayed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) DisplayPriceRule(c *gin.Context) {
+	priceRuleId, err := strconv.Atoi(c.Param("price_rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	priceRule, err := d.discountUsecase.DisplayPriceRule(priceRuleId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying price rule",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price rule displayed successfully",
+		Data:       priceRule,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) AddPromotion(c *gin.Context) {
+	var promotion helperStruct.Promotion
+	err := c.BindJSON(&promotion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newPromotion, err := d.discountUsecase.AddPromotion(promotion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding promotion",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "promotion added successfully",
+		Data:       newPromotion,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) UpdatePromotion(c *gin.Context) {
+	promotionId, err := strconv.Atoi(c.Param("promotion_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var promotion helperStruct.Promotion
+	err = c.BindJSON(&promotion)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedPromotion, err := d.discountUsecase.UpdatePromotion(promotion, promotionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating promotion",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "promotion updated successfully",
+		Data:       updatedPromotion,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) DeletePromotion(c *gin.Context) {
+	promotionId, err := strconv.Atoi(c.Param("promotion_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = d.discountUsecase.DeletePromotion(promotionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting promotion",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "promotion deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) ListPromotions(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	promotions, totalCount, err := d.discountUsecase.ListPromotions(queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying promotions",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Promotions []response.Promotion
+		NoOfPages  int
+	}{
+		Promotions: promotions,
+		NoOfPages:  noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "promotions displayed successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (d *DiscountHandler) DisplayPromotion(c *gin.Context) {
+	promotionId, err := strconv.Atoi(c.Param("promotion_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	promotion, err := d.discountUsecase.DisplayPromotion(promotionId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying promotion",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "promotion displayed successfully",
+		Data:       promotion,
+		Errors:     nil,
+	})
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	services "main.go/internal/usecase/interface"
+	"main.go/internal/web/handlerUtil"
+)
+
+type LoyaltyHandler struct {
+	loyaltyUsecase services.LoyaltyUseCase
+}
+
+func NewLoyaltyHandler(loyaltyUsecase services.LoyaltyUseCase) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyUsecase: loyaltyUsecase,
+	}
+}
+func (l *LoyaltyHandler) PointsSummary(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	summary, err := l.loyaltyUsecase.PointsSummary(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying loyalty points",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "loyalty points displayed successfully",
+		Data:       summary,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) PointsHistory(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	entries, totalCount, err := l.loyaltyUsecase.PointsHistory(userId, queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying loyalty points history",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Entries   []response.LoyaltyEntry
+		NoOfPages int
+	}{
+		Entries:   entries,
+		NoOfPages: noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "loyalty points history fetched successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) Settings(c *gin.Context) {
+	settings, err := l.loyaltyUsecase.Settings()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying loyalty settings",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "loyalty settings displayed successfully",
+		Data:       settings,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) UpdateSettings(c *gin.Context) {
+	var settings helperStruct.LoyaltySettings
+	err := c.BindJSON(&settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedSettings, err := l.loyaltyUsecase.UpdateSettings(settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating loyalty settings",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "loyalty settings updated successfully",
+		Data:       updatedSettings,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) AddTier(c *gin.Context) {
+	var tier helperStruct.LoyaltyTier
+	err := c.BindJSON(&tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newTier, err := l.loyaltyUsecase.AddTier(tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adding tier",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{
+		StatusCode: 201,
+		Message:    "tier added successfully",
+		Data:       newTier,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) UpdateTier(c *gin.Context) {
+	tierId, err := strconv.Atoi(c.Param("tier_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var tier helperStruct.LoyaltyTier
+	err = c.BindJSON(&tier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedTier, err := l.loyaltyUsecase.UpdateTier(tier, tierId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating tier",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "tier updated successfully",
+		Data:       updatedTier,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) DeleteTier(c *gin.Context) {
+	tierId, err := strconv.Atoi(c.Param("tier_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = l.loyaltyUsecase.DeleteTier(tierId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting tier",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "tier deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (l *LoyaltyHandler) ListTiers(c *gin.Context) {
+	tiers, err := l.loyaltyUsecase.ListTiers()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying tiers",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "tiers displayed successfully",
+		Data:       tiers,
+		Errors:     nil,
+	})
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -47,9 +48,10 @@ func (o *OrderHandler) OrderAll(c *gin.Context) {
 		})
 		return
 	}
+	// the body is optional, without a coupon name the coupon applied to the cart is used
 	var CouponName helperStruct.CouponName
-	err = c.BindJSON(&CouponName)
-	if err != nil {
+	err = c.ShouldBindJSON(&CouponName)
+	if err != nil && err != io.EOF {
 		c.JSON(http.StatusBadRequest, response.Response{
 			StatusCode: 400,
 			Message:    "error binding json",
@@ -58,7 +60,49 @@ func (o *OrderHandler) OrderAll(c *gin.Context) {
 		})
 		return
 	}
-	order, err := o.orderUsecase.OrderAll(userId, paymentTypeId, CouponName.CouponName)
+	// auto_apply_coupon=true applies the best coupon when none is given or on the cart
+	autoApplyCoupon := false
+	if param := c.Query("auto_apply_coupon"); param != "" {
+		autoApplyCoupon, err = strconv.ParseBool(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{
+				StatusCode: 400,
+				Message:    "error parsing auto_apply_coupon",
+				Data:       nil,
+				Errors:     err.Error(),
+			})
+			return
+		}
+	}
+	// redeem_points pays for part of the order with the user's loyalty points
+	redeemPoints := 0
+	if param := c.Query("redeem_points"); param != "" {
+		redeemPoints, err = strconv.Atoi(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{
+				StatusCode: 400,
+				Message:    "error parsing redeem_points",
+				Data:       nil,
+				Errors:     err.Error(),
+			})
+			return
+		}
+	}
+	// wallet_amount pays that much from the wallet and the rest through the chosen payment type
+	walletAmount := 0
+	if param := c.Query("wallet_amount"); param != "" {
+		walletAmount, err = strconv.Atoi(param)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{
+				StatusCode: 400,
+				Message:    "error parsing wallet_amount",
+				Data:       nil,
+				Errors:     err.Error(),
+			})
+			return
+		}
+	}
+	order, err := o.orderUsecase.OrderAll(userId, paymentTypeId, CouponName.CouponName, autoApplyCoupon, redeemPoints, walletAmount)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{
 			StatusCode: 400,
@@ -572,16 +616,23 @@ func (o *OrderHandler) InvoiceDownload(c *gin.Context) {
 	pdf.Ln(8)
 
 	pdf.SetFont("Arial", "", 12)
-	for _, product := range order.OrderProducts {
-		pdf.Cell(40, 10, fmt.Sprintf("Product: %s", product.ProductName))
+	pricing := order.Pricing
+	for _, line := range pricing.Lines {
+		pdf.Cell(40, 10, fmt.Sprintf("Product: %s", line.ProductName))
 		pdf.Ln(8)
-		pdf.Cell(40, 10, fmt.Sprintf("Price: Rs.%d", product.Price))
-		if product.DiscountPrice != 0 {
+		pdf.Cell(40, 10, fmt.Sprintf("Price: Rs.%.2f", line.ListPrice))
+		if line.Discount != line.PromotionDiscount {
+			pdf.Ln(8)
+			pdf.Cell(40, 10, fmt.Sprintf("Discount: Rs.%.2f", line.Discount-line.PromotionDiscount))
+		}
+		if line.PromotionDiscount != 0 {
 			pdf.Ln(8)
-			pdf.Cell(40, 10, fmt.Sprintf("Discount Price: Rs.%.2f", product.DiscountPrice))
+			pdf.Cell(40, 10, fmt.Sprintf("Promotion %s: Rs.-%.2f", line.Promotion, line.PromotionDiscount))
 		}
 		pdf.Ln(8)
-		pdf.Cell(40, 10, fmt.Sprintf("Quantity: %d", product.Quantity))
+		pdf.Cell(40, 10, fmt.Sprintf("Quantity: %d", line.Quantity))
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Amount: Rs.%.2f", line.Total))
 		pdf.Ln(10)
 
 	}
@@ -593,17 +644,37 @@ func (o *OrderHandler) InvoiceDownload(c *gin.Context) {
 	pdf.Ln(8)
 
 	pdf.SetFont("Arial", "", 12)
-	pdf.Cell(40, 10, fmt.Sprintf("Subtotal: Rs.%d", order.OrderResponse.SubTotal))
-	if order.OrderResponse.CouponCode != "" {
+	pdf.Cell(40, 10, fmt.Sprintf("Subtotal: Rs.%.2f", pricing.SubTotal))
+	if pricing.ItemDiscount != 0 {
 		pdf.Ln(8)
-		pdf.Cell(40, 10, fmt.Sprintf("Coupon Amount: Rs.%d", order.OrderResponse.CouponAmount))
+		pdf.Cell(40, 10, fmt.Sprintf("Discount: Rs.-%.2f", pricing.ItemDiscount))
 	}
-	if order.OrderResponse.DiscountPrice != 0 {
+	if pricing.CartDiscount != 0 {
 		pdf.Ln(8)
-		pdf.Cell(40, 10, fmt.Sprintf("Discount Price: Rs.%d", order.OrderResponse.DiscountPrice))
+		pdf.Cell(40, 10, fmt.Sprintf("Cart Discount: Rs.-%.2f", pricing.CartDiscount))
 	}
+	if pricing.CouponCode != "" {
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Coupon Amount: Rs.-%.2f", pricing.CouponDiscount))
+	}
+	if pricing.PointsDiscount != 0 {
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Loyalty Points (%d): Rs.-%.2f", pricing.PointsRedeemed, pricing.PointsDiscount))
+	}
+	pdf.Ln(8)
+	pdf.Cell(40, 10, fmt.Sprintf("Shipping: Rs.%.2f", pricing.Shipping))
 	pdf.Ln(8)
 	pdf.Cell(40, 10, fmt.Sprintf("Order Total: Rs.%d", order.OrderResponse.OrderTotal))
+	if order.OrderResponse.WalletAmount != 0 && order.OrderResponse.WalletAmount != order.OrderResponse.OrderTotal {
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Paid from Wallet: Rs.%d", order.OrderResponse.WalletAmount))
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Paid by %s: Rs.%d", order.OrderResponse.PaymentType, order.OrderResponse.OrderTotal-order.OrderResponse.WalletAmount))
+	}
+	if pricing.Tax != 0 {
+		pdf.Ln(8)
+		pdf.Cell(40, 10, fmt.Sprintf("Includes GST: Rs.%.2f", pricing.Tax))
+	}
 	pdf.Ln(10)
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "", 10)
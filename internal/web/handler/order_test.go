@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"main.go/internal/common/response"
+	mock_interfaces "main.go/internal/usecase/mockUsecase"
+	"main.go/internal/web/middleware"
+)
+
+func TestOrderAllQueryParams(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	orderUseCase := mock_interfaces.NewMockOrderUseCase(ctrl)
+	OrderHandler := NewOrderHandler(orderUseCase, nil)
+
+	testData := []struct {
+		name            string
+		query           string
+		buildStub       func(orderUseCase mock_interfaces.MockOrderUseCase)
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			name:  "no optional parameters",
+			query: "",
+			buildStub: func(orderUseCase mock_interfaces.MockOrderUseCase) {
+				orderUseCase.EXPECT().OrderAll(1, 2, "", false, 0, 0).Times(1).Return(response.ResponseOrder{}, nil)
+			},
+			expectedCode:    200,
+			expectedMessage: "order placed",
+		},
+		{
+			name:  "all optional parameters",
+			query: "?auto_apply_coupon=true&redeem_points=150&wallet_amount=300",
+			buildStub: func(orderUseCase mock_interfaces.MockOrderUseCase) {
+				orderUseCase.EXPECT().OrderAll(1, 2, "", true, 150, 300).Times(1).Return(response.ResponseOrder{}, nil)
+			},
+			expectedCode:    200,
+			expectedMessage: "order placed",
+		},
+		{
+			name:            "malformed auto_apply_coupon",
+			query:           "?auto_apply_coupon=yes",
+			buildStub:       func(orderUseCase mock_interfaces.MockOrderUseCase) {},
+			expectedCode:    400,
+			expectedMessage: "error parsing auto_apply_coupon",
+		},
+		{
+			name:            "malformed redeem_points",
+			query:           "?redeem_points=all",
+			buildStub:       func(orderUseCase mock_interfaces.MockOrderUseCase) {},
+			expectedCode:    400,
+			expectedMessage: "error parsing redeem_points",
+		},
+		{
+			name:            "malformed wallet_amount",
+			query:           "?wallet_amount=12.5",
+			buildStub:       func(orderUseCase mock_interfaces.MockOrderUseCase) {},
+			expectedCode:    400,
+			expectedMessage: "error parsing wallet_amount",
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*orderUseCase)
+			engine := gin.Default()
+			recorder := httptest.NewRecorder()
+			engine.POST("/user/order/orderall/:payment_id", middleware.TestUserAuth, OrderHandler.OrderAll)
+			url := "/user/order/orderall/2" + tt.query
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			engine.ServeHTTP(recorder, req)
+			var actual response.Response
+			err := json.Unmarshal(recorder.Body.Bytes(), &actual)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+			assert.Equal(t, tt.expectedMessage, actual.Message)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	services "main.go/internal/usecase/interface"
+	"main.go/internal/web/handlerUtil"
 )
 
 type PaymentHandler struct {
@@ -47,15 +48,18 @@ func (cr *PaymentHandler) CreateRazorpayPayment(c *gin.Context) {
 		})
 		return
 	}
+	// the part paid from the wallet is already settled
+	amountDue := order.OrderTotal - order.WalletAmount
 	c.HTML(200, "app.html", gin.H{
 		"UserID":       userId,
-		"total_price":  order.OrderTotal,
-		"total":        order.OrderTotal,
+		"total_price":  amountDue,
+		"total":        amountDue,
 		"orderData":    order.Id,
 		"orderid":      razorpayID,
-		"amount":       order.OrderTotal,
+		"amount":       amountDue,
 		"Email":        "vishnusunil243@gmail.com",
 		"Phone_Number": "8129987917",
+		"callback":     "/payment-handler",
 	})
 }
 
@@ -133,6 +137,75 @@ func (cr *PaymentHandler) PaymentSuccess(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (cr *PaymentHandler) CreateWalletTopUp(c *gin.Context) {
+	amount, err := strconv.Atoi(c.Query("amount"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "please enter a valid amount",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	topUp, err := cr.paymentUseCase.CreateWalletTopUp(userId, amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "can't top up the wallet",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.HTML(200, "app.html", gin.H{
+		"UserID":       userId,
+		"total_price":  topUp.Amount,
+		"total":        topUp.Amount,
+		"orderData":    topUp.Id,
+		"orderid":      topUp.RazorpayOrderId,
+		"amount":       topUp.Amount,
+		"Email":        "vishnusunil243@gmail.com",
+		"Phone_Number": "8129987917",
+		"callback":     "/wallet-topup-handler",
+	})
+}
+
+// WalletTopUpSuccess credits the wallet once Razorpay's checkout reports the
+// payment, the wallet and amount come from the stored top-up, not the query.
+func (cr *PaymentHandler) WalletTopUpSuccess(c *gin.Context) {
+	topUpVerifier := helperStruct.TopUpVerification{
+		RazorpayOrderId: strings.TrimSpace(c.Query("id")),
+		PaymentRef:      strings.TrimSpace(c.Query("payment_ref")),
+		Signature:       strings.TrimSpace(c.Query("signature")),
+	}
+	topUp, err := cr.paymentUseCase.VerifyWalletTopUp(topUpVerifier)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "failed to top up the wallet",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    fmt.Sprintf("rs.%d added to the wallet", topUp.Amount),
+		Data:       topUp,
+		Errors:     nil,
+	})
+}
 func (cr *PaymentHandler) AddPaymentType(c *gin.Context) {
 	var paymentType helperStruct.PaymentType
 	err := c.BindJSON(&paymentType)
@@ -1,24 +1,57 @@
 package handler
 
 import (
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"main.go/internal/common/helperStruct"
 	"main.go/internal/common/response"
 	services "main.go/internal/usecase/interface"
+	"main.go/internal/web/handlerUtil"
 )
 
 type ProductHandler struct {
-	productUseCase services.ProductUsecase
+	productUseCase        services.ProductUsecase
+	recommendationUsecase services.RecommendationUsecase
 }
 
-func NewProductHandler(productUseCase services.ProductUsecase) *ProductHandler {
+func NewProductHandler(productUseCase services.ProductUsecase, recommendationUsecase services.RecommendationUsecase) *ProductHandler {
 	return &ProductHandler{
-		productUseCase: productUseCase,
+		productUseCase:        productUseCase,
+		recommendationUsecase: recommendationUsecase,
 	}
 }
+
+// resolveParam reads an id or a slug from the path. Old slugs are answered
+// with a permanent redirect to the current one and ok is false.
+func (p *ProductHandler) resolveParam(c *gin.Context, param, entity string) (int, bool) {
+	value := c.Param(param)
+	if id, err := strconv.Atoi(value); err == nil {
+		return id, true
+	}
+	id, current, err := p.productUseCase.ResolveSlug(entity, value)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Response{
+			StatusCode: 404,
+			Message:    "not found",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return 0, false
+	}
+	if current != "" {
+		location := strings.TrimSuffix(c.Request.URL.Path, value) + current
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return 0, false
+	}
+	return id, true
+}
 func (cr *ProductHandler) CreateCategory(c *gin.Context) {
 	var Category helperStruct.Category
 	err := c.BindJSON(&Category)
@@ -87,6 +120,46 @@ func (cr *ProductHandler) UpdateCategory(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (cr *ProductHandler) MoveCategory(c *gin.Context) {
+	paramId := c.Param("id")
+	id, err := strconv.Atoi(paramId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error getting params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var move helperStruct.MoveCategory
+	err = c.BindJSON(&move)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	movedCategory, err := cr.productUseCase.MoveCategory(move, id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error moving category",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "category moved successfully",
+		Data:       movedCategory,
+		Errors:     nil,
+	})
+}
 func (cr *ProductHandler) DeleteCategory(c *gin.Context) {
 	paramId := c.Param("id")
 	id, err := strconv.Atoi(paramId)
@@ -136,15 +209,8 @@ func (cr *ProductHandler) ListAllCategories(c *gin.Context) {
 	})
 }
 func (cr *ProductHandler) DisplayCategory(c *gin.Context) {
-	paramId := c.Param("id")
-	id, err := strconv.Atoi(paramId)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			StatusCode: 400,
-			Message:    "error getting params",
-			Data:       nil,
-			Errors:     err.Error(),
-		})
+	id, ok := cr.resolveParam(c, "id", "category")
+	if !ok {
 		return
 	}
 	category, err := cr.productUseCase.DisplayCategory(id)
@@ -301,15 +367,8 @@ func (cr *ProductHandler) ListAllBrands(c *gin.Context) {
 	})
 }
 func (p *ProductHandler) DisplayBrand(c *gin.Context) {
-	paramId := c.Param("brand_id")
-	id, err := strconv.Atoi(paramId)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			StatusCode: 400,
-			Message:    "error parsing params",
-			Data:       nil,
-			Errors:     err.Error(),
-		})
+	id, ok := p.resolveParam(c, "brand_id", "brand")
+	if !ok {
 		return
 	}
 	brand, err := p.productUseCase.DisplayBrand(id)
@@ -437,7 +496,16 @@ func (p *ProductHandler) ListAllProducts(c *gin.Context) {
 	if c.Query("sort_desc") != "" {
 		queryParams.SortDesc = true
 	}
-	products, totalCount, err := p.productUseCase.ListAllProducts(queryParams)
+	var products []response.Product
+	var totalCount int
+	var err error
+	// logged in users get the plain listing ranked by their own activity
+	userId, userErr := handlerUtil.GetUserIdFromContext(c)
+	if userErr == nil && queryParams.SortBy == "" && queryParams.Query == "" && queryParams.Filter == "" {
+		products, totalCount, err = p.productUseCase.PersonalizedProducts(userId, queryParams)
+	} else {
+		products, totalCount, err = p.productUseCase.ListAllProducts(queryParams)
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{
 			StatusCode: 400,
@@ -471,15 +539,8 @@ func (p *ProductHandler) ListAllProducts(c *gin.Context) {
 	})
 }
 func (p *ProductHandler) DisplayProduct(c *gin.Context) {
-	paramId := c.Param("product_id")
-	id, err := strconv.Atoi(paramId)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			StatusCode: 400,
-			Message:    "error parsing params",
-			Data:       nil,
-			Errors:     err.Error(),
-		})
+	id, ok := p.resolveParam(c, "product_id", "product")
+	if !ok {
 		return
 	}
 	product, err := p.productUseCase.DisplayProduct(id)
@@ -641,15 +702,8 @@ func (p *ProductHandler) DeleteProductItem(c *gin.Context) {
 	})
 }
 func (p *ProductHandler) DisplayProductItem(c *gin.Context) {
-	paramId := c.Param("productItem_id")
-	id, err := strconv.Atoi(paramId)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{
-			StatusCode: 400,
-			Message:    "error parsing params",
-			Data:       nil,
-			Errors:     err.Error(),
-		})
+	id, ok := p.resolveParam(c, "productItem_id", "product_item")
+	if !ok {
 		return
 	}
 	productItem, err := p.productUseCase.DisplayProductItem(id)
@@ -662,6 +716,16 @@ func (p *ProductHandler) DisplayProductItem(c *gin.Context) {
 		})
 		return
 	}
+	// a lost view only thins out the user's history, the item is still shown
+	if userId, err := handlerUtil.GetUserIdFromContext(c); err == nil {
+		if err := p.productUseCase.RecordView(userId, id); err != nil {
+			log.Printf("recording view of product item %d for user %d: %v", id, userId, err)
+		}
+	}
+	// recommendations are optional, the item is still shown without them
+	if recommendations, err := p.recommendationUsecase.ProductItemRecommendations(id); err == nil {
+		productItem.Recommendations = &recommendations
+	}
 	c.JSON(http.StatusOK, response.Response{
 		StatusCode: 200,
 		Message:    "productitem displayed successfully",
@@ -687,31 +751,6 @@ func (cr *ProductHandler) UploadImage(c *gin.Context) {
 		return
 	}
 
-	// // Initialize MinIO client object
-	// minioClient, err := minio.New(viper.GetString("ENDPOINT"), &minio.Options{
-	// 	Creds:  credentials.NewStaticV4(viper.GetString("ACCESSKEY"), viper.GetString("SECRETKEY"), ""),
-	// 	Secure: false, // Change to true if using HTTPS
-	// })
-	// if err != nil {
-	// 	c.JSON(http.StatusInternalServerError, response.Response{
-	// 		StatusCode: 500,
-	// 		Message:    "failed to initialize MinIO client",
-	// 		Data:       nil,
-	// 		Errors:     err.Error(),
-	// 	})
-	// 	return
-	// }
-	// driveService, err := drive.NewService(context.Background())
-	// if err != nil {
-	// 	c.JSON(http.StatusInternalServerError, response.Response{
-	// 		StatusCode: 500,
-	// 		Message:    "failed to initialize Google Drive client",
-	// 		Data:       nil,
-	// 		Errors:     err.Error(),
-	// 	})
-	// 	return
-	// }
-
 	var Image response.Image
 
 	// Multipart form
@@ -739,61 +778,24 @@ func (cr *ProductHandler) UploadImage(c *gin.Context) {
 	}
 	images := make([]string, 0)
 	for _, file := range files {
-		// Upload the file to specific dst.
-		filePath := "../asset/uploads/" + file.Filename
-		c.SaveUploadedFile(file, filePath)
-
-		// objectName := fmt.Sprintf("prroduct_%d_%s", productId, file.Filename)
-		// Upload file to MinIO
-
-		// Open the file to get an io.Reader
-		// fileData, err := file.Open()
-		// if err != nil {
-		// 	c.JSON(http.StatusBadRequest, response.Response{
-		// 		StatusCode: 400,
-		// 		Message:    "can't open form file",
-		// 		Data:       nil,
-		// 		Errors:     err.Error(),
-		// 	})
-		// 	return
-		// }
-		// defer fileData.Close()
-		// Set the content type
-		// contentType := "image/webp"
-		// _, err = minioClient.PutObject(context.TODO(), viper.GetString("BUCKETNAME"), objectName, fileData, file.Size, minio.PutObjectOptions{
-		// 	ContentType: contentType,
-		// })
-		// // Create a new Google Drive file
-		// file1 := &drive.File{
-		// 	Name: file.Filename,
-		// }
-
-		// res, err := driveService.Files.Create(file1).Media(fileData).Do()
-
-		// if err != nil {
-		// 	c.JSON(http.StatusBadRequest, response.Response{
-		// 		StatusCode: 400,
-		// 		Message:    "can't upload images to google drive",
-		// 		Data:       nil,
-		// 		Errors:     err.Error(),
-		// 	})
-		// 	return
-		// }
-		// if err != nil {
-		// 	c.JSON(http.StatusBadRequest, response.Response{
-		// 		StatusCode: 400,
-		// 		Message:    "can't upload images to MinIO",
-		// 		Data:       nil,
-		// 		Errors:     err.Error(),
-		// 	})
-		// 	return
-		// }
-
-		// Get the MinIO URL for the uploaded file
-		// objectURL := fmt.Sprintf("%s/%s/%s", viper.GetString("ENDPOINT"), viper.GetString("BUCKETNAME"), objectName)
-		// objectURL := res.WebViewLink
-
-		Image, err = cr.productUseCase.UploadImage(filePath, productId)
+		fileData, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{
+				StatusCode: 400,
+				Message:    "can't open form file",
+				Data:       nil,
+				Errors:     err.Error(),
+			})
+			return
+		}
+		Image, err = cr.productUseCase.UploadImage(helperStruct.ImageHelper{
+			ImageFile:     fileData,
+			FileName:      file.Filename,
+			ImageType:     file.Header.Get("Content-Type"),
+			ProductItemId: uint(productId),
+			ImageSize:     file.Size,
+		})
+		fileData.Close()
 		if err != nil {
 			c.JSON(http.StatusBadRequest, response.Response{
 				StatusCode: 400,
@@ -881,3 +883,54 @@ func (p *ProductHandler) SearchProducts(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (p *ProductHandler) RecentlyViewed(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving user id from context",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	productItems, err := p.productUseCase.RecentlyViewed(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying recently viewed products",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "recently viewed products displayed successfully",
+		Data:       productItems,
+		Errors:     nil,
+	})
+}
+func (p *ProductHandler) PriceHistory(c *gin.Context) {
+	id, ok := p.resolveParam(c, "productItem_id", "product_item")
+	if !ok {
+		return
+	}
+	days, _ := strconv.Atoi(c.Query("days"))
+	priceHistory, err := p.productUseCase.PriceHistory(id, days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying price history",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "price history displayed successfully",
+		Data:       priceHistory,
+		Errors:     nil,
+	})
+}
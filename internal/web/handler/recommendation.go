@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"main.go/internal/common/helperStruct"
+	"main.go/internal/common/response"
+	services "main.go/internal/usecase/interface"
+)
+
+type RecommendationHandler struct {
+	recommendationUsecase services.RecommendationUsecase
+}
+
+func NewRecommendationHandler(recommendationUsecase services.RecommendationUsecase) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationUsecase: recommendationUsecase,
+	}
+}
+func (r *RecommendationHandler) AddOverride(c *gin.Context) {
+	var override helperStruct.RecommendationOverride
+	err := c.BindJSON(&override)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newOverride, err := r.recommendationUsecase.AddOverride(override)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error saving recommendation override",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "recommendation override saved successfully",
+		Data:       newOverride,
+		Errors:     nil,
+	})
+}
+func (r *RecommendationHandler) DeleteOverride(c *gin.Context) {
+	overrideId, err := strconv.Atoi(c.Param("override_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing params",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	err = r.recommendationUsecase.DeleteOverride(overrideId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deleting recommendation override",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "recommendation override deleted successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
+func (r *RecommendationHandler) ListOverrides(c *gin.Context) {
+	productItemId, _ := strconv.Atoi(c.Query("product_item_id"))
+	overrides, err := r.recommendationUsecase.ListOverrides(productItemId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying recommendation overrides",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "recommendation overrides displayed successfully",
+		Data:       overrides,
+		Errors:     nil,
+	})
+}
+func (r *RecommendationHandler) RefreshRecommendations(c *gin.Context) {
+	err := r.recommendationUsecase.RefreshRecommendations()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error refreshing recommendations",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "recommendations refreshed successfully",
+		Data:       nil,
+		Errors:     nil,
+	})
+}
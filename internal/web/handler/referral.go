@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -41,7 +42,7 @@ func (r *ReferralHandler) ReferralOffer(c *gin.Context) {
 		})
 		return
 	}
-	err = r.referralUsecase.ReferralOffer(userId, referralOffer.ReferralId)
+	reward, err := r.referralUsecase.ReferralOffer(userId, referralOffer.ReferralId)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{
 			StatusCode: 400,
@@ -53,8 +54,111 @@ func (r *ReferralHandler) ReferralOffer(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response.Response{
 		StatusCode: 200,
-		Message:    "referral offer redeemed successfully an amount of rs.20 will be deposited in the wallet shortly",
+		Message:    fmt.Sprintf("referral offer redeemed successfully an amount of rs.%d will be deposited in the wallet once your first order is past its return window", reward),
 		Data:       nil,
 		Errors:     nil,
 	})
 }
+func (r *ReferralHandler) ReferralDashboard(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	dashboard, err := r.referralUsecase.ReferralDashboard(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying referrals",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "referrals displayed successfully",
+		Data:       dashboard,
+		Errors:     nil,
+	})
+}
+func (r *ReferralHandler) RegenerateReferralCode(c *gin.Context) {
+	userId, err := handlerUtil.GetUserIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving userId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	referralCode, err := r.referralUsecase.RegenerateReferralCode(userId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error generating a new referral code",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "referral code changed successfully, the old code no longer works",
+		Data:       referralCode,
+		Errors:     nil,
+	})
+}
+func (r *ReferralHandler) ReferralSettings(c *gin.Context) {
+	settings, err := r.referralUsecase.ReferralSettings()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error displaying referral settings",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "referral settings displayed successfully",
+		Data:       settings,
+		Errors:     nil,
+	})
+}
+func (r *ReferralHandler) UpdateReferralSettings(c *gin.Context) {
+	var settings helperStruct.ReferralSettings
+	err := c.BindJSON(&settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	updatedSettings, err := r.referralUsecase.UpdateReferralSettings(settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error updating referral settings",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "referral settings updated successfully",
+		Data:       updatedSettings,
+		Errors:     nil,
+	})
+}
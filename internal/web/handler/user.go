@@ -40,6 +40,22 @@ func (cr *UserHandler) UserSignup(c *gin.Context) {
 		})
 		return
 	}
+	// referral links carry the code as ?ref= for the signup page to fill in
+	if user.ReferralCode == "" {
+		user.ReferralCode = c.Query("ref")
+	}
+	if user.ReferralCode != "" {
+		err = cr.referralUsecase.CheckReferralCode(user.ReferralCode)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{
+				StatusCode: 400,
+				Message:    "unable to apply the referral code",
+				Data:       nil,
+				Errors:     err.Error(),
+			})
+			return
+		}
+	}
 	if user.OTP == "" {
 		err = middleware.SendOTP(user.Email)
 		if err != nil {
@@ -101,7 +117,7 @@ func (cr *UserHandler) UserSignup(c *gin.Context) {
 		})
 		return
 	}
-	err = cr.referralUsecase.AddReferral(userData.Id, userData.Mobile)
+	err = cr.referralUsecase.AddReferral(userData.Id)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Response{
 			StatusCode: 400,
@@ -109,11 +125,26 @@ func (cr *UserHandler) UserSignup(c *gin.Context) {
 			Data:       nil,
 			Errors:     err.Error(),
 		})
+		return
+	}
+	message := "user signed up successfully"
+	if user.ReferralCode != "" {
+		reward, err := cr.referralUsecase.ReferralOffer(userData.Id, user.ReferralCode)
+		if err != nil {
+			c.JSON(http.StatusCreated, response.Response{
+				StatusCode: 201,
+				Message:    "user signed up successfully but the referral code couldn't be applied",
+				Data:       userData,
+				Errors:     err.Error(),
+			})
+			return
+		}
+		message = fmt.Sprintf("user signed up successfully an amount of rs.%d will be deposited in the wallet once your first order is past its return window", reward)
 	}
 
 	c.JSON(http.StatusCreated, response.Response{
 		StatusCode: 201,
-		Message:    "user signed up successfully",
+		Message:    message,
 		Data:       userData,
 		Errors:     nil,
 	})
@@ -206,3 +206,83 @@ func TestViewUserProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestUserSignupReferralCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	userUseCase := mock_interfaces.NewMockUserUseCase(ctrl)
+	cartUseCase := mock_interfaces.NewMockCartUseCase(ctrl)
+	walletUseCase := mock_interfaces.NewMockWalletUseCase(ctrl)
+	referralUseCase := mock_interfaces.NewMockReferralUseCase(ctrl)
+	UserHandler := NewUserHandler(userUseCase, cartUseCase, walletUseCase, referralUseCase)
+
+	testData := []struct {
+		name             string
+		signupData       helper.UserReq
+		url              string
+		buildStub        func(referralUseCase mock_interfaces.MockReferralUseCase)
+		expectedCode     int
+		expectedResponse response.Response
+	}{
+		{
+			name:       "invalid code in the body",
+			signupData: helper.UserReq{Name: "TestUser", Email: "test@gmail.com", Mobile: "1234567890", ReferralCode: "ZZZZZZZZ"},
+			url:        "/user/signup",
+			buildStub: func(referralUseCase mock_interfaces.MockReferralUseCase) {
+				referralUseCase.EXPECT().CheckReferralCode("ZZZZZZZZ").Times(1).Return(errors.New("invalid referral code"))
+			},
+			expectedCode: 400,
+			expectedResponse: response.Response{
+				StatusCode: 400,
+				Message:    "unable to apply the referral code",
+				Errors:     "invalid referral code",
+			},
+		},
+		{
+			name:       "code from the referral link",
+			signupData: helper.UserReq{Name: "TestUser", Email: "test@gmail.com", Mobile: "1234567890"},
+			url:        "/user/signup?ref=ab23cd45",
+			buildStub: func(referralUseCase mock_interfaces.MockReferralUseCase) {
+				referralUseCase.EXPECT().CheckReferralCode("ab23cd45").Times(1).Return(errors.New("the referral program isn't running right now"))
+			},
+			expectedCode: 400,
+			expectedResponse: response.Response{
+				StatusCode: 400,
+				Message:    "unable to apply the referral code",
+				Errors:     "the referral program isn't running right now",
+			},
+		},
+		{
+			name:       "body code wins over the link",
+			signupData: helper.UserReq{Name: "TestUser", Email: "test@gmail.com", Mobile: "1234567890", ReferralCode: "ZZZZZZZZ"},
+			url:        "/user/signup?ref=ab23cd45",
+			buildStub: func(referralUseCase mock_interfaces.MockReferralUseCase) {
+				referralUseCase.EXPECT().CheckReferralCode("ZZZZZZZZ").Times(1).Return(errors.New("invalid referral code"))
+			},
+			expectedCode: 400,
+			expectedResponse: response.Response{
+				StatusCode: 400,
+				Message:    "unable to apply the referral code",
+				Errors:     "invalid referral code",
+			},
+		},
+	}
+	for _, tt := range testData {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.buildStub(*referralUseCase)
+			engine := gin.Default()
+			recorder := httptest.NewRecorder()
+			engine.POST("/user/signup", UserHandler.UserSignup)
+			body, err := json.Marshal(tt.signupData)
+			assert.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewBuffer(body))
+			engine.ServeHTTP(recorder, req)
+			var actual response.Response
+			err = json.Unmarshal(recorder.Body.Bytes(), &actual)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, recorder.Code)
+			assert.Equal(t, tt.expectedResponse.Message, actual.Message)
+			assert.Equal(t, tt.expectedResponse.Errors, actual.Errors)
+		})
+	}
+}
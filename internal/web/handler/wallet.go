@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 
@@ -72,20 +73,12 @@ func (w *WalletHandler) WalletHistory(c *gin.Context) {
 		})
 		return
 	}
-	if queryParams.Limit == 0 {
-		queryParams.Limit = 10
-	}
 	responseStruct := struct {
-		WalletHistories []response.WalletHistories
-		NoOfPages       int
+		Transactions []response.WalletTransaction
+		NoOfPages    int
 	}{
-		WalletHistories: walletHistory,
-		NoOfPages:       totalCount / queryParams.Limit,
-	}
-	if responseStruct.NoOfPages == 0 {
-		responseStruct.NoOfPages = 1
-	} else if totalCount%queryParams.Limit != 0 {
-		responseStruct.NoOfPages = responseStruct.NoOfPages + 1
+		Transactions: walletHistory,
+		NoOfPages:    noOfPages(totalCount, queryParams.Limit),
 	}
 	c.JSON(http.StatusOK, response.Response{
 		StatusCode: 200,
@@ -94,3 +87,139 @@ func (w *WalletHandler) WalletHistory(c *gin.Context) {
 		Errors:     nil,
 	})
 }
+func (w *WalletHandler) AdjustWallet(c *gin.Context) {
+	var adjustment helperStruct.WalletAdjustment
+	err := c.BindJSON(&adjustment)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	adminId, err := handlerUtil.GetAdminIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving adminId",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	newAdjustment, err := w.walletUseCase.AdjustWallet(adjustment, adminId)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error adjusting wallet",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	message := "wallet adjusted successfully"
+	if newAdjustment.Status == "pending" {
+		message = "the adjustment is above the approval limit and is waiting for a superadmin's approval"
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    message,
+		Data:       newAdjustment,
+		Errors:     nil,
+	})
+}
+
+// decideWalletAdjustment approves or rejects a pending adjustment for a superadmin.
+func (w *WalletHandler) decideWalletAdjustment(c *gin.Context, approve bool) {
+	adjustmentId, err := strconv.Atoi(c.Param("adjustment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error parsing adjustment id",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	// the body is optional when approving
+	var decision helperStruct.WalletAdjustmentDecision
+	err = c.ShouldBindJSON(&decision)
+	if err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error binding json",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	superId, err := handlerUtil.GetSuperAdminIdFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error retrieving superadmin id",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	var adjustment response.WalletAdjustment
+	if approve {
+		adjustment, err = w.walletUseCase.ApproveWalletAdjustment(adjustmentId, superId, decision)
+	} else {
+		adjustment, err = w.walletUseCase.RejectWalletAdjustment(adjustmentId, superId, decision)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error deciding wallet adjustment",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "wallet adjustment " + adjustment.Status,
+		Data:       adjustment,
+		Errors:     nil,
+	})
+}
+func (w *WalletHandler) ApproveWalletAdjustment(c *gin.Context) {
+	w.decideWalletAdjustment(c, true)
+}
+func (w *WalletHandler) RejectWalletAdjustment(c *gin.Context) {
+	w.decideWalletAdjustment(c, false)
+}
+func (w *WalletHandler) ListWalletAdjustments(c *gin.Context) {
+	var queryParams helperStruct.QueryParams
+	queryParams.Page, _ = strconv.Atoi(c.Query("page"))
+	queryParams.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter := helperStruct.WalletAdjustmentFilter{Status: c.Query("status")}
+	filter.UserId, _ = strconv.Atoi(c.Query("user_id"))
+	adjustments, totalCount, err := w.walletUseCase.ListWalletAdjustments(filter, queryParams)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{
+			StatusCode: 400,
+			Message:    "error listing wallet adjustments",
+			Data:       nil,
+			Errors:     err.Error(),
+		})
+		return
+	}
+	responseStruct := struct {
+		Adjustments []response.WalletAdjustment
+		NoOfPages   int
+	}{
+		Adjustments: adjustments,
+		NoOfPages:   noOfPages(totalCount, queryParams.Limit),
+	}
+	c.JSON(http.StatusOK, response.Response{
+		StatusCode: 200,
+		Message:    "wallet adjustments fetched successfully",
+		Data:       responseStruct,
+		Errors:     nil,
+	})
+}
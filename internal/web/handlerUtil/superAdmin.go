@@ -0,0 +1,14 @@
+package handlerUtil
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetSuperAdminIdFromContext(c *gin.Context) (int, error) {
+	Id := c.Value("superId")
+	superId, err := strconv.Atoi(fmt.Sprintf("%v", Id))
+	return superId, err
+}
@@ -7,19 +7,14 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
-func SendCouponEmail(userEmail, couponCode string) error {
+func SendPriceDropEmail(userEmail, productName string, price float64) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", "your-email@example.com")
 	m.SetHeader("To", userEmail)
-	m.SetHeader("Subject", "Reward For Your Loyalty")
-	m.SetBody("text/html", fmt.Sprintf("Dear user,<br>Thank you for choosing us everytime.We have seen that you have completed 10 orders with us as a recognition of loyalty please accept this coupon with code: <strong>%s</strong>", couponCode))
+	m.SetHeader("Subject", "Price Drop On Your Wishlist")
+	m.SetBody("text/html", fmt.Sprintf("Dear user,<br>Good news! <strong>%s</strong> from your wishlist is now available for <strong>%.2f</strong>, at or below the price you were waiting for.", productName, price))
 
 	dialer := gomail.NewDialer("smtp.gmail.com", 587, viper.GetString("SMTP_USER"), viper.GetString("SMTP_PASSWORD"))
 
-	// Send the email
-	if err := dialer.DialAndSend(m); err != nil {
-		return err
-	}
-
-	return nil
+	return dialer.DialAndSend(m)
 }
@@ -26,6 +26,17 @@ func UserAuth(c *gin.Context) {
 	c.Set("userId", userId)
 	c.Next()
 }
+
+// OptionalUserAuth sets the user id when a valid login cookie is present and
+// lets anonymous requests through unchanged.
+func OptionalUserAuth(c *gin.Context) {
+	if tokenString, err := c.Cookie("UserAuth"); err == nil {
+		if userId, err := ValidateToken(tokenString); err == nil {
+			c.Set("userId", userId)
+		}
+	}
+	c.Next()
+}
 func TestUserAuth(c *gin.Context) {
 	c.Set("userId", 1)
 	c.Next()
@@ -4,31 +4,43 @@ import (
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"main.go/internal/infrastructure/concurrency"
+	"main.go/internal/infrastructure/storage"
 	"main.go/internal/web/handler"
 	"main.go/internal/web/middleware"
 )
 
 type ServerHTTP struct {
 	engine *gin.Engine
+	jobs   *concurrency.Concurrency
 }
 
 func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.AdminHandler,
 	productHandler *handler.ProductHandler, superadminHandler *handler.SuperAdminHandler, carrtHandler *handler.CartHandler,
 	orderHandler *handler.OrderHandler, walletHandler *handler.WalletHandler, paymentHandler *handler.PaymentHandler,
 	couponHandler *handler.CouponHandler, discountHandler *handler.DiscountHandler, referralHandler *handler.ReferralHandler,
-	wishListHandler *handler.WishlistHandler) *ServerHTTP {
+	wishListHandler *handler.WishlistHandler, collectionHandler *handler.CollectionHandler,
+	recommendationHandler *handler.RecommendationHandler, compareHandler *handler.CompareHandler,
+	loyaltyHandler *handler.LoyaltyHandler, jobs *concurrency.Concurrency) *ServerHTTP {
 	engine := gin.New()
 	engine.Use(gin.Logger())
 	engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	engine.GET("/payment-handler", paymentHandler.PaymentSuccess)
+	engine.GET("/wallet-topup-handler", paymentHandler.WalletTopUpSuccess)
+	// images kept by the local storage backend
+	engine.Static("/uploads", storage.LocalUploadDir)
 	home := engine.Group("/home")
 	{
-		home.GET("/", productHandler.ListAllProducts)
-		home.GET("/:productItem_id", productHandler.DisplayProductItem)
+		home.GET("/", middleware.OptionalUserAuth, productHandler.ListAllProducts)
+		home.GET("/:productItem_id", middleware.OptionalUserAuth, productHandler.DisplayProductItem)
+		home.GET("/:productItem_id/pricehistory", productHandler.PriceHistory)
 		home.GET("/brands", productHandler.ListAllBrands)
 		home.GET("/brands/:brand_id", productHandler.DisplayBrand)
 		home.GET("/categories", productHandler.ListAllCategories)
 		home.GET("/categories/:id", productHandler.DisplayCategory)
+		home.GET("/products/:product_id", productHandler.DisplayProduct)
+		home.GET("/collections", collectionHandler.Home)
+		home.GET("/collections/:slug", collectionHandler.StorefrontCollection)
 		home.POST("/search", productHandler.SearchProducts)
 	}
 	user := engine.Group("/user")
@@ -60,6 +72,9 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 				cart.GET("/", carrtHandler.ListCart)
 				cart.POST("/:product_item_id/addtocart", carrtHandler.AddToCart)
 				cart.DELETE("/:product_item_id/removefromcart", carrtHandler.RemoveFromCart)
+				cart.POST("/coupon", carrtHandler.ApplyCoupon)
+				cart.DELETE("/coupon", carrtHandler.RemoveCoupon)
+				cart.GET("/coupons", carrtHandler.AvailableCoupons)
 				order := cart.Group("/orders")
 				{
 					order.GET("/", orderHandler.ListAllOrders)
@@ -79,18 +94,37 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 			{
 				wallet.GET("/", walletHandler.DisplayWallet)
 				wallet.GET("/history", walletHandler.WalletHistory)
+				wallet.GET("/topup", paymentHandler.CreateWalletTopUp)
 			}
 			wishlist := user.Group("/wishlists")
 			{
 				wishlist.POST("/:product_item_id/add", wishListHandler.AddToWishlist)
 				wishlist.DELETE("/:product_item_id/remove", wishListHandler.RemoveFromWishlist)
 				wishlist.GET("/", wishListHandler.ListAllWishlist)
+				wishlist.GET("/pricealerts", wishListHandler.ListPriceAlerts)
+				wishlist.PUT("/:product_item_id/pricealert", wishListHandler.SetPriceAlert)
+				wishlist.DELETE("/:product_item_id/pricealert", wishListHandler.RemovePriceAlert)
 				wishlist.GET("/:product_item_id", wishListHandler.DisplayWishlistProduct)
 				wishlist.POST("/:product_item_id/addtocart", carrtHandler.AddToCart)
 			}
+			user.GET("/recentlyviewed", productHandler.RecentlyViewed)
+			compare := user.Group("/compare")
+			{
+				compare.GET("/", compareHandler.Compare)
+				compare.POST("/:product_item_id/add", compareHandler.AddToCompare)
+				compare.DELETE("/:product_item_id/remove", compareHandler.RemoveFromCompare)
+				compare.DELETE("/", compareHandler.ClearCompare)
+			}
 			referral := user.Group("/referrals")
 			{
 				referral.POST("/", referralHandler.ReferralOffer)
+				referral.GET("/", referralHandler.ReferralDashboard)
+				referral.POST("/code", referralHandler.RegenerateReferralCode)
+			}
+			loyalty := user.Group("/loyalty")
+			{
+				loyalty.GET("/", loyaltyHandler.PointsSummary)
+				loyalty.GET("/history", loyaltyHandler.PointsHistory)
 			}
 		}
 		user.Use(middleware.UserIsBlocked)
@@ -111,6 +145,7 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 			{
 				category.POST("/create", productHandler.CreateCategory)
 				category.PATCH("/update/:id", productHandler.UpdateCategory)
+				category.PATCH("/move/:id", productHandler.MoveCategory)
 				category.DELETE("/delete/:id", productHandler.DeleteCategory)
 				category.GET("/", productHandler.ListAllCategories)
 				category.GET("/:id", productHandler.DisplayCategory)
@@ -140,6 +175,30 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 				productItem.GET("/", productHandler.ListAllProductItems)
 				productItem.GET("/:productItem_id", productHandler.DisplayProductItem)
 				productItem.DELETE("/:image_id", productHandler.DeleteImage)
+				productItem.PUT("/:productItem_id/tags", collectionHandler.SetProductTags)
+			}
+			collection := admin.Group("/collections")
+			{
+				collection.POST("/add", collectionHandler.AddCollection)
+				collection.PATCH("/:collection_id", collectionHandler.UpdateCollection)
+				collection.DELETE("/:collection_id", collectionHandler.DeleteCollection)
+				collection.GET("/", collectionHandler.ListAllCollections)
+				collection.GET("/:collection_id", collectionHandler.DisplayCollection)
+				collection.PUT("/:collection_id/items", collectionHandler.SetCollectionItems)
+			}
+			banner := admin.Group("/banners")
+			{
+				banner.POST("/add", collectionHandler.AddBanner)
+				banner.PATCH("/:banner_id", collectionHandler.UpdateBanner)
+				banner.DELETE("/:banner_id", collectionHandler.DeleteBanner)
+				banner.GET("/", collectionHandler.ListAllBanners)
+			}
+			recommendation := admin.Group("/recommendations")
+			{
+				recommendation.GET("/overrides", recommendationHandler.ListOverrides)
+				recommendation.POST("/overrides", recommendationHandler.AddOverride)
+				recommendation.DELETE("/overrides/:override_id", recommendationHandler.DeleteOverride)
+				recommendation.POST("/refresh", recommendationHandler.RefreshRecommendations)
 			}
 			order := admin.Group("/orders")
 			{
@@ -165,6 +224,13 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 				coupon.GET("/", couponHandler.ListAllCoupons)
 				coupon.GET("/:coupon_id", couponHandler.DisplayCoupon)
 			}
+			campaign := admin.Group("/campaigns")
+			{
+				campaign.POST("/add", couponHandler.AddCampaign)
+				campaign.GET("/", couponHandler.ListCampaigns)
+				campaign.GET("/:campaign_id", couponHandler.DisplayCampaign)
+				campaign.GET("/:campaign_id/codes", couponHandler.DownloadCampaignCodes)
+			}
 			orderStatus := admin.Group("/orderstatuses")
 			{
 				orderStatus.GET("/", orderHandler.ListAllOrderStatuses)
@@ -178,6 +244,41 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 				discount.DELETE("/:discountId", discountHandler.DeleteDiscount)
 				discount.POST("/add", discountHandler.AddDiscount)
 			}
+			priceRule := admin.Group("/pricerules")
+			{
+				priceRule.POST("/add", discountHandler.AddPriceRule)
+				priceRule.PATCH("/:price_rule_id", discountHandler.UpdatePriceRule)
+				priceRule.DELETE("/:price_rule_id", discountHandler.DeletePriceRule)
+				priceRule.GET("/", discountHandler.ListPriceRules)
+				priceRule.GET("/:price_rule_id", discountHandler.DisplayPriceRule)
+			}
+			promotion := admin.Group("/promotions")
+			{
+				promotion.POST("/add", discountHandler.AddPromotion)
+				promotion.PATCH("/:promotion_id", discountHandler.UpdatePromotion)
+				promotion.DELETE("/:promotion_id", discountHandler.DeletePromotion)
+				promotion.GET("/", discountHandler.ListPromotions)
+				promotion.GET("/:promotion_id", discountHandler.DisplayPromotion)
+			}
+			referral := admin.Group("/referrals")
+			{
+				referral.GET("/settings", referralHandler.ReferralSettings)
+				referral.PATCH("/settings", referralHandler.UpdateReferralSettings)
+			}
+			wallet := admin.Group("/wallets")
+			{
+				wallet.POST("/adjustments", walletHandler.AdjustWallet)
+				wallet.GET("/adjustments", walletHandler.ListWalletAdjustments)
+			}
+			loyalty := admin.Group("/loyalty")
+			{
+				loyalty.GET("/settings", loyaltyHandler.Settings)
+				loyalty.PATCH("/settings", loyaltyHandler.UpdateSettings)
+				loyalty.GET("/tiers", loyaltyHandler.ListTiers)
+				loyalty.POST("/tiers/add", loyaltyHandler.AddTier)
+				loyalty.PATCH("/tiers/:tier_id", loyaltyHandler.UpdateTier)
+				loyalty.DELETE("/tiers/:tier_id", loyaltyHandler.DeleteTier)
+			}
 
 		}
 	}
@@ -202,6 +303,12 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 				user.PATCH("/:user_id/block", superadminHandler.BlockUser)
 				user.PATCH("/:user_id/unblock", superadminHandler.UnBlockUserManually)
 			}
+			wallet := superAdmin.Group("/wallets")
+			{
+				wallet.GET("/adjustments", walletHandler.ListWalletAdjustments)
+				wallet.PATCH("/adjustments/:adjustment_id/approve", walletHandler.ApproveWalletAdjustment)
+				wallet.PATCH("/adjustments/:adjustment_id/reject", walletHandler.RejectWalletAdjustment)
+			}
 			paymentType := superAdmin.Group("/paymenttypes")
 			{
 				paymentType.POST("/add", paymentHandler.AddPaymentType)
@@ -217,9 +324,11 @@ func NewServerHTTP(userHandler *handler.UserHandler, adminHandler *handler.Admin
 		}
 	}
 
-	return &ServerHTTP{engine: engine}
+	return &ServerHTTP{engine: engine, jobs: jobs}
 }
 func (sh *ServerHTTP) Start() {
+	// Start the UserStatusChecker goroutine and the other background jobs
+	sh.jobs.Start()
 	sh.engine.LoadHTMLGlob("../../templates/*.html")
 	sh.engine.Run(":8080")
 }
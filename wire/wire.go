@@ -2,8 +2,10 @@ package wire
 
 import (
 	"github.com/google/wire"
+	"main.go/internal/infrastructure/concurrency"
 	"main.go/internal/infrastructure/config"
 	db "main.go/internal/infrastructure/persistence"
+	"main.go/internal/infrastructure/storage"
 	"main.go/internal/repository"
 	"main.go/internal/usecase"
 	http "main.go/internal/web"
@@ -13,6 +15,7 @@ import (
 func InitializeAPI1(cfg config.Config) (*http.ServerHTTP, error) {
 	wire.Build(
 		db.ConnectDatabase,
+		storage.NewStorage,
 		repository.NewWalletRepo,
 		repository.NewUserRepo,
 		repository.NewAdminRepo,
@@ -25,6 +28,10 @@ func InitializeAPI1(cfg config.Config) (*http.ServerHTTP, error) {
 		repository.NewCouponRepo,
 		repository.NewWishlistRepo,
 		repository.NewDiscountRepo,
+		repository.NewCollectionRepo,
+		repository.NewRecommendationRepo,
+		repository.NewCompareRepo,
+		repository.NewLoyaltyRepo,
 		usecase.NewUserUsecase,
 		usecase.NewWishlistUseCase,
 		usecase.NewAdminUsecase,
@@ -37,6 +44,10 @@ func InitializeAPI1(cfg config.Config) (*http.ServerHTTP, error) {
 		usecase.NewCouponUsecase,
 		usecase.NewDiscountUseCase,
 		usecase.NewReferralUsecase,
+		usecase.NewCollectionUsecase,
+		usecase.NewRecommendationUsecase,
+		usecase.NewCompareUsecase,
+		usecase.NewLoyaltyUseCase,
 		handler.NewUserHandler,
 		handler.NewAdminHandler,
 		handler.NewProductHandler,
@@ -49,6 +60,11 @@ func InitializeAPI1(cfg config.Config) (*http.ServerHTTP, error) {
 		handler.NewWishlistHandler,
 		handler.NewReferralHandler,
 		handler.NewDiscountHandler,
+		handler.NewCollectionHandler,
+		handler.NewRecommendationHandler,
+		handler.NewCompareHandler,
+		handler.NewLoyaltyHandler,
+		concurrency.NewConcurrency,
 		http.NewServerHTTP,
 	)
 	return &http.ServerHTTP{}, nil
@@ -7,8 +7,10 @@
 package wire
 
 import (
+	"main.go/internal/infrastructure/concurrency"
 	"main.go/internal/infrastructure/config"
 	"main.go/internal/infrastructure/persistence"
+	"main.go/internal/infrastructure/storage"
 	"main.go/internal/repository"
 	"main.go/internal/usecase"
 	"main.go/internal/web"
@@ -25,25 +27,32 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 	userRepository := repository.NewUserRepo(gormDB)
 	userUseCase := usecase.NewUserUsecase(userRepository)
 	cartRepository := repository.NewCartRepo(gormDB)
-	cartUseCase := usecase.NewCartUseCase(cartRepository)
+	couponRepository := repository.NewCouponRepo(gormDB)
+	cartUseCase := usecase.NewCartUseCase(cartRepository, couponRepository, cfg)
 	walletRepository := repository.NewWalletRepo(gormDB)
-	walletUseCase := usecase.NewWalletUseCase(walletRepository)
+	walletUseCase := usecase.NewWalletUseCase(walletRepository, cfg)
 	referralRepository := repository.NewReferralRepo(gormDB)
-	referralUseCase := usecase.NewReferralUsecase(referralRepository)
+	referralUseCase := usecase.NewReferralUsecase(referralRepository, cfg)
 	userHandler := handler.NewUserHandler(userUseCase, cartUseCase, walletUseCase, referralUseCase)
 	adminRepository := repository.NewAdminRepo(gormDB)
 	adminUseCase := usecase.NewAdminUsecase(adminRepository)
 	adminHandler := handler.NewAdminHandler(adminUseCase)
 	productRepository := repository.NewProductRepo(gormDB)
-	productUsecase := usecase.NewProductUsecase(productRepository)
-	productHandler := handler.NewProductHandler(productUsecase)
+	storageStorage, err := storage.NewStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	productUsecase := usecase.NewProductUsecase(productRepository, storageStorage)
+	recommendationRepository := repository.NewRecommendationRepo(gormDB)
+	recommendationUsecase := usecase.NewRecommendationUsecase(recommendationRepository, storageStorage)
+	productHandler := handler.NewProductHandler(productUsecase, recommendationUsecase)
 	superAdminRepository := repository.NewSuperRepo(gormDB)
 	superAdminUseCase := usecase.NewSuperAdminUsecase(superAdminRepository)
 	superAdminHandler := handler.NewSuperAdminHandler(superAdminUseCase)
-	cartHandler := handler.NewCartHandler(cartUseCase)
+	cartHandler := handler.NewCartHandler(cartUseCase, recommendationUsecase)
 	orderRepository := repository.NewOrderRepo(gormDB)
-	couponRepository := repository.NewCouponRepo(gormDB)
-	orderUseCase := usecase.NewOrderUseCase(orderRepository, couponRepository)
+	loyaltyRepository := repository.NewLoyaltyRepo(gormDB)
+	orderUseCase := usecase.NewOrderUseCase(orderRepository, couponRepository, cartRepository, loyaltyRepository, cfg)
 	orderHandler := handler.NewOrderHandler(orderUseCase, adminUseCase)
 	walletHandler := handler.NewWalletHandler(walletUseCase)
 	paymentRepository := repository.NewPaymentRepo(gormDB)
@@ -58,6 +67,16 @@ func InitializeAPI(cfg config.Config) (*http.ServerHTTP, error) {
 	wishlistRepository := repository.NewWishlistRepo(gormDB)
 	wishlistUseCase := usecase.NewWishlistUseCase(wishlistRepository)
 	wishlistHandler := handler.NewWishlistHandler(wishlistUseCase)
-	serverHTTP := http.NewServerHTTP(userHandler, adminHandler, productHandler, superAdminHandler, cartHandler, orderHandler, walletHandler, paymentHandler, couponHandler, discountHandler, referralHandler, wishlistHandler)
+	collectionRepository := repository.NewCollectionRepo(gormDB)
+	collectionUsecase := usecase.NewCollectionUsecase(collectionRepository, storageStorage)
+	collectionHandler := handler.NewCollectionHandler(collectionUsecase)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationUsecase)
+	compareRepository := repository.NewCompareRepo(gormDB)
+	compareUsecase := usecase.NewCompareUsecase(compareRepository, storageStorage)
+	compareHandler := handler.NewCompareHandler(compareUsecase)
+	loyaltyUseCase := usecase.NewLoyaltyUseCase(loyaltyRepository)
+	loyaltyHandler := handler.NewLoyaltyHandler(loyaltyUseCase)
+	concurrencyConcurrency := concurrency.NewConcurrency(gormDB, productRepository, discountRepository, wishlistRepository, recommendationRepository, referralRepository, loyaltyRepository)
+	serverHTTP := http.NewServerHTTP(userHandler, adminHandler, productHandler, superAdminHandler, cartHandler, orderHandler, walletHandler, paymentHandler, couponHandler, discountHandler, referralHandler, wishlistHandler, collectionHandler, recommendationHandler, compareHandler, loyaltyHandler, concurrencyConcurrency)
 	return serverHTTP, nil
 }